@@ -0,0 +1,15 @@
+data "jsonschema_composed_schema" "service" {
+  all_of = ["${path.module}/schemas/base.json"]
+  one_of = [
+    "${path.module}/schemas/web.json",
+    "${path.module}/schemas/worker.json",
+  ]
+  title = "Service"
+  id    = "https://schemas.example.com/service.json"
+}
+
+# publish the composed schema next to its parts
+resource "local_file" "service_schema" {
+  filename = "${path.module}/dist/service.json"
+  content  = data.jsonschema_composed_schema.service.schema_json
+}
@@ -0,0 +1,12 @@
+data "jsonschema_evaluated_config" "example" {
+  input_pattern = "./config/*.jsonnet"
+  schema        = "./config/schema.json"
+
+  ext_vars = {
+    env = "prod"
+  }
+}
+
+output "example" {
+  value = { for file, content in data.jsonschema_evaluated_config.example.decoded : file => jsondecode(content) }
+}
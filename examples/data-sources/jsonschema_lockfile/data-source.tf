@@ -0,0 +1,8 @@
+data "jsonschema_lockfile" "schemas" {}
+
+data "jsonschema_validated_yaml" "workflows" {
+  input_pattern = ".github/workflows"
+  preset        = "github-workflow"
+
+  depends_on = [data.jsonschema_lockfile.schemas]
+}
@@ -0,0 +1,9 @@
+data "jsonschema_openapi_component" "order" {
+  document  = "${path.module}/api/openapi.yaml"
+  component = "Order"
+}
+
+# validate a fixture against the payload schema of the API
+output "fixture_is_valid" {
+  value = provider::jsonschema::matches(file("${path.module}/fixtures/order.json"), data.jsonschema_openapi_component.order.schema_json)
+}
@@ -0,0 +1,27 @@
+variable "schema_registry_api_key" {
+  type      = string
+  sensitive = true
+}
+
+data "jsonschema_registry_drift" "orders" {
+  schema   = "${path.module}/schemas/order.schema.json"
+  registry = "confluent"
+  url      = "https://psrc-abc123.europe-west3.gcp.confluent.cloud"
+  subject  = "orders-value"
+
+  request_headers = {
+    Authorization = "Basic ${base64encode(var.schema_registry_api_key)}"
+  }
+}
+
+# fail the plan if someone published a schema that is not in the repository
+resource "terraform_data" "publish_orders" {
+  input = data.jsonschema_registry_drift.orders.local_newer
+
+  lifecycle {
+    precondition {
+      condition     = data.jsonschema_registry_drift.orders.in_sync || data.jsonschema_registry_drift.orders.local_newer
+      error_message = "The registered schema of orders-value is newer than the local one: ${join(", ", data.jsonschema_registry_drift.orders.diff_summary)}"
+    }
+  }
+}
@@ -0,0 +1,13 @@
+data "jsonschema_schema_properties" "app" {
+  schema = "./schemas/app.json"
+}
+
+# a markdown table of the settings of the configuration files of the module
+output "app_settings" {
+  value = join("\n", concat(
+    ["| Setting | Type | Required | Default | Description |", "| --- | --- | --- | --- | --- |"],
+    [for name, property in data.jsonschema_schema_properties.app.properties :
+      "| `${name}` | `${property.type}` | ${property.required} | ${coalesce(property.default, "-")} | ${property.description} |"
+    ],
+  ))
+}
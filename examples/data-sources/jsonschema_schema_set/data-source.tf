@@ -0,0 +1,7 @@
+data "jsonschema_schema_set" "example" {
+  root = "./schemas"
+}
+
+output "publish_order" {
+  value = data.jsonschema_schema_set.example.publish_order
+}
@@ -0,0 +1,8 @@
+data "jsonschema_terraform_type" "app" {
+  schema = "./schemas/app.json#/$defs/app"
+}
+
+# paste the type into the variable of the module
+output "app_type" {
+  value = data.jsonschema_terraform_type.app.type
+}
@@ -0,0 +1,8 @@
+# aws cloudformation describe-type --type RESOURCE --type-name AWS::S3::Bucket --query Schema --output text > schemas/s3_bucket.json
+data "jsonschema_validated_cloudformation" "template" {
+  input = "./template.yaml"
+
+  resource_schemas = {
+    "AWS::S3::Bucket" = "./schemas/s3_bucket.json"
+  }
+}
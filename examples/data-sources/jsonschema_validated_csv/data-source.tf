@@ -0,0 +1,8 @@
+data "jsonschema_validated_csv" "example" {
+  input_pattern = "./example/*.csv"
+  schema        = "./example/schema.json"
+}
+
+output "example" {
+  value = { for file, rows in data.jsonschema_validated_csv.example.rows : file => jsondecode(rows) }
+}
@@ -0,0 +1,8 @@
+data "jsonschema_validated_dotenv" "example" {
+  input_pattern = "./environments/*.env"
+  schema        = "./environments/schema.json"
+}
+
+output "example" {
+  value = data.jsonschema_validated_dotenv.example.variables
+}
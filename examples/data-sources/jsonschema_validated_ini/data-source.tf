@@ -0,0 +1,8 @@
+data "jsonschema_validated_ini" "example" {
+  input_pattern = "./config/*.ini"
+  schema        = "./config/schema.json"
+}
+
+output "example" {
+  value = { for file, content in data.jsonschema_validated_ini.example.decoded : file => jsondecode(content) }
+}
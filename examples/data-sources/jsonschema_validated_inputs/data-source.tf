@@ -0,0 +1,17 @@
+variable "service" {
+  type = object({
+    name     = string
+    replicas = number
+    ports    = optional(list(number), [])
+  })
+}
+
+data "jsonschema_validated_inputs" "service" {
+  inputs = var.service
+  schema = "${path.module}/service.schema.json"
+}
+
+# only validated values are used below
+locals {
+  service = data.jsonschema_validated_inputs.service.values
+}
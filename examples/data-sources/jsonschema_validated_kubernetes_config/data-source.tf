@@ -0,0 +1,15 @@
+data "jsonschema_validated_kubernetes_config" "shop" {
+  context        = "production"
+  namespace      = "shop"
+  label_selector = "app.kubernetes.io/part-of=shop"
+
+  keys = {
+    "config.yaml" = "./schemas/config.json"
+  }
+
+  fail_on_invalid = false
+}
+
+output "config_violations" {
+  value = data.jsonschema_validated_kubernetes_config.shop.violations
+}
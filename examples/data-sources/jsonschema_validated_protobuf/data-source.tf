@@ -0,0 +1,13 @@
+# buf build -o contracts/descriptors.binpb
+data "jsonschema_validated_protobuf" "orders" {
+  input_pattern  = "${path.module}/fixtures/orders/*.yaml"
+  descriptor_set = "${path.module}/contracts/descriptors.binpb"
+  message        = "acme.shop.v1.Order"
+}
+
+# descriptors of a module of the Buf Schema Registry, authenticated with BUF_TOKEN
+data "jsonschema_validated_protobuf" "payments" {
+  input_pattern  = "${path.module}/fixtures/payments/*.json"
+  descriptor_set = "buf://buf.build/acme/payments:v1.4.0"
+  message        = "acme.payments.v1.Payment"
+}
@@ -0,0 +1,8 @@
+# terraform plan -out=tfplan && terraform show -json tfplan > plan.json
+data "jsonschema_validated_terraform_json" "policy" {
+  input = "./plan.json"
+
+  resource_schemas = {
+    aws_s3_bucket = "./policies/s3_bucket.json"
+  }
+}
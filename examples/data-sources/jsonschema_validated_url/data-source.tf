@@ -0,0 +1,17 @@
+variable "config_token" {
+  type      = string
+  sensitive = true
+}
+
+data "jsonschema_validated_url" "feature_flags" {
+  url    = "https://config.example.com/feature-flags.yaml"
+  schema = "${path.module}/feature-flags.schema.json"
+
+  request_headers = {
+    Authorization = "Bearer ${var.config_token}"
+  }
+}
+
+locals {
+  feature_flags = jsondecode(data.jsonschema_validated_url.feature_flags.decoded)
+}
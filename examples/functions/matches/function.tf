@@ -0,0 +1,6 @@
+locals {
+  database = yamldecode(file("${path.module}/database.yaml"))
+
+  # pick the module matching the variant of the database configuration
+  engine = provider::jsonschema::matches(jsonencode(local.database), file("${path.module}/postgres.schema.json")) ? "postgres" : "mysql"
+}
@@ -0,0 +1,12 @@
+locals {
+  replicas = jsondecode(provider::jsonschema::resolve(file("${path.module}/schema.json"), "#/properties/replicas"))
+}
+
+variable "replicas" {
+  type = number
+
+  validation {
+    condition     = var.replicas >= local.replicas.minimum && var.replicas <= local.replicas.maximum
+    error_message = "replicas must be between ${local.replicas.minimum} and ${local.replicas.maximum}"
+  }
+}
@@ -1,2 +1,19 @@
 provider "jsonschema" {
 }
+
+# Every provider configuration has a validation policy of its own,
+# data sources pick one with the provider meta-argument.
+provider "jsonschema" {
+  alias         = "strict"
+  default_draft = "draft-07"
+  loaders       = ["file", "urn"]
+
+  formats = {
+    assert = true
+  }
+}
+
+data "jsonschema_validated_yaml" "values" {
+  provider      = jsonschema.strict
+  input_pattern = "values.yaml"
+}
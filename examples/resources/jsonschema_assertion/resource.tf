@@ -0,0 +1,13 @@
+resource "local_file" "values" {
+  filename = "${path.module}/generated/values.yaml"
+  content  = yamlencode({ name = "example", replicas = 3 })
+}
+
+resource "jsonschema_assertion" "values" {
+  input_pattern = local_file.values.filename
+  schema        = "./schemas/values.json"
+
+  triggers = {
+    content = local_file.values.content
+  }
+}
@@ -0,0 +1,5 @@
+# a single file schema for editors and code generators, with the schemas referenced by service.json embedded
+resource "jsonschema_bundle_file" "service" {
+  path   = "${path.module}/dist/service.schema.json"
+  schema = "${path.module}/schemas/service.json"
+}
@@ -0,0 +1,4 @@
+resource "jsonschema_compatibility_gate" "example" {
+  schema        = "./schemas/service.json"
+  compatibility = "backward"
+}
@@ -0,0 +1,3 @@
+resource "jsonschema_formatted_file" "example" {
+  path = "./example/value.yaml"
+}
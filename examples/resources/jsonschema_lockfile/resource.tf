@@ -0,0 +1,7 @@
+# terraform apply -replace=jsonschema_lockfile.schemas updates the digests
+resource "jsonschema_lockfile" "schemas" {
+  schemas = [
+    "https://json.schemastore.org/github-workflow.json",
+    "./schemas/service.json",
+  ]
+}
@@ -0,0 +1,11 @@
+resource "jsonschema_modeline" "deployments" {
+  input_pattern = "${path.module}/deployments/*.yaml"
+  schema        = "${path.module}/schemas/deployment.schema.json"
+}
+
+# the files are validated against the schema their modelines reference
+data "jsonschema_validated_yaml" "deployments" {
+  input_pattern = jsonschema_modeline.deployments.input_pattern
+
+  depends_on = [jsonschema_modeline.deployments]
+}
@@ -0,0 +1,4 @@
+resource "jsonschema_schema_tracker" "services" {
+  input_pattern = "${path.module}/services/*.yaml"
+  schemas       = ["${path.module}/schemas/service.json"]
+}
@@ -0,0 +1,14 @@
+locals {
+  configs = "${path.module}/configs/**/*.yaml"
+}
+
+resource "jsonschema_validation_cache" "configs" {
+  path          = "${path.module}/.jsonschema-cache.json"
+  input_pattern = local.configs
+}
+
+# the same path instead of a reference, so the data source is read during the plan
+data "jsonschema_validated_yaml" "configs" {
+  input_pattern    = local.configs
+  validation_cache = "${path.module}/.jsonschema-cache.json"
+}
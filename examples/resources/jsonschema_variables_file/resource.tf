@@ -0,0 +1,5 @@
+# the inputs of the module, kept in lockstep with the schema its configuration files are validated against
+resource "jsonschema_variables_file" "app" {
+  path   = "${path.module}/modules/app/variables.tf"
+  schema = "${path.module}/schemas/app.json#/$defs/inputs"
+}
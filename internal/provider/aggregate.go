@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	aggregateShapeList  = "list"
+	aggregateShapeFiles = "files"
+)
+
+// aggregateDocuments assembles the documents into one value, a list of all
+// documents or an object mapping the key of each file to its document, or
+// to the list of its documents if it has more than one. It also returns the
+// JSON pointer of every document in the aggregate.
+func aggregateDocuments(documents []validDocument, shape string, keys map[string]string) (any, []string) {
+	pointers := make([]string, 0, len(documents))
+
+	if shape != aggregateShapeFiles {
+		aggregate := make([]any, 0, len(documents))
+		for i, document := range documents {
+			aggregate = append(aggregate, document.value)
+			pointers = append(pointers, "/"+strconv.Itoa(i))
+		}
+		return aggregate, pointers
+	}
+
+	counts := make(map[string]int)
+	for _, document := range documents {
+		counts[document.file]++
+	}
+
+	aggregate := make(map[string]any)
+	for _, document := range documents {
+		key := keys[document.file]
+		pointer := "/" + escapePointerToken(key)
+
+		if counts[document.file] == 1 {
+			aggregate[key] = document.value
+			pointers = append(pointers, pointer)
+			continue
+		}
+
+		fileDocuments, _ := aggregate[key].([]any)
+		aggregate[key] = append(fileDocuments, document.value)
+		pointers = append(pointers, pointer+"/"+strconv.Itoa(len(fileDocuments)))
+	}
+
+	return aggregate, pointers
+}
+
+// aggregateFindings attributes the violations of the aggregate to the
+// documents they are located in, violations of the aggregate as a whole
+// keep the file empty and the pointer into the aggregate.
+func aggregateFindings(findings []reportFinding, documents []validDocument, pointers []string) {
+	for i := range findings {
+		for j, pointer := range pointers {
+			if findings[i].Pointer != pointer && !strings.HasPrefix(findings[i].Pointer, pointer+"/") {
+				continue
+			}
+
+			findings[i].File = documents[j].file
+			findings[i].Document = documents[j].index
+			findings[i].Pointer = strings.TrimPrefix(findings[i].Pointer, pointer)
+			break
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAggregateDocuments(t *testing.T) {
+	documents := []validDocument{
+		{file: "/configs/web.yaml", value: "web"},
+		{file: "/configs/jobs.yaml", value: "backup"},
+		{file: "/configs/jobs.yaml", index: 1, value: "cleanup"},
+	}
+	keys := map[string]string{"/configs/web.yaml": "web.yaml", "/configs/jobs.yaml": "jobs/all.yaml"}
+
+	aggregate, pointers := aggregateDocuments(documents, aggregateShapeList, keys)
+	require.Equal(t, []any{"web", "backup", "cleanup"}, aggregate)
+	require.Equal(t, []string{"/0", "/1", "/2"}, pointers)
+
+	aggregate, pointers = aggregateDocuments(documents, aggregateShapeFiles, keys)
+	require.Equal(t, map[string]any{"web.yaml": "web", "jobs/all.yaml": []any{"backup", "cleanup"}}, aggregate)
+	require.Equal(t, []string{"/web.yaml", "/jobs~1all.yaml/0", "/jobs~1all.yaml/1"}, pointers)
+
+	findings := []reportFinding{{Pointer: "/jobs~1all.yaml/1/name"}, {Pointer: "/web.yaml"}, {Pointer: ""}}
+	aggregateFindings(findings, documents, pointers)
+	require.Equal(t, []reportFinding{
+		{File: "/configs/jobs.yaml", Document: 1, Pointer: "/name"},
+		{File: "/configs/web.yaml", Pointer: ""},
+		{Pointer: ""},
+	}, findings)
+}
+
+func TestAggregateSchemaYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"schema.json":           `{"type": "object", "required": ["env", "tier"]}`,
+		"aggregate.schema.json": `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "array", "contains": {"properties": {"env": {"const": "prod"}, "tier": {"const": "critical"}}, "required": ["env", "tier"]}, "minContains": 0, "maxContains": 1}`,
+		"files.schema.json":     `{"type": "object", "properties": {"jobs.yaml": {"type": "array", "maxItems": 1}}}`,
+		"web.yaml":              "# yaml-language-server: $schema=./schema.json\nenv: prod\ntier: critical\n",
+		"jobs.yaml":             "# yaml-language-server: $schema=./schema.json\nenv: prod\ntier: batch\n---\nenv: dev\ntier: critical\n",
+		"api.yaml":              "# yaml-language-server: $schema=./schema.json\nenv: prod\ntier: critical\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, "*.yaml"))
+	aggregateSchema := filepath.ToSlash(filepath.Join(tmpDir, "aggregate.schema.json"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccAggregateSchemaConfig, pattern, aggregateSchema, "list", true),
+				ExpectError: regexp.MustCompile(`Invalid aggregate`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccAggregateSchemaConfig, filepath.ToSlash(filepath.Join(tmpDir, "*s.yaml")), filepath.ToSlash(filepath.Join(tmpDir, "files.schema.json")), "files", true),
+				ExpectError: regexp.MustCompile(`Value at '/jobs.yaml' of the aggregate`),
+			},
+			{
+				Config: fmt.Sprintf(testAccAggregateSchemaConfig, filepath.ToSlash(filepath.Join(tmpDir, "[jw]*.yaml")), aggregateSchema, "list", true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(2),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccAggregateSchemaConfig, pattern, aggregateSchema, "list", false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringRegexp(regexp.MustCompile(`\{"file":"","document":0,"pointer":"","keyword":"maxContains","message":"[^"]*","severity":"error"\}`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccAggregateSchemaConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern    = "%s"
+  aggregate_schema = "%s"
+  aggregate_shape  = "%s"
+  fail_on_invalid  = %t
+  key_format       = "basename"
+}
+`
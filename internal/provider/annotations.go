@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"strings"
+)
+
+// annotationsVocabularyURL identifies the vocabulary retaining x-* keywords,
+// which the compiler otherwise drops.
+const annotationsVocabularyURL = "https://github.com/gaarutyunov/terraform-provider-jsonschema/vocab/annotations"
+
+// annotationsExt holds the x-* keywords of a compiled subschema.
+type annotationsExt map[string]any
+
+// Validate implements jsonschema.SchemaExt, annotations never fail validation.
+func (annotationsExt) Validate(ctx *jsonschema.ValidatorContext, v any) {}
+
+func annotationsVocabulary() *jsonschema.Vocabulary {
+	return &jsonschema.Vocabulary{
+		URL: annotationsVocabularyURL,
+		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
+			var ext annotationsExt
+			for key, value := range obj {
+				if strings.HasPrefix(key, "x-") {
+					if ext == nil {
+						ext = make(annotationsExt)
+					}
+					ext[key] = value
+				}
+			}
+			if ext == nil {
+				return nil, nil
+			}
+			return ext, nil
+		},
+	}
+}
+
+// collectAnnotations returns the x-* keywords of the subschemas matched by
+// value, keyed by the JSON pointer of the annotated value. Keywords of a
+// subschema take precedence over those of the subschemas it references.
+func collectAnnotations(sch *jsonschema.Schema, value any) map[string]map[string]any {
+	annotations := make(map[string]map[string]any)
+
+	walkSchema(sch, value, func(pointer string, _ any, schemas []*jsonschema.Schema) {
+		// applicable schemas are ordered from the outermost, which is applied last
+		for i := len(schemas) - 1; i >= 0; i-- {
+			for _, ext := range schemas[i].Extensions {
+				keywords, ok := ext.(annotationsExt)
+				if !ok {
+					continue
+				}
+				if annotations[pointer] == nil {
+					annotations[pointer] = make(map[string]any)
+				}
+				for key, keyword := range keywords {
+					annotations[pointer][key] = keyword
+				}
+			}
+		}
+	})
+
+	return annotations
+}
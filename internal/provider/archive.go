@@ -0,0 +1,386 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// archiveSeparator separates the path of an archive from the name of an
+// entry inside it, e.g. bundle.tar.gz//configs/app.yaml.
+const archiveSeparator = "//"
+
+// archiveExtensions are the extensions of the archives entries are read
+// from.
+var archiveExtensions = []string{".zip", ".tar", ".tar.gz", ".tgz"}
+
+// errStopArchive stops walkArchive without an error.
+var errStopArchive = errors.New("stop walking archive")
+
+// archiveListings holds the entries globArchiveEntries matched by the path
+// of their archive with forward slashes, so the entries are stat from the
+// listing and read in one more walk of the archive, instead of a walk of
+// the whole archive per entry. Entries are dropped once they are read, and
+// the listing with the last one, so contents are only held until the read
+// of the files that matched them.
+var archiveListings = struct {
+	sync.Mutex
+	archives map[string]*archiveListing
+}{archives: make(map[string]*archiveListing)}
+
+// archiveListing describes the matched entries of an archive as it was
+// when they were listed.
+type archiveListing struct {
+	modTime time.Time
+	size    int64
+	infos   map[string]fs.FileInfo
+	// contents holds the content of every entry of infos that was not read
+	// yet, once one is read.
+	contents map[string][]byte
+}
+
+// cachedArchiveListing returns the listing of archive, if it did not
+// change since it was listed. archiveListings must be locked.
+func cachedArchiveListing(archive string) *archiveListing {
+	listing, ok := archiveListings.archives[archive]
+	if !ok {
+		return nil
+	}
+
+	fi, err := os.Stat(filepath.FromSlash(archive))
+	if err != nil || !fi.ModTime().Equal(listing.modTime) || fi.Size() != listing.size {
+		delete(archiveListings.archives, archive)
+		return nil
+	}
+
+	return listing
+}
+
+// splitArchivePath splits file, a path or pattern of entries inside an
+// archive, into the path of the archive and the name of the entries. ok is
+// false if file does not address an archive.
+func splitArchivePath(file string) (archive, entry string, ok bool) {
+	if urlRegex.MatchString(file) {
+		return "", "", false
+	}
+
+	file = filepath.ToSlash(file)
+	for start := 0; ; {
+		i := strings.Index(file[start:], archiveSeparator)
+		if i < 0 {
+			return "", "", false
+		}
+		i += start
+
+		if isArchive(file[:i]) {
+			return file[:i], file[i+len(archiveSeparator):], true
+		}
+		start = i + 1
+	}
+}
+
+// isArchive reports whether file has one of the archiveExtensions.
+func isArchive(file string) bool {
+	return slices.ContainsFunc(archiveExtensions, func(extension string) bool {
+		return strings.HasSuffix(strings.ToLower(file), extension)
+	})
+}
+
+// globArchiveEntries returns the entries matched by pattern, the path or
+// glob pattern of archives and a glob pattern of the names of their
+// entries, e.g. releases/*.tar.gz//configs/*.yaml. Only regular files are
+// matched, optionally case-insensitively.
+func globArchiveEntries(pattern string, foldCase bool) ([]string, error) {
+	archivePattern, entryPattern, _ := splitArchivePath(pattern)
+	if _, err := path.Match(entryPattern, ""); err != nil {
+		return nil, err
+	}
+	if foldCase {
+		entryPattern = strings.ToLower(entryPattern)
+	}
+
+	archives, err := glob(filepath.FromSlash(archivePattern), foldCase)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for _, archive := range archives {
+		fi, err := os.Stat(archive)
+		if err != nil {
+			return nil, fmt.Errorf("could not list entries of %s: %w", archive, err)
+		}
+
+		listing := &archiveListing{modTime: fi.ModTime(), size: fi.Size(), infos: make(map[string]fs.FileInfo)}
+		err = walkArchive(archive, func(name string, info fs.FileInfo, open func() (io.ReadCloser, error)) error {
+			match := name
+			if foldCase {
+				match = strings.ToLower(name)
+			}
+			if ok, _ := path.Match(entryPattern, match); ok {
+				entries = append(entries, filepath.ToSlash(archive)+archiveSeparator+name)
+				listing.infos[name] = info
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not list entries of %s: %w", archive, err)
+		}
+
+		archiveListings.Lock()
+		archiveListings.archives[filepath.ToSlash(archive)] = listing
+		archiveListings.Unlock()
+	}
+
+	return entries, nil
+}
+
+// readArchiveEntry returns the content of the entry inside an archive file
+// addresses.
+func readArchiveEntry(file string) ([]byte, error) {
+	archive, entry, _ := splitArchivePath(file)
+
+	archiveListings.Lock()
+	defer archiveListings.Unlock()
+
+	if listing := cachedArchiveListing(archive); listing != nil {
+		if _, ok := listing.infos[entry]; ok {
+			if listing.contents == nil {
+				contents, err := readArchiveEntries(archive, listing.infos)
+				if err != nil {
+					return nil, err
+				}
+				listing.contents = contents
+			}
+			if content, ok := listing.contents[entry]; ok {
+				delete(listing.contents, entry)
+				delete(listing.infos, entry)
+				if len(listing.infos) == 0 {
+					delete(archiveListings.archives, archive)
+				}
+				return content, nil
+			}
+		}
+	}
+
+	var content []byte
+	err := walkArchive(archive, func(name string, info fs.FileInfo, open func() (io.ReadCloser, error)) error {
+		if name != entry {
+			return nil
+		}
+
+		r, err := open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		if content, err = io.ReadAll(r); err != nil {
+			return err
+		}
+		return errStopArchive
+	})
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, fmt.Errorf("archive %s has no file %s", archive, entry)
+	}
+
+	return content, nil
+}
+
+// readArchiveEntries returns the contents of the entries of infos inside
+// archive, which are read in a single walk.
+func readArchiveEntries(archive string, infos map[string]fs.FileInfo) (map[string][]byte, error) {
+	contents := make(map[string][]byte, len(infos))
+	err := walkArchive(archive, func(name string, info fs.FileInfo, open func() (io.ReadCloser, error)) error {
+		if _, ok := infos[name]; !ok {
+			return nil
+		}
+
+		r, err := open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		contents[name] = content
+
+		if len(contents) == len(infos) {
+			return errStopArchive
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
+// statArchiveEntry returns the file info of the entry inside an archive file
+// addresses.
+func statArchiveEntry(file string) (fs.FileInfo, error) {
+	archive, entry, _ := splitArchivePath(file)
+
+	archiveListings.Lock()
+	listing := cachedArchiveListing(archive)
+	archiveListings.Unlock()
+	if listing != nil {
+		if info, ok := listing.infos[entry]; ok {
+			return info, nil
+		}
+	}
+
+	var fi fs.FileInfo
+	err := walkArchive(archive, func(name string, info fs.FileInfo, open func() (io.ReadCloser, error)) error {
+		if name != entry {
+			return nil
+		}
+		fi = info
+		return errStopArchive
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fi == nil {
+		return nil, fmt.Errorf("archive %s has no file %s", archive, entry)
+	}
+
+	return fi, nil
+}
+
+// walkArchive calls visit with the cleaned name of every regular file of a
+// zip, tar or gzip compressed tar archive, in the order they are stored,
+// until visit returns an error. Entries are read in memory and never
+// extracted to disk.
+func walkArchive(archive string, visit func(name string, info fs.FileInfo, open func() (io.ReadCloser, error)) error) error {
+	err := walkArchiveEntries(archive, visit)
+	if errors.Is(err, errStopArchive) {
+		return nil
+	}
+
+	return err
+}
+
+// walkArchiveEntries is walkArchive, returning errStopArchive if visit
+// stopped walking.
+func walkArchiveEntries(archive string, visit func(name string, info fs.FileInfo, open func() (io.ReadCloser, error)) error) error {
+	if strings.EqualFold(filepath.Ext(archive), ".zip") {
+		r, err := zip.OpenReader(archive)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		for _, f := range r.File {
+			if !f.Mode().IsRegular() {
+				continue
+			}
+			if err := visit(archiveEntryName(f.Name), f.FileInfo(), f.Open); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if !strings.EqualFold(filepath.Ext(archive), ".tar") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := visit(archiveEntryName(header.Name), header.FileInfo(), func() (io.ReadCloser, error) { return io.NopCloser(tr), nil }); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveEntryName returns name without the leading ./ or / archiving
+// tools may write, e.g. tar -czf bundle.tar.gz ., so entries are addressed
+// by their path inside the archive.
+func archiveEntryName(name string) string {
+	name = path.Clean("/" + name)
+
+	return strings.TrimPrefix(name, "/")
+}
+
+// isArchiveEntry reports whether file is an entry inside an archive rather
+// than a file on disk.
+func isArchiveEntry(file string) bool {
+	_, _, ok := splitArchivePath(file)
+
+	return ok
+}
+
+// readInputFile returns the content of file, a file on disk or an entry
+// inside an archive.
+func readInputFile(file string) ([]byte, error) {
+	if isArchiveEntry(file) {
+		return readArchiveEntry(file)
+	}
+
+	return os.ReadFile(file)
+}
+
+// statInputFile returns the file info of file, a file on disk or an entry
+// inside an archive.
+func statInputFile(file string) (fs.FileInfo, error) {
+	if isArchiveEntry(file) {
+		return statArchiveEntry(file)
+	}
+
+	return os.Stat(file)
+}
+
+// archiveDir returns the directory of the archive of file if it is an
+// entry inside an archive, or the directory of file otherwise.
+func archiveDir(file string) string {
+	if archive, _, ok := splitArchivePath(file); ok {
+		return filepath.Dir(filepath.FromSlash(archive))
+	}
+
+	return filepath.Dir(file)
+}
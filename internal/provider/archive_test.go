@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"github.com/stretchr/testify/require"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTestTarGz writes a gzip compressed tar archive of files, with the
+// leading ./ of tar -czf bundle.tar.gz . and a directory entry.
+func writeTestTarGz(t *testing.T, file string, files map[string]string) {
+	f, err := os.Create(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./configs/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(files[name]))}))
+		_, err := tw.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+}
+
+func writeTestZip(t *testing.T, file string, files map[string]string) {
+	f, err := os.Create(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+}
+
+func TestSplitArchivePath(t *testing.T) {
+	for file, expected := range map[string][]string{
+		"bundle.tar.gz//configs/*.yaml":     {"bundle.tar.gz", "configs/*.yaml"},
+		"releases/v1.TGZ//app.yaml":         {"releases/v1.TGZ", "app.yaml"},
+		"a//b/bundle.zip//nested//app.json": {"a//b/bundle.zip", "nested//app.json"},
+		"configs//app.yaml":                 nil,
+		"https://example.com/bundle.zip//a": nil,
+	} {
+		archive, entry, ok := splitArchivePath(file)
+		if expected == nil {
+			require.False(t, ok, file)
+			continue
+		}
+		require.True(t, ok, file)
+		require.Equal(t, expected, []string{archive, entry}, file)
+	}
+}
+
+func TestArchiveEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"configs/app.yaml":     "name: app\n",
+		"configs/Worker.YAML":  "name: worker\n",
+		"configs/nested/x.yml": "name: x\n",
+		"README.md":            "# bundle\n",
+	}
+	writeTestTarGz(t, filepath.Join(tmpDir, "bundle.tar.gz"), files)
+	writeTestZip(t, filepath.Join(tmpDir, "bundle.zip"), files)
+
+	for _, archive := range []string{"bundle.tar.gz", "bundle.zip"} {
+		prefix := filepath.ToSlash(filepath.Join(tmpDir, archive)) + "//"
+
+		entries, err := globArchiveEntries(prefix+"configs/*.yaml", false)
+		require.NoError(t, err)
+		require.Equal(t, []string{prefix + "configs/app.yaml"}, entries)
+
+		entries, err = globArchiveEntries(prefix+"configs/*.yaml", true)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{prefix + "configs/app.yaml", prefix + "configs/Worker.YAML"}, entries)
+
+		content, err := readInputFile(prefix + "configs/Worker.YAML")
+		require.NoError(t, err)
+		require.Equal(t, "name: worker\n", string(content))
+
+		fi, err := statInputFile(prefix + "configs/nested/x.yml")
+		require.NoError(t, err)
+		require.Equal(t, int64(len("name: x\n")), fi.Size())
+
+		_, err = readInputFile(prefix + "configs/missing.yaml")
+		require.ErrorContains(t, err, "has no file configs/missing.yaml")
+	}
+
+	entries, err := globArchiveEntries(filepath.ToSlash(filepath.Join(tmpDir, "*.zip"))+"//*.md", false)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.ToSlash(filepath.Join(tmpDir, "bundle.zip")) + "//README.md"}, entries)
+
+	_, err = globArchiveEntries(filepath.ToSlash(filepath.Join(tmpDir, "bundle.zip"))+"//[", false)
+	require.Error(t, err)
+}
+
+func TestArchiveListing(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "bundle.tar.gz")
+	writeTestTarGz(t, file, map[string]string{"configs/a.yaml": "name: a\n", "configs/b.yaml": "name: b\n"})
+	prefix := filepath.ToSlash(file) + "//"
+
+	entries, err := globArchiveEntries(prefix+"configs/*.yaml", false)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// the first read reads every listed entry, which is dropped once it is read
+	content, err := readInputFile(entries[0])
+	require.NoError(t, err)
+	require.Equal(t, "name: a\n", string(content))
+
+	archiveListings.Lock()
+	listing := archiveListings.archives[filepath.ToSlash(file)]
+	archiveListings.Unlock()
+	require.Equal(t, map[string][]byte{"configs/b.yaml": []byte("name: b\n")}, listing.contents)
+
+	content, err = readInputFile(entries[1])
+	require.NoError(t, err)
+	require.Equal(t, "name: b\n", string(content))
+
+	archiveListings.Lock()
+	_, listed := archiveListings.archives[filepath.ToSlash(file)]
+	archiveListings.Unlock()
+	require.False(t, listed)
+
+	// entries are read again after they were dropped
+	content, err = readInputFile(entries[0])
+	require.NoError(t, err)
+	require.Equal(t, "name: a\n", string(content))
+
+	_, err = globArchiveEntries(prefix+"configs/*.yaml", false)
+	require.NoError(t, err)
+
+	// archives changed since they were listed are read again
+	writeTestTarGz(t, file, map[string]string{"configs/a.yaml": "name: changed\n", "configs/b.yaml": "name: b\n"})
+
+	content, err = readInputFile(entries[0])
+	require.NoError(t, err)
+	require.Equal(t, "name: changed\n", string(content))
+
+	fi, err := statInputFile(entries[0])
+	require.NoError(t, err)
+	require.Equal(t, int64(len("name: changed\n")), fi.Size())
+}
@@ -0,0 +1,361 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"path/filepath"
+	"strings"
+)
+
+// Ensure AssertionResource satisfies various resource interfaces.
+var _ resource.Resource = &AssertionResource{}
+var _ resource.ResourceWithConfigure = &AssertionResource{}
+var _ resource.ResourceWithModifyPlan = &AssertionResource{}
+
+func NewAssertionResource() resource.Resource {
+	return &AssertionResource{}
+}
+
+// AssertionResource defines the resource implementation.
+type AssertionResource struct {
+	compiler    *schemaCompiler
+	yamlDecoder yamlDecoder
+}
+
+// AssertionResourceModel describes the resource data model.
+type AssertionResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	InputPattern types.String `tfsdk:"input_pattern"`
+	Schema       types.String `tfsdk:"schema"`
+	Triggers     types.Map    `tfsdk:"triggers"`
+	ContentHash  types.String `tfsdk:"content_hash"`
+	ValidFiles   types.List   `tfsdk:"valid_files"`
+}
+
+func (r *AssertionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_assertion"
+}
+
+func (r *AssertionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Validates YAML and JSON files when the resource is applied instead of when it is planned, and fails the apply if a file is invalid, " +
+			"e.g. for files generated by other resources of the same apply. The files are validated again whenever their content or `triggers` change.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Glob pattern of the validated files",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of YAML and JSON files to validate, which do not need to exist until apply",
+				Required:    true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Path of the json schema every file is validated against, " +
+					"defaults to the schema referenced in the first line of YAML files (`# yaml-language-server: $schema=path`) and the `$schema` property of JSON files",
+				Optional: true,
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that validate the files again when they change, e.g. the `content` of the resource generating them",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the paths and content of the validated files",
+				Computed:    true,
+			},
+			"valid_files": schema.ListAttribute{
+				Description: "Paths of the validated files",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *AssertionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+	r.yamlDecoder = providerData.YAMLDecoder
+}
+
+// ModifyPlan plans the files to be validated again if their content changed
+// since the last apply, or if they do not exist yet.
+func (r *AssertionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state AssertionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.InputPattern.Equal(state.InputPattern) && plan.Schema.Equal(state.Schema) {
+		// errors, e.g. of files that do not exist yet, are left for the apply to report
+		var globDiags diag.Diagnostics
+		files := globInputFiles(plan.InputPattern.ValueString(), &globDiags)
+		if hash, err := assertionContentHash(files); !globDiags.HasError() && err == nil && hash == state.ContentHash.ValueString() {
+			return
+		}
+	}
+
+	plan.ContentHash = types.StringUnknown()
+	plan.ValidFiles = types.ListUnknown(types.StringType)
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *AssertionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AssertionResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.assert(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AssertionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AssertionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The files are only validated on apply, changes of their content are planned as updates.
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AssertionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AssertionResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.assert(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AssertionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to clean up, the files are not managed by the assertion.
+}
+
+// assert validates the files matched by data.InputPattern and fills in the
+// computed attributes of data.
+func (r *AssertionResource) assert(ctx context.Context, data *AssertionResourceModel, diags *diag.Diagnostics) {
+	files := globInputFiles(data.InputPattern.ValueString(), diags)
+	if diags.HasError() {
+		return
+	}
+
+	for _, file := range files {
+		if err := r.validate(file, data.Schema.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("input_pattern"),
+				"Assertion failed",
+				err.Error(),
+			)
+		}
+	}
+	if diags.HasError() {
+		return
+	}
+
+	hash, err := assertionContentHash(files)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			"Error reading file",
+			err.Error(),
+		)
+		return
+	}
+
+	validFiles, d := types.ListValueFrom(ctx, types.StringType, files)
+	diags.Append(d...)
+
+	data.ID = data.InputPattern
+	data.ContentHash = types.StringValue(hash)
+	data.ValidFiles = validFiles
+}
+
+// validate validates every document of file against schemaPath, or else the
+// schema the file references.
+func (r *AssertionResource) validate(file, schemaPath string) error {
+	contentRaw, err := readTextFile(file, decodeText)
+	if err != nil {
+		return fmt.Errorf("could not read file %s: %w", file, err)
+	}
+
+	content := string(contentRaw)
+	isJSON := isJSONInput(file, content)
+
+	values, err := decodeInputDocuments(file, content, isJSON, r.yamlDecoder)
+	if err != nil {
+		return err
+	}
+
+	if schemaPath == "" {
+		ref := referencedSchema(content, isJSON, values)
+		if ref == "" {
+			return fmt.Errorf("file %s does not reference a schema and no schema was provided", file)
+		}
+		schemaPath = resolveSchemaReference(file, ref)
+	}
+
+	return validateInputDocuments(r.compiler, file, values, schemaPath)
+}
+
+// decodeInputDocuments decodes the documents of the YAML or JSON content of
+// file, empty documents are skipped.
+func decodeInputDocuments(file, content string, isJSON bool, decoder yamlDecoder) ([]any, error) {
+	documents := []string{content}
+	if !isJSON {
+		documents = splitYAMLDocuments(content)
+	}
+
+	values := make([]any, 0, len(documents))
+	for _, document := range documents {
+		var value any
+		var err error
+		if isJSON {
+			value, err = jsonschema.UnmarshalJSON(strings.NewReader(document))
+		} else {
+			value, err = decoder.decode([]byte(document))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode file %s: %w", file, err)
+		}
+		// empty documents, e.g. before a leading document separator, are skipped
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// referencedSchema returns the schema reference of the first line of YAML
+// content or of the $schema property of JSON files, if there is one.
+func referencedSchema(content string, isJSON bool, values []any) string {
+	if isJSON {
+		if len(values) == 0 {
+			return ""
+		}
+		object, _ := values[0].(map[string]any)
+		ref, _ := object["$schema"].(string)
+		return ref
+	}
+
+	if matches := schemaRegex.FindStringSubmatch(content); len(matches) == 2 {
+		return matches[1]
+	}
+
+	return ""
+}
+
+// validateInputDocuments validates every document of file against the schemas
+// at schemaPaths, in order.
+func validateInputDocuments(compiler *schemaCompiler, file string, values []any, schemaPaths ...string) error {
+	for _, schemaPath := range schemaPaths {
+		compiledSchema, err := compiler.Compile(schemaPath)
+		if err != nil {
+			return fmt.Errorf("could not compile schema %s for file %s: %w", schemaPath, file, err)
+		}
+
+		for i, value := range values {
+			if err := compiledSchema.Validate(value); err != nil {
+				source := "File " + file
+				if len(values) > 1 {
+					source = fmt.Sprintf("Document %d of file %s", i, file)
+				}
+				return fmt.Errorf("%s does not conform to schema %s: %w", source, schemaPath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// assertionContentHash returns the SHA-256 hash of the paths and content of
+// files.
+func assertionContentHash(files []string) (string, error) {
+	hash := sha256.New()
+	for _, file := range files {
+		content, err := readInputFile(filepath.FromSlash(file))
+		if err != nil {
+			return "", fmt.Errorf("could not read file %s: %w", file, err)
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(file), len(content))
+		hash.Write(content)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
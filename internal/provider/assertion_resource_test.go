@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAssertion(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "generated.yaml")
+
+	writeFile := func(content string) func() {
+		return func() {
+			require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		}
+	}
+
+	err := os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	var hash string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the file does not exist yet
+			{
+				Config:      fmt.Sprintf(testAccAssertionResourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ExpectError: regexp.MustCompile(`No input files found`),
+			},
+			{
+				PreConfig: writeFile("# yaml-language-server: $schema=schema.json\nid: \"generated-id\"\nname: \"Generated\"\n"),
+				Config:    fmt.Sprintf(testAccAssertionResourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"jsonschema_assertion.test",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.ToSlash(file))}),
+					),
+				},
+				Check: func(s *terraform.State) error {
+					hash = s.RootModule().Resources["jsonschema_assertion.test"].Primary.Attributes["content_hash"]
+					return nil
+				},
+			},
+			// the file changed invalidly since the last apply
+			{
+				PreConfig:   writeFile("# yaml-language-server: $schema=schema.json\nid: 123\n"),
+				Config:      fmt.Sprintf(testAccAssertionResourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ExpectError: regexp.MustCompile(`Assertion failed`),
+			},
+			{
+				PreConfig: writeFile("# yaml-language-server: $schema=schema.json\nid: \"regenerated-id\"\nname: \"Regenerated\"\n"),
+				Config:    fmt.Sprintf(testAccAssertionResourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				Check: func(s *terraform.State) error {
+					if s.RootModule().Resources["jsonschema_assertion.test"].Primary.Attributes["content_hash"] == hash {
+						return fmt.Errorf("content_hash did not change")
+					}
+					return nil
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccAssertionResourceSchemaConfig, filepath.Join(tmpDir, "*.yaml"), filepath.Join(tmpDir, "strict.json")),
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "strict.json"), []byte(`{"required": ["owner"]}`), 0644))
+				},
+				ExpectError: regexp.MustCompile(`missing property 'owner'`),
+			},
+		},
+	})
+}
+
+const (
+	testAccAssertionResourceConfig = `
+resource "jsonschema_assertion" "test" {
+  input_pattern = "%s"
+}
+`
+	testAccAssertionResourceSchemaConfig = `
+resource "jsonschema_assertion" "test" {
+  input_pattern = "%s"
+  schema        = "%s"
+}
+`
+)
@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretsmanagertypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"sync"
+)
+
+// URL schemes of documents and schemas stored in AWS, e.g.
+// ssm:///app/prod/ for every parameter below a path of SSM Parameter Store
+// or secretsmanager://app/prod/config for a secret of Secrets Manager.
+const (
+	ssmScheme            = "ssm"
+	secretsManagerScheme = "secretsmanager"
+)
+
+// AWSConfigModel describes the aws block of the provider data model.
+type AWSConfigModel struct {
+	Region                 types.String `tfsdk:"region"`
+	Profile                types.String `tfsdk:"profile"`
+	SSMEndpoint            types.String `tfsdk:"ssm_endpoint"`
+	SecretsManagerEndpoint types.String `tfsdk:"secretsmanager_endpoint"`
+}
+
+// awsSession loads the AWS configuration on first use, so configurations
+// that do not reference AWS never need credentials for it.
+type awsSession struct {
+	config AWSConfigModel
+
+	mu     sync.Mutex
+	loaded *aws.Config
+}
+
+func newAWSSession(config *AWSConfigModel) *awsSession {
+	s := &awsSession{}
+	if config != nil {
+		s.config = *config
+	}
+	return s
+}
+
+func (s *awsSession) load(ctx context.Context) (aws.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loaded != nil {
+		return *s.loaded, nil
+	}
+
+	// the defaults are read from AWS_REGION, AWS_PROFILE and the rest of the standard credential chain
+	var options []func(*config.LoadOptions) error
+	if !s.config.Region.IsNull() {
+		options = append(options, config.WithRegion(s.config.Region.ValueString()))
+	}
+	if !s.config.Profile.IsNull() {
+		options = append(options, config.WithSharedConfigProfile(s.config.Profile.ValueString()))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	s.loaded = &cfg
+
+	return cfg, nil
+}
+
+// awsError marks client errors of AWS, e.g. of parameters that do not
+// exist, as permanent.
+func awsError(err error) error {
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) && !retryableStatus(responseErr.HTTPStatusCode()) {
+		return &permanentError{err}
+	}
+
+	return err
+}
+
+// ssmStore reads the parameters of SSM Parameter Store. Keys are the names
+// of parameters, prefixes are paths of their hierarchy and SecureString
+// parameters are decrypted.
+type ssmStore struct {
+	session *awsSession
+}
+
+func (s *ssmStore) client(ctx context.Context) (*ssm.Client, error) {
+	cfg, err := s.session.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssm.NewFromConfig(cfg, func(o *ssm.Options) {
+		if !s.session.config.SSMEndpoint.IsNull() {
+			o.BaseEndpoint = aws.String(s.session.config.SSMEndpoint.ValueString())
+		}
+	}), nil
+}
+
+func (s *ssmStore) keys(ctx context.Context, prefix string) ([]string, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// paths of the hierarchy have no trailing slash, except for the root
+	hierarchy := prefix
+	if hierarchy != "/" {
+		hierarchy = strings.TrimSuffix(hierarchy, "/")
+	}
+
+	var keys []string
+	paginator := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+		Path:      aws.String(hierarchy),
+		Recursive: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, awsError(err)
+		}
+
+		for _, parameter := range page.Parameters {
+			keys = append(keys, aws.ToString(parameter.Name))
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *ssmStore) get(ctx context.Context, key string) ([]byte, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(key),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, awsError(err)
+	}
+
+	return []byte(aws.ToString(out.Parameter.Value)), nil
+}
+
+// secretsManagerStore reads the secrets of Secrets Manager. Keys are the
+// names of secrets, prefixes are prefixes of their names.
+type secretsManagerStore struct {
+	session *awsSession
+}
+
+func (s *secretsManagerStore) client(ctx context.Context) (*secretsmanager.Client, error) {
+	cfg, err := s.session.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return secretsmanager.NewFromConfig(cfg, func(o *secretsmanager.Options) {
+		if !s.session.config.SecretsManagerEndpoint.IsNull() {
+			o.BaseEndpoint = aws.String(s.session.config.SecretsManagerEndpoint.ValueString())
+		}
+	}), nil
+}
+
+func (s *secretsManagerStore) keys(ctx context.Context, prefix string) ([]string, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	paginator := secretsmanager.NewListSecretsPaginator(client, &secretsmanager.ListSecretsInput{
+		Filters: []secretsmanagertypes.Filter{
+			{Key: secretsmanagertypes.FilterNameStringTypeName, Values: []string{prefix}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, awsError(err)
+		}
+
+		for _, secret := range page.SecretList {
+			// the name filter is not case-sensitive
+			if name := aws.ToString(secret.Name); strings.HasPrefix(name, prefix) {
+				keys = append(keys, name)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *secretsManagerStore) get(ctx context.Context, key string) ([]byte, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(key),
+	})
+	if err != nil {
+		return nil, awsError(err)
+	}
+
+	if out.SecretString != nil {
+		return []byte(*out.SecretString), nil
+	}
+
+	return out.SecretBinary, nil
+}
+
+func isAWSURL(ref string) bool {
+	return strings.HasPrefix(ref, ssmScheme+"://") || strings.HasPrefix(ref, secretsManagerScheme+"://")
+}
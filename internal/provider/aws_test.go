@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newTestAWSServer mocks the GetParameter and GetParametersByPath actions of
+// SSM and the ListSecrets and GetSecretValue actions of Secrets Manager,
+// parameters and secrets map names to their values. It sets credentials for
+// the AWS configuration of the test.
+func newTestAWSServer(t *testing.T, parameters, secrets map[string]string) *httptest.Server {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		notFound := func(errorType string) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"__type": errorType, "message": "not found"})
+		}
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "AmazonSSM.GetParameter":
+			name, _ := body["Name"].(string)
+			value, ok := parameters[name]
+			if !ok || body["WithDecryption"] != true {
+				notFound("ParameterNotFound")
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"Parameter": map[string]any{"Name": name, "Type": "SecureString", "Value": value}})
+		case "AmazonSSM.GetParametersByPath":
+			hierarchy, _ := body["Path"].(string)
+
+			var list []map[string]any
+			for _, name := range slices.Sorted(maps.Keys(parameters)) {
+				if strings.HasPrefix(name, strings.TrimSuffix(hierarchy, "/")+"/") {
+					list = append(list, map[string]any{"Name": name, "Type": "SecureString"})
+				}
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"Parameters": list})
+		case "secretsmanager.ListSecrets":
+			prefix := body["Filters"].([]any)[0].(map[string]any)["Values"].([]any)[0].(string)
+
+			var list []map[string]any
+			for _, name := range slices.Sorted(maps.Keys(secrets)) {
+				if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+					list = append(list, map[string]any{"Name": name})
+				}
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"SecretList": list})
+		case "secretsmanager.GetSecretValue":
+			name, _ := body["SecretId"].(string)
+			value, ok := secrets[name]
+			if !ok {
+				notFound("ResourceNotFoundException")
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"Name": name, "SecretString": value})
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestAWSDocuments(t *testing.T) {
+	server := newTestAWSServer(t, map[string]string{
+		"/app/prod/api":      `{"name": "api"}`,
+		"/app/prod/web":      `{"name": "web"}`,
+		"/app/production/db": `{"name": "db"}`,
+	}, map[string]string{
+		"app/prod/api":    `{"name": "api"}`,
+		"App/Prod/shared": `{"name": "shared"}`,
+		"app/schema":      `{"type": "object"}`,
+	})
+
+	kv := newKVDocuments(nil, nil, &AWSConfigModel{
+		Region:                 types.StringValue("eu-west-1"),
+		SSMEndpoint:            types.StringValue(server.URL),
+		SecretsManagerEndpoint: types.StringValue(server.URL),
+	})
+
+	ctx := context.Background()
+
+	// paths match whole segments of the hierarchy
+	documents, err := kv.documents(ctx, "ssm:///app/prod/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ssm:///app/prod/api", "ssm:///app/prod/web"}, documents)
+
+	content, err := kv.readDocument(ctx, "ssm:///app/prod/web")
+	require.NoError(t, err)
+	require.Equal(t, `{"name": "web"}`, string(content))
+
+	_, err = kv.readDocument(ctx, "ssm:///app/missing")
+	require.ErrorContains(t, err, "ParameterNotFound")
+
+	var permanent *permanentError
+	require.ErrorAs(t, err, &permanent)
+
+	// prefixes of secret names are case-sensitive although the filter is not
+	documents, err = kv.documents(ctx, "secretsmanager://app/prod/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"secretsmanager://app/prod/api"}, documents)
+
+	content, err = kv.readDocument(ctx, "secretsmanager://app/prod/api")
+	require.NoError(t, err)
+	require.Equal(t, `{"name": "api"}`, string(content))
+
+	schema, err := kv.Load("secretsmanager://app/schema")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"type": "object"}, schema)
+
+	_, err = kv.readDocument(ctx, "secretsmanager://app/missing")
+	require.ErrorContains(t, err, "ResourceNotFoundException")
+	require.ErrorAs(t, err, &permanent)
+}
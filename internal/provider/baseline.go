@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+)
+
+// readBaseline returns the findings of a baseline file, a report output of
+// a previous validation.
+func readBaseline(file string) ([]reportFinding, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var report validationReport
+	if err := json.Unmarshal(bytes.TrimPrefix(content, utf8BOM), &report); err != nil {
+		return nil, err
+	}
+
+	return report.Findings, nil
+}
+
+// baselineFindings downgrades the violations recorded in baseline to
+// warnings. Findings are compared by file, document, pointer and keyword,
+// so changed messages do not invalidate the baseline.
+func baselineFindings(findings, baseline []reportFinding) {
+	for i, finding := range findings {
+		if finding.Keyword == "" {
+			continue
+		}
+
+		if slices.ContainsFunc(baseline, func(b reportFinding) bool {
+			return b.File == finding.File && b.Document == finding.Document && b.Pointer == finding.Pointer && b.Keyword == finding.Keyword
+		}) {
+			findings[i].Severity = severityWarning
+			findings[i].Baselined = true
+		}
+	}
+}
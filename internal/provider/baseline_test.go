@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestBaselineYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "legacy.yaml")
+	baseline := filepath.Join(tmpDir, "baseline.json")
+
+	err := os.WriteFile(file, []byte(`# yaml-language-server: $schema=./schema.json
+id: 123
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.Join(tmpDir, "*.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// record the current violations
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceNonFatalConfig, pattern),
+				Check: func(s *terraform.State) error {
+					report := s.RootModule().Resources["data.jsonschema_validated_yaml.metadata"].Primary.Attributes["report"]
+					return os.WriteFile(baseline, []byte(report), 0644)
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccBaselineConfig, pattern, baseline),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringRegexp(regexp.MustCompile(`"pointer":"/id","keyword":"type","message":"[^"]*","severity":"warning","baselined":true`)),
+					),
+				},
+			},
+			// new violations fail
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(file, []byte("# yaml-language-server: $schema=./schema.json\nid: 123\ntags: \"a\"\n"), 0644))
+				},
+				Config:      fmt.Sprintf(testAccBaselineConfig, pattern, baseline),
+				ExpectError: regexp.MustCompile(`at '/tags'`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccBaselineConfig, pattern, filepath.Join(tmpDir, "missing.json")),
+				ExpectError: regexp.MustCompile(`Error reading baseline`),
+			},
+		},
+	})
+}
+
+const testAccBaselineConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  baseline_file = "%s"
+}
+`
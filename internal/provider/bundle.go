@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"maps"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// bundleNameRegex matches the characters that are replaced in the names of
+// bundled documents, which are derived from their URLs.
+var bundleNameRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// bundleSchema returns the JSON encoding of the schema document at location
+// as it is compiled, after ignore_keywords are applied, with every document
+// it references embedded as a compound schema document: the documents are
+// added to $defs, or definitions before draft 2019-09, with their URLs as
+// $id, so the references resolve to them without loading anything. The URLs
+// of local documents are relative to the bundled document, which keeps its
+// own URL as $id, so references between the documents resolve wherever the
+// bundle is written.
+func (c *schemaCompiler) bundleSchema(location string) (string, error) {
+	document, fragment, _ := strings.Cut(location, "#")
+	if fragment != "" {
+		return "", fmt.Errorf("schema %s has a fragment, only whole schema documents can be bundled", location)
+	}
+
+	// compiling first reports invalid schemas and unresolved references like validation does
+	sch, err := c.Compile(document)
+	if err != nil {
+		return "", fmt.Errorf("could not compile schema: %w", err)
+	}
+
+	if !urlRegex.MatchString(document) {
+		document = schemaFileURL(document)
+	}
+
+	value, err := c.loader.Load(document)
+	if err != nil {
+		return "", err
+	}
+
+	root, ok := value.(map[string]any)
+	if !ok {
+		// a boolean schema has no references
+		return encodeBundle(value)
+	}
+
+	base, err := url.Parse(document)
+	if err != nil {
+		return "", err
+	}
+
+	idKeyword, defsKeyword := "$id", "$defs"
+	switch {
+	case sch.DraftVersion == 4:
+		idKeyword, defsKeyword = "id", "definitions"
+	case sch.DraftVersion < 2019:
+		defsKeyword = "definitions"
+	}
+
+	bundle := maps.Clone(root)
+	if id, ok := root[idKeyword].(string); ok {
+		if resolved, err := base.Parse(id); err == nil {
+			base = resolved
+		}
+	} else {
+		bundle[idKeyword] = bundleID(base, base)
+	}
+
+	var defs map[string]any
+	switch existing := root[defsKeyword].(type) {
+	case nil:
+		defs = make(map[string]any)
+	case map[string]any:
+		defs = maps.Clone(existing)
+	default:
+		return "", fmt.Errorf("%s of schema %s is not an object", defsKeyword, displaySchemaLocation(document))
+	}
+
+	// documents are embedded in the order they are referenced, breadth first
+	resources := map[string]bool{document: true}
+	queue := []string{document}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		currentURL, err := url.Parse(current)
+		if err != nil {
+			return "", err
+		}
+
+		var currentValue any = root
+		if current != document {
+			if currentValue, err = c.loader.Load(current); err != nil {
+				return "", fmt.Errorf("could not load schema %s: %w", displaySchemaLocation(current), err)
+			}
+
+			embedded, ok := currentValue.(map[string]any)
+			if !ok {
+				// a boolean schema cannot have an $id, so it is applied by a subschema that has one
+				embedded = map[string]any{"allOf": []any{currentValue}}
+			}
+			embedded = maps.Clone(embedded)
+			if _, ok := embedded[idKeyword].(string); !ok {
+				embedded[idKeyword] = bundleID(base, currentURL)
+			}
+
+			defs[bundleName(defs, currentURL)] = embedded
+		}
+
+		for _, resource := range schemaResourceURLs(currentURL, currentValue) {
+			resources[resource] = true
+		}
+
+		for _, ref := range schemaReferences(currentURL, currentValue) {
+			if !resources[ref] {
+				resources[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	if len(defs) > 0 {
+		bundle[defsKeyword] = defs
+	}
+
+	return encodeBundle(bundle)
+}
+
+// schemaResourceURLs returns the URLs of the schema resources of a schema
+// document and its subschemas, i.e. their $id resolved against base and the
+// $id of enclosing subschemas, without fragments.
+func schemaResourceURLs(base *url.URL, value any) []string {
+	var resources []string
+
+	switch v := value.(type) {
+	case map[string]any:
+		if id, ok := v["$id"].(string); ok {
+			if resolved, err := base.Parse(id); err == nil {
+				base = resolved
+				resolved.Fragment = ""
+				resolved.RawFragment = ""
+				resources = append(resources, resolved.String())
+			}
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			// enum and const values are data, not subschemas
+			if key == "enum" || key == "const" || key == "examples" || key == "default" {
+				continue
+			}
+			resources = append(resources, schemaResourceURLs(base, v[key])...)
+		}
+	case []any:
+		for _, item := range v {
+			resources = append(resources, schemaResourceURLs(base, item)...)
+		}
+	}
+
+	return resources
+}
+
+// bundleID returns the $id of the document at target in a bundle of the
+// document at base. Local documents get paths relative to base, other
+// documents keep their URLs.
+func bundleID(base, target *url.URL) string {
+	if base.Scheme != "file" || target.Scheme != "file" {
+		return target.String()
+	}
+
+	baseFile, err := (jsonschema.FileLoader{}).ToFile(base.String())
+	if err != nil {
+		return target.String()
+	}
+	targetFile, err := (jsonschema.FileLoader{}).ToFile(target.String())
+	if err != nil {
+		return target.String()
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(baseFile), targetFile)
+	if err != nil {
+		return target.String()
+	}
+
+	return (&url.URL{Path: filepath.ToSlash(rel)}).String()
+}
+
+// bundleName returns a name for the document at target in defs, derived
+// from the last segment of its URL without extension and made unique with
+// a numeric suffix.
+func bundleName(defs map[string]any, target *url.URL) string {
+	segment := target.Opaque
+	if segment == "" {
+		segment = path.Base(target.Path)
+	} else if i := strings.LastIndex(segment, ":"); i >= 0 {
+		// e.g. urn:jsonschema:flux-kustomization
+		segment = segment[i+1:]
+	}
+	segment = strings.TrimSuffix(segment, path.Ext(segment))
+
+	name := strings.Trim(bundleNameRegex.ReplaceAllString(segment, "-"), "-")
+	if name == "" || name == "." || name == "/" {
+		name = "schema"
+	}
+
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := defs[unique]; !ok {
+			break
+		}
+		unique = name + "-" + strconv.Itoa(i)
+	}
+
+	return unique
+}
+
+// encodeBundle encodes a bundled schema with sorted keys and a trailing
+// newline, so it can be committed and diffed.
+func encodeBundle(bundle any) (string, error) {
+	encoded, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not encode bundled schema: %w", err)
+	}
+
+	return string(encoded) + "\n", nil
+}
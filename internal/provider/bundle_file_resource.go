@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+)
+
+// Ensure BundleFileResource satisfies various resource interfaces.
+var _ resource.Resource = &BundleFileResource{}
+var _ resource.ResourceWithConfigure = &BundleFileResource{}
+
+func NewBundleFileResource() resource.Resource {
+	return &BundleFileResource{}
+}
+
+// BundleFileResource defines the resource implementation.
+type BundleFileResource struct {
+	compiler *schemaCompiler
+}
+
+// BundleFileResourceModel describes the resource data model.
+type BundleFileResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Path    types.String `tfsdk:"path"`
+	Schema  types.String `tfsdk:"schema"`
+	Content types.String `tfsdk:"content"`
+}
+
+func (r *BundleFileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bundle_file"
+}
+
+func (r *BundleFileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Writes a schema and every schema it references to a single self-contained JSON file, " +
+			"for tools outside of Terraform that cannot resolve references. " +
+			"The referenced schemas are embedded under `$defs`, or `definitions` before draft 2019-09, with their URLs as `$id`, " +
+			"so references resolve without loading anything, like in a compound schema document of the specification. " +
+			"Local schemas get `$id` relative to the bundled schema, which keeps the file independent of the working directory. " +
+			"The file is written again if it is edited or removed outside of Terraform, or if the bundle of the schema changes. " +
+			"The file is left in place when the resource is destroyed.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Path of the bundle file",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the bundle file to write",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path or URL of the schema document to bundle",
+				Required:    true,
+			},
+			"content": schema.StringAttribute{
+				Description: "Bundled schema written to the file",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *BundleFileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+}
+
+func (r *BundleFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data BundleFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(&data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BundleFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data BundleFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The file was removed or edited outside of Terraform, so it has to be written again.
+	contentRaw, err := os.ReadFile(data.Path.ValueString())
+	if err != nil || string(contentRaw) != data.Content.ValueString() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// The schema or a schema it references changed, errors are reported when the file is written again.
+	content, err := r.compiler.bundleSchema(data.Schema.ValueString())
+	if err != nil || content != data.Content.ValueString() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BundleFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data BundleFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(&data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BundleFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The bundle file is consumed by other tools, so it is kept on disk.
+}
+
+// write bundles the schema of data.Schema, writes it to data.Path and fills
+// in the computed attributes of data.
+func (r *BundleFileResource) write(data *BundleFileResourceModel, diags *diag.Diagnostics) {
+	schemaPath := data.Schema.ValueString()
+
+	content, err := r.compiler.bundleSchema(schemaPath)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Error bundling schema",
+			"Could not bundle schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	file := data.Path.ValueString()
+
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		diags.AddAttributeError(
+			path.Root("path"),
+			"Error writing file",
+			"Could not write file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(file)
+	data.Content = types.StringValue(content)
+}
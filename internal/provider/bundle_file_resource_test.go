@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestBundleSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "common"), 0755))
+
+	err := os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "type": "object",
+  "properties": {
+    "name": {"$ref": "#/$defs/name"},
+    "owner": {"$ref": "common/defs.json#/$defs/owner"},
+    "kind": {"enum": [{"$ref": "other.json"}]}
+  },
+  "$defs": {
+    "name": {"type": "string", "maxLength": 8}
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	// references back to the bundled schema resolve like the references between embedded schemas
+	err = os.WriteFile(filepath.Join(tmpDir, "common", "defs.json"), []byte(`{"$defs": {"owner": {"type": "object", "properties": {"name": {"$ref": "../schema.json#/$defs/name"}, "email": {"$ref": "email.json"}}}}}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "common", "email.json"), []byte(`{"type": "string", "pattern": "@"}`), 0644)
+	require.NoError(t, err)
+
+	compiler := newSchemaCompiler(nil, nil)
+
+	content, err := compiler.bundleSchema(filepath.Join(tmpDir, "schema.json"))
+	require.NoError(t, err)
+
+	var bundle map[string]any
+	require.NoError(t, json.Unmarshal([]byte(content), &bundle))
+
+	require.Equal(t, "schema.json", bundle["$id"])
+	require.Equal(t, map[string]any{
+		"name": map[string]any{"type": "string", "maxLength": 8.0},
+		"defs": map[string]any{
+			"$id":   "common/defs.json",
+			"$defs": map[string]any{"owner": map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"$ref": "../schema.json#/$defs/name"}, "email": map[string]any{"$ref": "email.json"}}}},
+		},
+		"email": map[string]any{"$id": "common/email.json", "type": "string", "pattern": "@"},
+	}, bundle["$defs"])
+
+	// the bundle is validated the same way without the referenced schemas
+	bundleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "bundle.json"), []byte(content), 0644))
+
+	sch, err := newSchemaCompiler(nil, nil).Compile(filepath.Join(bundleDir, "bundle.json"))
+	require.NoError(t, err)
+
+	require.NoError(t, sch.Validate(map[string]any{"name": "a", "owner": map[string]any{"name": "b", "email": "c@d"}}))
+	require.Error(t, sch.Validate(map[string]any{"owner": map[string]any{"name": "too long name"}}))
+	require.Error(t, sch.Validate(map[string]any{"owner": map[string]any{"email": "c"}}))
+
+	// draft-07 embeds schemas under definitions
+	err = os.WriteFile(filepath.Join(tmpDir, "draft7.json"), []byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "properties": {"email": {"$ref": "common/email.json"}}}`), 0644)
+	require.NoError(t, err)
+
+	content, err = compiler.bundleSchema(filepath.Join(tmpDir, "draft7.json"))
+	require.NoError(t, err)
+	require.Contains(t, content, `"definitions": {`)
+	require.Contains(t, content, `"$id": "common/email.json"`)
+
+	_, err = compiler.bundleSchema(filepath.Join(tmpDir, "schema.json") + "#/$defs/name")
+	require.ErrorContains(t, err, "only whole schema documents can be bundled")
+}
+
+func TestBundleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	defsPath := filepath.Join(tmpDir, "defs.json")
+	file := filepath.Join(tmpDir, "out", "bundle.json")
+
+	require.NoError(t, os.Mkdir(filepath.Dir(file), 0755))
+
+	err := os.WriteFile(schemaPath, []byte(`{"properties": {"name": {"$ref": "defs.json"}}}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(defsPath, []byte(`{"type": "string"}`), 0644)
+	require.NoError(t, err)
+
+	config := fmt.Sprintf(testAccBundleFileResourceConfig, file, schemaPath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jsonschema_bundle_file.test", "id", file),
+					testAccCheckResourceFileContent("jsonschema_bundle_file.test", file, `"type": "string"`),
+				),
+			},
+			// Changes of referenced schemas are written again
+			{
+				PreConfig: func() {
+					err := os.WriteFile(defsPath, []byte(`{"type": "integer"}`), 0644)
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check:  testAccCheckResourceFileContent("jsonschema_bundle_file.test", file, `"type": "integer"`),
+			},
+			// Files edited outside of Terraform are written again
+			{
+				PreConfig: func() {
+					err := os.WriteFile(file, []byte("{}\n"), 0644)
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check:  testAccCheckResourceFileContent("jsonschema_bundle_file.test", file, `"type": "integer"`),
+			},
+		},
+	})
+}
+
+const testAccBundleFileResourceConfig = `
+resource "jsonschema_bundle_file" "test" {
+  path   = "%s"
+  schema = "%s"
+}
+`
@@ -0,0 +1,345 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+const (
+	compatibilityBackward = "backward"
+	compatibilityForward  = "forward"
+	compatibilityFull     = "full"
+)
+
+// schemaChange is a difference between two versions of a schema. A
+// narrowing change rejects documents the previous version accepted, which
+// breaks backward compatibility. A widening change accepts documents the
+// previous version rejected, which breaks forward compatibility.
+type schemaChange struct {
+	Pointer   string
+	Message   string
+	Narrowing bool
+	Widening  bool
+}
+
+func (c schemaChange) String() string {
+	pointer := c.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return "'" + pointer + "': " + c.Message
+}
+
+// breakingChanges returns the changes from before to after that break the
+// compatibility mode.
+func breakingChanges(before, after any, compatibility string) []schemaChange {
+	var breaking []schemaChange
+	for _, change := range diffSchemas(before, after, "") {
+		switch {
+		case compatibility == compatibilityBackward && change.Narrowing,
+			compatibility == compatibilityForward && change.Widening,
+			compatibility == compatibilityFull && (change.Narrowing || change.Widening):
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// diffSchemas compares the keywords of two schemas that constrain documents,
+// recursing into subschemas. References are compared by value, not resolved.
+func diffSchemas(before, after any, pointer string) []schemaChange {
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	// the false schema rejects every value
+	if before == false {
+		return []schemaChange{{Pointer: pointer, Message: "values allowed", Widening: true}}
+	}
+	if after == false {
+		return []schemaChange{{Pointer: pointer, Message: "values no longer allowed", Narrowing: true}}
+	}
+
+	oldObject, oldOK := before.(map[string]any)
+	newObject, newOK := after.(map[string]any)
+	if before == true {
+		oldObject, oldOK = map[string]any{}, true
+	}
+	if after == true {
+		newObject, newOK = map[string]any{}, true
+	}
+	if !oldOK || !newOK {
+		return []schemaChange{{Pointer: pointer, Message: "schema changed", Narrowing: true, Widening: true}}
+	}
+	if reflect.DeepEqual(oldObject, newObject) {
+		return nil
+	}
+
+	var changes []schemaChange
+	change := func(keyword, message string, narrowing, widening bool) {
+		changes = append(changes, schemaChange{Pointer: pointer + "/" + keyword, Message: message, Narrowing: narrowing, Widening: widening})
+	}
+
+	// the set of accepted types, all types if unset
+	oldTypes, newTypes := schemaTypes(oldObject), schemaTypes(newObject)
+	switch {
+	case oldTypes == nil && newTypes != nil:
+		change("type", "type restricted to "+fmt.Sprint(newTypes), true, false)
+	case oldTypes != nil && newTypes == nil:
+		change("type", "type restriction removed", false, true)
+	case oldTypes != nil:
+		for _, t := range oldTypes {
+			if !slices.Contains(newTypes, t) && !(t == "integer" && slices.Contains(newTypes, "number")) {
+				change("type", "type "+t+" removed", true, false)
+			}
+		}
+		for _, t := range newTypes {
+			if !slices.Contains(oldTypes, t) && !(t == "integer" && slices.Contains(oldTypes, "number")) {
+				change("type", "type "+t+" added", false, true)
+			}
+		}
+	}
+
+	// required properties
+	oldRequired, newRequired := stringList(oldObject["required"]), stringList(newObject["required"])
+	for _, name := range newRequired {
+		if !slices.Contains(oldRequired, name) {
+			change("required", "property "+name+" is now required", true, false)
+		}
+	}
+	for _, name := range oldRequired {
+		if !slices.Contains(newRequired, name) {
+			change("required", "property "+name+" is no longer required", false, true)
+		}
+	}
+
+	// values
+	if !reflect.DeepEqual(oldObject["enum"], newObject["enum"]) {
+		oldEnum, oldHasEnum := oldObject["enum"].([]any)
+		newEnum, newHasEnum := newObject["enum"].([]any)
+		switch {
+		case !oldHasEnum:
+			change("enum", "values restricted to an enum", true, false)
+		case !newHasEnum:
+			change("enum", "enum removed", false, true)
+		default:
+			for _, value := range oldEnum {
+				if !slices.ContainsFunc(newEnum, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+					change("enum", "value "+jsonString(value)+" removed", true, false)
+				}
+			}
+			for _, value := range newEnum {
+				if !slices.ContainsFunc(oldEnum, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+					change("enum", "value "+jsonString(value)+" added", false, true)
+				}
+			}
+		}
+	}
+	for _, keyword := range []string{"const", "pattern", "format", "$ref", "$dynamicRef", "multipleOf"} {
+		changeKeyword(oldObject, newObject, keyword, change)
+	}
+
+	// lower bounds narrow when they increase, upper bounds when they decrease
+	for _, keyword := range []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties", "minContains"} {
+		changeBound(oldObject, newObject, keyword, 1, change)
+	}
+	for _, keyword := range []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties", "maxContains"} {
+		changeBound(oldObject, newObject, keyword, -1, change)
+	}
+	changeFlag(oldObject, newObject, "uniqueItems", change)
+
+	// properties and the properties they leave to additionalProperties
+	oldProperties, _ := oldObject["properties"].(map[string]any)
+	newProperties, _ := newObject["properties"].(map[string]any)
+	for _, name := range sortedKeys(oldProperties, newProperties) {
+		propertyPointer := pointer + "/properties/" + escapePointerToken(name)
+		oldProperty, inOld := oldProperties[name]
+		newProperty, inNew := newProperties[name]
+		switch {
+		case !inOld:
+			oldProperty = additionalSchema(oldObject)
+		case !inNew:
+			newProperty = additionalSchema(newObject)
+		}
+		changes = append(changes, diffSchemas(oldProperty, newProperty, propertyPointer)...)
+	}
+	changes = append(changes, diffSchemas(additionalSchema(oldObject), additionalSchema(newObject), pointer+"/additionalProperties")...)
+
+	for _, keyword := range []string{"items", "additionalItems", "contains", "propertyNames", "not", "if", "then", "else", "unevaluatedItems", "unevaluatedProperties"} {
+		oldSub, inOld := oldObject[keyword]
+		newSub, inNew := newObject[keyword]
+		if !inOld && !inNew {
+			continue
+		}
+		if (!inOld || !inNew) && slices.Contains([]string{"contains", "if", "then", "else"}, keyword) {
+			// these have no equivalent subschema if unset
+			changeKeyword(oldObject, newObject, keyword, change)
+			continue
+		}
+		unset := any(true)
+		if keyword == "not" {
+			unset = false
+		}
+		if !inOld {
+			oldSub = unset
+		}
+		if !inNew {
+			newSub = unset
+		}
+		if keyword == "not" {
+			// not inverts the direction of the changes of its subschema
+			for _, c := range diffSchemas(oldSub, newSub, pointer+"/not") {
+				c.Narrowing, c.Widening = c.Widening, c.Narrowing
+				changes = append(changes, c)
+			}
+			continue
+		}
+		changes = append(changes, diffSchemas(oldSub, newSub, pointer+"/"+keyword)...)
+	}
+
+	for _, keyword := range []string{"$defs", "definitions", "patternProperties", "dependentSchemas"} {
+		oldDefs, _ := oldObject[keyword].(map[string]any)
+		newDefs, _ := newObject[keyword].(map[string]any)
+		for _, name := range sortedKeys(oldDefs, newDefs) {
+			oldDef, inOld := oldDefs[name]
+			newDef, inNew := newDefs[name]
+			// added definitions only matter once referenced, which is a change of $ref
+			if !inOld || !inNew {
+				if keyword == "patternProperties" || keyword == "dependentSchemas" {
+					changes = append(changes, schemaChange{Pointer: pointer + "/" + keyword + "/" + escapePointerToken(name), Message: keyword + " changed", Narrowing: true, Widening: true})
+				}
+				continue
+			}
+			changes = append(changes, diffSchemas(oldDef, newDef, pointer+"/"+keyword+"/"+escapePointerToken(name))...)
+		}
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+		oldList, _ := oldObject[keyword].([]any)
+		newList, _ := newObject[keyword].([]any)
+		if len(oldList) != len(newList) {
+			change(keyword, keyword+" changed from "+strconv.Itoa(len(oldList))+" to "+strconv.Itoa(len(newList))+" subschemas", true, true)
+			continue
+		}
+		for i := range oldList {
+			changes = append(changes, diffSchemas(oldList[i], newList[i], pointer+"/"+keyword+"/"+strconv.Itoa(i))...)
+		}
+	}
+
+	return changes
+}
+
+// additionalSchema returns the schema of properties that are not declared,
+// true if unset.
+func additionalSchema(object map[string]any) any {
+	if additional, ok := object["additionalProperties"]; ok {
+		return additional
+	}
+	return true
+}
+
+func schemaTypes(object map[string]any) []string {
+	switch t := object["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		return stringList(t)
+	}
+	return nil
+}
+
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func sortedKeys(maps ...map[string]any) []string {
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonString(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// changeKeyword reports adding a keyword as narrowing, removing it as
+// widening and changing its value as both.
+func changeKeyword(before, after map[string]any, keyword string, change func(keyword, message string, narrowing, widening bool)) {
+	oldValue, inOld := before[keyword]
+	newValue, inNew := after[keyword]
+	switch {
+	case !inOld && inNew:
+		change(keyword, keyword+" "+jsonString(newValue)+" added", true, false)
+	case inOld && !inNew:
+		change(keyword, keyword+" "+jsonString(oldValue)+" removed", false, true)
+	case !reflect.DeepEqual(oldValue, newValue):
+		change(keyword, keyword+" changed from "+jsonString(oldValue)+" to "+jsonString(newValue), true, true)
+	}
+}
+
+// changeFlag reports enabling a boolean keyword as narrowing and disabling
+// it as widening.
+func changeFlag(before, after map[string]any, keyword string, change func(keyword, message string, narrowing, widening bool)) {
+	oldValue, _ := before[keyword].(bool)
+	newValue, _ := after[keyword].(bool)
+	switch {
+	case !oldValue && newValue:
+		change(keyword, keyword+" enabled", true, false)
+	case oldValue && !newValue:
+		change(keyword, keyword+" disabled", false, true)
+	}
+}
+
+// changeBound compares a numeric bound. direction is 1 for lower bounds,
+// which narrow when they increase, and -1 for upper bounds.
+func changeBound(before, after map[string]any, keyword string, direction int, change func(keyword, message string, narrowing, widening bool)) {
+	oldValue, inOld := schemaNumber(before[keyword])
+	newValue, inNew := schemaNumber(after[keyword])
+	switch {
+	case !inOld && !inNew:
+	case !inOld:
+		change(keyword, keyword+" "+newValue.RatString()+" added", true, false)
+	case !inNew:
+		change(keyword, keyword+" "+oldValue.RatString()+" removed", false, true)
+	case newValue.Cmp(oldValue)*direction > 0:
+		change(keyword, keyword+" changed from "+oldValue.RatString()+" to "+newValue.RatString(), true, false)
+	case newValue.Cmp(oldValue)*direction < 0:
+		change(keyword, keyword+" changed from "+oldValue.RatString()+" to "+newValue.RatString(), false, true)
+	}
+}
+
+func schemaNumber(value any) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(v.String())
+	case float64:
+		return new(big.Rat).SetFloat64(v), true
+	}
+	return nil, false
+}
@@ -0,0 +1,328 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"os"
+	"strings"
+)
+
+// Ensure CompatibilityGateResource satisfies various resource interfaces.
+var _ resource.Resource = &CompatibilityGateResource{}
+var _ resource.ResourceWithConfigure = &CompatibilityGateResource{}
+var _ resource.ResourceWithModifyPlan = &CompatibilityGateResource{}
+
+func NewCompatibilityGateResource() resource.Resource {
+	return &CompatibilityGateResource{}
+}
+
+// CompatibilityGateResource defines the resource implementation.
+type CompatibilityGateResource struct {
+	compiler *schemaCompiler
+}
+
+// CompatibilityGateResourceModel describes the resource data model.
+type CompatibilityGateResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Schema        types.String `tfsdk:"schema"`
+	Compatibility types.String `tfsdk:"compatibility"`
+	SchemaHash    types.String `tfsdk:"schema_hash"`
+	Baseline      types.String `tfsdk:"baseline"`
+}
+
+func (r *CompatibilityGateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_compatibility_gate"
+}
+
+func (r *CompatibilityGateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Approves a json schema and fails the apply if a later version of the schema introduces breaking changes relative to the approved baseline. " +
+			"The schema is approved as the new baseline whenever it is applied without breaking changes. " +
+			"Changes are detected by comparing keywords like `type`, `required`, `enum`, bounds and `additionalProperties`, `$ref`s are compared but not resolved. " +
+			"Replace the resource to approve a breaking change.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Path of the schema",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path of the json schema to gate",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"compatibility": schema.StringAttribute{
+				MarkdownDescription: "Changes allowed relative to the baseline, defaults to `backward`. " +
+					"`backward` rejects changes that make documents valid under the baseline invalid, e.g. a new required property. " +
+					"`forward` rejects changes that make documents invalid under the baseline valid, e.g. a removed required property. " +
+					"`full` rejects both.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(compatibilityBackward),
+				Validators: []validator.String{
+					stringvalidator.OneOf(compatibilityBackward, compatibilityForward, compatibilityFull),
+				},
+			},
+			"schema_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the approved schema, ignoring formatting",
+				Computed:    true,
+			},
+			"baseline": schema.StringAttribute{
+				Description: "Approved schema the next version is compared with, encoded as compact JSON",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *CompatibilityGateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+}
+
+// ModifyPlan reads the schema, so edits of the file are planned as updates
+// of schema_hash and baseline, and warns about breaking changes early.
+func (r *CompatibilityGateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// the schema is not needed to destroy the gate
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan CompatibilityGateResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the path is not known until apply, e.g. if it is the result of another resource
+	if plan.Schema.IsUnknown() {
+		return
+	}
+
+	r.read(&plan, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state CompatibilityGateResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if breaking := compareBaseline(&state, &plan, &resp.Diagnostics); len(breaking) > 0 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("schema"),
+				"Breaking schema changes",
+				breakingChangesDetail(&plan, breaking)+". Applying the change will fail.",
+			)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *CompatibilityGateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CompatibilityGateResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the first version of the schema is approved as it is
+	if data.Baseline.IsUnknown() {
+		r.read(&data, &resp.Diagnostics)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CompatibilityGateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CompatibilityGateResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The approved baseline is only changed by an apply, edits of the schema are planned as updates.
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CompatibilityGateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state CompatibilityGateResourceModel
+
+	// Read Terraform plan and prior state data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the schema may only be known now, otherwise the planned baseline is the one approved
+	if data.Baseline.IsUnknown() {
+		r.read(&data, &resp.Diagnostics)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if breaking := compareBaseline(&state, &data, &resp.Diagnostics); len(breaking) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Breaking schema changes",
+			breakingChangesDetail(&data, breaking)+". Revert the changes or replace the resource to approve them.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CompatibilityGateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The schema is owned by the repository, only the baseline in state is removed.
+}
+
+// read compiles the schema at data.Schema and fills in its hash and
+// baseline.
+func (r *CompatibilityGateResource) read(data *CompatibilityGateResourceModel, diags *diag.Diagnostics) {
+	file := data.Schema.ValueString()
+
+	contentRaw, err := os.ReadFile(file)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Error reading schema",
+			"Could not read schema "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	if _, err := r.compiler.Compile(file); err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, bytes.TrimPrefix(contentRaw, utf8BOM)); err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Error reading schema",
+			"Could not decode schema "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	sum := sha256.Sum256(compact.Bytes())
+
+	data.ID = types.StringValue(file)
+	data.SchemaHash = types.StringValue(hex.EncodeToString(sum[:]))
+	data.Baseline = types.StringValue(compact.String())
+}
+
+// compareBaseline returns the changes of the schema planned in data that
+// break the compatibility mode relative to the baseline approved in state.
+func compareBaseline(state, data *CompatibilityGateResourceModel, diags *diag.Diagnostics) []schemaChange {
+	if state.SchemaHash.Equal(data.SchemaHash) {
+		return nil
+	}
+
+	before, err := jsonschema.UnmarshalJSON(strings.NewReader(state.Baseline.ValueString()))
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("baseline"),
+			"Error reading baseline",
+			"Could not decode the approved baseline: "+err.Error(),
+		)
+		return nil
+	}
+
+	after, err := jsonschema.UnmarshalJSON(strings.NewReader(data.Baseline.ValueString()))
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Error reading schema",
+			"Could not decode schema "+data.Schema.ValueString()+": "+err.Error(),
+		)
+		return nil
+	}
+
+	return breakingChanges(before, after, data.Compatibility.ValueString())
+}
+
+func breakingChangesDetail(data *CompatibilityGateResourceModel, breaking []schemaChange) string {
+	changes := make([]string, 0, len(breaking))
+	for _, change := range breaking {
+		changes = append(changes, change.String())
+	}
+
+	return "Schema " + data.Schema.ValueString() + " is not " + data.Compatibility.ValueString() + " compatible with the approved baseline: " + strings.Join(changes, ", ")
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestCompatibilityGate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "schema.json")
+
+	writeSchema := func(content string) func() {
+		return func() {
+			require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		}
+	}
+	writeSchema(`{
+  "type": "object",
+  "properties": {"name": {"type": "string"}}
+}`)()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the first version is approved
+			{
+				Config: fmt.Sprintf(testAccCompatibilityGateResourceConfig, file, "backward"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"jsonschema_compatibility_gate.test",
+						tfjsonpath.New("baseline"),
+						knownvalue.StringExact(`{"type":"object","properties":{"name":{"type":"string"}}}`),
+					),
+				},
+			},
+			// accepting null is backward compatible
+			{
+				PreConfig: writeSchema(`{"type": "object", "properties": {"name": {"type": ["string", "null"]}}}`),
+				Config:    fmt.Sprintf(testAccCompatibilityGateResourceConfig, file, "backward"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"jsonschema_compatibility_gate.test",
+						tfjsonpath.New("baseline"),
+						knownvalue.StringExact(`{"type":"object","properties":{"name":{"type":["string","null"]}}}`),
+					),
+				},
+			},
+			// a new required property is not
+			{
+				PreConfig:   writeSchema(`{"type": "object", "properties": {"name": {"type": ["string", "null"]}}, "required": ["name"]}`),
+				Config:      fmt.Sprintf(testAccCompatibilityGateResourceConfig, file, "backward"),
+				ExpectError: regexp.MustCompile(`(?s)Breaking schema changes.*property name is now\s+required`),
+			},
+			// but it is forward compatible
+			{
+				Config: fmt.Sprintf(testAccCompatibilityGateResourceConfig, file, "forward"),
+			},
+			{
+				PreConfig:   writeSchema(`{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`),
+				Config:      fmt.Sprintf(testAccCompatibilityGateResourceConfig, file, "full"),
+				ExpectError: regexp.MustCompile(`'/properties/name/type': type\s+null\s+removed`),
+			},
+		},
+	})
+}
+
+func TestBreakingChanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    string
+		after     string
+		narrowing []string
+		widening  []string
+	}{
+		{
+			name:      "required",
+			before:    `{"required": ["a", "b"]}`,
+			after:     `{"required": ["a", "c"]}`,
+			narrowing: []string{"'/required': property c is now required"},
+			widening:  []string{"'/required': property b is no longer required"},
+		},
+		{
+			name:      "type",
+			before:    `{"properties": {"id": {"type": "integer"}}}`,
+			after:     `{"properties": {"id": {"type": "number"}}}`,
+			widening:  []string{"'/properties/id/type': type number added"},
+			narrowing: []string{},
+		},
+		{
+			name:      "enum",
+			before:    `{"enum": ["a", "b"]}`,
+			after:     `{"enum": ["a", "c"]}`,
+			narrowing: []string{`'/enum': value "b" removed`},
+			widening:  []string{`'/enum': value "c" added`},
+		},
+		{
+			name:      "bounds",
+			before:    `{"minLength": 1, "maxLength": 10}`,
+			after:     `{"minLength": 2, "maxLength": 20}`,
+			narrowing: []string{"'/minLength': minLength changed from 1 to 2"},
+			widening:  []string{"'/maxLength': maxLength changed from 10 to 20"},
+		},
+		{
+			name:      "closed object",
+			before:    `{"properties": {"a": {"type": "string"}, "b": {}}}`,
+			after:     `{"properties": {"a": {"type": "string"}}, "additionalProperties": false}`,
+			narrowing: []string{"'/properties/b': values no longer allowed", "'/additionalProperties': values no longer allowed"},
+			widening:  []string{},
+		},
+		{
+			name:      "new declared property",
+			before:    `{"properties": {}}`,
+			after:     `{"properties": {"a": {"type": "string"}}}`,
+			narrowing: []string{"'/properties/a/type': type restricted to [string]"},
+			widening:  []string{},
+		},
+		{
+			name:      "not",
+			before:    `{"not": {"type": "string"}}`,
+			after:     `{"not": {}}`,
+			narrowing: []string{"'/not/type': type restriction removed"},
+			widening:  []string{},
+		},
+		{
+			name:      "unchanged",
+			before:    `{"$ref": "#/$defs/a", "$defs": {"a": {"type": "string"}}}`,
+			after:     `{"$defs": {"a": {"type": "string"}}, "$ref": "#/$defs/a"}`,
+			narrowing: []string{},
+			widening:  []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := decodeTestSchema(t, test.before)
+			after := decodeTestSchema(t, test.after)
+
+			narrowing := []string{}
+			for _, change := range breakingChanges(before, after, compatibilityBackward) {
+				narrowing = append(narrowing, change.String())
+			}
+			widening := []string{}
+			for _, change := range breakingChanges(before, after, compatibilityForward) {
+				widening = append(widening, change.String())
+			}
+
+			require.Equal(t, test.narrowing, narrowing)
+			require.Equal(t, test.widening, widening)
+			require.Len(t, breakingChanges(before, after, compatibilityFull), len(uniqueStrings(append(narrowing, widening...))))
+		})
+	}
+}
+
+func decodeTestSchema(t *testing.T, content string) any {
+	value, err := decodeYAML([]byte(content))
+	require.NoError(t, err)
+	return value
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+const testAccCompatibilityGateResourceConfig = `
+resource "jsonschema_compatibility_gate" "test" {
+  schema        = "%s"
+  compatibility = "%s"
+}
+`
@@ -0,0 +1,225 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// composedSchemaURL is the location the composed schema is compiled at to
+// check it, the references its parts keep are absolute URLs.
+const composedSchemaURL = "urn:jsonschema:composed"
+
+// Ensure ComposedSchemaDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &ComposedSchemaDataSource{}
+
+func NewComposedSchemaDataSource() datasource.DataSource {
+	return &ComposedSchemaDataSource{}
+}
+
+// ComposedSchemaDataSource defines the data source implementation.
+type ComposedSchemaDataSource struct {
+	compiler *schemaCompiler
+}
+
+// ComposedSchemaDataSourceModel describes the data source data model.
+type ComposedSchemaDataSourceModel struct {
+	AllOf      types.List   `tfsdk:"all_of"`
+	AnyOf      types.List   `tfsdk:"any_of"`
+	OneOf      types.List   `tfsdk:"one_of"`
+	Title      types.String `tfsdk:"title"`
+	ID         types.String `tfsdk:"id"`
+	SchemaJSON types.String `tfsdk:"schema_json"`
+}
+
+func (d *ComposedSchemaDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_composed_schema"
+}
+
+func (d *ComposedSchemaDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Combines json schemas with `allOf`, `anyOf` and `oneOf` into a single standalone schema, " +
+			"e.g. to validate files against a base schema and the schema of their environment with the `matches` function, or to publish it. " +
+			"The schemas are embedded as they are compiled, with every `$ref` replaced by the referenced subschema like `resolved_schema_json` of `jsonschema_validated_yaml`, " +
+			"references that cannot be inlined are kept with absolute URLs and the schemas keep their `$id`. " +
+			"The `$schema` the schemas declare moves to the composed schema, so they have to declare the same draft or none.",
+
+		Attributes: map[string]schema.Attribute{
+			"all_of": schema.ListAttribute{
+				Description: "Paths or URLs of the schemas a value has to match all of",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"any_of": schema.ListAttribute{
+				Description: "Paths or URLs of the schemas a value has to match at least one of",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"one_of": schema.ListAttribute{
+				Description: "Paths or URLs of the schemas a value has to match exactly one of",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "`title` of the composed schema",
+				Optional:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "`$id` of the composed schema, e.g. the URL it is published at",
+				Optional:            true,
+			},
+			"schema_json": schema.StringAttribute{
+				Description: "JSON encoded composed schema",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ComposedSchemaDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *ComposedSchemaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ComposedSchemaDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	composed := make(map[string]any)
+	draft := ""
+	for _, keyword := range []struct {
+		name      string
+		attribute string
+		schemas   types.List
+	}{
+		{"allOf", "all_of", data.AllOf},
+		{"anyOf", "any_of", data.AnyOf},
+		{"oneOf", "one_of", data.OneOf},
+	} {
+		if keyword.schemas.IsNull() {
+			continue
+		}
+
+		var locations []string
+		resp.Diagnostics.Append(keyword.schemas.ElementsAs(ctx, &locations, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		parts := make([]any, 0, len(locations))
+		for i, location := range locations {
+			if _, err := d.compiler.Compile(location); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(keyword.attribute).AtListIndex(i),
+					"Error compiling schema",
+					"Could not compile schema "+location+": "+err.Error(),
+				)
+				return
+			}
+
+			resolved, err := d.compiler.resolveSchema(location, nil)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(keyword.attribute).AtListIndex(i),
+					"Error resolving schema",
+					"Could not resolve schema "+location+": "+err.Error(),
+				)
+				return
+			}
+
+			// the parts are subschemas of the composed schema, which declares their draft
+			if object, ok := resolved.(map[string]any); ok {
+				if partDraft, ok := object["$schema"].(string); ok {
+					if draft != "" && partDraft != draft {
+						resp.Diagnostics.AddAttributeError(
+							path.Root(keyword.attribute).AtListIndex(i),
+							"Error composing schemas",
+							"Schema "+location+" declares $schema "+partDraft+", other schemas declare "+draft,
+						)
+						return
+					}
+					draft = partDraft
+				}
+				delete(object, "$schema")
+			}
+
+			parts = append(parts, resolved)
+		}
+
+		if len(parts) > 0 {
+			composed[keyword.name] = parts
+		}
+	}
+
+	if len(composed) == 0 {
+		resp.Diagnostics.AddError(
+			"Missing schemas",
+			"At least one schema must be set in all_of, any_of or one_of",
+		)
+		return
+	}
+
+	if draft != "" {
+		composed["$schema"] = draft
+	}
+	if !data.ID.IsNull() {
+		composed["$id"] = data.ID.ValueString()
+	}
+	if !data.Title.IsNull() {
+		composed["title"] = data.Title.ValueString()
+	}
+
+	compiler := d.compiler.newCompiler()
+	err := compiler.AddResource(composedSchemaURL, composed)
+	if err == nil {
+		_, err = compiler.Compile(composedSchemaURL)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error compiling schema",
+			"Could not compile the composed schema: "+err.Error(),
+		)
+		return
+	}
+
+	encoded, err := json.MarshalIndent(composed, "", "  ")
+	if err != nil {
+		resp.Diagnostics.AddError("Error encoding schema", "Could not encode the composed schema: "+err.Error())
+		return
+	}
+
+	data.SchemaJSON = types.StringValue(string(encoded))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
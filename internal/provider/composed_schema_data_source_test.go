@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestComposedSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"base.json":    `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "required": ["name"], "properties": {"name": {"$ref": "#/$defs/name"}}, "$defs": {"name": {"type": "string"}}}`,
+		"web.json":     `{"properties": {"kind": {"const": "web"}, "port": {"type": "integer"}}}`,
+		"worker.json":  `{"properties": {"kind": {"const": "worker"}}}`,
+		"draft07.json": `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object"}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	schemaPath := func(name string) string {
+		return filepath.ToSlash(filepath.Join(tmpDir, name))
+	}
+
+	// the references of the parts are inlined
+	expected, err := json.MarshalIndent(map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     "https://example.com/service.json",
+		"title":   "Service",
+		"allOf": []any{
+			map[string]any{
+				"type":       "object",
+				"required":   []any{"name"},
+				"properties": map[string]any{"name": map[string]any{"type": "string"}},
+				"$defs":      map[string]any{"name": map[string]any{"type": "string"}},
+			},
+		},
+		"oneOf": []any{
+			map[string]any{"properties": map[string]any{"kind": map[string]any{"const": "web"}, "port": map[string]any{"type": "integer"}}},
+			map[string]any{"properties": map[string]any{"kind": map[string]any{"const": "worker"}}},
+		},
+	}, "", "  ")
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      `data "jsonschema_composed_schema" "test" {}`,
+				ExpectError: regexp.MustCompile(`Missing schemas`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccComposedSchemaDataSourceConfig, schemaPath("base.json"), schemaPath("draft07.json"), schemaPath("worker.json")),
+				ExpectError: regexp.MustCompile(`Error composing schemas`),
+			},
+			{
+				Config: fmt.Sprintf(testAccComposedSchemaDataSourceConfig, schemaPath("base.json"), schemaPath("web.json"), schemaPath("worker.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_composed_schema.test",
+						tfjsonpath.New("schema_json"),
+						knownvalue.StringExact(string(expected)),
+					),
+					statecheck.ExpectKnownOutputValue("valid", knownvalue.Bool(true)),
+				},
+			},
+		},
+	})
+}
+
+const testAccComposedSchemaDataSourceConfig = `
+data "jsonschema_composed_schema" "test" {
+  all_of = ["%s"]
+  one_of = ["%s", "%s"]
+  title  = "Service"
+  id     = "https://example.com/service.json"
+}
+
+output "valid" {
+  value = provider::jsonschema::matches(data.jsonschema_composed_schema.test.schema_json, jsonencode({ name = "orders", kind = "worker" }))
+}
+`
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ContentConfigModel describes the content assertions of the provider.
+type ContentConfigModel struct {
+	Assert types.Bool `tfsdk:"assert"`
+}
+
+// configureContent enables the content assertions of config with compiler.
+func configureContent(compiler *jsonschema.Compiler, config *ContentConfigModel) {
+	if config == nil || !config.Assert.ValueBool() {
+		return
+	}
+
+	compiler.AssertContent()
+
+	// YAML content is validated against contentSchema like JSON content
+	compiler.RegisterContentMediaType(&jsonschema.MediaType{
+		Name: "application/yaml",
+		Validate: func(content []byte) error {
+			_, err := decodeYAML(content)
+			return err
+		},
+		UnmarshalJSON: decodeYAML,
+	})
+	compiler.RegisterContentMediaType(&jsonschema.MediaType{
+		Name:     "application/x-pem-file",
+		Validate: validatePEM,
+	})
+	compiler.RegisterContentMediaType(&jsonschema.MediaType{
+		Name: "application/pkix-cert",
+		Validate: func(content []byte) error {
+			_, err := x509.ParseCertificate(content)
+			return err
+		},
+	})
+}
+
+// validatePEM checks that content consists of PEM blocks only.
+func validatePEM(content []byte) error {
+	rest := bytes.TrimSpace(content)
+	if len(rest) == 0 {
+		return errors.New("no PEM block found")
+	}
+
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return errors.New("not a valid PEM block")
+		}
+		rest = bytes.TrimSpace(rest)
+	}
+
+	return nil
+}
+
+// reportContent is a value of a document decoded by its contentEncoding.
+type reportContent struct {
+	File      string `json:"file"`
+	Document  int    `json:"document"`
+	Pointer   string `json:"pointer"`
+	Encoding  string `json:"encoding"`
+	MediaType string `json:"media_type,omitempty"`
+	// Length is the number of decoded bytes.
+	Length int `json:"length"`
+	// SHA256 is the hex encoded SHA-256 digest of the decoded bytes.
+	SHA256 string `json:"sha256"`
+}
+
+// documentContents returns the values of a document with a contentEncoding,
+// which is only known to sch if content is asserted. Values that do not
+// decode are left to the validation to report.
+func documentContents(file string, document int, sch *jsonschema.Schema, value any) []reportContent {
+	var contents []reportContent
+
+	walkSchema(sch, value, func(pointer string, value any, schemas []*jsonschema.Schema) {
+		s, ok := value.(string)
+		if !ok {
+			return
+		}
+
+		for _, schema := range schemas {
+			if schema.ContentEncoding == nil {
+				continue
+			}
+
+			decoded, err := schema.ContentEncoding.Decode(s)
+			if err != nil {
+				return
+			}
+
+			content := reportContent{
+				File:     file,
+				Document: document,
+				Pointer:  pointer,
+				Encoding: schema.ContentEncoding.Name,
+				Length:   len(decoded),
+			}
+			if schema.ContentMediaType != nil {
+				content.MediaType = schema.ContentMediaType.Name
+			}
+			sum := sha256.Sum256(decoded)
+			content.SHA256 = hex.EncodeToString(sum[:])
+
+			contents = append(contents, content)
+			return
+		}
+	})
+
+	return contents
+}
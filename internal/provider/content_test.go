@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const testContentPEM = "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIUAQ==\n-----END CERTIFICATE-----\n"
+
+const testContentSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "certificate": {"type": "string", "contentEncoding": "base64", "contentMediaType": "application/x-pem-file"},
+    "settings": {
+      "type": "string",
+      "contentEncoding": "base64",
+      "contentMediaType": "application/yaml",
+      "contentSchema": {"type": "object", "required": ["replicas"]}
+    }
+  }
+}`
+
+func TestValidatePEM(t *testing.T) {
+	require.NoError(t, validatePEM([]byte(testContentPEM+testContentPEM)))
+	require.ErrorContains(t, validatePEM([]byte(testContentPEM+"trailing")), "not a valid PEM block")
+	require.ErrorContains(t, validatePEM([]byte(" \n")), "no PEM block found")
+}
+
+func TestDocumentContents(t *testing.T) {
+	tmpDir := t.TempDir()
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(testContentSchema), 0644))
+
+	compiler := newSchemaCompiler(nil, func(compiler *jsonschema.Compiler) {
+		configureContent(compiler, &ContentConfigModel{Assert: types.BoolValue(true)})
+	})
+	sch, err := compiler.Compile(schemaPath)
+	require.NoError(t, err)
+
+	certificate := base64.StdEncoding.EncodeToString([]byte(testContentPEM))
+	settings := base64.StdEncoding.EncodeToString([]byte("replicas: 2\n"))
+
+	require.NoError(t, sch.Validate(map[string]any{"certificate": certificate, "settings": settings}))
+	require.ErrorContains(t, sch.Validate(map[string]any{"certificate": "not base64"}), "base64")
+	require.ErrorContains(t, sch.Validate(map[string]any{"certificate": base64.StdEncoding.EncodeToString([]byte("plain"))}), "not a valid PEM block")
+	require.ErrorContains(t, sch.Validate(map[string]any{"settings": base64.StdEncoding.EncodeToString([]byte("name: web\n"))}), "missing property 'replicas'")
+
+	sum := sha256.Sum256([]byte(testContentPEM))
+	require.Equal(t, []reportContent{{
+		File:      "values.yaml",
+		Pointer:   "/certificate",
+		Encoding:  "base64",
+		MediaType: "application/x-pem-file",
+		Length:    len(testContentPEM),
+		SHA256:    hex.EncodeToString(sum[:]),
+	}}, documentContents("values.yaml", 0, sch, map[string]any{"certificate": certificate}))
+
+	// the content keywords are annotations unless content is asserted
+	sch, err = newSchemaCompiler(nil, nil).Compile(schemaPath)
+	require.NoError(t, err)
+	require.NoError(t, sch.Validate(map[string]any{"certificate": "not base64"}))
+	require.Empty(t, documentContents("values.yaml", 0, sch, map[string]any{"certificate": certificate}))
+}
+
+func TestContentYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "values.yaml")
+	writeValues := func(settings string) func() {
+		return func() {
+			content := fmt.Sprintf("# yaml-language-server: $schema=./schema.json\ncertificate: %s\nsettings: %s\n",
+				base64.StdEncoding.EncodeToString([]byte(testContentPEM)), base64.StdEncoding.EncodeToString([]byte(settings)))
+			require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		}
+	}
+	writeValues("name: web\n")()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testContentSchema), 0644))
+
+	sum := sha256.Sum256([]byte(testContentPEM))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccContentConfig, true, file),
+				ExpectError: regexp.MustCompile(`missing property 'replicas'`),
+			},
+			{
+				Config: fmt.Sprintf(testAccContentConfig, false, file),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.content",
+						tfjsonpath.New("report"),
+						knownvalue.StringExact(`{"findings":[],"matches":[]}`),
+					),
+				},
+			},
+			{
+				PreConfig: writeValues("replicas: 2\n"),
+				Config:    fmt.Sprintf(testAccContentConfig, true, file),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.content",
+						tfjsonpath.New("report"),
+						knownvalue.StringRegexp(regexp.MustCompile(
+							`"contents":\[\{"file":"[^"]*","document":0,"pointer":"/certificate","encoding":"base64","media_type":"application/x-pem-file","length":`+
+								fmt.Sprint(len(testContentPEM))+`,"sha256":"`+hex.EncodeToString(sum[:])+`"\},\{[^}]*"pointer":"/settings"[^}]*"length":12,`,
+						)),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccContentConfig = `
+provider "jsonschema" {
+  content = {
+    assert = %t
+  }
+}
+
+data "jsonschema_validated_yaml" "content" {
+  input_pattern = "%s"
+}
+`
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// propertyCoverage accumulates which properties declared by a schema the
+// validated documents use and which of their keys no property declares.
+type propertyCoverage struct {
+	// base is the URL of the schema document, properties declared in it
+	// are reported by their pointer.
+	base       string
+	declared   []string
+	used       map[string]bool
+	additional []coverageKey
+}
+
+// coverageKey is a key of a document that falls through to
+// additionalProperties, i.e. no property or pattern property of the
+// schemas of its object declares it.
+type coverageKey struct {
+	File     string `json:"file"`
+	Document int    `json:"document"`
+	Pointer  string `json:"pointer"`
+}
+
+// coverageReport is the property coverage of a schema.
+type coverageReport struct {
+	UnusedProperties []string      `json:"unused_properties"`
+	AdditionalKeys   []coverageKey `json:"additional_keys"`
+}
+
+// propertyUsage is the coverage of a schema by a single document.
+type propertyUsage struct {
+	used       []string
+	additional []coverageKey
+}
+
+// newPropertyCoverage returns the coverage of the properties declared by
+// sch and the schemas reachable from it.
+func newPropertyCoverage(sch *jsonschema.Schema) *propertyCoverage {
+	base, _, _ := strings.Cut(sch.Location, "#")
+	coverage := &propertyCoverage{
+		base: base,
+		used: make(map[string]bool),
+	}
+
+	seen := make(map[*jsonschema.Schema]bool)
+
+	var visit func(s *jsonschema.Schema)
+	visit = func(s *jsonschema.Schema) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+
+		for _, key := range slices.Sorted(maps.Keys(s.Properties)) {
+			coverage.declared = append(coverage.declared, s.Properties[key].Location)
+		}
+		for _, sub := range slices.Concat(inPlaceSchemas(s), nestedSchemas(s)) {
+			visit(sub)
+		}
+	}
+	visit(sch)
+
+	return coverage
+}
+
+// add records the usage of a document.
+func (c *propertyCoverage) add(usage propertyUsage) {
+	for _, location := range usage.used {
+		c.used[location] = true
+	}
+	c.additional = append(c.additional, usage.additional...)
+}
+
+// report returns the declared properties no document used, sorted by
+// location, and the keys that fell through to additionalProperties.
+func (c *propertyCoverage) report() coverageReport {
+	unused := make([]string, 0)
+	for _, location := range c.declared {
+		if c.used[location] {
+			continue
+		}
+		if ptr, ok := strings.CutPrefix(location, c.base+"#"); ok {
+			location = "#" + ptr
+		}
+		unused = append(unused, location)
+	}
+	slices.Sort(unused)
+
+	return coverageReport{
+		UnusedProperties: slices.Compact(unused),
+		AdditionalKeys:   append(make([]coverageKey, 0, len(c.additional)), c.additional...),
+	}
+}
+
+// documentPropertyUsage returns the properties of sch used by the objects
+// of a document and the keys of objects whose schemas declare properties
+// but none matching the key.
+func documentPropertyUsage(file string, document int, sch *jsonschema.Schema, value any) propertyUsage {
+	var usage propertyUsage
+
+	walkSchema(sch, value, func(pointer string, value any, schemas []*jsonschema.Schema) {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		// objects of free-form maps have no properties to fall through
+		if !slices.ContainsFunc(schemas, func(s *jsonschema.Schema) bool { return len(s.Properties) > 0 || len(s.PatternProperties) > 0 }) {
+			return
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(object)) {
+			declared := false
+			for _, s := range schemas {
+				if property, ok := s.Properties[key]; ok {
+					usage.used = append(usage.used, property.Location)
+					declared = true
+				}
+				for re := range s.PatternProperties {
+					if re.MatchString(key) {
+						declared = true
+					}
+				}
+			}
+
+			if !declared {
+				usage.additional = append(usage.additional, coverageKey{
+					File:     file,
+					Document: document,
+					Pointer:  pointer + "/" + escapePointerToken(key),
+				})
+			}
+		}
+	})
+
+	return usage
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const testCoverageSchema = `{
+  "type": "object",
+  "properties": {
+    "id": { "type": "string" },
+    "legacy_id": { "type": "integer" },
+    "owner": { "$ref": "#/$defs/owner" },
+    "labels": { "type": "object", "additionalProperties": { "type": "string" } }
+  },
+  "patternProperties": { "^x-": true },
+  "$defs": {
+    "owner": {
+      "properties": { "name": { "type": "string" }, "email": { "type": "string" } }
+    }
+  }
+}`
+
+func TestPropertyCoverage(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(testCoverageSchema))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource("schema.json", schema))
+	sch, err := compiler.Compile("schema.json")
+	require.NoError(t, err)
+
+	coverage := newPropertyCoverage(sch)
+	for i, document := range []map[string]any{
+		{"id": "web", "owner": map[string]any{"name": "Web", "team": "platform"}, "labels": map[string]any{"tier": "frontend"}},
+		{"id": "db", "x-note": "managed", "region": "eu"},
+	} {
+		coverage.add(documentPropertyUsage("values.yaml", i, sch, document))
+	}
+
+	require.Equal(t, coverageReport{
+		UnusedProperties: []string{"#/$defs/owner/properties/email", "#/properties/legacy_id"},
+		AdditionalKeys: []coverageKey{
+			{File: "values.yaml", Document: 0, Pointer: "/owner/team"},
+			{File: "values.yaml", Document: 1, Pointer: "/region"},
+		},
+	}, coverage.report())
+}
+
+func TestExportPropertyCoverageYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"schema.json":  testCoverageSchema,
+		"web.yaml":     "# yaml-language-server: $schema=./schema.json\nid: web\nowner:\n  name: Web\n  email: web@example.com\n",
+		"worker.yaml":  "# yaml-language-server: $schema=./schema.json\nid: worker\nlegacy_id: 7\nregion: eu\n",
+		"invalid.yaml": "# yaml-language-server: $schema=./schema.json\nid: 42\nzone: b\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "schema.json"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccExportPropertyCoverageConfig, filepath.Join(tmpDir, "*.yaml"), false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("property_coverage_json"),
+						knownvalue.MapSizeExact(0),
+					),
+				},
+			},
+			// the invalid file is left out
+			{
+				Config: fmt.Sprintf(testAccExportPropertyCoverageConfig, filepath.Join(tmpDir, "*.yaml"), true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("property_coverage_json").AtMapKey(schemaPath),
+						knownvalue.StringExact(fmt.Sprintf(
+							`{"unused_properties":["#/properties/labels"],"additional_keys":[{"file":%q,"document":0,"pointer":"/region"}]}`,
+							filepath.Join(tmpDir, "worker.yaml"),
+						)),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccExportPropertyCoverageConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern            = "%s"
+  fail_on_invalid          = false
+  export_property_coverage = %t
+}
+`
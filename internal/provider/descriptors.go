@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	bufScheme   = "buf"
+	grpcScheme  = "grpc"
+	grpcsScheme = "grpcs"
+
+	// bufReflectionPath is the Connect endpoint of the reflection API of the
+	// Buf Schema Registry.
+	bufReflectionPath = "/buf.reflect.v1beta1.FileDescriptorSetService/GetFileDescriptorSet"
+)
+
+// BufConfigModel describes the connection to the Buf Schema Registry.
+type BufConfigModel struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Token    types.String `tfsdk:"token"`
+}
+
+// descriptorSources loads file descriptor sets from files, the Buf Schema
+// Registry and gRPC servers with the reflection service.
+type descriptorSources struct {
+	buf    BufConfigModel
+	client *http.Client
+}
+
+func newDescriptorSources(buf *BufConfigModel) *descriptorSources {
+	sources := &descriptorSources{client: &http.Client{Timeout: httpLoadTimeout}}
+	if buf != nil {
+		sources.buf = *buf
+	}
+	return sources
+}
+
+// load returns the files of the descriptor set at location with the files
+// message needs: a path of a binary FileDescriptorSet,
+// buf://registry/owner/module[:version] of a module of the Buf Schema
+// Registry or grpc://host:port and grpcs://host:port of a gRPC server with
+// the reflection service.
+func (s *descriptorSources) load(ctx context.Context, location, message string) (*protoregistry.Files, error) {
+	if !urlRegex.MatchString(location) {
+		return readDescriptorSet(location)
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	var set *descriptorpb.FileDescriptorSet
+	switch u.Scheme {
+	case bufScheme:
+		set, err = s.bufDescriptorSet(ctx, u, message)
+	case grpcScheme, grpcsScheme:
+		set, err = grpcDescriptorSet(ctx, u, message)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %s, descriptor sets are files or %s://, %s:// and %s:// URLs", u.Scheme, bufScheme, grpcScheme, grpcsScheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return protodesc.NewFiles(set)
+}
+
+// bufDescriptorSet requests the files of the module u refers to that
+// message needs from the reflection API of the Buf Schema Registry.
+func (s *descriptorSources) bufDescriptorSet(ctx context.Context, u *url.URL, message string) (*descriptorpb.FileDescriptorSet, error) {
+	modulePath, version, _ := strings.Cut(u.Path, ":")
+	module := u.Host + modulePath
+	if strings.Count(module, "/") != 2 {
+		return nil, fmt.Errorf("%s is not a module of the Buf Schema Registry, expected buf://registry/owner/module[:version]", u.Redacted())
+	}
+
+	// modules are served by the registry they are named after
+	endpoint := s.buf.Endpoint.ValueString()
+	if endpoint == "" {
+		endpoint = "https://" + u.Host
+	}
+
+	// the default is read from BUF_TOKEN like the buf CLI does
+	token := s.buf.Token.ValueString()
+	if token == "" {
+		token = os.Getenv("BUF_TOKEN")
+	}
+
+	body, err := json.Marshal(map[string]any{"module": module, "version": version, "symbols": []string{message}})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+bufReflectionPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// errors of the Connect protocol have a code and a message
+		var connectErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(content, &connectErr) == nil && connectErr.Code != "" {
+			return nil, fmt.Errorf("%s returned %s: %s: %s", req.URL.Redacted(), resp.Status, connectErr.Code, connectErr.Message)
+		}
+		return nil, fmt.Errorf("%s returned %s: %s", req.URL.Redacted(), resp.Status, strings.TrimSpace(string(content)))
+	}
+
+	var response struct {
+		FileDescriptorSet json.RawMessage `json:"fileDescriptorSet"`
+	}
+	if err := json.Unmarshal(content, &response); err != nil {
+		return nil, fmt.Errorf("could not decode response of %s: %w", req.URL.Redacted(), err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := protojson.Unmarshal(response.FileDescriptorSet, &set); err != nil {
+		return nil, fmt.Errorf("could not decode file descriptor set of %s: %w", module, err)
+	}
+
+	return &set, nil
+}
+
+// grpcDescriptorSet requests the file defining message and the files it
+// imports from the reflection service of the gRPC server u refers to, with
+// TLS for grpcs://.
+func grpcDescriptorSet(ctx context.Context, u *url.URL, message string) (*descriptorpb.FileDescriptorSet, error) {
+	creds := insecure.NewCredentials()
+	if u.Scheme == grpcsScheme {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+
+	conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, httpLoadTimeout)
+	defer cancel()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the reflection service of %s: %w", u.Host, err)
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	set := &descriptorpb.FileDescriptorSet{}
+	files := make(map[string]bool)
+	requested := make(map[string]bool)
+
+	pending := []*reflectionpb.ServerReflectionRequest{{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: message},
+	}}
+
+	for len(pending) > 0 {
+		if err := stream.Send(pending[0]); err != nil {
+			return nil, fmt.Errorf("could not request descriptors of %s: %w", u.Host, err)
+		}
+		pending = pending[1:]
+
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("could not receive descriptors of %s: %w", u.Host, err)
+		}
+
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return nil, fmt.Errorf("reflection service of %s returned %s", u.Host, errResp.GetErrorMessage())
+		}
+
+		// the response has the requested file and may have some of its dependencies
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			var file descriptorpb.FileDescriptorProto
+			if err := proto.Unmarshal(raw, &file); err != nil {
+				return nil, fmt.Errorf("could not decode descriptor of %s: %w", u.Host, err)
+			}
+
+			if files[file.GetName()] {
+				continue
+			}
+			files[file.GetName()] = true
+			set.File = append(set.File, &file)
+
+			for _, dependency := range file.GetDependency() {
+				if !files[dependency] && !requested[dependency] {
+					requested[dependency] = true
+					pending = append(pending, &reflectionpb.ServerReflectionRequest{
+						MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dependency},
+					})
+				}
+			}
+		}
+	}
+
+	return set, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestBufServer mocks the reflection API of the Buf Schema Registry,
+// serving the test descriptor set for the module buf.build/acme/shop to
+// requests with token.
+func newTestBufServer(t *testing.T, token string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		connectError := func(status int, code, message string) {
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": code, "message": message})
+		}
+
+		if r.Method != http.MethodPost || r.URL.Path != bufReflectionPath {
+			connectError(http.StatusNotFound, "unimplemented", r.URL.Path+" is not implemented")
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			connectError(http.StatusUnauthorized, "unauthenticated", "invalid token")
+			return
+		}
+
+		var request struct {
+			Module  string   `json:"module"`
+			Version string   `json:"version"`
+			Symbols []string `json:"symbols"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Module != "buf.build/acme/shop" || request.Version != "v1" {
+			connectError(http.StatusNotFound, "not_found", "module not found")
+			return
+		}
+
+		set, err := protojson.Marshal(testDescriptorSet())
+		require.NoError(t, err)
+
+		_ = json.NewEncoder(w).Encode(map[string]json.RawMessage{"fileDescriptorSet": set})
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newTestReflectionServer starts a gRPC server whose reflection service
+// serves the test descriptor set and returns its address.
+func newTestReflectionServer(t *testing.T) string {
+	files, err := protodesc.NewFiles(testDescriptorSet())
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	reflectionpb.RegisterServerReflectionServer(server, reflection.NewServerV1(reflection.ServerOptions{DescriptorResolver: files}))
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestDescriptorSources(t *testing.T) {
+	ctx := context.Background()
+
+	server := newTestBufServer(t, "buf-token")
+
+	sources := newDescriptorSources(&BufConfigModel{Endpoint: types.StringValue(server.URL), Token: types.StringValue("buf-token")})
+
+	files, err := sources.load(ctx, "buf://buf.build/acme/shop:v1", "shop.v1.Order")
+	require.NoError(t, err)
+
+	_, err = newProtobufMessage(files, "shop.v1.Order")
+	require.NoError(t, err)
+
+	_, err = sources.load(ctx, "buf://buf.build/acme/missing", "shop.v1.Order")
+	require.ErrorContains(t, err, "returned 404 Not Found: not_found: module not found")
+
+	_, err = sources.load(ctx, "buf://buf.build/acme", "shop.v1.Order")
+	require.ErrorContains(t, err, "is not a module of the Buf Schema Registry")
+
+	// the token defaults to BUF_TOKEN
+	t.Setenv("BUF_TOKEN", "other-token")
+	_, err = newDescriptorSources(&BufConfigModel{Endpoint: types.StringValue(server.URL)}).load(ctx, "buf://buf.build/acme/shop:v1", "shop.v1.Order")
+	require.ErrorContains(t, err, "unauthenticated: invalid token")
+
+	// the imports of the file are requested from the reflection service too
+	address := newTestReflectionServer(t)
+
+	files, err = sources.load(ctx, "grpc://"+address, "shop.v1.Order")
+	require.NoError(t, err)
+	require.Equal(t, 2, files.NumFiles())
+
+	_, err = sources.load(ctx, "grpc://"+address, "shop.v1.Missing")
+	require.ErrorContains(t, err, "reflection service of "+address+" returned")
+
+	_, err = sources.load(ctx, "https://example.com/descriptors.binpb", "shop.v1.Order")
+	require.ErrorContains(t, err, "unsupported scheme https")
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"io/fs"
+	"strings"
+)
+
+// embeddedSchemaPrefix is the URN prefix of the schemas bundled with the
+// provider, e.g. urn:jsonschema:json-patch.
+const embeddedSchemaPrefix = "urn:jsonschema:"
+
+//go:embed schemas/*.json
+var embeddedSchemas embed.FS
+
+// metaSchemaAliases maps the URN names of the draft meta-schemas to their
+// URLs, which the compiler resolves without network access.
+var metaSchemaAliases = map[string]string{
+	"draft-04":      "http://json-schema.org/draft-04/schema#",
+	"draft-06":      "http://json-schema.org/draft-06/schema#",
+	"draft-07":      "http://json-schema.org/draft-07/schema#",
+	"draft-2019-09": "https://json-schema.org/draft/2019-09/schema",
+	"draft-2020-12": "https://json-schema.org/draft/2020-12/schema",
+}
+
+// embeddedLoader loads the schemas bundled with the provider by URN.
+type embeddedLoader struct{}
+
+// Ensure embeddedLoader can load schemas.
+var _ jsonschema.URLLoader = embeddedLoader{}
+
+func (embeddedLoader) Load(url string) (any, error) {
+	content, err := embeddedLoader{}.LoadRaw(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonschema.UnmarshalJSON(bytes.NewReader(content))
+}
+
+func (embeddedLoader) LoadRaw(url string) ([]byte, error) {
+	name, ok := strings.CutPrefix(url, embeddedSchemaPrefix)
+	if !ok {
+		return nil, fmt.Errorf("unsupported URN %q, expected %s<name>", url, embeddedSchemaPrefix)
+	}
+
+	if meta, ok := metaSchemaAliases[name]; ok {
+		return json.Marshal(map[string]any{"$ref": meta})
+	}
+
+	content, err := embeddedSchemas.ReadFile("schemas/" + name + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no schema named %q is bundled with the provider", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
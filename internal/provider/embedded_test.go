@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedSchemas(t *testing.T) {
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(jsonschema.SchemeURLLoader{"urn": embeddedLoader{}})
+	compiler.AssertFormat()
+
+	files, err := fs.Glob(embeddedSchemas, "schemas/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			_, err := compiler.Compile(embeddedSchemaPrefix + name)
+			require.NoError(t, err)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		doc     any
+		wantErr bool
+	}{
+		{
+			name: "draft-2020-12",
+			doc:  map[string]any{"type": "object"},
+		},
+		{
+			name:    "draft-07",
+			doc:     map[string]any{"type": "unknown"},
+			wantErr: true,
+		},
+		{
+			name: "json-patch",
+			doc:  []any{map[string]any{"op": "replace", "path": "/a", "value": 1}},
+		},
+		{
+			name:    "json-patch",
+			doc:     []any{map[string]any{"op": "copy", "path": "/a"}},
+			wantErr: true,
+		},
+		{
+			name: "json-api-error",
+			doc:  map[string]any{"errors": []any{map[string]any{"status": "404", "title": "Not Found"}}},
+		},
+		{
+			name: "argocd",
+			doc: map[string]any{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "Application",
+				"metadata":   map[string]any{"name": "guestbook"},
+				"spec": map[string]any{
+					"project":     "default",
+					"source":      map[string]any{"repoURL": "https://github.com/argoproj/argocd-example-apps", "path": "guestbook"},
+					"destination": map[string]any{"server": "https://kubernetes.default.svc", "namespace": "guestbook"},
+				},
+			},
+		},
+		{
+			name: "argocd",
+			doc: map[string]any{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "ApplicationSet",
+				"metadata":   map[string]any{"name": "guestbook"},
+				"spec": map[string]any{
+					"generators": []any{map[string]any{"list": map[string]any{"elements": []any{}}}},
+					"template":   map[string]any{"metadata": map[string]any{}, "spec": map[string]any{"project": "default"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "argocd",
+			doc:  map[string]any{"apiVersion": "v1", "kind": "ConfigMap"},
+		},
+		{
+			name: "flux",
+			doc: map[string]any{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+				"kind":       "Kustomization",
+				"metadata":   map[string]any{"name": "apps"},
+				"spec": map[string]any{
+					"interval":  "10m",
+					"prune":     true,
+					"sourceRef": map[string]any{"kind": "GitRepository", "name": "flux-system"},
+				},
+			},
+		},
+		{
+			name: "flux",
+			doc: map[string]any{
+				"apiVersion": "helm.toolkit.fluxcd.io/v2",
+				"kind":       "HelmRelease",
+				"metadata":   map[string]any{"name": "podinfo"},
+				"spec":       map[string]any{"interval": "ten minutes"},
+			},
+			wantErr: true,
+		},
+		{
+			// the Kustomization of kustomize shares the kind of Flux
+			name: "flux",
+			doc:  map[string]any{"apiVersion": "kustomize.config.k8s.io/v1beta1", "kind": "Kustomization", "resources": []any{"deployment.yaml"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch, err := compiler.Compile(embeddedSchemaPrefix + tt.name)
+			require.NoError(t, err)
+
+			if tt.wantErr {
+				require.Error(t, sch.Validate(tt.doc))
+			} else {
+				require.NoError(t, sch.Validate(tt.doc))
+			}
+		})
+	}
+
+	_, err = compiler.Compile(embeddedSchemaPrefix + "unknown")
+	require.ErrorContains(t, err, "no schema named")
+}
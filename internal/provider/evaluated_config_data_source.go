@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/load"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-jsonnet"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	languageJsonnet = "jsonnet"
+	languageCUE     = "cue"
+)
+
+// languageExtensions maps file extensions to the language used to evaluate them.
+var languageExtensions = map[string]string{
+	".jsonnet":   languageJsonnet,
+	".libsonnet": languageJsonnet,
+	".cue":       languageCUE,
+}
+
+func NewEvaluatedConfigDataSource() datasource.DataSource {
+	return &EvaluatedConfigDataSource{}
+}
+
+// EvaluatedConfigDataSource defines the data source implementation.
+type EvaluatedConfigDataSource struct {
+	compiler *schemaCompiler
+}
+
+// EvaluatedConfigDataSourceModel describes the data source data model.
+type EvaluatedConfigDataSourceModel struct {
+	InputPattern types.String `tfsdk:"input_pattern"`
+	Schema       types.String `tfsdk:"schema"`
+	Language     types.String `tfsdk:"language"`
+	ExtVars      types.Map    `tfsdk:"ext_vars"`
+	Decoded      types.Map    `tfsdk:"decoded"`
+}
+
+func (d *EvaluatedConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_evaluated_config"
+}
+
+func (d *EvaluatedConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Jsonnet or CUE sources evaluated into JSON and validated against a json schema.",
+
+		Attributes: map[string]schema.Attribute{
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of Jsonnet or CUE files to evaluate and validate",
+				Required:    true,
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path of the json schema the result of every file is validated against",
+				Required:    true,
+			},
+			"language": schema.StringAttribute{
+				MarkdownDescription: "Language of the files, `jsonnet` or `cue`. Defaults to `jsonnet` for `.jsonnet` and `.libsonnet` files and `cue` for `.cue` files.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(languageJsonnet, languageCUE),
+				},
+			},
+			"ext_vars": schema.MapAttribute{
+				MarkdownDescription: "External variables available to Jsonnet files via `std.extVar`",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"decoded": schema.MapAttribute{
+				Description: "Map of file paths to the JSON encoded validated result of the evaluation",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *EvaluatedConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *EvaluatedConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data EvaluatedConfigDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	extVars := make(map[string]string)
+	resp.Diagnostics.Append(data.ExtVars.ElementsAs(ctx, &extVars, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	decodedMap := make(map[string]string)
+	for _, file := range files {
+		language := data.Language.ValueString()
+		if language == "" {
+			language = languageExtensions[strings.ToLower(filepath.Ext(file))]
+		}
+
+		var output []byte
+		switch language {
+		case languageJsonnet:
+			output, err = evaluateJsonnet(file, extVars)
+		case languageCUE:
+			output, err = evaluateCUE(file)
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("language"),
+				"Unknown language",
+				"Could not determine the language of file "+file+", set the language attribute to jsonnet or cue",
+			)
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error evaluating "+language,
+				"Could not evaluate "+language+" file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		value, err := jsonschema.UnmarshalJSON(bytes.NewReader(output))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error decoding JSON",
+				"Could not decode result of file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		err = compiledSchema.Validate(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error validating "+language,
+				"Result of file "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
+			)
+			continue
+		}
+
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, output); err != nil {
+			resp.Diagnostics.AddError(
+				"Error encoding JSON",
+				"Could not encode result of file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		decodedMap[file] = compact.String()
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	decoded, diag := types.MapValueFrom(ctx, types.StringType, decodedMap)
+	resp.Diagnostics.Append(diag...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Decoded = decoded
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// evaluateJsonnet evaluates a Jsonnet file, imports are resolved relative
+// to the directory of the file.
+func evaluateJsonnet(file string, extVars map[string]string) ([]byte, error) {
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.FileImporter{JPaths: []string{filepath.Dir(file)}})
+
+	for k, v := range extVars {
+		vm.ExtVar(k, v)
+	}
+
+	output, err := vm.EvaluateFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(output), nil
+}
+
+// evaluateCUE evaluates a CUE file, which must be concrete, i.e. fully
+// resolve to data.
+func evaluateCUE(file string) ([]byte, error) {
+	instances := load.Instances([]string{filepath.Base(file)}, &load.Config{Dir: filepath.Dir(file)})
+	if len(instances) != 1 {
+		return nil, fmt.Errorf("expected a single instance, got %d", len(instances))
+	}
+
+	if err := instances[0].Err; err != nil {
+		return nil, err
+	}
+
+	value := cuecontext.New().BuildInstance(instances[0])
+	if err := value.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := value.Validate(cue.Concrete(true)); err != nil {
+		return nil, err
+	}
+
+	return value.MarshalJSON()
+}
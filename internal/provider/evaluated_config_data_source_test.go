@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestEvaluatedJsonnet(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "common.libsonnet"), []byte(`{ tags: ["tag1", "tag2"] }`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "example.jsonnet"), []byte(`
+local common = import "common.libsonnet";
+{
+  id: "example-" + std.extVar("env"),
+  name: "Example Name",
+  tags: common.tags,
+}
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccEvaluatedConfigDataSourceExtVarsConfig, filepath.Join(tmpDir, "*.jsonnet"), schemaPath),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_evaluated_config.test",
+						tfjsonpath.New("decoded").AtMapKey(filepath.Join(tmpDir, "example.jsonnet")),
+						knownvalue.StringExact(`{"id":"example-prod","name":"Example Name","tags":["tag1","tag2"]}`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestEvaluatedCUE(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.cue"), []byte(`
+_base: {
+	name: string | *"Example Name"
+}
+
+_base & {
+	id: "example-id"
+}
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccEvaluatedConfigDataSourceConfig, filepath.Join(tmpDir, "*.cue"), schemaPath),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_evaluated_config.test",
+						tfjsonpath.New("decoded").AtMapKey(filepath.Join(tmpDir, "example.cue")),
+						knownvalue.StringExact(`{"id":"example-id","name":"Example Name"}`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestInvalidEvaluatedConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.jsonnet"), []byte(`{ id: 12345, name: "Example Name" }`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config:      fmt.Sprintf(testAccEvaluatedConfigDataSourceConfig, filepath.Join(tmpDir, "*.jsonnet"), schemaPath),
+				ExpectError: regexp.MustCompile(`- at '/id': got number, want string`),
+			},
+		},
+	})
+}
+
+const (
+	testAccEvaluatedConfigDataSourceConfig = `
+data "jsonschema_evaluated_config" "test" {
+  input_pattern = "%s"
+  schema        = "%s"
+}
+`
+	testAccEvaluatedConfigDataSourceExtVarsConfig = `
+data "jsonschema_evaluated_config" "test" {
+  input_pattern = "%s"
+  schema        = "%s"
+
+  ext_vars = {
+    env = "prod"
+  }
+}
+`
+)
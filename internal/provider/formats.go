@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeStrict  = "strict"
+	dateTimeLenient = "lenient"
+)
+
+// FormatsConfigModel describes the format assertions of the provider.
+type FormatsConfigModel struct {
+	DateTime types.String `tfsdk:"date_time"`
+	Assert   types.Bool   `tfsdk:"assert"`
+}
+
+// configureFormats registers the formats of config with compiler.
+func configureFormats(compiler *jsonschema.Compiler, config *FormatsConfigModel) {
+	if config == nil {
+		return
+	}
+
+	// formats are only asserted by default up to draft-07
+	if config.Assert.ValueBool() {
+		compiler.AssertFormat()
+	}
+
+	if config.DateTime.ValueString() == dateTimeLenient {
+		compiler.RegisterFormat(&jsonschema.Format{Name: "date-time", Validate: validateLenientDateTime})
+		compiler.RegisterFormat(&jsonschema.Format{Name: "date", Validate: validateLenientDate})
+		compiler.RegisterFormat(&jsonschema.Format{Name: "duration", Validate: validateLenientDuration})
+	}
+}
+
+// lenientDateTimeLayouts are the ISO 8601 date-times accepted in lenient
+// mode, after the date and time are separated by T.
+var lenientDateTimeLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+}
+
+// validateLenientDateTime accepts RFC 3339 date-times as well as ISO 8601
+// date-times separated by a space, without seconds or without an offset.
+func validateLenientDateTime(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	if len(s) > 10 && (s[10] == ' ' || s[10] == 't') {
+		s = s[:10] + "T" + s[11:]
+	}
+	s = strings.Replace(strings.ToUpper(s), ":60", ":59", 1)
+
+	for _, layout := range lenientDateTimeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return nil
+		}
+	}
+
+	return errors.New("not a valid ISO 8601 date-time")
+}
+
+// validateLenientDate accepts full dates in the extended (2006-01-02) and
+// basic (20060102) formats of ISO 8601.
+func validateLenientDate(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "20060102"} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return nil
+		}
+	}
+
+	return errors.New("not a valid ISO 8601 date")
+}
+
+// lenientDurationRegex matches ISO 8601 durations with decimal fractions and
+// weeks combined with other units.
+var lenientDurationRegex = regexp.MustCompile(`^P(?:\d+(?:[.,]\d+)?[YMWD])*(?:T(?:\d+(?:[.,]\d+)?[HMS])+)?$`)
+
+// validateLenientDuration accepts ISO 8601 durations as well as durations
+// like 1h30m.
+func validateLenientDuration(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	if s != "P" && lenientDurationRegex.MatchString(s) {
+		return nil
+	}
+
+	if _, err := time.ParseDuration(s); err == nil {
+		return nil
+	}
+
+	return errors.New("not a valid ISO 8601 or Go duration")
+}
@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLenientFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(v any) error
+		value    string
+		valid    bool
+	}{
+		{name: "rfc 3339 date-time", validate: validateLenientDateTime, value: "2024-01-02T10:30:00Z", valid: true},
+		{name: "date-time with space", validate: validateLenientDateTime, value: "2024-01-02 10:30:00+01:00", valid: true},
+		{name: "date-time without offset", validate: validateLenientDateTime, value: "2024-01-02T10:30:00.5", valid: true},
+		{name: "date-time without seconds", validate: validateLenientDateTime, value: "2024-01-02 10:30", valid: true},
+		{name: "date-time with leap second", validate: validateLenientDateTime, value: "2016-12-31T23:59:60Z", valid: true},
+		{name: "date only", validate: validateLenientDateTime, value: "2024-01-02", valid: false},
+		{name: "invalid date-time", validate: validateLenientDateTime, value: "2024-13-02 10:30", valid: false},
+		{name: "extended date", validate: validateLenientDate, value: "2024-01-02", valid: true},
+		{name: "basic date", validate: validateLenientDate, value: "20240102", valid: true},
+		{name: "invalid date", validate: validateLenientDate, value: "2024-02-30", valid: false},
+		{name: "iso duration", validate: validateLenientDuration, value: "P1DT2H", valid: true},
+		{name: "fractional duration", validate: validateLenientDuration, value: "PT1.5H", valid: true},
+		{name: "go duration", validate: validateLenientDuration, value: "1h30m", valid: true},
+		{name: "empty duration", validate: validateLenientDuration, value: "P", valid: false},
+		{name: "invalid duration", validate: validateLenientDuration, value: "1 hour", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.validate(test.value)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+
+	require.NoError(t, validateLenientDateTime(1))
+}
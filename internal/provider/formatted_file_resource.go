@@ -0,0 +1,530 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ensure FormattedFileResource satisfies various resource interfaces.
+var _ resource.Resource = &FormattedFileResource{}
+var _ resource.ResourceWithConfigure = &FormattedFileResource{}
+
+func NewFormattedFileResource() resource.Resource {
+	return &FormattedFileResource{}
+}
+
+// FormattedFileResource defines the resource implementation.
+type FormattedFileResource struct {
+	compiler *schemaCompiler
+}
+
+// FormattedFileResourceModel describes the resource data model.
+type FormattedFileResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Path    types.String `tfsdk:"path"`
+	Schema  types.String `tfsdk:"schema"`
+	Indent  types.Int64  `tfsdk:"indent"`
+	Content types.String `tfsdk:"content"`
+}
+
+func (r *FormattedFileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_formatted_file"
+}
+
+func (r *FormattedFileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Rewrites a YAML file into canonical form: keys are ordered as the properties are declared in the json schema " +
+			"and indentation is made consistent. The file is left in place when the resource is destroyed.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Path of the formatted file",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the YAML file to format",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path or URL of the json schema defining the key order, defaults to the schema referenced in the first line of the file",
+				Optional:    true,
+			},
+			"indent": schema.Int64Attribute{
+				Description: "Number of spaces used for indentation, between 2 and 9",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(2),
+				Validators: []validator.Int64{
+					int64validator.Between(2, 9),
+				},
+			},
+			"content": schema.StringAttribute{
+				Description: "Formatted content written to the file",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *FormattedFileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+}
+
+func (r *FormattedFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FormattedFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(&data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FormattedFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FormattedFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	contentRaw, err := os.ReadFile(data.Path.ValueString())
+	if os.IsNotExist(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading file",
+			"Could not read file "+data.Path.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// The file was edited outside of Terraform, so it has to be formatted again.
+	if string(contentRaw) != data.Content.ValueString() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FormattedFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FormattedFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(&data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FormattedFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The formatted file is owned by the repository, so it is kept on disk.
+}
+
+// write formats the file at data.Path, writes it back if it changed and
+// fills in the computed attributes of data.
+func (r *FormattedFileResource) write(data *FormattedFileResourceModel, diags *diag.Diagnostics) {
+	file := data.Path.ValueString()
+
+	fi, err := os.Stat(file)
+	if err != nil {
+		diags.AddError(
+			"Error opening file",
+			"Could not open file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	contentRaw, err := os.ReadFile(file)
+	if err != nil {
+		diags.AddError(
+			"Error reading file",
+			"Could not read file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	content := string(contentRaw)
+
+	modeline := ""
+	schemaPath := data.Schema.ValueString()
+
+	// the schema reference is kept as the first line of the formatted file
+	matches := schemaRegex.FindStringSubmatchIndex(content)
+	if len(matches) == 4 {
+		modeline = content[matches[0]:matches[1]]
+		if schemaPath == "" {
+			schemaPath = resolveSchemaReference(file, content[matches[2]:matches[3]])
+		}
+		content = content[:matches[0]] + content[matches[1]:]
+	}
+
+	if schemaPath == "" {
+		diags.AddError(
+			"Error formatting file",
+			"File "+file+" does not contain a valid schema reference in the first line, e.g. '# yaml-language-server: $schema=path', and no schema was provided",
+		)
+		return
+	}
+
+	compiledSchema, err := r.compiler.Compile(schemaPath)
+	if err != nil {
+		diags.AddError(
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+" for file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	var nodes []*yaml.Node
+
+	// every document of the file is ordered and written back
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var node yaml.Node
+		err = decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			diags.AddError(
+				"Error decoding YAML",
+				"Could not decode YAML file "+file+": "+err.Error(),
+			)
+			return
+		}
+		nodes = append(nodes, &node)
+	}
+
+	order := &propertyOrder{loader: r.compiler.loader, documents: make(map[string]*yaml.Node)}
+
+	for _, node := range nodes {
+		err = order.apply(node, compiledSchema)
+		if err != nil {
+			diags.AddError(
+				"Error formatting file",
+				"Could not order keys of file "+file+" by schema "+schemaPath+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+
+	if modeline != "" {
+		buf.WriteString(modeline + "\n")
+	}
+
+	// an empty file has nothing to encode
+	if len(nodes) > 0 {
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(int(data.Indent.ValueInt64()))
+
+		for _, node := range nodes {
+			if err = encoder.Encode(node); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = encoder.Close()
+		}
+		if err != nil {
+			diags.AddError(
+				"Error encoding YAML",
+				"Could not encode YAML file "+file+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	formatted := buf.String()
+
+	if formatted != string(contentRaw) {
+		err = os.WriteFile(file, []byte(formatted), fi.Mode().Perm())
+		if err != nil {
+			diags.AddError(
+				"Error writing file",
+				"Could not write file "+file+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	data.ID = types.StringValue(file)
+	data.Content = types.StringValue(formatted)
+}
+
+// propertyOrder sorts YAML mappings by the order in which properties are
+// declared in the schema source, which compiled schemas do not retain.
+type propertyOrder struct {
+	// loader loads the schema sources like the compiler of the provider.
+	loader jsonschema.URLLoader
+	// documents caches schema sources by URL, decoded as YAML to keep key order.
+	documents map[string]*yaml.Node
+}
+
+func (o *propertyOrder) apply(node *yaml.Node, sch *jsonschema.Schema) error {
+	if sch == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := o.apply(child, sch); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		schemas := expandSchema(sch)
+
+		var keys []string
+		for _, s := range schemas {
+			declared, err := o.keys(s)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, declared...)
+		}
+
+		rank := make(map[string]int, len(keys))
+		for _, key := range keys {
+			if _, ok := rank[key]; !ok {
+				rank[key] = len(rank)
+			}
+		}
+
+		pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+		}
+
+		// keys missing from the schema keep their relative order after the declared ones
+		sort.SliceStable(pairs, func(i, j int) bool {
+			ri, ok := rank[pairs[i][0].Value]
+			if !ok {
+				ri = len(rank)
+			}
+			rj, ok := rank[pairs[j][0].Value]
+			if !ok {
+				rj = len(rank)
+			}
+			return ri < rj
+		})
+
+		for i, pair := range pairs {
+			node.Content[2*i], node.Content[2*i+1] = pair[0], pair[1]
+
+			if err := o.apply(pair[1], propertySchema(schemas, pair[0].Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		item := itemSchema(expandSchema(sch))
+		for _, child := range node.Content {
+			if err := o.apply(child, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// keys returns the property names of sch in declaration order.
+func (o *propertyOrder) keys(sch *jsonschema.Schema) ([]string, error) {
+	if len(sch.Properties) == 0 {
+		return nil, nil
+	}
+
+	loc, ptr, _ := strings.Cut(sch.Location, "#")
+
+	doc, ok := o.documents[loc]
+	if !ok {
+		raw, err := loadRaw(o.loader, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		doc = &yaml.Node{}
+		if err := yaml.Unmarshal(raw, doc); err != nil {
+			return nil, err
+		}
+
+		o.documents[loc] = doc
+	}
+
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, token := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		if token == "" || node == nil {
+			continue
+		}
+		node = childNode(node, strings.NewReplacer("~1", "/", "~0", "~").Replace(token))
+	}
+
+	properties := childNode(node, "properties")
+	if properties == nil || properties.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(properties.Content)/2)
+	for i := 0; i < len(properties.Content); i += 2 {
+		keys = append(keys, properties.Content[i].Value)
+	}
+
+	return keys, nil
+}
+
+// childNode returns the value under key of a mapping node or the
+// element at index key of a sequence node.
+func childNode(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(key)
+		if err == nil && i >= 0 && i < len(node.Content) {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
+
+// expandSchema returns sch together with the schemas it references via
+// $ref and allOf, which apply to the same instance.
+func expandSchema(sch *jsonschema.Schema) []*jsonschema.Schema {
+	var schemas []*jsonschema.Schema
+
+	seen := make(map[*jsonschema.Schema]bool)
+
+	var visit func(s *jsonschema.Schema)
+	visit = func(s *jsonschema.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		schemas = append(schemas, s)
+
+		visit(s.Ref)
+		for _, sub := range s.AllOf {
+			visit(sub)
+		}
+	}
+
+	visit(sch)
+
+	return schemas
+}
+
+func propertySchema(schemas []*jsonschema.Schema, key string) *jsonschema.Schema {
+	for _, s := range schemas {
+		if sub, ok := s.Properties[key]; ok {
+			return sub
+		}
+	}
+
+	for _, s := range schemas {
+		if sub, ok := s.AdditionalProperties.(*jsonschema.Schema); ok {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+func itemSchema(schemas []*jsonschema.Schema) *jsonschema.Schema {
+	for _, s := range schemas {
+		if s.Items2020 != nil {
+			return s.Items2020
+		}
+		if sub, ok := s.Items.(*jsonschema.Schema); ok {
+			return sub
+		}
+	}
+
+	return nil
+}
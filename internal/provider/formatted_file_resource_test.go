@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestFormattedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "example.yaml")
+
+	err := os.WriteFile(file, []byte(`
+# yaml-language-server: $schema=./schema.json
+extra: true
+tags:
+      - "tag1"
+      - "tag2"
+name: "Example Name" # display name
+id: "example-id"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	formatted := `# yaml-language-server: $schema=./schema.json
+id: "example-id"
+name: "Example Name" # display name
+tags:
+  - "tag1"
+  - "tag2"
+extra: true
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fmt.Sprintf(testAccFormattedFileResourceConfig, file),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"jsonschema_formatted_file.test",
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(formatted),
+					),
+				},
+				Check: testAccCheckFileContent(file, formatted),
+			},
+			// Update testing
+			{
+				Config: fmt.Sprintf(testAccFormattedFileResourceIndentConfig, file),
+				Check: testAccCheckFileContent(file, `# yaml-language-server: $schema=./schema.json
+id: "example-id"
+name: "Example Name" # display name
+tags:
+    - "tag1"
+    - "tag2"
+extra: true
+`),
+			},
+		},
+	})
+}
+
+func TestFormattedFileDrift(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "example.yaml")
+
+	err := os.WriteFile(file, []byte("name: \"Example Name\"\nid: \"example-id\"\n"), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	formatted := "id: \"example-id\"\nname: \"Example Name\"\n"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccFormattedFileResourceSchemaConfig, file, schemaPath),
+				Check:  testAccCheckFileContent(file, formatted),
+			},
+			// Files edited outside of Terraform are formatted again
+			{
+				PreConfig: func() {
+					err := os.WriteFile(file, []byte("name: \"Example Name\"\nid: \"example-id\"\n"), 0644)
+					require.NoError(t, err)
+				},
+				Config: fmt.Sprintf(testAccFormattedFileResourceSchemaConfig, file, schemaPath),
+				Check:  testAccCheckFileContent(file, formatted),
+			},
+		},
+	})
+}
+
+func TestFormattedFileMultipleDocuments(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "example.yaml")
+
+	err := os.WriteFile(file, []byte("# yaml-language-server: $schema=./schema.json\nname: \"First\"\nid: \"first\"\n---\nname: \"Second\" # kept\nid: \"second\"\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccFormattedFileResourceConfig, file),
+				Check:  testAccCheckFileContent(file, "# yaml-language-server: $schema=./schema.json\nid: \"first\"\nname: \"First\"\n---\nid: \"second\"\nname: \"Second\" # kept\n"),
+			},
+		},
+	})
+}
+
+func TestFormattedFileLoadedSchemas(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"properties": {"name": {"type": "string"}, "id": {"type": "string"}}}`))
+	}))
+	defer server.Close()
+
+	problem := filepath.Join(tmpDir, "problem.yaml")
+	remote := filepath.Join(tmpDir, "remote.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// keys are ordered by schemas that are not local files, as they are loaded by the provider
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(problem, []byte("detail: \"Out of credit\"\ntitle: \"Forbidden\"\nstatus: 403\n"), 0644))
+					require.NoError(t, os.WriteFile(remote, []byte("id: \"remote-id\"\nname: \"Remote\"\n"), 0644))
+				},
+				Config: fmt.Sprintf(testAccFormattedFileResourceSchemaConfig, problem, "urn:jsonschema:problem-details") +
+					fmt.Sprintf(testAccFormattedFileResourceRemoteConfig, remote, server.URL+"/schema.json"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFileContent(problem, "status: 403\ntitle: \"Forbidden\"\ndetail: \"Out of credit\"\n"),
+					testAccCheckFileContent(remote, "name: \"Remote\"\nid: \"remote-id\"\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestFormattedFileInvalidIndent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "example.yaml")
+
+	err := os.WriteFile(file, []byte("name: \"Example Name\"\n"), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccFormattedFileResourceIndentValueConfig, file, -1),
+				ExpectError: regexp.MustCompile(`must be between 2 and 9`),
+			},
+		},
+	})
+}
+
+func testAccCheckFileContent(file, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if string(content) != expected {
+			return fmt.Errorf("expected file %s to contain:\n%s\ngot:\n%s", file, expected, content)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckResourceFileContent checks that the file holds the content of
+// the resource at address, including expected.
+func testAccCheckResourceFileContent(address, file, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if !strings.Contains(string(content), expected) {
+			return fmt.Errorf("expected file %s to contain %s, got:\n%s", file, expected, content)
+		}
+
+		return resource.TestCheckResourceAttr(address, "content", string(content))(s)
+	}
+}
+
+const (
+	testAccFormattedFileResourceConfig = `
+resource "jsonschema_formatted_file" "test" {
+  path = "%s"
+}
+`
+	testAccFormattedFileResourceIndentConfig = `
+resource "jsonschema_formatted_file" "test" {
+  path   = "%s"
+  indent = 4
+}
+`
+	testAccFormattedFileResourceIndentValueConfig = `
+resource "jsonschema_formatted_file" "test" {
+  path   = "%s"
+  indent = %d
+}
+`
+	testAccFormattedFileResourceRemoteConfig = `
+resource "jsonschema_formatted_file" "remote" {
+  path   = "%s"
+  schema = "%s"
+}
+`
+	testAccFormattedFileResourceSchemaConfig = `
+resource "jsonschema_formatted_file" "test" {
+  path   = "%s"
+  schema = "%s"
+}
+`
+)
@@ -0,0 +1,749 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+	"io"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// ageExtension is the extension of age encrypted input files.
+const ageExtension = ".age"
+
+// noInputFilesSummary is the summary of the error of patterns that match no
+// input files.
+const noInputFilesSummary = "No input files found"
+
+// urlRegex matches references with a URL scheme, e.g. vault://mount/path,
+// and URNs of the embedded schemas.
+var urlRegex = regexp.MustCompile(`^(?:[a-zA-Z][a-zA-Z0-9+.-]*://|urn:)`)
+
+// resolveSchemaReference returns the location of the schema referenced as
+// ref by file. Absolute paths and file:// URIs are used as local paths.
+// Relative paths are resolved against the directory of file, or against the
+// URL of file if it is not a local file. If the schema does not exist next
+// to a local file, roots are searched in order and the first existing schema
+// is used.
+func resolveSchemaReference(file, ref string, roots ...string) string {
+	location := ref
+
+	switch {
+	case strings.HasPrefix(ref, "file://"):
+		// file URIs are compiled as paths, so every form of a location shares a compiled schema
+		uri, fragment, hasFragment := strings.Cut(ref, "#")
+		if local, err := (jsonschema.FileLoader{}).ToFile(uri); err == nil {
+			location = filepath.Clean(local)
+			if hasFragment {
+				location += "#" + fragment
+			}
+		}
+	case urlRegex.MatchString(ref):
+	case filepath.IsAbs(ref):
+		location = filepath.Clean(ref)
+	case urlRegex.MatchString(file):
+		base, err := url.Parse(file)
+		if err == nil {
+			if resolved, err := base.Parse(ref); err == nil {
+				location = resolved.String()
+			}
+		}
+	default:
+		// entries inside archives resolve references next to the archive
+		location = filepath.Join(archiveDir(file), ref)
+
+		if _, err := os.Stat(location); err != nil {
+			for _, root := range roots {
+				candidate := filepath.Join(root, ref)
+				if _, err := os.Stat(candidate); err == nil {
+					location = candidate
+					break
+				}
+			}
+		}
+	}
+
+	if isVaultURL(location) {
+		location = vaultSchemaLocation(location)
+	}
+
+	return location
+}
+
+// globInputFiles returns the files matched by pattern, adding an error
+// diagnostic if the pattern is malformed or matches nothing. Patterns may use
+// forward slashes on every platform and are matched case-insensitively on
+// Windows. The files are returned with forward slashes, so the keys of the
+// outputs are the same on every platform. Paths of virtual files, which do
+// not exist on disk, are matched against pattern too. Patterns of entries
+// inside zip and tar archives separate the archive from the entries with
+// //, e.g. bundle.tar.gz//configs/*.yaml.
+func globInputFiles(pattern string, diags *diag.Diagnostics, virtual ...string) []string {
+	var files []string
+	var err error
+	if isArchiveEntry(pattern) {
+		files, err = globArchiveEntries(pattern, runtime.GOOS == "windows")
+	} else {
+		files, err = glob(filepath.FromSlash(pattern), runtime.GOOS == "windows")
+	}
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			"Error reading input files",
+			"Could not read input files: "+err.Error(),
+		)
+		return nil
+	}
+
+	for _, file := range virtual {
+		if ok, _ := filepath.Match(filepath.FromSlash(pattern), filepath.FromSlash(file)); ok && !slices.Contains(files, filepath.FromSlash(file)) {
+			files = append(files, filepath.FromSlash(file))
+		}
+	}
+
+	if len(virtual) > 0 {
+		slices.Sort(files)
+	}
+
+	if len(files) == 0 {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			noInputFilesSummary,
+			"No files matched the provided input pattern: "+pattern,
+		)
+		return nil
+	}
+
+	for i, file := range files {
+		files[i] = filepath.ToSlash(file)
+	}
+
+	return files
+}
+
+const (
+	keyFormatAbsolute = "absolute"
+	keyFormatRelative = "relative"
+	keyFormatBasename = "basename"
+)
+
+// keyFormatter returns the function that maps the files matched by pattern
+// to the keys of the outputs. format is absolute, relative to the directory
+// before the first glob meta character of pattern, basename, or a regular
+// expression whose capture groups, joined by '/', are the key. Without a
+// format the files are keys as they are.
+func keyFormatter(format, pattern string) (func(file string) (string, error), error) {
+	switch format {
+	case "":
+		return func(file string) (string, error) { return file, nil }, nil
+	case keyFormatAbsolute:
+		return func(file string) (string, error) {
+			abs, err := filepath.Abs(filepath.FromSlash(file))
+			return filepath.ToSlash(abs), err
+		}, nil
+	case keyFormatRelative:
+		base := filepath.FromSlash(pattern)
+		if i := strings.IndexAny(base, "*?["); i >= 0 {
+			base = base[:i]
+		}
+		// the directory of the partial name before the meta character, e.g. envs for envs/dev-*
+		base = filepath.Dir(base + "x")
+
+		return func(file string) (string, error) {
+			rel, err := filepath.Rel(base, filepath.FromSlash(file))
+			return filepath.ToSlash(rel), err
+		}, nil
+	case keyFormatBasename:
+		return func(file string) (string, error) { return filepath.Base(filepath.FromSlash(file)), nil }, nil
+	}
+
+	re, err := regexp.Compile(format)
+	if err != nil {
+		return nil, err
+	}
+	if re.NumSubexp() == 0 {
+		return nil, fmt.Errorf("regular expression %q has no capture groups", format)
+	}
+
+	return func(file string) (string, error) {
+		matches := re.FindStringSubmatch(file)
+		if matches == nil {
+			return "", fmt.Errorf("file %s does not match %q", file, format)
+		}
+		return strings.Join(matches[1:], "/"), nil
+	}, nil
+}
+
+// defaultYAMLExtensions are the extensions of the files included if the
+// input pattern is a directory.
+var defaultYAMLExtensions = []string{".yaml", ".yml"}
+
+// walkInputDir returns the files below dir, recursively, that have one of
+// extensions, adding an error diagnostic if there are none. Extensions are
+// compared case-insensitively and age encrypted files have the extension of
+// the decrypted file. Virtual files below dir are included too.
+func walkInputDir(dir string, extensions []string, diags *diag.Diagnostics, virtual ...string) []string {
+	var files []string
+
+	err := filepath.WalkDir(filepath.FromSlash(dir), func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && hasInputExtension(file, extensions) {
+			files = append(files, filepath.ToSlash(file))
+		}
+		return nil
+	})
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			"Error reading input files",
+			"Could not read input directory "+dir+": "+err.Error(),
+		)
+		return nil
+	}
+
+	prefix := strings.TrimSuffix(filepath.ToSlash(filepath.Clean(dir)), "/") + "/"
+	for _, file := range virtual {
+		if strings.HasPrefix(file, prefix) && hasInputExtension(file, extensions) && !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+
+	if len(files) == 0 {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			noInputFilesSummary,
+			"No files with the extensions "+strings.Join(extensions, ", ")+" found in the input directory: "+dir,
+		)
+		return nil
+	}
+
+	slices.Sort(files)
+
+	return files
+}
+
+// filterInputExtensions returns the files matched by pattern that have one
+// of extensions, adding an error diagnostic if there are none.
+func filterInputExtensions(pattern string, files, extensions []string, diags *diag.Diagnostics) []string {
+	if len(files) == 0 {
+		return files
+	}
+
+	files = slices.DeleteFunc(files, func(file string) bool { return !hasInputExtension(file, extensions) })
+	if len(files) == 0 {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			noInputFilesSummary,
+			"No files with the extensions "+strings.Join(extensions, ", ")+" matched the provided input pattern: "+pattern,
+		)
+	}
+
+	return files
+}
+
+// filterHiddenFiles returns the files matched by pattern without hidden
+// files and files in hidden directories, whose names start with a dot,
+// adding an error diagnostic if there are none. Names are only hidden if
+// pattern does not match them with a dot explicitly, e.g. .github/*.yml, and
+// below a directory pattern every name is compared.
+func filterHiddenFiles(pattern string, files []string, diags *diag.Diagnostics) []string {
+	if len(files) == 0 {
+		return files
+	}
+
+	dir := isInputDir(pattern)
+	patternSegments := strings.Split(filepath.ToSlash(filepath.Clean(filepath.FromSlash(pattern))), "/")
+
+	filtered := slices.DeleteFunc(slices.Clone(files), func(file string) bool {
+		name := filepath.Clean(filepath.FromSlash(file))
+		if dir {
+			// names below the directory, which may be hidden itself
+			if rel, err := filepath.Rel(filepath.FromSlash(pattern), name); err == nil {
+				name = rel
+			}
+		}
+		segments := strings.Split(filepath.ToSlash(name), "/")
+
+		for i, segment := range segments {
+			if !strings.HasPrefix(segment, ".") || segment == "." || segment == ".." {
+				continue
+			}
+
+			// segments of glob matches line up with the pattern from the end
+			if j := len(patternSegments) - len(segments) + i; !dir && j >= 0 && strings.HasPrefix(patternSegments[j], ".") {
+				continue
+			}
+
+			return true
+		}
+
+		return false
+	})
+
+	if len(filtered) == 0 {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			noInputFilesSummary,
+			"Only hidden files, or files in hidden directories, matched the provided input pattern: "+pattern+". Set include_hidden to true to validate them",
+		)
+	}
+
+	return filtered
+}
+
+func hasInputExtension(file string, extensions []string) bool {
+	if strings.EqualFold(filepath.Ext(file), ageExtension) {
+		file = file[:len(file)-len(ageExtension)]
+	}
+
+	// extensions may have several parts, e.g. .tfvars.json
+	return slices.ContainsFunc(extensions, func(extension string) bool {
+		return strings.HasSuffix(strings.ToLower(file), strings.ToLower(extension))
+	})
+}
+
+// isInputDir reports whether pattern is the path of a directory rather than
+// a glob pattern.
+func isInputDir(pattern string) bool {
+	if hasGlobMeta(pattern) {
+		return false
+	}
+
+	fi, err := os.Stat(filepath.FromSlash(pattern))
+
+	return err == nil && fi.IsDir()
+}
+
+// glob is filepath.Glob, optionally matching names case-insensitively.
+func glob(pattern string, foldCase bool) ([]string, error) {
+	if !foldCase {
+		return filepath.Glob(pattern)
+	}
+
+	// validate the whole pattern up front like filepath.Glob
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	if !hasGlobMeta(pattern) {
+		if _, err := os.Lstat(pattern); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := filepath.Split(pattern)
+	dir = cleanGlobDir(dir)
+
+	if !hasGlobMeta(dir) {
+		return globDir(dir, file, nil), nil
+	}
+
+	if dir == pattern {
+		return nil, filepath.ErrBadPattern
+	}
+
+	dirs, err := glob(dir, foldCase)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, d := range dirs {
+		matches = globDir(d, file, matches)
+	}
+
+	return matches, nil
+}
+
+// globDir appends the entries of dir whose lowercase name matches the
+// lowercase pattern to matches.
+func globDir(dir, pattern string, matches []string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return matches
+	}
+
+	pattern = strings.ToLower(pattern)
+	for _, entry := range entries {
+		if ok, _ := filepath.Match(pattern, strings.ToLower(entry.Name())); ok {
+			matches = append(matches, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return matches
+}
+
+func cleanGlobDir(dir string) string {
+	switch dir {
+	case "":
+		return "."
+	case string(filepath.Separator):
+		return dir
+	}
+
+	// keep the separator of volume roots, e.g. C:\
+	if trimmed := dir[:len(dir)-1]; filepath.VolumeName(trimmed) != trimmed {
+		return trimmed
+	}
+
+	return dir
+}
+
+func hasGlobMeta(path string) bool {
+	magic := `*?[`
+	if runtime.GOOS != "windows" {
+		magic = `*?[\`
+	}
+	return strings.ContainsAny(path, magic)
+}
+
+// checkInputSizes aborts with an error if a file is larger than
+// maxFileSize or all files together are larger than maxTotalSize, limits of
+// 0 are not enforced. Files that cannot be stat'ed, e.g. vault:// documents,
+// are left for reading to report.
+func checkInputSizes(files []string, maxFileSize, maxTotalSize int64, diags *diag.Diagnostics) {
+	if maxFileSize <= 0 && maxTotalSize <= 0 {
+		return
+	}
+
+	var total int64
+	for _, file := range files {
+		fi, err := statInputFile(file)
+		if err != nil {
+			continue
+		}
+
+		if maxFileSize > 0 && fi.Size() > maxFileSize {
+			diags.AddAttributeError(
+				path.Root("max_file_size"),
+				"File too large",
+				fmt.Sprintf("File %s is %d bytes, which exceeds max_file_size of %d bytes", file, fi.Size(), maxFileSize),
+			)
+		}
+
+		total += fi.Size()
+	}
+
+	if maxTotalSize > 0 && total > maxTotalSize {
+		diags.AddAttributeError(
+			path.Root("max_total_size"),
+			"Input files too large",
+			fmt.Sprintf("The %d matched files are %d bytes in total, which exceeds max_total_size of %d bytes", len(files), total, maxTotalSize),
+		)
+	}
+}
+
+// checkInputCount aborts with an error if more than maxFiles files are
+// matched, a limit of 0 is not enforced. The error names the directories
+// with the most files, which are usually the ones a pattern should not
+// match, e.g. node_modules.
+func checkInputCount(files []string, maxFiles int64, diags *diag.Diagnostics) {
+	if maxFiles <= 0 || int64(len(files)) <= maxFiles {
+		return
+	}
+
+	// files are counted by the first directory below the directories all files are in
+	common := strings.Split(files[0], "/")
+	common = common[:len(common)-1]
+	for _, file := range files[1:] {
+		segments := strings.Split(file, "/")
+		n := 0
+		for n < len(common) && n < len(segments)-1 && common[n] == segments[n] {
+			n++
+		}
+		common = common[:n]
+	}
+
+	counts := make(map[string]int)
+	for _, file := range files {
+		segments := strings.Split(file, "/")
+		if len(segments) > len(common)+1 {
+			counts[strings.Join(segments[:len(common)+1], "/")]++
+		}
+	}
+
+	dirs := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+
+	var largest []string
+	for _, dir := range dirs[:min(len(dirs), 3)] {
+		largest = append(largest, fmt.Sprintf("%s (%d files)", dir, counts[dir]))
+	}
+
+	detail := fmt.Sprintf("%d files were matched, which exceeds max_files of %d. Narrow the pattern or set extensions to leave out files that are not meant to be validated", len(files), maxFiles)
+	if len(largest) > 0 {
+		detail += ", the most files are below " + strings.Join(largest, ", ")
+	}
+
+	diags.AddAttributeError(
+		path.Root("max_files"),
+		"Too many input files",
+		detail+".",
+	)
+}
+
+// utf8BOM is the byte order mark some Windows tools write to UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// readTextFile returns the content of file, which may be an entry inside an
+// archive, decoded by decode, e.g. a textDecoder.
+func readTextFile(file string, decode func(content []byte) ([]byte, error)) ([]byte, error) {
+	content, err := readInputFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(content)
+}
+
+// decodeText returns content as UTF-8 without a byte order mark. UTF-16 is
+// detected by its byte order mark or, like YAML does, by a null byte in the
+// first two bytes. Any other content is returned as is.
+func decodeText(content []byte) ([]byte, error) {
+	var decoder *encoding.Decoder
+
+	switch {
+	case bytes.HasPrefix(content, []byte{0xFE, 0xFF}), len(content) >= 2 && content[0] == 0 && content[1] != 0:
+		decoder = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+	case bytes.HasPrefix(content, []byte{0xFF, 0xFE}), len(content) >= 2 && content[0] != 0 && content[1] == 0:
+		decoder = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+	default:
+		return bytes.TrimPrefix(content, utf8BOM), nil
+	}
+
+	decoded, _, err := transform.Bytes(unicode.BOMOverride(decoder), content)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode UTF-16: %w", err)
+	}
+
+	return decoded, nil
+}
+
+const (
+	// encodingUTF8 decodes input files with decodeText.
+	encodingUTF8 = "utf-8"
+	// encodingAuto decodes input files with decodeText and falls back to
+	// Windows-1252 for anything that is not valid UTF-8.
+	encodingAuto = "auto"
+)
+
+// encodingAttribute returns the encoding attribute of data sources reading
+// input files.
+func encodingAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "Encoding of the input files, an IANA or WHATWG name such as `iso-8859-1` (`latin-1`), `windows-1252` or `shift_jis`. " +
+			"Defaults to `utf-8`, which also decodes UTF-16 files and strips byte order marks. " +
+			"`auto` decodes like `utf-8` and falls back to `windows-1252` for files that are not valid UTF-8.",
+		Optional: true,
+	}
+}
+
+// textDecoder returns the decoder of input files in the named encoding, an
+// IANA or WHATWG name or alias such as iso-8859-1, windows-1252 or shift_jis.
+func textDecoder(name string) (func(content []byte) ([]byte, error), error) {
+	switch strings.ToLower(name) {
+	case "", encodingUTF8, "utf8":
+		return decodeText, nil
+	case encodingAuto:
+		return func(content []byte) ([]byte, error) {
+			decoded, err := decodeText(content)
+			if err != nil || utf8.Valid(decoded) {
+				return decoded, err
+			}
+			return charmap.Windows1252.NewDecoder().Bytes(content)
+		}, nil
+	case "latin-1":
+		name = "latin1"
+	}
+
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		if enc, err = htmlindex.Get(name); err != nil {
+			return nil, fmt.Errorf("unsupported encoding %q", name)
+		}
+	}
+
+	return enc.NewDecoder().Bytes, nil
+}
+
+// normalizeText converts CRLF and CR line endings of content to LF and
+// removes trailing spaces and tabs from every line, as requested.
+func normalizeText(content string, lineEndings, trailingWhitespace bool) string {
+	if lineEndings {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+		content = strings.ReplaceAll(content, "\r", "\n")
+	}
+
+	if trailingWhitespace {
+		lines := strings.Split(content, "\n")
+		for i, line := range lines {
+			// keep a CR of CRLF line endings unless they are normalized too
+			cr := strings.HasSuffix(line, "\r")
+			line = strings.TrimRight(line, " \t\r")
+			if cr {
+				line += "\r"
+			}
+			lines[i] = line
+		}
+		content = strings.Join(lines, "\n")
+	}
+
+	return content
+}
+
+// renderTemplate executes content as a Go template with vars as data,
+// referencing a variable that is not set is an error.
+func renderTemplate(name, content string, vars map[string]string) (string, error) {
+	tmpl, err := template.New(filepath.Base(name)).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, vars); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// expandVariables substitutes ${VAR} references in content like docker
+// compose does, supporting ${VAR:-default}, ${VAR-default}, ${VAR:?message}
+// and ${VAR?message}, and $$ for a literal $. Unbraced references are left
+// as is, so keywords like $schema or $ref are not mistaken for variables. A
+// reference to a variable that lookup does not know and that has no default
+// is an error.
+func expandVariables(content string, lookup func(name string) (string, bool)) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(content); i++ {
+		if content[i] != '$' || i+1 == len(content) {
+			sb.WriteByte(content[i])
+			continue
+		}
+
+		switch next := content[i+1]; {
+		case next == '$':
+			sb.WriteByte('$')
+			i++
+		case next == '{':
+			end := strings.IndexByte(content[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference at offset %d", i)
+			}
+
+			expr := content[i+2 : i+2+end]
+			i += 2 + end
+
+			value, err := expandVariable(expr, lookup)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(value)
+		default:
+			sb.WriteByte('$')
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func expandVariable(expr string, lookup func(name string) (string, bool)) (string, error) {
+	end := 0
+	for end < len(expr) && isVariableNameChar(expr[end], end == 0) {
+		end++
+	}
+
+	name, modifier := expr[:end], expr[end:]
+	if name == "" {
+		return "", fmt.Errorf("invalid variable reference ${%s}", expr)
+	}
+
+	value, ok := lookup(name)
+
+	switch {
+	case modifier == "":
+		if !ok {
+			return "", fmt.Errorf("variable %s is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(modifier, ":-"):
+		if !ok || value == "" {
+			return modifier[2:], nil
+		}
+		return value, nil
+	case strings.HasPrefix(modifier, "-"):
+		if !ok {
+			return modifier[1:], nil
+		}
+		return value, nil
+	case strings.HasPrefix(modifier, ":?"):
+		if !ok || value == "" {
+			return "", fmt.Errorf("variable %s is required: %s", name, modifier[2:])
+		}
+		return value, nil
+	case strings.HasPrefix(modifier, "?"):
+		if !ok {
+			return "", fmt.Errorf("variable %s is required: %s", name, modifier[1:])
+		}
+		return value, nil
+	}
+
+	return "", fmt.Errorf("invalid variable reference ${%s}", expr)
+}
+
+func isVariableNameChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// decryptAge decrypts binary or armored age encrypted content.
+func decryptAge(content []byte, identities []age.Identity) ([]byte, error) {
+	if len(identities) == 0 {
+		return nil, errors.New("no age_identities are configured in the provider")
+	}
+
+	var src io.Reader = bytes.NewReader(content)
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte(armor.Header)) {
+		src = armor.NewReader(src)
+	}
+
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
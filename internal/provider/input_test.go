@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGlobFoldCase(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, dir := range []string{"Configs", "other"} {
+		require.NoError(t, os.Mkdir(filepath.Join(tmpDir, dir), 0755))
+	}
+
+	for _, file := range []string{"Configs/A.YAML", "Configs/b.yaml", "Configs/c.json", "other/D.Yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, file), nil, 0644))
+	}
+
+	matches, err := glob(filepath.Join(tmpDir, "*", "*.yaml"), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(tmpDir, "Configs", "A.YAML"),
+		filepath.Join(tmpDir, "Configs", "b.yaml"),
+		filepath.Join(tmpDir, "other", "D.Yaml"),
+	}, matches)
+
+	matches, err = glob(filepath.Join(tmpDir, "configs*", "*.JSON"), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(tmpDir, "Configs", "c.json")}, matches)
+
+	matches, err = glob(filepath.Join(tmpDir, "*", "*.yaml"), false)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(tmpDir, "Configs", "b.yaml")}, matches)
+
+	_, err = glob(filepath.Join(tmpDir, "["), true)
+	require.ErrorIs(t, err, filepath.ErrBadPattern)
+}
+
+func TestFilterHiddenFiles(t *testing.T) {
+	tmpDir := filepath.ToSlash(t.TempDir())
+
+	// a hidden directory given explicitly is not hidden itself
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, ".config"), 0755))
+
+	tests := []struct {
+		pattern string
+		files   []string
+		want    []string
+	}{
+		{
+			pattern: tmpDir + "/*/*.yaml",
+			files:   []string{tmpDir + "/.git/a.yaml", tmpDir + "/apps/.b.yaml", tmpDir + "/apps/c.yaml"},
+			want:    []string{tmpDir + "/apps/c.yaml"},
+		},
+		{
+			pattern: tmpDir + "/.github/workflows/*.yml",
+			files:   []string{tmpDir + "/.github/workflows/ci.yml"},
+			want:    []string{tmpDir + "/.github/workflows/ci.yml"},
+		},
+		{
+			pattern: tmpDir + "/.config",
+			files:   []string{tmpDir + "/.config/a.yaml", tmpDir + "/.config/.cache/b.yaml"},
+			want:    []string{tmpDir + "/.config/a.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var diags diag.Diagnostics
+			require.Equal(t, tt.want, filterHiddenFiles(tt.pattern, tt.files, &diags))
+			require.False(t, diags.HasError())
+		})
+	}
+
+	var diags diag.Diagnostics
+	require.Empty(t, filterHiddenFiles(tmpDir+"/*", []string{tmpDir + "/.env"}, &diags))
+	require.True(t, diags.HasError())
+}
+
+func TestResolveSchemaReferenceRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, dir := range []string{"app", "schemas", "vendor"} {
+		require.NoError(t, os.Mkdir(filepath.Join(tmpDir, dir), 0755))
+	}
+
+	for _, file := range []string{"app/local.json", "schemas/local.json", "schemas/shared.json", "vendor/shared.json", "vendor/vendored.json"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, file), []byte(`{}`), 0644))
+	}
+
+	file := filepath.Join(tmpDir, "app", "config.yaml")
+	roots := []string{filepath.Join(tmpDir, "schemas"), filepath.Join(tmpDir, "vendor")}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "local.json", want: "app/local.json"},
+		{ref: "shared.json", want: "schemas/shared.json"},
+		{ref: "vendored.json", want: "vendor/vendored.json"},
+		{ref: "missing.json", want: "app/missing.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			require.Equal(t, filepath.Join(tmpDir, tt.want), resolveSchemaReference(file, tt.ref, roots...))
+		})
+	}
+}
+
+func TestExpandVariables(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		value, ok := map[string]string{"ENV": "prod", "EMPTY": ""}[name]
+		return value, ok
+	}
+
+	for input, expected := range map[string]string{
+		"env: ${ENV}":                "env: prod",
+		"$schema: $ENV":              "$schema: $ENV",
+		"price: $$5":                 "price: $5",
+		"region: ${REGION:-eu}":      "region: eu",
+		"region: ${EMPTY:-eu}":       "region: eu",
+		"region: ${EMPTY-eu}":        "region: ",
+		"region: ${ENV:?required}":   "region: prod",
+		"literal: $ ":                "literal: $ ",
+		"trailing: $":                "trailing: $",
+		"numbers: $1 stay unchanged": "numbers: $1 stay unchanged",
+	} {
+		actual, err := expandVariables(input, lookup)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, actual, input)
+	}
+
+	for input, message := range map[string]string{
+		"env: ${MISSING}":          "variable MISSING is not set",
+		"env: ${EMPTY:?set it}":    "variable EMPTY is required: set it",
+		"env: ${MISSING?set it}":   "variable MISSING is required: set it",
+		"env: ${ENV":               "unterminated variable reference",
+		"env: ${ENV:+alternative}": "invalid variable reference",
+	} {
+		_, err := expandVariables(input, lookup)
+		require.ErrorContains(t, err, message, input)
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	encodeUTF16 := func(s string, bigEndian, bom bool) []byte {
+		var out []byte
+		if bom {
+			out = append(out, 0xFF, 0xFE)
+			if bigEndian {
+				out = []byte{0xFE, 0xFF}
+			}
+		}
+		for _, r := range s {
+			if bigEndian {
+				out = append(out, byte(r>>8), byte(r))
+			} else {
+				out = append(out, byte(r), byte(r>>8))
+			}
+		}
+		return out
+	}
+
+	const text = "name: Zoë\n"
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "utf-8", content: []byte(text)},
+		{name: "utf-8 bom", content: append([]byte{0xEF, 0xBB, 0xBF}, text...)},
+		{name: "utf-16le bom", content: encodeUTF16(text, false, true)},
+		{name: "utf-16be bom", content: encodeUTF16(text, true, true)},
+		{name: "utf-16le", content: encodeUTF16(text, false, false)},
+		{name: "utf-16be", content: encodeUTF16(text, true, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := decodeText(tt.content)
+			require.NoError(t, err)
+			require.Equal(t, text, string(decoded))
+		})
+	}
+}
+
+func TestTextDecoder(t *testing.T) {
+	latin1 := []byte("name: Zo\xeb\n")
+
+	tests := []struct {
+		encoding string
+		content  []byte
+		want     string
+	}{
+		{encoding: "", content: []byte("name: Zoë\n"), want: "name: Zoë\n"},
+		{encoding: "latin-1", content: latin1, want: "name: Zoë\n"},
+		{encoding: "ISO-8859-1", content: latin1, want: "name: Zoë\n"},
+		{encoding: "windows-1252", content: []byte("price: \x80 5\n"), want: "price: € 5\n"},
+		{encoding: "auto", content: latin1, want: "name: Zoë\n"},
+		{encoding: "auto", content: []byte("name: Zoë\n"), want: "name: Zoë\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			decode, err := textDecoder(tt.encoding)
+			require.NoError(t, err)
+
+			decoded, err := decode(tt.content)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(decoded))
+		})
+	}
+
+	_, err := textDecoder("klingon")
+	require.ErrorContains(t, err, `unsupported encoding "klingon"`)
+}
+
+func TestNormalizeText(t *testing.T) {
+	const content = "a: 1  \r\nb: 2\t\rc: 3 \n"
+
+	require.Equal(t, content, normalizeText(content, false, false))
+	require.Equal(t, "a: 1  \nb: 2\t\nc: 3 \n", normalizeText(content, true, false))
+	require.Equal(t, "a: 1\r\nb: 2\t\rc: 3\n", normalizeText(content, false, true))
+	require.Equal(t, "a: 1\nb: 2\nc: 3\n", normalizeText(content, true, true))
+}
+
+func TestKeyFormatter(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "", want: "config/envs/dev/values.yaml"},
+		{format: "absolute", want: filepath.ToSlash(filepath.Join(wd, "config/envs/dev/values.yaml"))},
+		{format: "relative", want: "dev/values.yaml"},
+		{format: "basename", want: "values.yaml"},
+		{format: `envs/([^/]+)/values\.yaml$`, want: "dev"},
+		{format: `config/(\w+)/(\w+)/`, want: "envs/dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			fileKey, err := keyFormatter(tt.format, "config/envs/*/values.yaml")
+			require.NoError(t, err)
+
+			key, err := fileKey("config/envs/dev/values.yaml")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, key)
+		})
+	}
+
+	_, err = keyFormatter(`envs/[^/]+`, "")
+	require.ErrorContains(t, err, "no capture groups")
+
+	fileKey, err := keyFormatter(`envs/([^/]+)`, "")
+	require.NoError(t, err)
+
+	_, err = fileKey("config/values.yaml")
+	require.ErrorContains(t, err, "does not match")
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"slices"
+)
+
+var (
+	// schemaMapKeywords hold objects whose values are subschemas.
+	schemaMapKeywords = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas", "dependencies"}
+	// schemaListKeywords hold arrays of subschemas.
+	schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+	// subschemaKeywords hold a single subschema.
+	subschemaKeywords = []string{
+		"items", "additionalItems", "additionalProperties", "contains", "propertyNames", "not",
+		"if", "then", "else", "unevaluatedItems", "unevaluatedProperties", "contentSchema",
+	}
+)
+
+// ignoringLoader loads schemas with loader and removes keywords from them
+// and their subschemas.
+type ignoringLoader struct {
+	loader   jsonschema.URLLoader
+	keywords []string
+}
+
+func (l ignoringLoader) Load(url string) (any, error) {
+	document, err := l.loader.Load(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return stripKeywords(document, l.keywords), nil
+}
+
+// LoadRaw returns the documents as they are stored, with the keywords.
+func (l ignoringLoader) LoadRaw(url string) ([]byte, error) {
+	return loadRaw(l.loader, url)
+}
+
+// stripKeywords returns schema without keywords, which are removed from its
+// subschemas too. Values that are not subschemas, e.g. of enum or the names
+// of properties, are kept as they are.
+func stripKeywords(schema any, keywords []string) any {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return schema
+	}
+
+	stripped := make(map[string]any, len(object))
+	for key, value := range object {
+		if slices.Contains(keywords, key) {
+			continue
+		}
+
+		switch {
+		case slices.Contains(schemaMapKeywords, key):
+			if subschemas, ok := value.(map[string]any); ok {
+				strippedSubschemas := make(map[string]any, len(subschemas))
+				for name, subschema := range subschemas {
+					// dependencies of draft-07 and earlier may also list property names
+					strippedSubschemas[name] = stripKeywords(subschema, keywords)
+				}
+				value = strippedSubschemas
+			}
+		case slices.Contains(schemaListKeywords, key):
+			if subschemas, ok := value.([]any); ok {
+				strippedSubschemas := make([]any, 0, len(subschemas))
+				for _, subschema := range subschemas {
+					strippedSubschemas = append(strippedSubschemas, stripKeywords(subschema, keywords))
+				}
+				value = strippedSubschemas
+				break
+			}
+			fallthrough
+		case slices.Contains(subschemaKeywords, key):
+			value = stripKeywords(value, keywords)
+		}
+
+		stripped[key] = value
+	}
+
+	return stripped
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestStripKeywords(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+  "format": "uri",
+  "properties": {
+    "format": {"type": "string", "format": "email"},
+    "items": {"type": "array", "items": {"format": "date"}, "prefixItems": [{"format": "uuid"}]}
+  },
+  "enum": [{"format": "kept"}],
+  "allOf": [{"not": {"format": "ipv4"}}],
+  "$defs": {"name": {"contentMediaType": "text/plain"}}
+}`))
+	require.NoError(t, err)
+
+	expected, err := jsonschema.UnmarshalJSON(strings.NewReader(`{
+  "properties": {
+    "format": {"type": "string"},
+    "items": {"type": "array", "items": {}, "prefixItems": [{}]}
+  },
+  "enum": [{"format": "kept"}],
+  "allOf": [{"not": {}}],
+  "$defs": {"name": {}}
+}`))
+	require.NoError(t, err)
+
+	require.Equal(t, expected, stripKeywords(schema, []string{"format", "contentMediaType"}))
+}
+
+func TestIgnoreKeywords(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "values.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+email: "not an email"
+format: "yaml"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "email": {"type": "string", "format": "email"},
+    "format": {"$ref": "./defs.json"}
+  },
+  "additionalProperties": false
+}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "defs.json"), []byte(`{"type": "string", "maxLength": 3}`), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.Join(tmpDir, "*.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccIgnoreKeywordsConfig, `["format"]`, pattern),
+				ExpectError: regexp.MustCompile(`maxLength`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccIgnoreKeywordsConfig, `["maxLength"]`, pattern),
+				ExpectError: regexp.MustCompile(`is not valid email`),
+			},
+			{
+				Config: fmt.Sprintf(testAccIgnoreKeywordsConfig, `["format", "maxLength"]`, pattern),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccIgnoreKeywordsConfig = `
+provider "jsonschema" {
+  ignore_keywords = %s
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
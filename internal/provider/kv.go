@@ -0,0 +1,421 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// URL schemes of documents and schemas stored in key-value stores, e.g.
+// consul://config/apps/ for every key below a prefix or
+// etcd://config/apps/web.yaml for a single key.
+const (
+	consulScheme = "consul"
+	etcdScheme   = "etcd"
+)
+
+// errKVNotFound is returned for keys and prefixes that do not exist.
+var errKVNotFound = errors.New("not found")
+
+const (
+	defaultConsulAddress = "http://127.0.0.1:8500"
+	defaultEtcdEndpoint  = "http://127.0.0.1:2379"
+)
+
+// ConsulConfigModel describes the consul block of the provider data model.
+type ConsulConfigModel struct {
+	Address    types.String `tfsdk:"address"`
+	Token      types.String `tfsdk:"token"`
+	Datacenter types.String `tfsdk:"datacenter"`
+}
+
+// EtcdConfigModel describes the etcd block of the provider data model.
+type EtcdConfigModel struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+}
+
+// kvStore reads the values of a key-value store.
+type kvStore interface {
+	// keys returns the keys below prefix, without the keys of folders.
+	keys(ctx context.Context, prefix string) ([]string, error)
+	// get returns the value of key.
+	get(ctx context.Context, key string) ([]byte, error)
+}
+
+// kvDocuments reads documents and schemas from the key-value stores of the
+// consul://, etcd://, ssm:// and secretsmanager:// schemes. References
+// ending with a slash are prefixes, which match every key below them.
+type kvDocuments struct {
+	stores map[string]kvStore
+}
+
+// Ensure kvDocuments can load schemas.
+var _ jsonschema.URLLoader = &kvDocuments{}
+
+func newKVDocuments(consul *ConsulConfigModel, etcd *EtcdConfigModel, aws *AWSConfigModel) *kvDocuments {
+	client := &http.Client{Timeout: httpLoadTimeout}
+
+	c := &consulClient{client: client}
+	if consul != nil {
+		c.config = *consul
+	}
+
+	e := &etcdClient{client: client}
+	if etcd != nil {
+		e.config = *etcd
+	}
+
+	session := newAWSSession(aws)
+
+	return &kvDocuments{stores: map[string]kvStore{
+		consulScheme:         c,
+		etcdScheme:           e,
+		ssmScheme:            &ssmStore{session: session},
+		secretsManagerScheme: &secretsManagerStore{session: session},
+	}}
+}
+
+// store returns the store and the key of ref.
+func (k *kvDocuments) store(ref string) (kvStore, string, error) {
+	scheme, key, _ := strings.Cut(ref, "://")
+	store, ok := k.stores[scheme]
+	if !ok || key == "" {
+		return nil, "", fmt.Errorf("invalid key-value reference %q, expected scheme://key with a scheme of %s", ref, strings.Join(slices.Sorted(maps.Keys(k.stores)), ", "))
+	}
+
+	// fragments are JSON pointers of schemas, not part of the key
+	key, _, _ = strings.Cut(key, "#")
+	if unescaped, err := url.PathUnescape(key); err == nil {
+		key = unescaped
+	}
+
+	return store, key, nil
+}
+
+// documents returns the references of the documents matched by ref, the
+// keys below a prefix or the key itself.
+func (k *kvDocuments) documents(ctx context.Context, ref string) ([]string, error) {
+	if !strings.HasSuffix(ref, "/") {
+		return []string{ref}, nil
+	}
+
+	store, prefix, err := k.store(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := store.keys(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme, _, _ := strings.Cut(ref, "://")
+	documents := make([]string, 0, len(keys))
+	for _, key := range keys {
+		documents = append(documents, scheme+"://"+key)
+	}
+	slices.Sort(documents)
+
+	return documents, nil
+}
+
+// readDocument returns the value of the key of ref.
+func (k *kvDocuments) readDocument(ctx context.Context, ref string) ([]byte, error) {
+	store, key, err := k.store(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.get(ctx, key)
+}
+
+func (k *kvDocuments) LoadRaw(ref string) ([]byte, error) {
+	return k.readDocument(context.Background(), ref)
+}
+
+// Load implements jsonschema.URLLoader, values are decoded as JSON.
+func (k *kvDocuments) Load(ref string) (any, error) {
+	value, err := k.readDocument(context.Background(), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(value))
+	if err != nil {
+		return nil, &permanentError{err}
+	}
+
+	return doc, nil
+}
+
+func isKVURL(ref string) bool {
+	return strings.HasPrefix(ref, consulScheme+"://") || strings.HasPrefix(ref, etcdScheme+"://") || isAWSURL(ref)
+}
+
+// kvRequest sends req with client and returns the body of the response.
+// Client errors are permanent, so loads of schemas are not retried.
+func kvRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &permanentError{fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), errKVNotFound)}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%s %s returned %s: %s", req.Method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+		if !retryableStatus(resp.StatusCode) {
+			return nil, &permanentError{err}
+		}
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// consulClient reads the Consul KV store with the HTTP API.
+type consulClient struct {
+	config ConsulConfigModel
+	client *http.Client
+}
+
+// request sends a GET request of the KV endpoint of key with query.
+func (c *consulClient) request(ctx context.Context, key string, query url.Values) ([]byte, error) {
+	// the defaults are read from CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN like the Consul CLI does
+	address := c.config.Address.ValueString()
+	if address == "" {
+		address = os.Getenv("CONSUL_HTTP_ADDR")
+	}
+	if address == "" {
+		address = defaultConsulAddress
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	token := c.config.Token.ValueString()
+	if token == "" {
+		token = os.Getenv("CONSUL_HTTP_TOKEN")
+	}
+
+	if !c.config.Datacenter.IsNull() {
+		query.Set("dc", c.config.Datacenter.ValueString())
+	}
+
+	endpoint := strings.TrimSuffix(address, "/") + "/v1/kv/" + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+
+	return kvRequest(c.client, req)
+}
+
+func (c *consulClient) keys(ctx context.Context, prefix string) ([]string, error) {
+	body, err := c.request(ctx, prefix, url.Values{"keys": {""}})
+	// Consul responds with 404 to prefixes without keys
+	if errors.Is(err, errKVNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, fmt.Errorf("could not decode the keys below %s: %w", prefix, err)
+	}
+
+	// folders are keys ending with a slash
+	return slices.DeleteFunc(keys, func(key string) bool { return strings.HasSuffix(key, "/") }), nil
+}
+
+func (c *consulClient) get(ctx context.Context, key string) ([]byte, error) {
+	return c.request(ctx, key, url.Values{"raw": {""}})
+}
+
+// etcdClient reads the etcd v3 KV store with the JSON gateway of its API.
+type etcdClient struct {
+	config EtcdConfigModel
+	client *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// etcdKeyValue is a key-value pair of a range response.
+type etcdKeyValue struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// endpoint returns the endpoint of the etcd server. The defaults are read
+// from ETCDCTL_ENDPOINTS like etcdctl does, the first endpoint is used.
+func (e *etcdClient) endpoint() string {
+	endpoint := e.config.Endpoint.ValueString()
+	if endpoint == "" {
+		endpoint, _, _ = strings.Cut(os.Getenv("ETCDCTL_ENDPOINTS"), ",")
+	}
+	if endpoint == "" {
+		endpoint = defaultEtcdEndpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+
+	return strings.TrimSuffix(endpoint, "/")
+}
+
+// post sends body as JSON to the API at path and decodes the response
+// into result.
+func (e *etcdClient) post(ctx context.Context, path string, body, result any, token string) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint()+path, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	response, err := kvRequest(e.client, req)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(response, result)
+}
+
+// authToken returns the token of the user, which is requested on first
+// use. Without a user, requests are not authenticated.
+func (e *etcdClient) authToken(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.token != "" {
+		return e.token, nil
+	}
+
+	// the defaults are read from ETCDCTL_USER, as user or user:password, and ETCDCTL_PASSWORD like etcdctl does
+	username, password := e.config.Username.ValueString(), e.config.Password.ValueString()
+	if username == "" {
+		username, password, _ = strings.Cut(os.Getenv("ETCDCTL_USER"), ":")
+	}
+	if password == "" {
+		password = os.Getenv("ETCDCTL_PASSWORD")
+	}
+	if username == "" {
+		return "", nil
+	}
+
+	var auth struct {
+		Token string `json:"token"`
+	}
+	if err := e.post(ctx, "/v3/auth/authenticate", map[string]string{"name": username, "password": password}, &auth, ""); err != nil {
+		return "", fmt.Errorf("could not authenticate as %s: %w", username, err)
+	}
+
+	e.token = auth.Token
+
+	return e.token, nil
+}
+
+// rangeKeys returns the key-value pairs of the range from key to rangeEnd,
+// or of key if rangeEnd is nil.
+func (e *etcdClient) rangeKeys(ctx context.Context, key, rangeEnd []byte, keysOnly bool) ([]etcdKeyValue, error) {
+	token, err := e.authToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := map[string]any{"key": base64.StdEncoding.EncodeToString(key), "keys_only": keysOnly}
+	if rangeEnd != nil {
+		request["range_end"] = base64.StdEncoding.EncodeToString(rangeEnd)
+	}
+
+	var response struct {
+		KVs []etcdKeyValue `json:"kvs"`
+	}
+	if err := e.post(ctx, "/v3/kv/range", request, &response, token); err != nil {
+		return nil, err
+	}
+
+	return response.KVs, nil
+}
+
+func (e *etcdClient) keys(ctx context.Context, prefix string) ([]string, error) {
+	kvs, err := e.rangeKeys(ctx, []byte(prefix), etcdPrefixEnd([]byte(prefix)), true)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(kvs))
+	for _, kv := range kvs {
+		keys = append(keys, string(kv.Key))
+	}
+
+	return keys, nil
+}
+
+func (e *etcdClient) get(ctx context.Context, key string) ([]byte, error) {
+	kvs, err := e.rangeKeys(ctx, []byte(key), nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(kvs) == 0 {
+		return nil, &permanentError{fmt.Errorf("key %s: %w", key, errKVNotFound)}
+	}
+
+	return kvs[0].Value, nil
+}
+
+// etcdPrefixEnd returns the end of the range of the keys starting with
+// prefix, prefix with its last byte below 0xff incremented.
+func etcdPrefixEnd(prefix []byte) []byte {
+	end := slices.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	// every key is after a prefix of 0xff bytes
+	return []byte{0}
+}
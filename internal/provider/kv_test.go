@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newTestConsulServer mocks the KV endpoints of the Consul HTTP API, values
+// maps keys to their values. Folders are listed like Consul lists keys
+// ending with a slash.
+func newTestConsulServer(t *testing.T, token string, values map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("Permission denied"))
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+
+		if r.URL.Query().Has("keys") {
+			var keys []string
+			for _, k := range slices.Sorted(maps.Keys(values)) {
+				if strings.HasPrefix(k, key) {
+					keys = append(keys, k)
+				}
+			}
+			if len(keys) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(append(keys, key+"folder/"))
+			return
+		}
+
+		value, ok := values[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(value))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newTestEtcdServer mocks the authentication and range endpoints of the JSON
+// gateway of the etcd v3 API, values maps keys to their values.
+func newTestEtcdServer(t *testing.T, values map[string]string) *httptest.Server {
+	const token = "etcd-token"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v3/auth/authenticate":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["name"] != "user" || body["password"] != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"etcdserver: authentication failed, invalid user ID or password"}`))
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": token})
+		case "/v3/kv/range":
+			if r.Header.Get("Authorization") != token {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"etcdserver: user name is empty"}`))
+				return
+			}
+
+			var body struct {
+				Key      []byte `json:"key"`
+				RangeEnd []byte `json:"range_end"`
+				KeysOnly bool   `json:"keys_only"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			var kvs []etcdKeyValue
+			for _, k := range slices.Sorted(maps.Keys(values)) {
+				key := []byte(k)
+				if body.RangeEnd == nil && !bytes.Equal(key, body.Key) {
+					continue
+				}
+				if body.RangeEnd != nil && (bytes.Compare(key, body.Key) < 0 || bytes.Compare(key, body.RangeEnd) >= 0) {
+					continue
+				}
+
+				kv := etcdKeyValue{Key: key}
+				if !body.KeysOnly {
+					kv.Value = []byte(values[k])
+				}
+				kvs = append(kvs, kv)
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"kvs": kvs})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestKVDocuments(t *testing.T) {
+	values := map[string]string{
+		"config/apps/api.yaml": "name: api\n",
+		"config/apps/web.yaml": "name: web\n",
+		"config/other.yaml":    "name: other\n",
+		"config/schema.json":   `{"type": "object"}`,
+	}
+
+	consul := newTestConsulServer(t, "consul-token", values)
+	etcd := newTestEtcdServer(t, values)
+
+	kv := newKVDocuments(
+		&ConsulConfigModel{Address: types.StringValue(consul.URL), Token: types.StringValue("consul-token")},
+		&EtcdConfigModel{Endpoint: types.StringValue(etcd.URL), Username: types.StringValue("user"), Password: types.StringValue("password")},
+		nil,
+	)
+
+	ctx := context.Background()
+
+	for _, scheme := range []string{consulScheme, etcdScheme} {
+		t.Run(scheme, func(t *testing.T) {
+			documents, err := kv.documents(ctx, scheme+"://config/apps/")
+			require.NoError(t, err)
+			require.Equal(t, []string{scheme + "://config/apps/api.yaml", scheme + "://config/apps/web.yaml"}, documents)
+
+			// keys are documents of their own
+			documents, err = kv.documents(ctx, scheme+"://config/other.yaml")
+			require.NoError(t, err)
+			require.Equal(t, []string{scheme + "://config/other.yaml"}, documents)
+
+			documents, err = kv.documents(ctx, scheme+"://missing/")
+			require.NoError(t, err)
+			require.Empty(t, documents)
+
+			content, err := kv.readDocument(ctx, scheme+"://config/apps/web.yaml")
+			require.NoError(t, err)
+			require.Equal(t, "name: web\n", string(content))
+
+			_, err = kv.readDocument(ctx, scheme+"://config/missing.yaml")
+			require.ErrorIs(t, err, errKVNotFound)
+
+			var permanent *permanentError
+			require.ErrorAs(t, err, &permanent)
+
+			schema, err := kv.Load(scheme + "://config/schema.json")
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{"type": "object"}, schema)
+		})
+	}
+
+	// credentials are required by the servers
+	unauthenticated := newKVDocuments(
+		&ConsulConfigModel{Address: types.StringValue(consul.URL)},
+		&EtcdConfigModel{Endpoint: types.StringValue(etcd.URL), Username: types.StringValue("user"), Password: types.StringValue("wrong")},
+		nil,
+	)
+
+	_, err := unauthenticated.readDocument(ctx, "consul://config/other.yaml")
+	require.ErrorContains(t, err, "403 Forbidden")
+
+	_, err = unauthenticated.readDocument(ctx, "etcd://config/other.yaml")
+	require.ErrorContains(t, err, "could not authenticate as user")
+
+	_, err = kv.readDocument(ctx, "consul://")
+	require.ErrorContains(t, err, "invalid key-value reference")
+}
+
+func TestEtcdPrefixEnd(t *testing.T) {
+	require.Equal(t, []byte("config0"), etcdPrefixEnd([]byte("config/")))
+	require.Equal(t, []byte("b"), etcdPrefixEnd([]byte{'a', 0xff}))
+	require.Equal(t, []byte{0}, etcdPrefixEnd([]byte{0xff, 0xff}))
+}
@@ -0,0 +1,338 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/vault/api"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"io"
+	"net/http"
+	gourl "net/url"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryMinBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff = 10 * time.Second
+	defaultRetryDeadline   = time.Minute
+
+	// httpLoadTimeout bounds a single attempt of loading a schema over HTTP.
+	httpLoadTimeout = 30 * time.Second
+)
+
+// RetryConfigModel describes the retry block of the provider data model.
+type RetryConfigModel struct {
+	Attempts   types.Int64  `tfsdk:"attempts"`
+	MinBackoff types.String `tfsdk:"min_backoff"`
+	MaxBackoff types.String `tfsdk:"max_backoff"`
+	Deadline   types.String `tfsdk:"deadline"`
+}
+
+// retryPolicy controls how often loads of remote schemas are attempted.
+type retryPolicy struct {
+	attempts   int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	deadline   time.Duration
+}
+
+// newRetryPolicy returns the policy of config, unset attributes keep their
+// defaults.
+func newRetryPolicy(config *RetryConfigModel) (retryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := retryPolicy{
+		attempts:   defaultRetryAttempts,
+		minBackoff: defaultRetryMinBackoff,
+		maxBackoff: defaultRetryMaxBackoff,
+		deadline:   defaultRetryDeadline,
+	}
+
+	if config == nil {
+		return policy, diags
+	}
+
+	if !config.Attempts.IsNull() {
+		policy.attempts = int(config.Attempts.ValueInt64())
+	}
+
+	durations := []struct {
+		name  string
+		value types.String
+		dst   *time.Duration
+	}{
+		{"min_backoff", config.MinBackoff, &policy.minBackoff},
+		{"max_backoff", config.MaxBackoff, &policy.maxBackoff},
+		{"deadline", config.Deadline, &policy.deadline},
+	}
+
+	for _, d := range durations {
+		if d.value.IsNull() {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(d.value.ValueString())
+		if err != nil || parsed < 0 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName(d.name),
+				"Invalid retry configuration",
+				fmt.Sprintf("Could not parse %s %q as a non-negative duration, e.g. 500ms or 1m", d.name, d.value.ValueString()),
+			)
+			continue
+		}
+
+		*d.dst = parsed
+	}
+
+	return policy, diags
+}
+
+// rawLoader is implemented by loaders that return the content of documents
+// as it is stored, e.g. to read the order properties are declared in, which
+// decoded documents do not retain.
+type rawLoader interface {
+	LoadRaw(url string) ([]byte, error)
+}
+
+// loadRaw returns the content of the document at url loaded with loader.
+// Documents of loaders without raw content are encoded as JSON, with their
+// keys sorted.
+func loadRaw(loader jsonschema.URLLoader, url string) ([]byte, error) {
+	if schemes, ok := loader.(jsonschema.SchemeURLLoader); ok {
+		u, err := gourl.Parse(url)
+		if err != nil {
+			return nil, err
+		}
+		// unsupported schemes are reported by the loader
+		if schemeLoader, ok := schemes[u.Scheme]; ok {
+			loader = schemeLoader
+		}
+	}
+
+	if raw, ok := loader.(rawLoader); ok {
+		return raw.LoadRaw(url)
+	}
+
+	doc, err := loader.Load(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// permanentError marks load errors that are not worth retrying, e.g. a 404.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// retryingLoader retries the loads of a jsonschema.URLLoader with
+// exponential backoff until the attempts or the deadline are exhausted.
+type retryingLoader struct {
+	// ctx carries the logger of the provider, URLLoader.Load has no context.
+	ctx    context.Context
+	loader jsonschema.URLLoader
+	policy retryPolicy
+}
+
+func (l *retryingLoader) Load(url string) (any, error) {
+	var doc any
+	err := l.retry(url, func() (err error) {
+		doc, err = l.loader.Load(url)
+		return err
+	})
+
+	return doc, err
+}
+
+func (l *retryingLoader) LoadRaw(url string) ([]byte, error) {
+	var content []byte
+	err := l.retry(url, func() (err error) {
+		content, err = loadRaw(l.loader, url)
+		return err
+	})
+
+	return content, err
+}
+
+// retry calls load until it succeeds, fails permanently or the policy is
+// exhausted.
+func (l *retryingLoader) retry(url string, load func() error) error {
+	start := time.Now()
+	backoff := l.policy.minBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := load()
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= l.policy.attempts {
+			return err
+		}
+
+		if time.Since(start)+backoff > l.policy.deadline {
+			return fmt.Errorf("giving up after %d attempts within %s: %w", attempt, l.policy.deadline, err)
+		}
+
+		tflog.Warn(l.ctx, "Retrying schema load", map[string]interface{}{
+			"url":     url,
+			"attempt": attempt,
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+
+		time.Sleep(backoff)
+
+		backoff = min(2*backoff, l.policy.maxBackoff)
+	}
+}
+
+// httpLoader loads schemas over HTTP and HTTPS.
+type httpLoader struct {
+	client *http.Client
+}
+
+func newHTTPLoader() *httpLoader {
+	return &httpLoader{client: &http.Client{Timeout: httpLoadTimeout}}
+}
+
+func (l *httpLoader) Load(url string) (any, error) {
+	content, err := l.LoadRaw(url)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(content))
+	if err != nil {
+		return nil, &permanentError{err}
+	}
+
+	return doc, nil
+}
+
+func (l *httpLoader) LoadRaw(url string) ([]byte, error) {
+	resp, err := l.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%s returned %s", url, resp.Status)
+		if !retryableStatus(resp.StatusCode) {
+			return nil, &permanentError{err}
+		}
+		return nil, err
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// vaultLoader marks client errors of Vault as permanent.
+type vaultLoader struct {
+	vault *vaultClient
+}
+
+func (l *vaultLoader) Load(url string) (any, error) {
+	doc, err := l.vault.Load(url)
+	if err != nil {
+		return nil, vaultLoadError(err)
+	}
+
+	return doc, nil
+}
+
+func (l *vaultLoader) LoadRaw(url string) ([]byte, error) {
+	content, err := l.vault.readDocument(context.Background(), url)
+	if err != nil {
+		return nil, vaultLoadError(err)
+	}
+
+	return content, nil
+}
+
+func vaultLoadError(err error) error {
+	var responseErr *api.ResponseError
+	if errors.As(err, &responseErr) && !retryableStatus(responseErr.StatusCode) {
+		return &permanentError{err}
+	}
+
+	return err
+}
+
+// textFileLoader is jsonschema.FileLoader for files that may start with a
+// byte order mark or be encoded as UTF-16.
+type textFileLoader struct{}
+
+func (textFileLoader) Load(url string) (any, error) {
+	file, err := (jsonschema.FileLoader{}).ToFile(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadTextFile(file)
+}
+
+func (textFileLoader) LoadRaw(url string) ([]byte, error) {
+	file, err := (jsonschema.FileLoader{}).ToFile(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return readTextFile(file, decodeText)
+}
+
+func loadTextFile(file string) (any, error) {
+	content, err := readTextFile(file, decodeText)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonschema.UnmarshalJSON(bytes.NewReader(content))
+}
+
+// driveLoader loads schemas referenced by Windows paths with a drive letter,
+// e.g. C:\schemas\person.json, which are parsed as URLs with the scheme c.
+type driveLoader struct{}
+
+func (driveLoader) Load(url string) (any, error) {
+	return loadTextFile(filepath.FromSlash(url))
+}
+
+func (driveLoader) LoadRaw(url string) ([]byte, error) {
+	return readTextFile(filepath.FromSlash(url), decodeText)
+}
+
+// registerDriveLetters adds driveLoader for every drive letter on Windows.
+func registerDriveLetters(loader jsonschema.SchemeURLLoader) {
+	if runtime.GOOS != "windows" {
+		return
+	}
+
+	for drive := 'a'; drive <= 'z'; drive++ {
+		loader[string(drive)] = driveLoader{}
+	}
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer serves schema after failing the first failures requests
+// with status, the returned counter holds the number of requests.
+func newFlakyServer(t *testing.T, failures int32, status int, schema string) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/schema+json")
+		_, _ = w.Write([]byte(schema))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestRetryingLoader(t *testing.T) {
+	policy := retryPolicy{
+		attempts:   3,
+		minBackoff: time.Millisecond,
+		maxBackoff: 2 * time.Millisecond,
+		deadline:   time.Second,
+	}
+
+	tests := []struct {
+		name     string
+		failures int32
+		status   int
+		wantErr  bool
+		requests int32
+	}{
+		{
+			name:     "retries transient errors",
+			failures: 2,
+			status:   http.StatusServiceUnavailable,
+			requests: 3,
+		},
+		{
+			name:     "gives up after attempts",
+			failures: 3,
+			status:   http.StatusBadGateway,
+			wantErr:  true,
+			requests: 3,
+		},
+		{
+			name:     "does not retry client errors",
+			failures: 1,
+			status:   http.StatusNotFound,
+			wantErr:  true,
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newFlakyServer(t, tt.failures, tt.status, `{"type": "object"}`)
+
+			loader := &retryingLoader{ctx: context.Background(), loader: newHTTPLoader(), policy: policy}
+
+			doc, err := loader.Load(server.URL + "/schema.json")
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, map[string]any{"type": "object"}, doc)
+			}
+
+			require.Equal(t, tt.requests, requests.Load())
+		})
+	}
+}
+
+func TestRetryingLoaderDeadline(t *testing.T) {
+	server, requests := newFlakyServer(t, 10, http.StatusServiceUnavailable, `{}`)
+
+	loader := &retryingLoader{
+		ctx:    context.Background(),
+		loader: newHTTPLoader(),
+		policy: retryPolicy{
+			attempts:   10,
+			minBackoff: 30 * time.Millisecond,
+			maxBackoff: 30 * time.Millisecond,
+			deadline:   50 * time.Millisecond,
+		},
+	}
+
+	_, err := loader.Load(server.URL)
+	require.ErrorContains(t, err, "giving up after 2 attempts")
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	policy, diags := newRetryPolicy(nil)
+	require.False(t, diags.HasError())
+	require.Equal(t, defaultRetryAttempts, policy.attempts)
+
+	policy, diags = newRetryPolicy(&RetryConfigModel{
+		Attempts:   types.Int64Value(5),
+		MinBackoff: types.StringValue("1s"),
+		MaxBackoff: types.StringNull(),
+		Deadline:   types.StringValue("2m"),
+	})
+	require.False(t, diags.HasError())
+	require.Equal(t, retryPolicy{
+		attempts:   5,
+		minBackoff: time.Second,
+		maxBackoff: defaultRetryMaxBackoff,
+		deadline:   2 * time.Minute,
+	}, policy)
+
+	_, diags = newRetryPolicy(&RetryConfigModel{
+		Attempts:   types.Int64Null(),
+		MinBackoff: types.StringValue("soon"),
+		MaxBackoff: types.StringNull(),
+		Deadline:   types.StringNull(),
+	})
+	require.True(t, diags.HasError())
+}
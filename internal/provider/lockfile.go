@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultLockfile is the path of the lockfile, relative to the working
+	// directory like the dependency lock file of Terraform.
+	defaultLockfile = "jsonschema.lock.json"
+	lockfileVersion = 1
+)
+
+// schemaLockfile pins the remote schemas resolved from a set of schemas to
+// the digests of their content.
+type schemaLockfile struct {
+	Version int `json:"version"`
+	// Schemas maps the URLs of remote schemas to their digests.
+	Schemas map[string]string `json:"schemas"`
+}
+
+// recordingLoader loads schemas with loader and records the documents of
+// remote schemas by URL, or of every schema if local is set.
+type recordingLoader struct {
+	loader jsonschema.URLLoader
+	local  bool
+
+	mu        sync.Mutex
+	documents map[string]any
+}
+
+func (l *recordingLoader) Load(url string) (any, error) {
+	document, err := l.loader.Load(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.local || isRemoteSchema(url) {
+		l.mu.Lock()
+		l.documents[url] = document
+		l.mu.Unlock()
+	}
+
+	return document, nil
+}
+
+// isRemoteSchema reports whether url is loaded over HTTP, local files and
+// embedded schemas do not change without the configuration changing.
+func isRemoteSchema(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// schemaDigest returns the SHA-256 digest of the JSON encoding of document,
+// which does not depend on the formatting of the schema or the order of its
+// keys.
+func schemaDigest(document any) (string, error) {
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// lockSchemas compiles the schemas at locations and returns the digests of
+// every remote schema they are resolved from, including their references.
+func (c *schemaCompiler) lockSchemas(locations []string) (map[string]string, error) {
+	loader := &recordingLoader{loader: c.loader, documents: make(map[string]any)}
+
+	// a compiler of its own loads every schema again instead of taking it from the cache
+	compiler := c.newCompiler()
+	compiler.UseLoader(loader)
+
+	for _, location := range locations {
+		if _, err := compiler.Compile(location); err != nil {
+			return nil, fmt.Errorf("could not compile schema %s: %w", location, err)
+		}
+	}
+
+	digests := make(map[string]string, len(loader.documents))
+	for url, document := range loader.documents {
+		digest, err := schemaDigest(document)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode schema %s: %w", url, err)
+		}
+		digests[url] = digest
+	}
+
+	return digests, nil
+}
+
+// hashSchemas compiles the schemas at locations and returns the SHA-256 hash
+// of the digests of every schema they are resolved from, local or remote.
+func (c *schemaCompiler) hashSchemas(locations []string) (string, error) {
+	loader := &recordingLoader{loader: c.loader, local: true, documents: make(map[string]any)}
+
+	// a compiler of its own loads every schema again instead of taking it from the cache
+	compiler := c.newCompiler()
+	compiler.UseLoader(loader)
+
+	for _, location := range locations {
+		if _, err := compiler.Compile(location); err != nil {
+			return "", fmt.Errorf("could not compile schema %s: %w", location, err)
+		}
+	}
+
+	hash := sha256.New()
+	for _, url := range sortedKeys(loader.documents) {
+		digest, err := schemaDigest(loader.documents[url])
+		if err != nil {
+			return "", fmt.Errorf("could not encode schema %s: %w", url, err)
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00", url, digest)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifySchema loads the remote schema at url and returns its digest, which
+// differs from the pinned digest if the schema changed.
+func (c *schemaCompiler) verifySchema(url string) (string, error) {
+	document, err := c.loader.Load(url)
+	if err != nil {
+		return "", err
+	}
+
+	return schemaDigest(document)
+}
+
+func readLockfile(file string) (schemaLockfile, error) {
+	var lockfile schemaLockfile
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return lockfile, err
+	}
+
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return lockfile, err
+	}
+
+	if lockfile.Version != lockfileVersion {
+		return lockfile, fmt.Errorf("unsupported lockfile version %d, expected %d", lockfile.Version, lockfileVersion)
+	}
+
+	return lockfile, nil
+}
+
+// encodeLockfile encodes the lockfile of digests with sorted keys and a
+// trailing newline, so it can be committed and diffed.
+func encodeLockfile(digests map[string]string) (string, error) {
+	encoded, err := json.MarshalIndent(schemaLockfile{Version: lockfileVersion, Schemas: digests}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded) + "\n", nil
+}
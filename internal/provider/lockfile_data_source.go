@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"slices"
+)
+
+func NewLockfileDataSource() datasource.DataSource {
+	return &LockfileDataSource{}
+}
+
+// LockfileDataSource defines the data source implementation.
+type LockfileDataSource struct {
+	compiler *schemaCompiler
+}
+
+// LockfileDataSourceModel describes the data source data model.
+type LockfileDataSourceModel struct {
+	Path    types.String `tfsdk:"path"`
+	Digests types.Map    `tfsdk:"digests"`
+}
+
+func (d *LockfileDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lockfile"
+}
+
+func (d *LockfileDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Verifies that the remote schemas pinned by a lockfile of the `jsonschema_lockfile` resource still have the digests of the lockfile, " +
+			"so validation fails instead of silently using schemas that changed upstream. " +
+			"Data sources validating against the pinned schemas can depend on it with `depends_on`.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the lockfile, defaults to `" + defaultLockfile + "`",
+				Optional:            true,
+			},
+			"digests": schema.MapAttribute{
+				Description: "Map of the URLs of the verified remote schemas to their digests",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *LockfileDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *LockfileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LockfileDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	file := data.Path.ValueString()
+	if file == "" {
+		file = defaultLockfile
+	}
+
+	lockfile, err := readLockfile(file)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Error reading lockfile",
+			"Could not read lockfile "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	urls := make([]string, 0, len(lockfile.Schemas))
+	for url := range lockfile.Schemas {
+		urls = append(urls, url)
+	}
+	slices.Sort(urls)
+
+	for _, url := range urls {
+		digest, err := d.compiler.verifySchema(url)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("path"),
+				"Error loading schema",
+				"Could not load schema "+url+" pinned by lockfile "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		if digest != lockfile.Schemas[url] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("path"),
+				"Schema digest mismatch",
+				"Schema "+url+" has digest "+digest+", but lockfile "+file+" pins "+lockfile.Schemas[url]+". "+
+					"Replace the jsonschema_lockfile resource to update the lockfile if the change is expected.",
+			)
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	digests, diags := types.MapValueFrom(ctx, types.StringType, lockfile.Schemas)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Digests = digests
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,225 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"maps"
+	"os"
+)
+
+// Ensure LockfileResource satisfies various resource interfaces.
+var _ resource.Resource = &LockfileResource{}
+var _ resource.ResourceWithConfigure = &LockfileResource{}
+
+func NewLockfileResource() resource.Resource {
+	return &LockfileResource{}
+}
+
+// LockfileResource defines the resource implementation.
+type LockfileResource struct {
+	compiler *schemaCompiler
+}
+
+// LockfileResourceModel describes the resource data model.
+type LockfileResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Path    types.String `tfsdk:"path"`
+	Schemas types.List   `tfsdk:"schemas"`
+	Digests types.Map    `tfsdk:"digests"`
+}
+
+func (r *LockfileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lockfile"
+}
+
+func (r *LockfileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Writes a lockfile pinning the remote schemas (`http://` and `https://`) that schemas are resolved from, including their references, " +
+			"to the SHA-256 digests of their content. The `jsonschema_lockfile` data source verifies the digests on later runs, " +
+			"so validation is reproducible like with the dependency lock file of Terraform. " +
+			"Digests are only updated if `schemas` change or the resource is replaced, e.g. with `terraform apply -replace`. " +
+			"The lockfile is left in place when the resource is destroyed.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Path of the lockfile",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the lockfile, defaults to `" + defaultLockfile + "`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(defaultLockfile),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schemas": schema.ListAttribute{
+				Description: "Locations of the schemas whose remote schemas are pinned, e.g. the URLs of remote schemas or local schemas referencing them",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"digests": schema.MapAttribute{
+				Description: "Map of the URLs of the pinned remote schemas to their digests",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *LockfileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+}
+
+func (r *LockfileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data LockfileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LockfileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data LockfileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var digests map[string]string
+	resp.Diagnostics.Append(data.Digests.ElementsAs(ctx, &digests, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The lockfile was removed or edited outside of Terraform, so it has to be written again.
+	lockfile, err := readLockfile(data.Path.ValueString())
+	if err != nil || !maps.Equal(lockfile.Schemas, digests) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LockfileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data LockfileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *LockfileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The lockfile is committed to the repository, so it is kept on disk.
+}
+
+// write resolves the remote schemas of data.Schemas, writes their digests
+// to the lockfile and fills in the computed attributes of data.
+func (r *LockfileResource) write(ctx context.Context, data *LockfileResourceModel, diags *diag.Diagnostics) {
+	var locations []string
+	diags.Append(data.Schemas.ElementsAs(ctx, &locations, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	digests, err := r.compiler.lockSchemas(locations)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("schemas"),
+			"Error locking schemas",
+			"Could not resolve the remote schemas: "+err.Error(),
+		)
+		return
+	}
+
+	content, err := encodeLockfile(digests)
+	if err != nil {
+		diags.AddError(
+			"Error encoding lockfile",
+			"Could not encode lockfile: "+err.Error(),
+		)
+		return
+	}
+
+	file := data.Path.ValueString()
+
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		diags.AddAttributeError(
+			path.Root("path"),
+			"Error writing lockfile",
+			"Could not write lockfile "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	lockedDigests, d := types.MapValueFrom(ctx, types.StringType, digests)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(file)
+	data.Digests = lockedDigests
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestLockfile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var mu sync.Mutex
+	schemas := map[string]string{
+		"/root.json": `{"type": "object", "properties": {"name": {"$ref": "defs.json#/$defs/name"}}}`,
+		"/defs.json": `{"$defs": {"name": {"type": "string"}}}`,
+	}
+	setSchema := func(name, content string) {
+		mu.Lock()
+		defer mu.Unlock()
+		schemas[name] = content
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		schema, ok := schemas[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(schema))
+	}))
+	defer server.Close()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "local.json"), []byte(fmt.Sprintf(`{"$ref": "%s/root.json"}`, server.URL)), 0644)
+	require.NoError(t, err)
+
+	lockfile := filepath.Join(tmpDir, defaultLockfile)
+	config := fmt.Sprintf(testAccLockfileConfig, lockfile, filepath.Join(tmpDir, "local.json"))
+
+	digests := statecheck.ExpectKnownValue(
+		"jsonschema_lockfile.test",
+		tfjsonpath.New("digests"),
+		knownvalue.MapExact(map[string]knownvalue.Check{
+			server.URL + "/root.json": knownvalue.StringRegexp(regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)),
+			server.URL + "/defs.json": knownvalue.StringRegexp(regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)),
+		}),
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				ConfigStateChecks: []statecheck.StateCheck{
+					digests,
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_lockfile.test",
+						tfjsonpath.New("digests"),
+						knownvalue.MapSizeExact(2),
+					),
+				},
+			},
+			// formatting does not change the digest
+			{
+				PreConfig: func() {
+					setSchema("/defs.json", "{\n  \"$defs\": {\"name\": {\"type\": \"string\"}}\n}\n")
+				},
+				Config:            config,
+				ConfigStateChecks: []statecheck.StateCheck{digests},
+			},
+			{
+				PreConfig: func() {
+					setSchema("/defs.json", `{"$defs": {"name": {"type": "string", "maxLength": 3}}}`)
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile(`(?s)Schema digest mismatch.*defs\.json`),
+			},
+			// the lockfile is written again, if it was removed
+			{
+				PreConfig: func() {
+					setSchema("/defs.json", `{"$defs": {"name": {"type": "string"}}}`)
+					require.NoError(t, os.Remove(lockfile))
+				},
+				Config:            config,
+				ConfigStateChecks: []statecheck.StateCheck{digests},
+			},
+		},
+	})
+
+	content, err := os.ReadFile(lockfile)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"version": 1`)
+	require.Contains(t, string(content), server.URL+"/defs.json")
+}
+
+const testAccLockfileConfig = `
+resource "jsonschema_lockfile" "test" {
+  path    = "%s"
+  schemas = ["%s"]
+}
+
+data "jsonschema_lockfile" "test" {
+  path = jsonschema_lockfile.test.path
+}
+`
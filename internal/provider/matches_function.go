@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"strings"
+)
+
+// inlineSchemaLocation is the location schemas passed to provider functions
+// are compiled at, relative references resolve against the working directory.
+const inlineSchemaLocation = "inline.schema.json"
+
+// Ensure MatchesFunction satisfies the function interface.
+var _ function.Function = &MatchesFunction{}
+
+func NewMatchesFunction() function.Function {
+	return &MatchesFunction{}
+}
+
+// MatchesFunction defines the function implementation.
+type MatchesFunction struct{}
+
+func (f *MatchesFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "matches"
+}
+
+func (f *MatchesFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether a document conforms to a json schema",
+		MarkdownDescription: "Returns `true` if the JSON or YAML encoded document conforms to the json schema and `false` otherwise, " +
+			"including when the document cannot be decoded. Only a schema that does not compile is an error, " +
+			"which makes the function suitable for conditional expressions picking the schema variant a document satisfies.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "doc",
+				MarkdownDescription: "JSON or YAML encoded document, e.g. the result of `jsonencode`",
+			},
+			function.StringParameter{
+				Name:                "schema_json",
+				MarkdownDescription: "JSON encoded json schema",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *MatchesFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var doc, schemaJSON string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &doc, &schemaJSON))
+	if resp.Error != nil {
+		return
+	}
+
+	compiledSchema, err := compileSchemaString(schemaJSON)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Could not compile schema: "+err.Error())
+		return
+	}
+
+	matches := false
+
+	if value, err := decodeYAML([]byte(doc)); err == nil {
+		matches = compiledSchema.Validate(value) == nil
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, matches))
+}
+
+// compileSchemaString compiles a JSON encoded schema with a compiler of its
+// own, so that schemas passed to functions never clash with each other.
+func compileSchemaString(schemaJSON string) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schemaJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(inlineSchemaLocation, doc); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(inlineSchemaLocation)
+}
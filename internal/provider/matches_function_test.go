@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestMatchesFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccMatchesFunctionConfig, `jsonencode({ id = "example-id", name = "Example Name" })`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Bool(true)),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccMatchesFunctionConfig, `"id: example-id\nname: Example Name"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Bool(true)),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccMatchesFunctionConfig, `jsonencode({ id = "example-id" })`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Bool(false)),
+				},
+			},
+			// documents that cannot be decoded do not match
+			{
+				Config: fmt.Sprintf(testAccMatchesFunctionConfig, `"{ not: [valid"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Bool(false)),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::jsonschema::matches("{}", "{ not json")
+}
+`,
+				ExpectError: regexp.MustCompile(`Could not compile schema`),
+			},
+		},
+	})
+}
+
+const testAccMatchesFunctionConfig = `
+output "test" {
+  value = provider::jsonschema::matches(%s, jsonencode({
+    type     = "object"
+    required = ["id", "name"]
+    properties = {
+      id   = { type = "string" }
+      name = { type = "string" }
+    }
+  }))
+}
+`
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// modelinePrefix is the start of the modeline of the YAML language server
+// that references the schema of a file.
+const modelinePrefix = "# yaml-language-server: $schema="
+
+// modelineReference returns the reference to schema in the modeline of
+// file: URLs as they are and local paths relative to the directory of file,
+// which references in modelines are resolved against.
+func modelineReference(file, schema string) (string, error) {
+	if urlRegex.MatchString(schema) {
+		return schema, nil
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filepath.FromSlash(file)))
+	if err != nil {
+		return "", err
+	}
+
+	abs, err := filepath.Abs(filepath.FromSlash(schema))
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// setModeline returns content with the modeline referencing ref as its
+// first line. A modeline in the first line is replaced, a modeline in a
+// comment line further down is moved to the first line, since the first
+// modeline of a file applies. Byte order marks and CRLF line endings are
+// kept.
+func setModeline(content, ref string) string {
+	bom := ""
+	if strings.HasPrefix(content, string(utf8BOM)) {
+		bom = string(utf8BOM)
+		content = content[len(bom):]
+	}
+
+	newline := "\n"
+	if line, _, ok := strings.Cut(content, "\n"); ok && strings.HasSuffix(line, "\r") {
+		newline = "\r\n"
+	}
+
+	modeline := modelinePrefix + ref
+
+	if loc := schemaRegex.FindStringIndex(content); loc != nil {
+		// the whole line holding the modeline, up to and including its line ending
+		start := strings.LastIndex(content[:loc[0]], "\n") + 1
+		end := len(content)
+		if i := strings.Index(content[loc[1]:], "\n"); i >= 0 {
+			end = loc[1] + i + 1
+		}
+
+		switch {
+		case start == 0 && loc[0] == 0:
+			return bom + modeline + newline + content[end:]
+		case strings.TrimSpace(content[start:loc[0]]) == "":
+			content = content[:start] + content[end:]
+		}
+	}
+
+	return bom + modeline + newline + content
+}
@@ -0,0 +1,270 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"maps"
+	"os"
+	"slices"
+)
+
+// Ensure ModelineResource satisfies various resource interfaces.
+var _ resource.Resource = &ModelineResource{}
+var _ resource.ResourceWithConfigure = &ModelineResource{}
+
+func NewModelineResource() resource.Resource {
+	return &ModelineResource{}
+}
+
+// ModelineResource defines the resource implementation.
+type ModelineResource struct {
+	compiler *schemaCompiler
+}
+
+// ModelineResourceModel describes the resource data model.
+type ModelineResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	InputPattern types.String `tfsdk:"input_pattern"`
+	Schema       types.String `tfsdk:"schema"`
+	References   types.Map    `tfsdk:"references"`
+}
+
+func (r *ModelineResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_modeline"
+}
+
+func (r *ModelineResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Ensures the first line of YAML files is the modeline `# yaml-language-server: $schema=...` referencing a json schema, " +
+			"so editors and the data sources of this provider associate the files with the same schema. " +
+			"Missing modelines are added, modelines referencing another schema are fixed and modelines further down a file are moved to its first line. " +
+			"Local schemas are referenced relative to the directory of each file, like references in modelines are resolved. " +
+			"The files are written again if their modelines are edited outside of Terraform or the pattern matches other files. " +
+			"The files are left as they are when the resource is destroyed.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Input pattern of the files",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of the YAML files to write the modeline to",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path or URL of the json schema the modeline references",
+				Required:    true,
+			},
+			"references": schema.MapAttribute{
+				Description: "Schema references written to the modelines, by file",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *ModelineResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+}
+
+func (r *ModelineResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ModelineResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ModelineResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ModelineResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	references := make(map[string]string)
+	resp.Diagnostics.Append(data.References.ElementsAs(ctx, &references, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The pattern matches other files now, so the modelines have to be written again.
+	var globDiags diag.Diagnostics
+	files := globInputFiles(data.InputPattern.ValueString(), &globDiags)
+	slices.Sort(files)
+	if !slices.Equal(files, slices.Sorted(maps.Keys(references))) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// A file was removed or its modeline edited outside of Terraform.
+	for file, ref := range references {
+		contentRaw, err := os.ReadFile(file)
+		if err != nil || setModeline(string(contentRaw), ref) != string(contentRaw) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ModelineResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ModelineResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ModelineResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The modelines are part of the files, so they are kept on disk.
+}
+
+// write sets the modeline referencing data.Schema in the files matched by
+// data.InputPattern, writes back the files that changed and fills in the
+// computed attributes of data.
+func (r *ModelineResource) write(ctx context.Context, data *ModelineResourceModel, diags *diag.Diagnostics) {
+	schemaPath := data.Schema.ValueString()
+
+	// a modeline referencing a schema that does not compile would fail validation later
+	if _, err := r.compiler.Compile(schemaPath); err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), diags)
+	if diags.HasError() {
+		return
+	}
+
+	references := make(map[string]string, len(files))
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error opening file",
+				"Could not open file "+file+": "+err.Error(),
+			)
+			return
+		}
+
+		contentRaw, err := os.ReadFile(file)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error reading file",
+				"Could not read file "+file+": "+err.Error(),
+			)
+			return
+		}
+
+		if isJSONInput(file, string(contentRaw)) {
+			diags.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error writing modeline",
+				"File "+file+" is JSON, which has no comments and references its schema in the $schema property",
+			)
+			return
+		}
+
+		ref, err := modelineReference(file, schemaPath)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("schema"),
+				"Error writing modeline",
+				"Could not reference schema "+schemaPath+" from file "+file+": "+err.Error(),
+			)
+			return
+		}
+
+		content := setModeline(string(contentRaw), ref)
+
+		if content != string(contentRaw) {
+			if err := os.WriteFile(file, []byte(content), fi.Mode().Perm()); err != nil {
+				diags.AddAttributeError(
+					path.Root("input_pattern"),
+					"Error writing file",
+					"Could not write file "+file+": "+err.Error(),
+				)
+				return
+			}
+		}
+
+		references[file] = ref
+	}
+
+	referencesMap, mapDiags := types.MapValueFrom(ctx, types.StringType, references)
+	diags.Append(mapDiags...)
+
+	data.ID = types.StringValue(data.InputPattern.ValueString())
+	data.References = referencesMap
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestSetModeline(t *testing.T) {
+	for name, tc := range map[string]struct {
+		content  string
+		expected string
+	}{
+		"missing": {
+			content:  "id: example-id\n",
+			expected: "# yaml-language-server: $schema=schema.json\nid: example-id\n",
+		},
+		"empty": {
+			content:  "",
+			expected: "# yaml-language-server: $schema=schema.json\n",
+		},
+		"up to date": {
+			content:  "# yaml-language-server: $schema=schema.json\nid: example-id\n",
+			expected: "# yaml-language-server: $schema=schema.json\nid: example-id\n",
+		},
+		"other schema": {
+			content:  "# yaml-language-server: $schema=../old.json  \nid: example-id\n",
+			expected: "# yaml-language-server: $schema=schema.json\nid: example-id\n",
+		},
+		"further down": {
+			content:  "---\n# a comment\n  # yaml-language-server: $schema=old.json\nid: example-id\n",
+			expected: "# yaml-language-server: $schema=schema.json\n---\n# a comment\nid: example-id\n",
+		},
+		"trailing comment": {
+			content:  "id: example-id # yaml-language-server: $schema=old.json\n",
+			expected: "# yaml-language-server: $schema=schema.json\nid: example-id # yaml-language-server: $schema=old.json\n",
+		},
+		"crlf": {
+			content:  "\xEF\xBB\xBFid: example-id\r\nname: Example\r\n",
+			expected: "\xEF\xBB\xBF# yaml-language-server: $schema=schema.json\r\nid: example-id\r\nname: Example\r\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, setModeline(tc.content, "schema.json"))
+			require.Equal(t, tc.expected, setModeline(tc.expected, "schema.json"))
+		})
+	}
+}
+
+func TestModelineReference(t *testing.T) {
+	ref, err := modelineReference("configs/apps/web.yaml", "schemas/app.json")
+	require.NoError(t, err)
+	require.Equal(t, "../../schemas/app.json", ref)
+
+	ref, err = modelineReference("web.yaml", "https://example.com/app.json")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/app.json", ref)
+}
+
+func TestModeline(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.Join(tmpDir, "schemas", "schema.json")
+	otherSchemaPath := filepath.Join(tmpDir, "schemas", "other.json")
+
+	for name, content := range map[string]string{
+		"schemas/schema.json":  testAccValidatedYAMLDataSourceSchema,
+		"schemas/other.json":   `{"type": "object"}`,
+		"configs/web.yaml":     "id: web\nname: Web\n",
+		"configs/worker.yaml":  "# yaml-language-server: $schema=./old.json\nid: worker\nname: Worker\n",
+		"configs/invalid.json": `{"id": "invalid"}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	web := filepath.ToSlash(filepath.Join(tmpDir, "configs", "web.yaml"))
+	worker := filepath.ToSlash(filepath.Join(tmpDir, "configs", "worker.yaml"))
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, "configs", "*.yaml"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccModelineResourceConfig, filepath.ToSlash(filepath.Join(tmpDir, "configs", "*.json")), filepath.ToSlash(schemaPath)),
+				ExpectError: regexp.MustCompile(`Error writing modeline`),
+			},
+			{
+				Config: fmt.Sprintf(testAccModelineResourceConfig, pattern, filepath.ToSlash(schemaPath)),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"jsonschema_modeline.test",
+						tfjsonpath.New("references"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							web:    knownvalue.StringExact("../schemas/schema.json"),
+							worker: knownvalue.StringExact("../schemas/schema.json"),
+						}),
+					),
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckFileContent(web, "# yaml-language-server: $schema=../schemas/schema.json\nid: web\nname: Web\n"),
+					testAccCheckFileContent(worker, "# yaml-language-server: $schema=../schemas/schema.json\nid: worker\nname: Worker\n"),
+				),
+			},
+			// Modelines edited outside of Terraform and new files are written again
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(web, []byte("id: web\nname: Web\n"), 0644))
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "configs", "db.yaml"), []byte("id: db\nname: DB\n"), 0644))
+				},
+				Config: fmt.Sprintf(testAccModelineResourceConfig, pattern, filepath.ToSlash(schemaPath)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckFileContent(web, "# yaml-language-server: $schema=../schemas/schema.json\nid: web\nname: Web\n"),
+					testAccCheckFileContent(filepath.Join(tmpDir, "configs", "db.yaml"), "# yaml-language-server: $schema=../schemas/schema.json\nid: db\nname: DB\n"),
+				),
+			},
+			// Update testing
+			{
+				Config: fmt.Sprintf(testAccModelineResourceConfig, pattern, filepath.ToSlash(otherSchemaPath)),
+				Check:  testAccCheckFileContent(worker, "# yaml-language-server: $schema=../schemas/other.json\nid: worker\nname: Worker\n"),
+			},
+		},
+	})
+}
+
+const testAccModelineResourceConfig = `
+resource "jsonschema_modeline" "test" {
+  input_pattern = "%s"
+  schema        = "%s"
+}
+`
@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// openAPIComponent returns the component schema name of an OpenAPI 3.x or
+// Swagger 2.0 document as a standalone JSON Schema of draft 2020-12, with
+// the components it references embedded under $defs and their references
+// rewritten accordingly. The names of the embedded components are returned
+// with the schema.
+func openAPIComponent(document any, name string) (map[string]any, []string, error) {
+	root, ok := document.(map[string]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("document is not an object")
+	}
+
+	var components map[string]any
+	var prefix, version string
+	switch {
+	case root["openapi"] != nil:
+		version = fmt.Sprint(root["openapi"])
+		prefix = "#/components/schemas/"
+		container, _ := root["components"].(map[string]any)
+		components, _ = container["schemas"].(map[string]any)
+	case root["swagger"] != nil:
+		version = fmt.Sprint(root["swagger"])
+		prefix = "#/definitions/"
+		components, _ = root["definitions"].(map[string]any)
+	default:
+		return nil, nil, fmt.Errorf("document has neither openapi nor swagger version")
+	}
+
+	// the schemas of OpenAPI 3.1 are JSON Schema already, earlier versions use a dialect of draft-04
+	jsonSchema := !strings.HasPrefix(version, "3.0") && !strings.HasPrefix(version, "2.")
+
+	if _, ok := components[name]; !ok {
+		return nil, nil, fmt.Errorf("document has no component schema %s, components: %s", name, strings.Join(slices.Sorted(maps.Keys(components)), ", "))
+	}
+
+	// components are converted when they are first referenced
+	converted := make(map[string]any)
+	pending := []string{name}
+
+	var convertErr error
+	rewrite := func(ref string) string {
+		if !strings.HasPrefix(ref, prefix) {
+			if strings.HasPrefix(ref, "#") && convertErr == nil {
+				convertErr = fmt.Errorf("reference %s is not a component schema of the document", ref)
+			}
+			return ref
+		}
+
+		target := openAPIComponentName(strings.TrimPrefix(ref, prefix))
+		if target == name {
+			return "#"
+		}
+		if _, ok := components[target]; !ok && convertErr == nil {
+			convertErr = fmt.Errorf("reference %s does not resolve to a component schema", ref)
+		}
+		if _, ok := converted[target]; !ok && !slices.Contains(pending, target) {
+			pending = append(pending, target)
+		}
+
+		return "#/$defs/" + escapePointerToken(target)
+	}
+
+	for len(pending) > 0 {
+		component := pending[0]
+		pending = pending[1:]
+		converted[component] = convertOpenAPISchema(components[component], jsonSchema, rewrite)
+	}
+
+	if convertErr != nil {
+		return nil, nil, convertErr
+	}
+
+	schema, ok := converted[name].(map[string]any)
+	if !ok {
+		schema = map[string]any{"allOf": []any{converted[name]}}
+	}
+	delete(converted, name)
+
+	if len(converted) > 0 {
+		defs, _ := schema["$defs"].(map[string]any)
+		if defs == nil {
+			defs = make(map[string]any, len(converted))
+		}
+		for component, value := range converted {
+			if _, ok := defs[component]; ok {
+				return nil, nil, fmt.Errorf("$defs of component schema %s already have a schema %s", name, component)
+			}
+			defs[component] = value
+		}
+		schema["$defs"] = defs
+	}
+
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+
+	return schema, slices.Sorted(maps.Keys(converted)), nil
+}
+
+// openAPIComponentName returns the name of a component from the last token
+// of its reference, which may be percent-encoded and pointer-escaped.
+func openAPIComponentName(token string) string {
+	if unescaped, err := url.PathUnescape(token); err == nil {
+		token = unescaped
+	}
+
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+// convertOpenAPISchema returns a copy of schema with references rewritten
+// by rewrite. Schemas of OpenAPI 3.0 and Swagger 2.0, which are not
+// jsonSchema but a dialect of draft-04, are converted to draft 2020-12:
+// nullable adds null to type and enum, example becomes examples and
+// boolean exclusiveMinimum and exclusiveMaximum become numbers. Values that
+// are not subschemas, e.g. of enum or the names of properties, are kept as
+// they are.
+func convertOpenAPISchema(schema any, jsonSchema bool, rewrite func(ref string) string) any {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return schema
+	}
+
+	converted := make(map[string]any, len(object))
+	for key, value := range object {
+		switch {
+		case key == "$ref":
+			if ref, ok := value.(string); ok {
+				value = rewrite(ref)
+			}
+		case slices.Contains(schemaMapKeywords, key):
+			if subschemas, ok := value.(map[string]any); ok {
+				convertedSubschemas := make(map[string]any, len(subschemas))
+				for name, subschema := range subschemas {
+					convertedSubschemas[name] = convertOpenAPISchema(subschema, jsonSchema, rewrite)
+				}
+				value = convertedSubschemas
+			}
+		case slices.Contains(schemaListKeywords, key):
+			if subschemas, ok := value.([]any); ok {
+				convertedSubschemas := make([]any, 0, len(subschemas))
+				for _, subschema := range subschemas {
+					convertedSubschemas = append(convertedSubschemas, convertOpenAPISchema(subschema, jsonSchema, rewrite))
+				}
+				value = convertedSubschemas
+				break
+			}
+			fallthrough
+		case slices.Contains(subschemaKeywords, key):
+			value = convertOpenAPISchema(value, jsonSchema, rewrite)
+		}
+
+		converted[key] = value
+	}
+
+	if jsonSchema {
+		return converted
+	}
+
+	if nullable, _ := converted["nullable"].(bool); nullable {
+		switch t := converted["type"].(type) {
+		case string:
+			converted["type"] = []any{t, "null"}
+		case []any:
+			if !slices.Contains(t, any("null")) {
+				converted["type"] = append(t, "null")
+			}
+		}
+		if enum, ok := converted["enum"].([]any); ok && !slices.Contains(enum, nil) {
+			converted["enum"] = append(enum, nil)
+		}
+	}
+	delete(converted, "nullable")
+
+	if example, ok := converted["example"]; ok {
+		if _, ok := converted["examples"]; !ok {
+			converted["examples"] = []any{example}
+		}
+		delete(converted, "example")
+	}
+
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		if isExclusive, ok := converted[exclusive].(bool); ok {
+			delete(converted, exclusive)
+			if limit, ok := converted[bound]; ok && isExclusive {
+				converted[exclusive] = limit
+				delete(converted, bound)
+			}
+		}
+	}
+
+	return converted
+}
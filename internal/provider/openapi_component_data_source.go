@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"strings"
+)
+
+// Ensure OpenAPIComponentDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &OpenAPIComponentDataSource{}
+
+func NewOpenAPIComponentDataSource() datasource.DataSource {
+	return &OpenAPIComponentDataSource{}
+}
+
+// OpenAPIComponentDataSource defines the data source implementation.
+type OpenAPIComponentDataSource struct {
+	compiler *schemaCompiler
+}
+
+// OpenAPIComponentDataSourceModel describes the data source data model.
+type OpenAPIComponentDataSourceModel struct {
+	Document   types.String `tfsdk:"document"`
+	Component  types.String `tfsdk:"component"`
+	SchemaJSON types.String `tfsdk:"schema_json"`
+	Components types.List   `tfsdk:"components"`
+}
+
+func (d *OpenAPIComponentDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_openapi_component"
+}
+
+func (d *OpenAPIComponentDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Extracts a component schema of an OpenAPI 3.x or Swagger 2.0 document as a standalone json schema, " +
+			"so the payload schemas of an API can validate configuration fixtures, e.g. with the `matches` function or written to a file with `local_file`. " +
+			"The components the schema references are embedded under `$defs` and references to them are rewritten, references to the component itself become `#`. " +
+			"Schemas of OpenAPI 3.0 and Swagger 2.0 are converted to draft 2020-12: `nullable` adds `null` to `type` and `enum`, `example` becomes `examples` " +
+			"and boolean `exclusiveMinimum` and `exclusiveMaximum` become numbers. " +
+			"References to other parts of the document fail, references to other documents are kept as they are.",
+
+		Attributes: map[string]schema.Attribute{
+			"document": schema.StringAttribute{
+				MarkdownDescription: "Path or URL of the OpenAPI document. Local documents may be YAML or JSON, remote documents are loaded like schemas and have to be JSON.",
+				Required:            true,
+			},
+			"component": schema.StringAttribute{
+				MarkdownDescription: "Name of the schema below `components.schemas`, or `definitions` of Swagger 2.0",
+				Required:            true,
+			},
+			"schema_json": schema.StringAttribute{
+				Description: "JSON encoded standalone json schema of the component",
+				Computed:    true,
+			},
+			"components": schema.ListAttribute{
+				MarkdownDescription: "Names of the components embedded under `$defs`, sorted",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *OpenAPIComponentDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *OpenAPIComponentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OpenAPIComponentDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	location := data.Document.ValueString()
+
+	document, err := d.loadDocument(location)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("document"),
+			"Error loading document",
+			"Could not load OpenAPI document "+location+": "+err.Error(),
+		)
+		return
+	}
+
+	component, components, err := openAPIComponent(document, data.Component.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("component"),
+			"Error extracting component",
+			"Could not extract component "+data.Component.ValueString()+" of OpenAPI document "+location+": "+err.Error(),
+		)
+		return
+	}
+
+	encoded, err := json.MarshalIndent(component, "", "  ")
+	if err != nil {
+		resp.Diagnostics.AddError("Error encoding schema", "Could not encode schema of component "+data.Component.ValueString()+": "+err.Error())
+		return
+	}
+
+	data.SchemaJSON = types.StringValue(string(encoded))
+
+	componentsList, diags := types.ListValueFrom(ctx, types.StringType, components)
+	resp.Diagnostics.Append(diags...)
+
+	data.Components = componentsList
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// loadDocument decodes the local OpenAPI document at location as YAML,
+// which JSON is a subset of, or loads a remote one with the loaders of the
+// provider.
+func (d *OpenAPIComponentDataSource) loadDocument(location string) (any, error) {
+	file := location
+	if urlRegex.MatchString(location) {
+		if !strings.HasPrefix(location, "file://") {
+			return d.compiler.loader.Load(location)
+		}
+
+		local, err := (jsonschema.FileLoader{}).ToFile(location)
+		if err != nil {
+			return nil, err
+		}
+		file = local
+	}
+
+	content, err := readTextFile(file, decodeText)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeYAML(content)
+}
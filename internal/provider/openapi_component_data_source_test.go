@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const testAccOpenAPIDocument = `openapi: 3.0.3
+info:
+  title: Pet store
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          example: Rex
+        age:
+          type: integer
+          minimum: 0
+          exclusiveMinimum: true
+        nullable:
+          type: string
+          nullable: true
+          enum: [a, b]
+        category:
+          $ref: '#/components/schemas/Category'
+        parent:
+          $ref: '#/components/schemas/Pet'
+    Category:
+      type: object
+      properties:
+        tags:
+          type: array
+          items:
+            $ref: '#/components/schemas/Tag'
+    Tag:
+      type: string
+    Unrelated:
+      type: object
+    Broken:
+      $ref: '#/components/responses/Error'
+`
+
+func TestOpenAPIComponent(t *testing.T) {
+	document, err := decodeYAML([]byte(testAccOpenAPIDocument))
+	require.NoError(t, err)
+
+	component, components, err := openAPIComponent(document, "Pet")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Category", "Tag"}, components)
+
+	// compare the JSON encoding, decoded YAML numbers are json.Number
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["name"],
+  "properties": {
+    "name": {"type": "string", "examples": ["Rex"]},
+    "age": {"type": "integer", "exclusiveMinimum": 0},
+    "nullable": {"type": ["string", "null"], "enum": ["a", "b", null]},
+    "category": {"$ref": "#/$defs/Category"},
+    "parent": {"$ref": "#"}
+  },
+  "$defs": {
+    "Category": {"type": "object", "properties": {"tags": {"type": "array", "items": {"$ref": "#/$defs/Tag"}}}},
+    "Tag": {"type": "string"}
+  }
+}`
+	actual, err := json.Marshal(component)
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(actual))
+
+	// the converted schema compiles and validates like the component
+	schemaPath := filepath.Join(t.TempDir(), "pet.json")
+	require.NoError(t, os.WriteFile(schemaPath, actual, 0644))
+
+	sch, err := newSchemaCompiler(nil, nil).Compile(schemaPath)
+	require.NoError(t, err)
+	require.NoError(t, sch.Validate(map[string]any{"name": "Rex", "nullable": nil, "parent": map[string]any{"name": "Max"}, "category": map[string]any{"tags": []any{"dog"}}}))
+	require.Error(t, sch.Validate(map[string]any{"name": "Rex", "age": 0}))
+	require.Error(t, sch.Validate(map[string]any{"name": "Rex", "category": map[string]any{"tags": []any{1}}}))
+
+	_, _, err = openAPIComponent(document, "Broken")
+	require.ErrorContains(t, err, "reference #/components/responses/Error is not a component schema")
+
+	_, _, err = openAPIComponent(document, "Missing")
+	require.ErrorContains(t, err, "document has no component schema Missing, components: Broken, Category, Pet, Tag, Unrelated")
+
+	// schemas of OpenAPI 3.1 and definitions of Swagger 2.0
+	component, _, err = openAPIComponent(map[string]any{
+		"openapi":    "3.1.0",
+		"components": map[string]any{"schemas": map[string]any{"Name": map[string]any{"type": []any{"string", "null"}, "example": "a"}}},
+	}, "Name")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": []any{"string", "null"}, "example": "a"}, component)
+
+	component, components, err = openAPIComponent(map[string]any{
+		"swagger":     "2.0",
+		"definitions": map[string]any{"Pet": map[string]any{"$ref": "#/definitions/Name"}, "Name": map[string]any{"type": "string"}},
+	}, "Pet")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Name"}, components)
+	require.Equal(t, "#/$defs/Name", component["$ref"])
+}
+
+func TestOpenAPIComponentDataSource(t *testing.T) {
+	documentPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	err := os.WriteFile(documentPath, []byte(testAccOpenAPIDocument), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccOpenAPIComponentDataSourceConfig, documentPath, "Pet"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_openapi_component.pet",
+						tfjsonpath.New("components"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("Category"), knownvalue.StringExact("Tag")}),
+					),
+					statecheck.ExpectKnownOutputValue("valid", knownvalue.Bool(true)),
+					statecheck.ExpectKnownOutputValue("invalid", knownvalue.Bool(false)),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccOpenAPIComponentDataSourceConfig, documentPath, "Broken"),
+				ExpectError: regexp.MustCompile(`Error extracting component`),
+			},
+		},
+	})
+}
+
+const testAccOpenAPIComponentDataSourceConfig = `
+data "jsonschema_openapi_component" "pet" {
+  document  = "%s"
+  component = "%s"
+}
+
+output "valid" {
+  value = provider::jsonschema::matches(jsonencode({
+    name     = "Rex"
+    category = { tags = ["dog"] }
+  }), data.jsonschema_openapi_component.pet.schema_json)
+}
+
+output "invalid" {
+  value = provider::jsonschema::matches(jsonencode({
+    name     = "Rex"
+    category = { tags = [1] }
+  }), data.jsonschema_openapi_component.pet.schema_json)
+}
+`
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"reflect"
+	"slices"
+)
+
+// overlayLoader loads schemas with loader and merges overlay onto the
+// document at url.
+type overlayLoader struct {
+	loader  jsonschema.URLLoader
+	url     string
+	overlay map[string]any
+}
+
+func (l overlayLoader) Load(url string) (any, error) {
+	document, err := l.loader.Load(url)
+	if err != nil || url != l.url {
+		return document, err
+	}
+
+	return mergeSchemaOverlay(document, l.overlay), nil
+}
+
+// mergeSchemaOverlay returns document with overlay deep merged onto it,
+// without modifying either. Objects are merged by keyword, a null removes
+// the keyword. Arrays are extended with the values they do not contain yet,
+// e.g. to require more properties or to allow more enum values. Any other
+// value replaces the value of the document.
+func mergeSchemaOverlay(document, overlay any) any {
+	switch o := overlay.(type) {
+	case map[string]any:
+		d, ok := document.(map[string]any)
+		if !ok {
+			// e.g. a boolean schema, which is replaced by the overlay
+			d = map[string]any{}
+		}
+		merged := make(map[string]any, len(d)+len(o))
+		for key, value := range d {
+			merged[key] = value
+		}
+		for key, value := range o {
+			if value == nil {
+				delete(merged, key)
+				continue
+			}
+			merged[key] = mergeSchemaOverlay(d[key], value)
+		}
+		return merged
+	case []any:
+		d, ok := document.([]any)
+		if !ok {
+			return o
+		}
+		merged := slices.Clone(d)
+		for _, value := range o {
+			if !slices.ContainsFunc(merged, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+				merged = append(merged, value)
+			}
+		}
+		return merged
+	}
+
+	return overlay
+}
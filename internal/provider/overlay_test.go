@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestMergeSchemaOverlay(t *testing.T) {
+	decode := func(s string) any {
+		value, err := jsonschema.UnmarshalJSON(strings.NewReader(s))
+		require.NoError(t, err)
+		return value
+	}
+
+	for name, tt := range map[string]struct {
+		document, overlay, expected string
+	}{
+		"required": {
+			document: `{"type": "object", "required": ["id"]}`,
+			overlay:  `{"required": ["id", "owner"]}`,
+			expected: `{"type": "object", "required": ["id", "owner"]}`,
+		},
+		"enum": {
+			document: `{"properties": {"env": {"enum": ["dev", "prod"]}}}`,
+			overlay:  `{"properties": {"env": {"enum": ["staging"]}}}`,
+			expected: `{"properties": {"env": {"enum": ["dev", "prod", "staging"]}}}`,
+		},
+		"replace": {
+			document: `{"maxItems": 10, "additionalProperties": true}`,
+			overlay:  `{"maxItems": 5, "additionalProperties": {"type": "string"}}`,
+			expected: `{"maxItems": 5, "additionalProperties": {"type": "string"}}`,
+		},
+		"remove": {
+			document: `{"type": "object", "minProperties": 1}`,
+			overlay:  `{"minProperties": null}`,
+			expected: `{"type": "object"}`,
+		},
+		"boolean schema": {
+			document: `{"properties": {"id": true}}`,
+			overlay:  `{"properties": {"id": {"type": "string"}}}`,
+			expected: `{"properties": {"id": {"type": "string"}}}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			document := decode(tt.document)
+			merged := mergeSchemaOverlay(document, decode(tt.overlay))
+			require.Equal(t, decode(tt.expected), merged)
+			require.Equal(t, decode(tt.document), document, "document modified")
+		})
+	}
+}
+
+func TestSchemaOverlayYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "values.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+env: "staging"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"type": "object", "properties": {"env": {"enum": ["dev", "prod"]}}}`), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.Join(tmpDir, "*.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, pattern),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config: fmt.Sprintf(testAccSchemaOverlayConfig, pattern, `{"properties": {"env": {"enum": ["staging"]}}}`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccSchemaOverlayConfig, pattern, `{"properties": {"env": {"enum": ["staging"]}}, "required": ["owner"]}`),
+				ExpectError: regexp.MustCompile(`missing property 'owner'`),
+			},
+			// the schema compiled without an overlay is not affected
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, pattern),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccSchemaOverlayConfig, pattern, `["owner"]`),
+				ExpectError: regexp.MustCompile(`Invalid schema overlay`),
+			},
+		},
+	})
+}
+
+const testAccSchemaOverlayConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern  = "%s"
+  schema_overlay = %q
+}
+`
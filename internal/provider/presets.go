@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"slices"
+)
+
+const (
+	presetGitHubWorkflow = "github-workflow"
+	presetCompose        = "compose"
+	presetArgoCD         = "argocd"
+	presetFlux           = "flux"
+	presetRenovate       = "renovate"
+	presetDependabot     = "dependabot"
+)
+
+var (
+	// schemaStoreURL is the base URL of the SchemaStore schemas of presets.
+	schemaStoreURL = "https://json.schemastore.org"
+	// composeLegacySchemaURL is the base URL of the schemas of the legacy
+	// 2.x and 3.x Compose file formats, which predate the Compose Specification.
+	composeLegacySchemaURL = "https://raw.githubusercontent.com/docker/compose/v1/compose/config"
+	// renovateSchemaURL is the schema published with the documentation of
+	// Renovate, which is updated with every release.
+	renovateSchemaURL = "https://docs.renovatebot.com/renovate-schema.json"
+)
+
+// composeLegacyVersionRegex matches the versions of the legacy Compose file
+// formats with a schema, 2.0 to 2.4 and 3.0 to 3.9.
+var composeLegacyVersionRegex = regexp.MustCompile(`^(?:2(?:\.[0-4])?|3(?:\.[0-9])?)$`)
+
+// preset validates well-known files against their published schema, so the
+// files do not need to reference it.
+type preset struct {
+	// inputPattern is the default input_pattern.
+	inputPattern string
+	// schema returns the location of the schema of a file without a schema
+	// reference, given its content.
+	schema func(content string) string
+}
+
+var presets = map[string]preset{
+	presetGitHubWorkflow: {
+		inputPattern: ".github/workflows",
+		schema:       schemaStoreSchema("github-workflow.json"),
+	},
+	presetCompose: {
+		// compose.yaml, docker-compose.yml and overrides like docker-compose.prod.yml
+		inputPattern: "*compose*.y*ml",
+		schema:       composeSchema,
+	},
+	presetArgoCD: {
+		// the directory of the app of apps pattern
+		inputPattern: "apps",
+		schema:       embeddedSchema("argocd"),
+	},
+	presetFlux: {
+		// the directory of flux bootstrap
+		inputPattern: "clusters",
+		schema:       embeddedSchema("flux"),
+	},
+	presetRenovate: {
+		inputPattern: "renovate.json",
+		schema:       func(string) string { return renovateSchemaURL },
+	},
+	presetDependabot: {
+		// dependabot.yml and dependabot.yaml
+		inputPattern: ".github/dependabot.y*ml",
+		schema:       schemaStoreSchema("dependabot-2.0.json"),
+	},
+}
+
+// presetNames returns the names of the presets in order.
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func schemaStoreSchema(name string) func(string) string {
+	return func(string) string {
+		return schemaStoreURL + "/" + name
+	}
+}
+
+// embeddedSchema returns the location of a schema bundled with the
+// provider, which is used without network access.
+func embeddedSchema(name string) func(string) string {
+	return func(string) string {
+		return embeddedSchemaPrefix + name
+	}
+}
+
+// composeSchema returns the schema of the legacy file format a Compose file
+// declares with its version, or else the schema of the Compose
+// Specification, which ignores the version.
+func composeSchema(content string) string {
+	var file struct {
+		Version string `yaml:"version"`
+	}
+	// undecodable files are left for the validation to report
+	if err := yaml.Unmarshal([]byte(content), &file); err == nil && composeLegacyVersionRegex.MatchString(file.Version) {
+		version := file.Version
+		if len(version) == 1 {
+			version += ".0"
+		}
+		return composeLegacySchemaURL + "/config_schema_v" + version + ".json"
+	}
+
+	return schemaStoreURL + "/docker-compose.json"
+}
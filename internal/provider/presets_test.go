@@ -0,0 +1,378 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// newSchemaStore serves schemas by name in place of SchemaStore for the
+// duration of the test.
+func newSchemaStore(t *testing.T, schemas map[string]string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schema, ok := schemas[r.URL.Path[1:]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/schema+json")
+		_, _ = w.Write([]byte(schema))
+	}))
+
+	t.Cleanup(server.Close)
+
+	previous := schemaStoreURL
+	schemaStoreURL = server.URL
+	t.Cleanup(func() { schemaStoreURL = previous })
+}
+
+func TestPresetGitHubWorkflow(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	newSchemaStore(t, map[string]string{
+		"github-workflow.json": `{"type": "object", "required": ["on", "jobs"], "properties": {"jobs": {"type": "object", "minProperties": 1}}}`,
+	})
+
+	workflows := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflows, 0755))
+
+	err := os.WriteFile(filepath.Join(workflows, "ci.yml"), []byte(`on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: go test ./...
+`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccPresetConfig, workflows, presetGitHubWorkflow),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.preset",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.ToSlash(filepath.Join(workflows, "ci.yml")))}),
+					),
+				},
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(workflows, "broken.yaml"), []byte("on: push\njobs: {}\n"), 0644))
+				},
+				Config:      fmt.Sprintf(testAccPresetConfig, workflows, presetGitHubWorkflow),
+				ExpectError: regexp.MustCompile(`(?s)broken\.yaml.*github-workflow\.json`),
+			},
+			// the preset defaults to the workflows of the working directory
+			{
+				Config:      fmt.Sprintf(`data "jsonschema_validated_yaml" "preset" { preset = "%s" }`, presetGitHubWorkflow),
+				ExpectError: regexp.MustCompile(`input pattern: \.github/workflows`),
+			},
+			{
+				Config:      `data "jsonschema_validated_yaml" "preset" {}`,
+				ExpectError: regexp.MustCompile(`Missing input pattern`),
+			},
+		},
+	})
+}
+
+const testAccPresetConfig = `
+data "jsonschema_validated_yaml" "preset" {
+  input_pattern = "%s"
+  preset        = "%s"
+}
+`
+
+func TestComposeSchema(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		schema  string
+	}{
+		"specification":   {content: "services:\n  web:\n    image: nginx\n", schema: schemaStoreURL + "/docker-compose.json"},
+		"major version":   {content: "version: '2'\n", schema: composeLegacySchemaURL + "/config_schema_v2.0.json"},
+		"minor version":   {content: "version: 3.8\n", schema: composeLegacySchemaURL + "/config_schema_v3.8.json"},
+		"unknown version": {content: "version: '4.0'\n", schema: schemaStoreURL + "/docker-compose.json"},
+		"invalid yaml":    {content: "[services", schema: schemaStoreURL + "/docker-compose.json"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.schema, composeSchema(test.content))
+		})
+	}
+}
+
+func TestPresetCompose(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	newSchemaStore(t, map[string]string{
+		"docker-compose.json":     `{"type": "object", "properties": {"services": {"type": "object", "additionalProperties": {"required": ["image"]}}}}`,
+		"config_schema_v2.4.json": `{"type": "object", "required": ["version"], "properties": {"services": {"type": "object", "additionalProperties": {"required": ["build"]}}}}`,
+	})
+
+	previous := composeLegacySchemaURL
+	composeLegacySchemaURL = schemaStoreURL
+	t.Cleanup(func() { composeLegacySchemaURL = previous })
+
+	err := os.WriteFile(filepath.Join(tmpDir, "compose.yaml"), []byte("services:\n  web:\n    image: nginx\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "docker-compose.legacy.yml"), []byte("version: '2.4'\nservices:\n  web:\n    build: .\n"), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, presets[presetCompose].inputPattern))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccPresetConfig, pattern, presetCompose),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.preset",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "compose.yaml"))),
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "docker-compose.legacy.yml"))),
+						}),
+					),
+				},
+			},
+			// the legacy schema applies to files declaring its version only
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docker-compose.override.yml"), []byte("services:\n  web:\n    build: .\n"), 0644))
+				},
+				Config:      fmt.Sprintf(testAccPresetConfig, pattern, presetCompose),
+				ExpectError: regexp.MustCompile(`(?s)docker-compose\.override\.yml.*docker-compose\.json`),
+			},
+		},
+	})
+}
+
+func TestPresetArgoCD(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "guestbook.yaml"), []byte(`apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+spec:
+  project: default
+  source:
+    repoURL: https://github.com/argoproj/argocd-example-apps
+    path: guestbook
+  destination:
+    server: https://kubernetes.default.svc
+    namespace: guestbook
+  syncPolicy:
+    automated:
+      prune: true
+`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "broken.yaml"), []byte(`apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: broken
+spec:
+  source:
+    path: guestbook
+`), 0644))
+				},
+				Config:      fmt.Sprintf(testAccPresetConfig, filepath.ToSlash(tmpDir), presetArgoCD),
+				ExpectError: regexp.MustCompile(`(?s)broken\.yaml.*urn:jsonschema:argocd`),
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.Remove(filepath.Join(tmpDir, "broken.yaml")))
+				},
+				Config: fmt.Sprintf(testAccPresetConfig, filepath.ToSlash(tmpDir), presetArgoCD),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.preset",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "guestbook.yaml")))}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestPresetFlux(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// manifests of other kinds in the same file are not validated
+	err := os.WriteFile(filepath.Join(tmpDir, "podinfo.yaml"), []byte(`apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: podinfo
+spec:
+  url: https://stefanprodan.github.io/podinfo
+---
+apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: podinfo
+spec:
+  interval: 10m
+  chart:
+    spec:
+      chart: podinfo
+      sourceRef:
+        kind: HelmRepository
+        name: podinfo
+`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "apps.yaml"), []byte(`apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+spec:
+  interval: 10m
+  sourceRef:
+    kind: HelmRepository
+    name: podinfo
+`), 0644))
+				},
+				Config:      fmt.Sprintf(testAccPresetConfig, filepath.ToSlash(tmpDir), presetFlux),
+				ExpectError: regexp.MustCompile(`(?s)apps\.yaml.*urn:jsonschema:flux`),
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.Remove(filepath.Join(tmpDir, "apps.yaml")))
+				},
+				Config: fmt.Sprintf(testAccPresetConfig, filepath.ToSlash(tmpDir), presetFlux),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.preset",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "podinfo.yaml")))}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestPresetRenovate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	newSchemaStore(t, map[string]string{
+		"renovate-schema.json": `{"type": "object", "properties": {"extends": {"type": "array", "items": {"type": "string"}}}}`,
+	})
+
+	previous := renovateSchemaURL
+	renovateSchemaURL = schemaStoreURL + "/renovate-schema.json"
+	t.Cleanup(func() { renovateSchemaURL = previous })
+
+	err := os.WriteFile(filepath.Join(tmpDir, "renovate.json"), []byte(`{"extends": ["config:recommended"]}`), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, presets[presetRenovate].inputPattern))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccPresetConfig, pattern, presetRenovate),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.preset",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(pattern)}),
+					),
+				},
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "renovate.json"), []byte(`{"extends": "config:recommended"}`), 0644))
+				},
+				Config:      fmt.Sprintf(testAccPresetConfig, pattern, presetRenovate),
+				ExpectError: regexp.MustCompile(`(?s)renovate\.json.*renovate-schema\.json`),
+			},
+		},
+	})
+}
+
+func TestPresetDependabot(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	newSchemaStore(t, map[string]string{
+		"dependabot-2.0.json": `{"type": "object", "required": ["version", "updates"], "properties": {"version": {"const": 2}}}`,
+	})
+
+	github := filepath.Join(tmpDir, ".github")
+	require.NoError(t, os.MkdirAll(github, 0755))
+
+	err := os.WriteFile(filepath.Join(github, "dependabot.yml"), []byte(`version: 2
+updates:
+  - package-ecosystem: gomod
+    directory: /
+    schedule:
+      interval: weekly
+`), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, presets[presetDependabot].inputPattern))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(github, "dependabot.yaml"), []byte("version: 1\n"), 0644))
+				},
+				Config:      fmt.Sprintf(testAccPresetConfig, pattern, presetDependabot),
+				ExpectError: regexp.MustCompile(`(?s)dependabot\.yaml.*dependabot-2\.0\.json`),
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.Remove(filepath.Join(github, "dependabot.yaml")))
+				},
+				Config: fmt.Sprintf(testAccPresetConfig, pattern, presetDependabot),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.preset",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.ToSlash(filepath.Join(github, "dependabot.yml")))}),
+					),
+				},
+			},
+		},
+	})
+}
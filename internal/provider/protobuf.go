@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"os"
+)
+
+// protobufMessage validates documents against a message type of a set of
+// file descriptors with the semantics of protojson.
+type protobufMessage struct {
+	descriptor protoreflect.MessageDescriptor
+	types      *dynamicpb.Types
+}
+
+// readDescriptorSet reads a binary FileDescriptorSet, e.g. written by
+// protoc --descriptor_set_out --include_imports or buf build.
+func readDescriptorSet(file string) (*protoregistry.Files, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("could not decode file descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file descriptor set, it has to include the imports of its files: %w", err)
+	}
+
+	return files, nil
+}
+
+// newProtobufMessage returns the message type name of files.
+func newProtobufMessage(files *protoregistry.Files, name string) (*protobufMessage, error) {
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("message %s not found in the file descriptor set: %w", name, err)
+	}
+
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message but a %T", name, descriptor)
+	}
+
+	return &protobufMessage{descriptor: messageDescriptor, types: dynamicpb.NewTypes(files)}, nil
+}
+
+// decode decodes value, e.g. a decoded YAML document, into the message the
+// way protojson decodes its JSON encoding, and returns the JSON encoding of
+// the message with the field names of the proto files. Unknown fields fail
+// unless discardUnknown.
+func (m *protobufMessage) decode(value any, discardUnknown bool) (string, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	message := dynamicpb.NewMessage(m.descriptor)
+
+	options := protojson.UnmarshalOptions{DiscardUnknown: discardUnknown, Resolver: m.types}
+	if err := options.Unmarshal(content, message); err != nil {
+		return "", err
+	}
+
+	encoded, err := protojson.MarshalOptions{Resolver: m.types, UseProtoNames: true}.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+
+	// protojson randomizes its whitespace, compacting it keeps the encoding stable
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, encoded); err != nil {
+		return "", err
+	}
+
+	return compacted.String(), nil
+}
@@ -5,15 +5,30 @@ package provider
 
 import (
 	"context"
+	"filippo.io/age"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/santhosh-tekuri/jsonschema/v6"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
 )
 
 // Ensure JsonschemaProvider satisfies various provider interfaces.
 var _ provider.Provider = &JsonschemaProvider{}
+var _ provider.ProviderWithFunctions = &JsonschemaProvider{}
 
 // JsonschemaProvider defines the provider implementation.
 type JsonschemaProvider struct {
@@ -25,8 +40,54 @@ type JsonschemaProvider struct {
 
 // NewsProviderModel describes the provider data model.
 type NewsProviderModel struct {
+	AgeIdentities  types.List          `tfsdk:"age_identities"`
+	Vault          *VaultConfigModel   `tfsdk:"vault"`
+	Consul         *ConsulConfigModel  `tfsdk:"consul"`
+	Etcd           *EtcdConfigModel    `tfsdk:"etcd"`
+	AWS            *AWSConfigModel     `tfsdk:"aws"`
+	Buf            *BufConfigModel     `tfsdk:"buf"`
+	Tracing        *TracingConfigModel `tfsdk:"tracing"`
+	Retry          *RetryConfigModel   `tfsdk:"retry"`
+	Formats        *FormatsConfigModel `tfsdk:"formats"`
+	Content        *ContentConfigModel `tfsdk:"content"`
+	Regex          *RegexConfigModel   `tfsdk:"regex"`
+	IgnoreKeywords types.List          `tfsdk:"ignore_keywords"`
+	YAMLTags       types.Map           `tfsdk:"yaml_tags"`
+	YAMLLimits     *YAMLLimitsModel    `tfsdk:"yaml_limits"`
+	DefaultDraft   types.String        `tfsdk:"default_draft"`
+	Loaders        types.List          `tfsdk:"loaders"`
 }
 
+// YAMLLimitsModel describes the limits of decoded YAML documents.
+type YAMLLimitsModel struct {
+	MaxDepth   types.Int64 `tfsdk:"max_depth"`
+	MaxAliases types.Int64 `tfsdk:"max_aliases"`
+	MaxNodes   types.Int64 `tfsdk:"max_nodes"`
+}
+
+// JsonschemaProviderData is passed to data sources and resources on configuration.
+type JsonschemaProviderData struct {
+	// Compiler compiles schemas shared by all data sources and resources.
+	Compiler *schemaCompiler
+	// AgeIdentities decrypt age encrypted input files.
+	AgeIdentities []age.Identity
+	// Vault reads vault:// schemas and documents.
+	Vault *vaultClient
+	// KV reads consul://, etcd://, ssm:// and secretsmanager:// schemas and
+	// documents.
+	KV *kvDocuments
+	// Descriptors loads the file descriptor sets of protobuf messages.
+	Descriptors *descriptorSources
+	// Tracing emits spans of the validation phases.
+	Tracing *tracing
+	// YAMLDecoder decodes YAML documents with the custom tags and limits
+	// of the provider.
+	YAMLDecoder yamlDecoder
+}
+
+// loaderSchemes are the URL schemes of the loaders of schemas.
+var loaderSchemes = []string{"file", "http", "https", "urn", vaultScheme, consulScheme, etcdScheme, ssmScheme, secretsManagerScheme}
+
 func (p *JsonschemaProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "jsonschema"
 	resp.Version = p.version
@@ -34,7 +95,309 @@ func (p *JsonschemaProvider) Metadata(ctx context.Context, req provider.Metadata
 
 func (p *JsonschemaProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Provider for working with jsonschema.",
+		MarkdownDescription: "Provider for working with jsonschema.\n\n" +
+			"The draft meta-schemas and a few common schemas are bundled with the provider and can be referenced by URN without network access: " +
+			"`urn:jsonschema:draft-04`, `urn:jsonschema:draft-06`, `urn:jsonschema:draft-07`, `urn:jsonschema:draft-2019-09`, `urn:jsonschema:draft-2020-12`, " +
+			"`urn:jsonschema:json-api-error` (JSON:API error document), `urn:jsonschema:json-patch` (RFC 6902), `urn:jsonschema:problem-details` (RFC 9457), " +
+			"`urn:jsonschema:argocd-application`, `urn:jsonschema:argocd-applicationset`, `urn:jsonschema:flux-kustomization` and `urn:jsonschema:flux-helmrelease`, " +
+			"and `urn:jsonschema:argocd` and `urn:jsonschema:flux` validating the manifests of those kinds by `kind`.\n\n" +
+			"Schemas are compiled by every provider configuration with its own formats, drafts and loaders, " +
+			"so provider aliases like `jsonschema.strict` and `jsonschema.lenient` can carry different validation policies that data sources pick with the `provider` meta-argument.",
+		Attributes: map[string]schema.Attribute{
+			"age_identities": schema.ListAttribute{
+				MarkdownDescription: "age identities (`AGE-SECRET-KEY-1...`) used to decrypt input files with the `.age` extension",
+				Optional:            true,
+				Sensitive:           true,
+				ElementType:         types.StringType,
+			},
+			"default_draft": schema.StringAttribute{
+				MarkdownDescription: "Draft of schemas without `$schema`, defaults to `draft-2020-12`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(slices.Sorted(maps.Keys(drafts))...),
+				},
+			},
+			"formats": schema.SingleNestedAttribute{
+				MarkdownDescription: "Validation of the `format` keyword, which is only asserted by default for draft-07 and earlier schemas",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"date_time": schema.StringAttribute{
+						MarkdownDescription: "Strictness of the `date-time`, `date` and `duration` formats, defaults to `strict`. " +
+							"`strict` requires RFC 3339 date-times and dates and ISO 8601 durations as specified by JSON Schema. " +
+							"`lenient` also accepts ISO 8601 date-times separated by a space, without seconds or without an offset, basic dates like `20240102`, " +
+							"durations with fractions like `PT1.5H` and Go durations like `1h30m`.",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(dateTimeStrict, dateTimeLenient),
+						},
+					},
+					"assert": schema.BoolAttribute{
+						Description: "Assert formats for draft 2019-09 and later schemas too",
+						Optional:    true,
+					},
+				},
+			},
+			"content": schema.SingleNestedAttribute{
+				MarkdownDescription: "Validation of the `contentEncoding`, `contentMediaType` and `contentSchema` keywords, which are only annotations by default",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"assert": schema.BoolAttribute{
+						MarkdownDescription: "Assert the content keywords: strings with a `contentEncoding` like `base64` have to decode, " +
+							"content of the `application/json` and `application/yaml` media types has to parse and is validated against `contentSchema`, " +
+							"`application/x-pem-file` content has to consist of PEM blocks and `application/pkix-cert` content has to be a DER encoded X.509 certificate. " +
+							"The `report` of `jsonschema_validated_yaml` lists the length and digest of the decoded values in `contents`.",
+						Optional: true,
+					},
+				},
+			},
+			"ignore_keywords": schema.ListAttribute{
+				MarkdownDescription: "Keywords removed from every loaded schema and its subschemas before compiling, e.g. `[\"format\", \"contentMediaType\"]`, " +
+					"for upstream schemas that are stricter than the documents can satisfy yet. Property names and values of keywords like `enum` are not affected.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"loaders": schema.ListAttribute{
+				MarkdownDescription: "URL schemes schemas may be loaded from, of `file`, `http`, `https`, `urn` (schemas bundled with the provider), `vault`, `consul`, `etcd`, `ssm` and `secretsmanager`, defaults to all of them. " +
+					"Loading schemas from other schemes fails, e.g. `[\"file\", \"urn\"]` keeps validation from reaching the network.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(loaderSchemes...)),
+				},
+			},
+			"regex": schema.SingleNestedAttribute{
+				MarkdownDescription: "Regular expressions of the `pattern` and `patternProperties` keywords and the `regex` format. " +
+					"JSON Schema specifies ECMA-262 regular expressions, but Go's RE2 engine is used by default, which does not support lookarounds or backreferences.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"engine": schema.StringAttribute{
+						MarkdownDescription: "Regular expression engine, `re2` (default) or `ecma` for ECMA-262 semantics. " +
+							"`ecma` backtracks, matches taking longer than a second fail.",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(regexEngineRE2, regexEngineECMA),
+						},
+					},
+					"skip_unsupported": schema.BoolAttribute{
+						Description: "Compile schemas with patterns the engine does not support, which then match every string, instead of failing. Skipped patterns are logged as warnings.",
+						Optional:    true,
+					},
+				},
+			},
+			"retry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Retries of remote schema loads (`http://`, `https://`, `vault://`, `consul://`, `etcd://`, `ssm://` and `secretsmanager://`) with exponential backoff, " +
+					"so transient network errors do not fail a plan. Client errors like `404 Not Found` are not retried.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"attempts": schema.Int64Attribute{
+						Description: "Maximum number of attempts per schema, defaults to 3",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"min_backoff": schema.StringAttribute{
+						MarkdownDescription: "Backoff before the first retry, doubled on every further retry, defaults to `500ms`",
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: "Upper bound of the backoff, defaults to `10s`",
+						Optional:            true,
+					},
+					"deadline": schema.StringAttribute{
+						MarkdownDescription: "Overall time after which a schema load is no longer retried, defaults to `1m`",
+						Optional:            true,
+					},
+				},
+			},
+			"tracing": schema.SingleNestedAttribute{
+				MarkdownDescription: "Export OpenTelemetry spans of the validation phases (glob, read, compile and validate of every file) to an OTLP/HTTP endpoint. " +
+					"No spans are exported if unset.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"endpoint": schema.StringAttribute{
+						MarkdownDescription: "Host and port of the OTLP/HTTP endpoint, e.g. `localhost:4318`",
+						Required:            true,
+					},
+					"insecure": schema.BoolAttribute{
+						Description: "Export over HTTP instead of HTTPS",
+						Optional:    true,
+					},
+					"headers": schema.MapAttribute{
+						Description: "Headers sent with every export request, e.g. for authentication",
+						Optional:    true,
+						Sensitive:   true,
+						ElementType: types.StringType,
+					},
+				},
+			},
+			"vault": schema.SingleNestedAttribute{
+				MarkdownDescription: "Connection to HashiCorp Vault for schemas and documents referenced as `vault://mount/path#field`. " +
+					"Unset attributes default to the standard `VAULT_*` environment variables.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"address": schema.StringAttribute{
+						Description: "Address of the Vault server",
+						Optional:    true,
+					},
+					"token": schema.StringAttribute{
+						Description: "Token used to authenticate",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"namespace": schema.StringAttribute{
+						Description: "Vault Enterprise namespace",
+						Optional:    true,
+					},
+					"kv_version": schema.Int64Attribute{
+						Description: "Version of the KV secrets engine, 1 or 2 (default)",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.OneOf(1, 2),
+						},
+					},
+					"approle": schema.SingleNestedAttribute{
+						Description: "AppRole credentials used to log in instead of a token",
+						Optional:    true,
+						Attributes: map[string]schema.Attribute{
+							"role_id": schema.StringAttribute{
+								Description: "Role ID",
+								Required:    true,
+							},
+							"secret_id": schema.StringAttribute{
+								Description: "Secret ID",
+								Required:    true,
+								Sensitive:   true,
+							},
+							"mount": schema.StringAttribute{
+								Description: "Mount path of the AppRole auth method, defaults to approle",
+								Optional:    true,
+							},
+						},
+					},
+				},
+			},
+			"aws": schema.SingleNestedAttribute{
+				MarkdownDescription: "Connection to AWS for schemas and documents stored in SSM Parameter Store, referenced as `ssm:///path/name` or `ssm:///path/` for every parameter below a path, " +
+					"and in Secrets Manager, referenced as `secretsmanager://name` or `secretsmanager://prefix/` for every secret whose name starts with the prefix. " +
+					"SecureString parameters are decrypted. Unset attributes default to the standard `AWS_*` environment variables and shared configuration files.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"region": schema.StringAttribute{
+						Description: "AWS region",
+						Optional:    true,
+					},
+					"profile": schema.StringAttribute{
+						Description: "Profile of the shared configuration files",
+						Optional:    true,
+					},
+					"ssm_endpoint": schema.StringAttribute{
+						Description: "Endpoint of SSM, e.g. of a VPC endpoint or LocalStack",
+						Optional:    true,
+					},
+					"secretsmanager_endpoint": schema.StringAttribute{
+						Description: "Endpoint of Secrets Manager, e.g. of a VPC endpoint or LocalStack",
+						Optional:    true,
+					},
+				},
+			},
+			"buf": schema.SingleNestedAttribute{
+				MarkdownDescription: "Connection to the Buf Schema Registry for the descriptor sets of `jsonschema_validated_protobuf` referenced as `buf://buf.build/owner/module`, " +
+					"optionally with a label or commit like `buf://buf.build/owner/module:v1.2.0`. Unset attributes default to the `BUF_TOKEN` environment variable.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"endpoint": schema.StringAttribute{
+						Description: "Endpoint of the reflection API, defaults to the registry the module is named after",
+						Optional:    true,
+					},
+					"token": schema.StringAttribute{
+						Description: "Token used to authenticate, public modules need none",
+						Optional:    true,
+						Sensitive:   true,
+					},
+				},
+			},
+			"consul": schema.SingleNestedAttribute{
+				MarkdownDescription: "Connection to the Consul KV store for schemas and documents referenced as `consul://key`, or `consul://prefix/` for every key below a prefix. " +
+					"Unset attributes default to the `CONSUL_HTTP_ADDR` and `CONSUL_HTTP_TOKEN` environment variables.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"address": schema.StringAttribute{
+						Description: "Address of the Consul agent, defaults to http://127.0.0.1:8500",
+						Optional:    true,
+					},
+					"token": schema.StringAttribute{
+						Description: "ACL token used to authenticate",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"datacenter": schema.StringAttribute{
+						Description: "Datacenter to read from, defaults to the datacenter of the agent",
+						Optional:    true,
+					},
+				},
+			},
+			"etcd": schema.SingleNestedAttribute{
+				MarkdownDescription: "Connection to the etcd v3 KV store for schemas and documents referenced as `etcd://key`, or `etcd://prefix/` for every key below a prefix. " +
+					"The JSON gateway of the etcd API is used. Unset attributes default to the `ETCDCTL_ENDPOINTS`, `ETCDCTL_USER` and `ETCDCTL_PASSWORD` environment variables.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"endpoint": schema.StringAttribute{
+						Description: "Endpoint of the etcd server, defaults to http://127.0.0.1:2379",
+						Optional:    true,
+					},
+					"username": schema.StringAttribute{
+						Description: "User to authenticate as, requests are not authenticated without one",
+						Optional:    true,
+					},
+					"password": schema.StringAttribute{
+						Description: "Password of the user",
+						Optional:    true,
+						Sensitive:   true,
+					},
+				},
+			},
+			"yaml_limits": schema.SingleNestedAttribute{
+				MarkdownDescription: "Limits of decoded YAML documents, so documents expanding aliases exponentially (billion laughs) matched by a glob cannot exhaust the memory of the provider. " +
+					"Documents exceeding a limit fail to decode.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"max_depth": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Maximum nesting of a document, including the nesting of expanded aliases, defaults to %d", defaultYAMLMaxDepth),
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"max_aliases": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Maximum number of aliases expanded in a document, defaults to %d", defaultYAMLMaxAliases),
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"max_nodes": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("Maximum number of nodes decoded from a document, including the nodes of expanded aliases, defaults to %d", defaultYAMLMaxNodes),
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+			},
+			"yaml_tags": schema.MapAttribute{
+				MarkdownDescription: "Map of custom YAML tags, e.g. `!vault` or `!include`, to how their values are decoded: " +
+					"`string` decodes scalars as strings, e.g. `!vault 42` as `\"42\"`, " +
+					"`map` decodes values as an object of the tag name to the value, e.g. `!Ref Bucket` as `{\"Ref\": \"Bucket\"}`, " +
+					"and `error` fails decoding. Scalars with other custom tags are decoded as strings.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^![^!]`), "must be a local tag like !vault")),
+					mapvalidator.ValueStringsAre(stringvalidator.OneOf(yamlTagString, yamlTagMap, yamlTagError)),
+				},
+			},
+		},
 	}
 }
 
@@ -47,19 +410,186 @@ func (p *JsonschemaProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	compiler := jsonschema.NewCompiler()
+	policy, diags := newRetryPolicy(data.Retry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vault := newVaultClient(data.Vault)
+	kv := newKVDocuments(data.Consul, data.Etcd, data.AWS)
+	web := newHTTPLoader()
+
+	loader := jsonschema.SchemeURLLoader{
+		"file":               textFileLoader{},
+		"http":               &retryingLoader{ctx: ctx, loader: web, policy: policy},
+		"https":              &retryingLoader{ctx: ctx, loader: web, policy: policy},
+		"urn":                embeddedLoader{},
+		vaultScheme:          &retryingLoader{ctx: ctx, loader: &vaultLoader{vault}, policy: policy},
+		consulScheme:         &retryingLoader{ctx: ctx, loader: kv, policy: policy},
+		etcdScheme:           &retryingLoader{ctx: ctx, loader: kv, policy: policy},
+		ssmScheme:            &retryingLoader{ctx: ctx, loader: kv, policy: policy},
+		secretsManagerScheme: &retryingLoader{ctx: ctx, loader: kv, policy: policy},
+	}
+
+	if !data.Loaders.IsNull() {
+		var schemes []string
+		resp.Diagnostics.Append(data.Loaders.ElementsAs(ctx, &schemes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		maps.DeleteFunc(loader, func(scheme string, _ jsonschema.URLLoader) bool {
+			return !slices.Contains(schemes, scheme)
+		})
+	}
+
+	// paths with drive letters are local files
+	if loader["file"] != nil {
+		registerDriveLetters(loader)
+	}
 
-	resp.DataSourceData = compiler
-	resp.ResourceData = compiler
+	var schemaLoader jsonschema.URLLoader = loader
+	if !data.IgnoreKeywords.IsNull() {
+		var keywords []string
+		resp.Diagnostics.Append(data.IgnoreKeywords.ElementsAs(ctx, &keywords, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		schemaLoader = ignoringLoader{loader: loader, keywords: keywords}
+	}
+
+	providerData := &JsonschemaProviderData{
+		Compiler: newSchemaCompiler(schemaLoader, func(compiler *jsonschema.Compiler) {
+			compiler.UseRegexpEngine(regexEngine(ctx, data.Regex))
+			configureFormats(compiler, data.Formats)
+			configureContent(compiler, data.Content)
+			if !data.DefaultDraft.IsNull() {
+				compiler.DefaultDraft(drafts[data.DefaultDraft.ValueString()])
+			}
+
+			// custom vocabularies are only applied to draft 2019-09 and later schemas when vocabularies are asserted
+			compiler.RegisterVocabulary(annotationsVocabulary())
+			compiler.AssertVocabs()
+		}),
+		Vault:       vault,
+		KV:          kv,
+		Descriptors: newDescriptorSources(data.Buf),
+	}
+
+	settings, err := validationSettings(req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading provider configuration",
+			"Could not hash the validation settings of the provider: "+err.Error(),
+		)
+		return
+	}
+	providerData.Compiler.settings = settings
+
+	if !data.YAMLTags.IsNull() {
+		var tags map[string]string
+		resp.Diagnostics.Append(data.YAMLTags.ElementsAs(ctx, &tags, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		providerData.YAMLDecoder.tags = yamlTagResolvers(tags)
+	}
+
+	if data.YAMLLimits != nil {
+		providerData.YAMLDecoder.maxDepth = int(data.YAMLLimits.MaxDepth.ValueInt64())
+		providerData.YAMLDecoder.maxAliases = int(data.YAMLLimits.MaxAliases.ValueInt64())
+		providerData.YAMLDecoder.maxNodes = int(data.YAMLLimits.MaxNodes.ValueInt64())
+	}
+
+	var tracingHeaders map[string]string
+	if data.Tracing != nil && !data.Tracing.Headers.IsNull() {
+		resp.Diagnostics.Append(data.Tracing.Headers.ElementsAs(ctx, &tracingHeaders, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	tracing, err := newTracing(ctx, data.Tracing, tracingHeaders, p.version)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("tracing"),
+			"Invalid tracing configuration",
+			"Could not create the OTLP exporter: "+err.Error(),
+		)
+		return
+	}
+
+	providerData.Tracing = tracing
+
+	if !data.AgeIdentities.IsNull() {
+		var identities []string
+
+		resp.Diagnostics.Append(data.AgeIdentities.ElementsAs(ctx, &identities, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		parsed, err := age.ParseIdentities(strings.NewReader(strings.Join(identities, "\n")))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("age_identities"),
+				"Invalid age identities",
+				"Could not parse age identities: "+err.Error(),
+			)
+			return
+		}
+
+		providerData.AgeIdentities = parsed
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 }
 
 func (p *JsonschemaProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewFormattedFileResource,
+		NewCompatibilityGateResource,
+		NewAssertionResource,
+		NewLockfileResource,
+		NewBundleFileResource,
+		NewVariablesFileResource,
+		NewModelineResource,
+		NewSchemaTrackerResource,
+		NewValidationCacheResource,
+	}
 }
 
 func (p *JsonschemaProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewValidatedYAMLDataSource,
+		NewValidatedCSVDataSource,
+		NewValidatedDotenvDataSource,
+		NewValidatedINIDataSource,
+		NewEvaluatedConfigDataSource,
+		NewSchemaSetDataSource,
+		NewValidatedTerraformJSONDataSource,
+		NewValidatedCloudFormationDataSource,
+		NewValidatedKubernetesConfigDataSource,
+		NewTerraformTypeDataSource,
+		NewOpenAPIComponentDataSource,
+		NewValidatedProtobufDataSource,
+		NewValidatedURLDataSource,
+		NewRegistryDriftDataSource,
+		NewLockfileDataSource,
+		NewComposedSchemaDataSource,
+		NewValidatedInputsDataSource,
+		NewSchemaPropertiesDataSource,
+	}
+}
+
+func (p *JsonschemaProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewMatchesFunction,
+		NewResolveFunction,
 	}
 }
 
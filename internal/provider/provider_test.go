@@ -4,10 +4,19 @@
 package provider
 
 import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 // testAccProtoV6ProviderFactories is used to instantiate a provider during acceptance testing.
@@ -22,3 +31,54 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// Terraform starts a provider instance with a compiler of its own for every
+// provider configuration, but acceptance tests reattach every configuration
+// to the same instance, so the policies are tested one at a time.
+func TestProviderValidationPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	values := filepath.Join(tmpDir, "values.yaml")
+
+	err := os.WriteFile(values, []byte("# yaml-language-server: $schema=./schema.json\nemail: not an email\n"), 0644)
+	require.NoError(t, err)
+
+	// formats are asserted for draft-07 schemas, which the schema is if it has no $schema
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"properties": {"email": {"type": "string", "format": "email"}}}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccProviderValidationPolicyConfig, `default_draft = "draft-07"`, values),
+				ExpectError: regexp.MustCompile(`is\s+not\s+valid\s+email`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccProviderValidationPolicyConfig, `loaders = ["http", "https"]`, values),
+				ExpectError: regexp.MustCompile(`no\s+URLLoader\s+registered`),
+			},
+			{
+				Config: fmt.Sprintf(testAccProviderValidationPolicyConfig, `loaders = ["file"]`, values),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.values",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(values)}),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccProviderValidationPolicyConfig = `
+provider "jsonschema" {
+  %s
+}
+
+data "jsonschema_validated_yaml" "values" {
+  input_pattern = "%s"
+}
+`
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strconv"
+)
+
+// ReferenceModel describes a reference of the documents to the documents
+// of other files, like a foreign key.
+type ReferenceModel struct {
+	Pointer       types.String `tfsdk:"pointer"`
+	TargetPattern types.String `tfsdk:"target_pattern"`
+	TargetPointer types.String `tfsdk:"target_pointer"`
+}
+
+// validDocument is a document of a valid file, whose values may reference
+// other documents and are part of the aggregate.
+type validDocument struct {
+	file      string
+	index     int
+	value     any
+	sensitive bool
+}
+
+// referenceTargets returns the JSON encoded values at tokens of the
+// documents of the files matched by pattern, which are not validated.
+func referenceTargets(pattern string, tokens []string, decoder yamlDecoder) (map[string]bool, error) {
+	var globDiags diag.Diagnostics
+	files := globInputFiles(pattern, &globDiags)
+	if globDiags.HasError() {
+		return nil, errors.New(globDiags.Errors()[0].Detail())
+	}
+
+	targets := make(map[string]bool)
+	for _, file := range files {
+		content, err := readTextFile(file, decodeText)
+		if err != nil {
+			return nil, fmt.Errorf("could not read file %s: %w", file, err)
+		}
+
+		for _, document := range splitYAMLDocuments(string(content)) {
+			value, err := decoder.decode([]byte(document))
+			if err != nil {
+				return nil, fmt.Errorf("could not decode file %s: %w", file, err)
+			}
+
+			if target, ok := pointerValue(value, tokens); ok {
+				targets[jsonString(target)] = true
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// brokenReferences returns a violation for every value at tokens of the
+// documents that is not one of targets. Each item of a list is a reference
+// of its own, documents without a value at tokens reference nothing.
+func brokenReferences(documents []validDocument, pointer string, tokens []string, targetPattern, targetPointer string, targets map[string]bool) []reportFinding {
+	var findings []reportFinding
+
+	for _, document := range documents {
+		value, ok := pointerValue(document.value, tokens)
+		if !ok || value == nil {
+			continue
+		}
+
+		pointers, references := []string{pointer}, []any{value}
+		if items, ok := value.([]any); ok {
+			pointers, references = nil, items
+			for i := range items {
+				pointers = append(pointers, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+
+		for i, itemPointer := range pointers {
+			reference := jsonString(references[i])
+			if targets[reference] {
+				continue
+			}
+
+			// values of sensitive files stay out of the messages
+			message := "value at '" + itemPointer + "' is not a '" + targetPointer + "' of the files matched by " + targetPattern
+			if !document.sensitive {
+				message = "value " + reference + " at '" + itemPointer + "' is not a '" + targetPointer + "' of the files matched by " + targetPattern
+			}
+
+			findings = append(findings, reportFinding{
+				File:     document.file,
+				Document: document.index,
+				Pointer:  itemPointer,
+				Keyword:  "references",
+				Message:  message,
+				Severity: severityError,
+			})
+		}
+	}
+
+	return findings
+}
+
+// pointerValue returns the value at the decoded tokens of a JSON pointer in
+// value, if there is one.
+func pointerValue(value any, tokens []string) (any, bool) {
+	for _, token := range tokens {
+		switch node := value.(type) {
+		case map[string]any:
+			child, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
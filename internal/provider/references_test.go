@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestBrokenReferences(t *testing.T) {
+	value, ok := pointerValue(map[string]any{"spec": map[string]any{"teams": []any{"a", "b"}}}, []string{"spec", "teams", "1"})
+	require.True(t, ok)
+	require.Equal(t, "b", value)
+
+	_, ok = pointerValue(map[string]any{"spec": "a"}, []string{"spec", "teams"})
+	require.False(t, ok)
+
+	findings := brokenReferences([]validDocument{
+		{file: "web.yaml", value: map[string]any{"team": "platform"}},
+		{file: "api.yaml", index: 1, value: map[string]any{"team": []any{"platform", "payments"}}},
+		{file: "db.yaml", value: map[string]any{"team": "secret"}, sensitive: true},
+		{file: "cache.yaml", value: map[string]any{"name": "cache"}},
+	}, "/team", []string{"team"}, "teams/*.yaml", "/id", map[string]bool{`"platform"`: true})
+
+	require.Equal(t, []reportFinding{
+		{File: "api.yaml", Document: 1, Pointer: "/team/1", Keyword: "references", Message: `value "payments" at '/team/1' is not a '/id' of the files matched by teams/*.yaml`, Severity: severityError},
+		{File: "db.yaml", Pointer: "/team", Keyword: "references", Message: "value at '/team' is not a '/id' of the files matched by teams/*.yaml", Severity: severityError},
+	}, findings)
+}
+
+func TestReferencesYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "teams"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "services"), 0755))
+
+	for name, content := range map[string]string{
+		"schema.json":         `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`,
+		"teams/platform.yaml": "id: platform\n---\nid: payments\n",
+		"teams/data.yaml":     "id: data\n",
+		"services/web.yaml":   "# yaml-language-server: $schema=../schema.json\nname: web\nteam: platform\n",
+		"services/api.yaml":   "# yaml-language-server: $schema=../schema.json\nname: api\nteam: [payments, billing]\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, "services", "*.yaml"))
+	teams := filepath.ToSlash(filepath.Join(tmpDir, "teams", "*.yaml"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccReferencesConfig, pattern, teams, true, ""),
+				ExpectError: regexp.MustCompile(`value "billing" at '/team/1' is not a '/id'`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccReferencesConfig, pattern, filepath.ToSlash(filepath.Join(tmpDir, "missing", "*.yaml")), true, ""),
+				ExpectError: regexp.MustCompile(`Error reading referenced files`),
+			},
+			{
+				Config: fmt.Sprintf(testAccReferencesConfig, pattern, teams, false, ""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringRegexp(regexp.MustCompile(`"pointer":"/team/1","keyword":"references","message":"value \\"billing\\" at '/team/1' is not a '/id' of the files matched by [^"]*","severity":"error"`)),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccReferencesConfig, pattern, teams, true, `suppressions = [{ file_glob = "api.yaml", keyword = "references" }]`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringRegexp(regexp.MustCompile(`"keyword":"references","message":".*","severity":"warning","suppressed":true`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccReferencesConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern   = "%s"
+  references      = [{ pointer = "/team", target_pattern = "%s", target_pointer = "/id" }]
+  fail_on_invalid = %t
+  %s
+}
+`
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"github.com/dlclark/regexp2"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"regexp"
+	"time"
+)
+
+const (
+	regexEngineRE2  = "re2"
+	regexEngineECMA = "ecma"
+)
+
+// ecmaMatchTimeout bounds the backtracking of a single ECMA-262 match.
+const ecmaMatchTimeout = time.Second
+
+// RegexConfigModel describes the regular expressions of the provider.
+type RegexConfigModel struct {
+	Engine          types.String `tfsdk:"engine"`
+	SkipUnsupported types.Bool   `tfsdk:"skip_unsupported"`
+}
+
+// regexEngine returns the engine of the pattern and patternProperties
+// keywords and the regex format, or nil for the default RE2 engine.
+func regexEngine(ctx context.Context, config *RegexConfigModel) jsonschema.RegexpEngine {
+	if config == nil {
+		return nil
+	}
+
+	engine := compileRE2
+	if config.Engine.ValueString() == regexEngineECMA {
+		engine = compileECMA
+	}
+
+	if !config.SkipUnsupported.ValueBool() {
+		return engine
+	}
+
+	return func(pattern string) (jsonschema.Regexp, error) {
+		re, err := engine(pattern)
+		if err != nil {
+			tflog.Warn(ctx, "Skipping unsupported pattern", map[string]interface{}{
+				"pattern": pattern,
+				"error":   err.Error(),
+			})
+			return skippedRegexp(pattern), nil
+		}
+		return re, nil
+	}
+}
+
+func compileRE2(pattern string) (jsonschema.Regexp, error) {
+	return regexp.Compile(pattern)
+}
+
+func compileECMA(pattern string) (jsonschema.Regexp, error) {
+	re, err := regexp2.Compile(pattern, regexp2.ECMAScript)
+	if err != nil {
+		return nil, err
+	}
+	re.MatchTimeout = ecmaMatchTimeout
+	return (*ecmaRegexp)(re), nil
+}
+
+// ecmaRegexp adapts regexp2.Regexp to jsonschema.Regexp, matches that time
+// out do not match.
+type ecmaRegexp regexp2.Regexp
+
+func (re *ecmaRegexp) MatchString(s string) bool {
+	matched, err := (*regexp2.Regexp)(re).MatchString(s)
+	return err == nil && matched
+}
+
+func (re *ecmaRegexp) String() string {
+	return (*regexp2.Regexp)(re).String()
+}
+
+// skippedRegexp matches every string in place of a pattern the engine does
+// not support.
+type skippedRegexp string
+
+func (re skippedRegexp) MatchString(string) bool {
+	return true
+}
+
+func (re skippedRegexp) String() string {
+	return string(re)
+}
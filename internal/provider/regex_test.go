@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRegexEngine(t *testing.T) {
+	ctx := context.Background()
+	lookahead := `^(?!admin$)[a-z]+$`
+
+	require.Nil(t, regexEngine(ctx, nil))
+
+	_, err := regexEngine(ctx, &RegexConfigModel{})(lookahead)
+	require.Error(t, err)
+
+	re, err := regexEngine(ctx, &RegexConfigModel{Engine: types.StringValue(regexEngineECMA)})(lookahead)
+	require.NoError(t, err)
+	require.True(t, re.MatchString("alice"))
+	require.False(t, re.MatchString("admin"))
+	require.Equal(t, lookahead, re.String())
+
+	re, err = regexEngine(ctx, &RegexConfigModel{SkipUnsupported: types.BoolValue(true)})(lookahead)
+	require.NoError(t, err)
+	require.True(t, re.MatchString("admin"))
+
+	re, err = regexEngine(ctx, &RegexConfigModel{SkipUnsupported: types.BoolValue(true)})(`^[a-z]+$`)
+	require.NoError(t, err)
+	require.False(t, re.MatchString("Admin"))
+}
@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	registryConfluent = "confluent"
+	registryApicurio  = "apicurio"
+	registryHTTP      = "http"
+
+	// confluentContentType is the media type of the REST API of the
+	// Confluent Schema Registry.
+	confluentContentType = "application/vnd.schemaregistry.v1+json"
+
+	// apicurioDefaultGroup is the group of artifacts registered without
+	// one.
+	apicurioDefaultGroup = "default"
+)
+
+// registeredSchema is the latest version of a schema in a registry.
+type registeredSchema struct {
+	content []byte
+	// version is the version of the registry, or the ETag of a document
+	// served over HTTP.
+	version string
+	// lastModified is the Last-Modified header of a document served over
+	// HTTP, zero if the server sends none.
+	lastModified time.Time
+}
+
+// registryClient reads the schema registered under subject in a Confluent
+// Schema Registry or an Apicurio Registry, or a schema served at url over
+// HTTP.
+type registryClient struct {
+	kind    string
+	url     string
+	subject string
+	headers map[string]string
+	client  *http.Client
+}
+
+// latest returns the latest version of the schema.
+func (c *registryClient) latest(ctx context.Context) (*registeredSchema, error) {
+	switch c.kind {
+	case registryConfluent:
+		body, _, err := c.request(ctx, http.MethodGet, c.confluentURL("versions", "latest"), nil, false)
+		if err != nil {
+			return nil, err
+		}
+
+		var version struct {
+			Version    int    `json:"version"`
+			Schema     string `json:"schema"`
+			SchemaType string `json:"schemaType"`
+		}
+		if err := json.Unmarshal(body, &version); err != nil {
+			return nil, fmt.Errorf("could not decode latest version of subject %s: %w", c.subject, err)
+		}
+		// schemas registered without a type are Avro
+		if version.SchemaType != "JSON" {
+			return nil, fmt.Errorf("subject %s has a %s schema, not a json schema", c.subject, cmp.Or(version.SchemaType, "AVRO"))
+		}
+
+		return &registeredSchema{content: []byte(version.Schema), version: strconv.Itoa(version.Version)}, nil
+	case registryApicurio:
+		body, _, err := c.request(ctx, http.MethodGet, c.apicurioURL("meta"), nil, false)
+		if err != nil {
+			return nil, err
+		}
+
+		var meta struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(body, &meta); err != nil {
+			return nil, fmt.Errorf("could not decode metadata of artifact %s: %w", c.subject, err)
+		}
+
+		content, _, err := c.request(ctx, http.MethodGet, c.apicurioURL("versions", meta.Version), nil, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return &registeredSchema{content: content, version: meta.Version}, nil
+	}
+
+	content, header, err := c.request(ctx, http.MethodGet, c.url, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := &registeredSchema{content: content, version: header.Get("ETag")}
+	if lastModified, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		registered.lastModified = lastModified
+	}
+
+	return registered, nil
+}
+
+// lookup returns the version content is registered as, if any. Documents
+// served over HTTP have no versions to look up.
+func (c *registryClient) lookup(ctx context.Context, content []byte) (string, bool, error) {
+	var body []byte
+	var err error
+	switch c.kind {
+	case registryConfluent:
+		request, marshalErr := json.Marshal(map[string]string{"schemaType": "JSON", "schema": string(content)})
+		if marshalErr != nil {
+			return "", false, marshalErr
+		}
+		body, _, err = c.request(ctx, http.MethodPost, c.confluentURL(), request, true)
+	case registryApicurio:
+		body, _, err = c.request(ctx, http.MethodPost, c.apicurioURL("meta"), content, true)
+	default:
+		return "", false, nil
+	}
+	if err != nil || body == nil {
+		return "", false, err
+	}
+
+	var version struct {
+		Version json.Number `json:"version"`
+	}
+	if err := json.Unmarshal(body, &version); err != nil {
+		return "", false, fmt.Errorf("could not decode version of subject %s: %w", c.subject, err)
+	}
+
+	return version.Version.String(), true, nil
+}
+
+// confluentURL returns the URL of the subject with the segments appended.
+func (c *registryClient) confluentURL(segments ...string) string {
+	return strings.TrimSuffix(c.url, "/") + "/subjects/" + url.PathEscape(c.subject) + joinURLSegments(segments)
+}
+
+// apicurioURL returns the URL of the artifact of the registry API v2 with
+// the segments appended. Subjects are artifact IDs, optionally prefixed
+// with their group like group/artifact.
+func (c *registryClient) apicurioURL(segments ...string) string {
+	group, artifact, ok := strings.Cut(c.subject, "/")
+	if !ok {
+		group, artifact = apicurioDefaultGroup, c.subject
+	}
+
+	return strings.TrimSuffix(c.url, "/") + "/groups/" + url.PathEscape(group) + "/artifacts/" + url.PathEscape(artifact) + joinURLSegments(segments)
+}
+
+func joinURLSegments(segments []string) string {
+	var joined string
+	for _, segment := range segments {
+		joined += "/" + url.PathEscape(segment)
+	}
+
+	return joined
+}
+
+// request sends a request with the headers of the client and returns the
+// body and the headers of the response. Responses other than 200 OK are
+// errors, except for 404 Not Found of lookups, which return no body.
+func (c *registryClient) request(ctx context.Context, method, requestURL string, body []byte, lookup bool) ([]byte, http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch c.kind {
+	case registryConfluent:
+		req.Header.Set("Accept", confluentContentType)
+		if body != nil {
+			req.Header.Set("Content-Type", confluentContentType)
+		}
+	case registryApicurio:
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if lookup && resp.StatusCode == http.StatusNotFound {
+		return nil, resp.Header, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%s %s returned %s: %s", req.Method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(content)))
+	}
+
+	return content, resp.Header, nil
+}
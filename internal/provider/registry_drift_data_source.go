@@ -0,0 +1,287 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+)
+
+// Ensure RegistryDriftDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &RegistryDriftDataSource{}
+
+func NewRegistryDriftDataSource() datasource.DataSource {
+	return &RegistryDriftDataSource{}
+}
+
+// RegistryDriftDataSource defines the data source implementation.
+type RegistryDriftDataSource struct {
+	compiler *schemaCompiler
+	client   *http.Client
+}
+
+// RegistryDriftDataSourceModel describes the data source data model.
+type RegistryDriftDataSourceModel struct {
+	Schema            types.String `tfsdk:"schema"`
+	Registry          types.String `tfsdk:"registry"`
+	URL               types.String `tfsdk:"url"`
+	Subject           types.String `tfsdk:"subject"`
+	RequestHeaders    types.Map    `tfsdk:"request_headers"`
+	InSync            types.Bool   `tfsdk:"in_sync"`
+	LocalNewer        types.Bool   `tfsdk:"local_newer"`
+	RegisteredVersion types.String `tfsdk:"registered_version"`
+	RegisteredSchema  types.String `tfsdk:"registered_schema"`
+	DiffSummary       types.List   `tfsdk:"diff_summary"`
+}
+
+func (d *RegistryDriftDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_drift"
+}
+
+func (d *RegistryDriftDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Compares a local json schema with the latest version registered in a Confluent Schema Registry or an Apicurio Registry, or served over HTTP, " +
+			"so drift can block a publish with a `precondition` or trigger one. " +
+			"The schemas are compared as JSON values, so formatting and the order of keys do not matter. " +
+			"A local schema that differs is newer if it is not registered as any version of the subject, rather than being an earlier version the registry moved on from. " +
+			"Schemas served over HTTP have no versions, the local schema is newer if the file was modified after the `Last-Modified` header of the response, or always if there is none.",
+
+		Attributes: map[string]schema.Attribute{
+			"schema": schema.StringAttribute{
+				Description: "Path of the local json schema",
+				Required:    true,
+			},
+			"registry": schema.StringAttribute{
+				MarkdownDescription: "Kind of the registry, `confluent`, `apicurio` or `http`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(registryConfluent, registryApicurio, registryHTTP),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "URL of the Confluent Schema Registry, of the API v2 of the Apicurio Registry like `https://registry.example.com/apis/registry/v2`, or of the schema served over HTTP",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http:// or https:// URL"),
+				},
+			},
+			"subject": schema.StringAttribute{
+				MarkdownDescription: "Subject of the Confluent Schema Registry, or artifact ID of the Apicurio Registry optionally prefixed with its group like `group/artifact`, " +
+					"artifacts without a group are in the `default` group. Required unless `registry` is `http`.",
+				Optional: true,
+			},
+			"request_headers": schema.MapAttribute{
+				MarkdownDescription: "Headers of the requests, e.g. `Authorization`",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"in_sync": schema.BoolAttribute{
+				Description: "Whether the local schema equals the registered schema",
+				Computed:    true,
+			},
+			"local_newer": schema.BoolAttribute{
+				Description: "Whether the local schema differs from the registered schema and is newer than it, i.e. has to be published",
+				Computed:    true,
+			},
+			"registered_version": schema.StringAttribute{
+				MarkdownDescription: "Latest version of the subject, or the `ETag` of the schema served over HTTP, empty if the server sends none",
+				Computed:            true,
+			},
+			"registered_schema": schema.StringAttribute{
+				Description: "JSON encoded registered schema",
+				Computed:    true,
+			},
+			"diff_summary": schema.ListAttribute{
+				MarkdownDescription: "Changes of the local schema relative to the registered schema, e.g. `'/required': property email is now required`, empty if they are in sync",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *RegistryDriftDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+	d.client = &http.Client{Timeout: httpLoadTimeout}
+}
+
+func (d *RegistryDriftDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RegistryDriftDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry := data.Registry.ValueString()
+
+	if registry != registryHTTP && data.Subject.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("subject"),
+			"Missing subject",
+			"subject must be set for the "+registry+" registry",
+		)
+		return
+	}
+
+	headers := make(map[string]string)
+	if !data.RequestHeaders.IsNull() {
+		resp.Diagnostics.Append(data.RequestHeaders.ElementsAs(ctx, &headers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	if _, err := d.compiler.Compile(schemaPath); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	contentRaw, err := readTextFile(schemaPath, decodeText)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error reading schema",
+			"Could not read schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	// registries compare the text of schemas, which is compacted like the serializers register it
+	var local bytes.Buffer
+	if err := json.Compact(&local, contentRaw); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error reading schema",
+			"Could not decode schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	localSchema, err := jsonschema.UnmarshalJSON(bytes.NewReader(local.Bytes()))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error reading schema",
+			"Could not decode schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	client := &registryClient{
+		kind:    registry,
+		url:     data.URL.ValueString(),
+		subject: data.Subject.ValueString(),
+		headers: headers,
+		client:  d.client,
+	}
+
+	registered, err := client.latest(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Error reading registered schema",
+			"Could not read the registered schema of "+data.URL.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	registeredSchema, err := jsonschema.UnmarshalJSON(bytes.NewReader(registered.content))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Error reading registered schema",
+			"Could not decode the registered schema of "+data.URL.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	inSync := reflect.DeepEqual(localSchema, registeredSchema)
+
+	summary := make([]string, 0)
+	localNewer := false
+	if !inSync {
+		for _, change := range diffSchemas(registeredSchema, localSchema, "") {
+			summary = append(summary, change.String())
+		}
+		// the keywords that constrain documents are the same
+		if len(summary) == 0 {
+			summary = append(summary, schemaChange{Message: "annotations changed"}.String())
+		}
+
+		switch registry {
+		case registryHTTP:
+			fi, err := os.Stat(schemaPath)
+			localNewer = err == nil && (registered.lastModified.IsZero() || fi.ModTime().After(registered.lastModified))
+		default:
+			_, found, err := client.lookup(ctx, local.Bytes())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("subject"),
+					"Error looking up schema",
+					"Could not look up schema "+schemaPath+" in the versions of "+data.Subject.ValueString()+": "+err.Error(),
+				)
+				return
+			}
+			localNewer = !found
+		}
+	}
+
+	data.InSync = types.BoolValue(inSync)
+	data.LocalNewer = types.BoolValue(localNewer)
+	data.RegisteredVersion = types.StringValue(registered.version)
+	data.RegisteredSchema = types.StringValue(jsonString(registeredSchema))
+
+	summaryList, diags := types.ListValueFrom(ctx, types.StringType, summary)
+	resp.Diagnostics.Append(diags...)
+
+	data.DiffSummary = summaryList
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// testRegistryVersions are the versions of the schema registered in the
+// test registries.
+var testRegistryVersions = []string{
+	`{"type": "object", "properties": {"id": {"type": "string"}}}`,
+	`{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`,
+}
+
+// newTestRegistryServer serves testRegistryVersions as the subject
+// orders-value of a Confluent Schema Registry below /confluent, as the
+// artifact shop/order of an Apicurio Registry below /apicurio and the
+// latest version at /schema.json.
+func newTestRegistryServer(t *testing.T) *httptest.Server {
+	compact := func(content []byte) string {
+		var buf bytes.Buffer
+		require.NoError(t, json.Compact(&buf, content))
+		return buf.String()
+	}
+
+	lookup := func(w http.ResponseWriter, schema []byte) {
+		i := slices.IndexFunc(testRegistryVersions, func(version string) bool { return compact([]byte(version)) == compact(schema) })
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"version": %d}`, i+1)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /confluent/subjects/orders-value/versions/latest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic a2V5OnNlY3JldA==" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		encoded, err := json.Marshal(map[string]any{
+			"subject":    "orders-value",
+			"version":    len(testRegistryVersions),
+			"schemaType": "JSON",
+			"schema":     testRegistryVersions[len(testRegistryVersions)-1],
+		})
+		require.NoError(t, err)
+		_, _ = w.Write(encoded)
+	})
+	mux.HandleFunc("GET /confluent/subjects/avro-value/versions/latest", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"subject": "avro-value", "version": 1, "schema": "\"string\""}`))
+	})
+	mux.HandleFunc("POST /confluent/subjects/orders-value", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Schema string `json:"schema"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		lookup(w, []byte(request.Schema))
+	})
+	mux.HandleFunc("GET /apicurio/groups/shop/artifacts/order/meta", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"groupId": "shop", "id": "order", "version": "%d"}`, len(testRegistryVersions))
+	})
+	mux.HandleFunc("GET /apicurio/groups/shop/artifacts/order/versions/{version}", func(w http.ResponseWriter, r *http.Request) {
+		var version int
+		_, err := fmt.Sscan(r.PathValue("version"), &version)
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(testRegistryVersions[version-1]))
+	})
+	mux.HandleFunc("POST /apicurio/groups/shop/artifacts/order/meta", func(w http.ResponseWriter, r *http.Request) {
+		content, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		lookup(w, content)
+	})
+	mux.HandleFunc("GET /schema.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		w.Header().Set("Last-Modified", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+		_, _ = w.Write([]byte(testRegistryVersions[len(testRegistryVersions)-1]))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRegistryDrift(t *testing.T) {
+	server := newTestRegistryServer(t)
+
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		// the latest version, formatted differently
+		"latest.json": "{\n  \"required\": [\"id\"],\n  \"properties\": {\"id\": {\"type\": \"string\"}},\n  \"type\": \"object\"\n}\n",
+		"stale.json":  testRegistryVersions[0],
+		"newer.json":  `{"type": "object", "properties": {"id": {"type": "string"}, "email": {"type": "string"}}, "required": ["id", "email"]}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	latest := filepath.ToSlash(filepath.Join(tmpDir, "latest.json"))
+	stale := filepath.ToSlash(filepath.Join(tmpDir, "stale.json"))
+	newer := filepath.ToSlash(filepath.Join(tmpDir, "newer.json"))
+
+	drift := func(inSync, localNewer bool, version string, summary ...string) []statecheck.StateCheck {
+		checks := make([]knownvalue.Check, 0, len(summary))
+		for _, change := range summary {
+			checks = append(checks, knownvalue.StringExact(change))
+		}
+
+		return []statecheck.StateCheck{
+			statecheck.ExpectKnownValue("data.jsonschema_registry_drift.test", tfjsonpath.New("in_sync"), knownvalue.Bool(inSync)),
+			statecheck.ExpectKnownValue("data.jsonschema_registry_drift.test", tfjsonpath.New("local_newer"), knownvalue.Bool(localNewer)),
+			statecheck.ExpectKnownValue("data.jsonschema_registry_drift.test", tfjsonpath.New("registered_version"), knownvalue.StringExact(version)),
+			statecheck.ExpectKnownValue("data.jsonschema_registry_drift.test", tfjsonpath.New("diff_summary"), knownvalue.ListExact(checks)),
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:            fmt.Sprintf(testAccRegistryDriftDataSourceConfig, latest, registryConfluent, server.URL+"/confluent", "orders-value"),
+				ConfigStateChecks: drift(true, false, "2"),
+			},
+			{
+				Config:            fmt.Sprintf(testAccRegistryDriftDataSourceConfig, stale, registryConfluent, server.URL+"/confluent", "orders-value"),
+				ConfigStateChecks: drift(false, false, "2", "'/required': property id is no longer required"),
+			},
+			{
+				Config:            fmt.Sprintf(testAccRegistryDriftDataSourceConfig, newer, registryConfluent, server.URL+"/confluent", "orders-value"),
+				ConfigStateChecks: drift(false, true, "2", "'/required': property email is now required", "'/properties/email/type': type restricted to [string]"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccRegistryDriftDataSourceConfig, newer, registryConfluent, server.URL+"/confluent", "avro-value"),
+				ExpectError: regexp.MustCompile(`not a json schema`),
+			},
+			{
+				Config:            fmt.Sprintf(testAccRegistryDriftDataSourceConfig, stale, registryApicurio, server.URL+"/apicurio", "shop/order"),
+				ConfigStateChecks: drift(false, false, "2", "'/required': property id is no longer required"),
+			},
+			{
+				Config:            fmt.Sprintf(testAccRegistryDriftDataSourceConfig, newer, registryApicurio, server.URL+"/apicurio", "shop/order"),
+				ConfigStateChecks: drift(false, true, "2", "'/required': property email is now required", "'/properties/email/type': type restricted to [string]"),
+			},
+			// the local files were modified after the Last-Modified header
+			{
+				Config:            fmt.Sprintf(testAccRegistryDriftDataSourceConfig, stale, registryHTTP, server.URL+"/schema.json", ""),
+				ConfigStateChecks: drift(false, true, `"v2"`, "'/required': property id is no longer required"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccRegistryDriftDataSourceConfig, latest, registryApicurio, server.URL+"/apicurio", ""),
+				ExpectError: regexp.MustCompile(`Missing subject`),
+			},
+		},
+	})
+}
+
+const testAccRegistryDriftDataSourceConfig = `
+data "jsonschema_registry_drift" "test" {
+  schema   = "%s"
+  registry = "%s"
+  url      = "%s"
+  subject  = "%s"
+
+  request_headers = {
+    Authorization = "Basic ${base64encode("key:secret")}"
+  }
+}
+`
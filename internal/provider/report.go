@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+const (
+	accessModeRead  = "read"
+	accessModeWrite = "write"
+)
+
+const (
+	secretDetectionError     = "error"
+	secretDetectionSensitive = "sensitive"
+)
+
+// validationReport is the JSON encoded report output of data sources.
+type validationReport struct {
+	Findings []reportFinding `json:"findings"`
+	Matches  []reportMatch   `json:"matches"`
+	Contents []reportContent `json:"contents,omitempty"`
+}
+
+// reportFinding is a single violation or warning of a document.
+type reportFinding struct {
+	File     string `json:"file"`
+	Document int    `json:"document"`
+	Pointer  string `json:"pointer"`
+	Keyword  string `json:"keyword"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	// Suppressed is set for violations downgraded to warnings by a suppression.
+	Suppressed bool `json:"suppressed,omitempty"`
+	// Baselined is set for violations downgraded to warnings by a baseline.
+	Baselined bool `json:"baselined,omitempty"`
+}
+
+// reportMatch is an anyOf or oneOf branch matched by a value of a document.
+type reportMatch struct {
+	File     string `json:"file"`
+	Document int    `json:"document"`
+	Pointer  string `json:"pointer"`
+	Keyword  string `json:"keyword"`
+	Branch   string `json:"branch"`
+}
+
+var reportPrinter = message.NewPrinter(language.English)
+
+// validationFindings flattens a validation error into a finding per
+// violated keyword.
+func validationFindings(file string, document int, err error) []reportFinding {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []reportFinding{{
+			File:     file,
+			Document: document,
+			Message:  err.Error(),
+			Severity: severityError,
+		}}
+	}
+
+	var findings []reportFinding
+
+	var visit func(e *jsonschema.ValidationError)
+	visit = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				visit(cause)
+			}
+			return
+		}
+
+		findings = append(findings, reportFinding{
+			File:     file,
+			Document: document,
+			Pointer:  instancePointer(e.InstanceLocation),
+			Keyword:  strings.Join(e.ErrorKind.KeywordPath(), "/"),
+			Message:  e.ErrorKind.LocalizedString(reportPrinter),
+			Severity: severityError,
+		})
+	}
+
+	visit(validationErr)
+
+	return findings
+}
+
+// findingLocations describes findings by the pointers and keywords of their
+// values only, as the messages of sensitive files could contain secrets.
+func findingLocations(findings []reportFinding) string {
+	locations := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		if finding.Keyword != "" {
+			locations = append(locations, "'"+finding.Pointer+"' ("+finding.Keyword+")")
+		}
+	}
+	if len(locations) == 0 {
+		return "the values of sensitive files are not shown"
+	}
+
+	return strings.Join(locations, ", ")
+}
+
+// deprecationFindings returns a warning for every value of a document that
+// is matched by a subschema marked deprecated.
+func deprecationFindings(file string, document int, sch *jsonschema.Schema, value any) []reportFinding {
+	var findings []reportFinding
+
+	walkSchema(sch, value, func(pointer string, _ any, schemas []*jsonschema.Schema) {
+		for _, s := range schemas {
+			if s.Deprecated {
+				findings = append(findings, reportFinding{
+					File:     file,
+					Document: document,
+					Pointer:  pointer,
+					Keyword:  "deprecated",
+					Message:  "value at '" + pointer + "' is deprecated",
+					Severity: severityWarning,
+				})
+				return
+			}
+		}
+	})
+
+	return findings
+}
+
+func instancePointer(location []string) string {
+	var sb strings.Builder
+	for _, token := range location {
+		sb.WriteString("/" + escapePointerToken(token))
+	}
+	return sb.String()
+}
+
+// accessModeFindings returns a violation for every value of a document that
+// is matched by a subschema marked writeOnly in read mode or readOnly in
+// write mode.
+func accessModeFindings(file string, document int, sch *jsonschema.Schema, value any, mode string) []reportFinding {
+	var findings []reportFinding
+
+	walkSchema(sch, value, func(pointer string, _ any, schemas []*jsonschema.Schema) {
+		for _, s := range schemas {
+			keyword := ""
+			switch {
+			case mode == accessModeRead && s.WriteOnly:
+				keyword = "writeOnly"
+			case mode == accessModeWrite && s.ReadOnly:
+				keyword = "readOnly"
+			default:
+				continue
+			}
+
+			findings = append(findings, reportFinding{
+				File:     file,
+				Document: document,
+				Pointer:  pointer,
+				Keyword:  keyword,
+				Message:  "value at '" + pointer + "' is " + keyword + " and not allowed in " + mode + " mode",
+				Severity: severityError,
+			})
+			return
+		}
+	})
+
+	return findings
+}
+
+// secretFindings returns a violation for every value of a document that is
+// matched by a subschema marking it a secret with writeOnly or x-secret.
+func secretFindings(file string, document int, sch *jsonschema.Schema, value any) []reportFinding {
+	var findings []reportFinding
+
+	walkSchema(sch, value, func(pointer string, _ any, schemas []*jsonschema.Schema) {
+		for _, s := range schemas {
+			keyword := ""
+			switch {
+			case s.WriteOnly:
+				keyword = "writeOnly"
+			case slices.ContainsFunc(s.Extensions, func(ext jsonschema.SchemaExt) bool {
+				keywords, ok := ext.(annotationsExt)
+				return ok && keywords["x-secret"] == true
+			}):
+				keyword = "x-secret"
+			default:
+				continue
+			}
+
+			findings = append(findings, reportFinding{
+				File:     file,
+				Document: document,
+				Pointer:  pointer,
+				Keyword:  keyword,
+				Message:  "value at '" + pointer + "' is a secret marked " + keyword,
+				Severity: severityError,
+			})
+			return
+		}
+	})
+
+	return findings
+}
+
+// branchMatches returns the anyOf and oneOf branches matched by the values
+// of a document, identified by their title or else their schema location.
+func branchMatches(file string, document int, sch *jsonschema.Schema, value any) []reportMatch {
+	var matches []reportMatch
+
+	walkSchema(sch, value, func(pointer string, value any, schemas []*jsonschema.Schema) {
+		for _, s := range schemas {
+			for keyword, branches := range map[string][]*jsonschema.Schema{"anyOf": s.AnyOf, "oneOf": s.OneOf} {
+				for _, branch := range branches {
+					if branch.Validate(value) != nil {
+						continue
+					}
+					matches = append(matches, reportMatch{
+						File:     file,
+						Document: document,
+						Pointer:  pointer,
+						Keyword:  keyword,
+						Branch:   branchName(branch),
+					})
+				}
+			}
+		}
+	})
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Pointer < matches[j].Pointer ||
+			matches[i].Pointer == matches[j].Pointer && matches[i].Keyword < matches[j].Keyword
+	})
+
+	return matches
+}
+
+// branchName returns the title of a branch, following a $ref to a titled
+// subschema, and falls back to the location of the branch.
+func branchName(branch *jsonschema.Schema) string {
+	for s := branch; s != nil; s = s.Ref {
+		if s.Title != "" {
+			return s.Title
+		}
+	}
+
+	if _, ptr, ok := strings.Cut(branch.Location, "#"); ok {
+		return "#" + ptr
+	}
+
+	return branch.Location
+}
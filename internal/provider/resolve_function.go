@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxRefDepth limits the number of $ref followed when resolving a pointer,
+// guarding against reference cycles.
+const maxRefDepth = 32
+
+// Ensure ResolveFunction satisfies the function interface.
+var _ function.Function = &ResolveFunction{}
+
+func NewResolveFunction() function.Function {
+	return &ResolveFunction{}
+}
+
+// ResolveFunction defines the function implementation.
+type ResolveFunction struct{}
+
+func (f *ResolveFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "resolve"
+}
+
+func (f *ResolveFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the subschema at a JSON pointer",
+		MarkdownDescription: "Returns the JSON encoded subschema of a json schema at a JSON pointer, e.g. `#/properties/replicas`, " +
+			"to reuse its constraints with `jsondecode`. Local `$ref`s along the pointer and of the subschema are followed, " +
+			"keywords next to a `$ref` take precedence over the referenced subschema.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "schema_json",
+				MarkdownDescription: "JSON encoded json schema",
+			},
+			function.StringParameter{
+				Name:                "pointer",
+				MarkdownDescription: "JSON pointer of the subschema, optionally in URI fragment form starting with `#`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ResolveFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var schemaJSON, pointer string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &schemaJSON, &pointer))
+	if resp.Error != nil {
+		return
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schemaJSON))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Could not decode schema: "+err.Error())
+		return
+	}
+
+	subschema, err := resolvePointer(doc, pointer)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Could not resolve pointer "+pointer+": "+err.Error())
+		return
+	}
+
+	encoded, err := json.Marshal(subschema)
+	if err != nil {
+		resp.Error = function.NewFuncError("Could not encode subschema: " + err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, string(encoded)))
+}
+
+// resolvePointer returns the value at pointer in root following local $refs.
+func resolvePointer(root any, pointer string) (any, error) {
+	return resolvePointerDepth(root, pointer, 0)
+}
+
+func resolvePointerDepth(root any, pointer string, depth int) (any, error) {
+	if depth > maxRefDepth {
+		return nil, fmt.Errorf("more than %d nested $ref", maxRefDepth)
+	}
+
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	current := root
+	for i, token := range tokens {
+		current, err = followRef(root, current, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("no property %q at /%s", token, strings.Join(tokens[:i], "/"))
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("no item %q at /%s", token, strings.Join(tokens[:i], "/"))
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("cannot descend into a scalar at /%s", strings.Join(tokens[:i], "/"))
+		}
+	}
+
+	return followRef(root, current, depth)
+}
+
+// followRef replaces a subschema holding a local $ref with the referenced
+// subschema, merged with the keywords next to the $ref.
+func followRef(root, node any, depth int) (any, error) {
+	object, ok := node.(map[string]any)
+	if !ok {
+		return node, nil
+	}
+
+	ref, ok := object["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#") {
+		return node, nil
+	}
+
+	target, err := resolvePointerDepth(root, ref, depth+1)
+	if err != nil {
+		return nil, fmt.Errorf("$ref %s: %w", ref, err)
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		return target, nil
+	}
+
+	merged := make(map[string]any, len(targetObject)+len(object))
+	for k, v := range targetObject {
+		merged[k] = v
+	}
+	for k, v := range object {
+		if k != "$ref" {
+			merged[k] = v
+		}
+	}
+
+	return merged, nil
+}
+
+// splitPointer decodes the reference tokens of a JSON pointer, which may
+// be given in URI fragment form.
+func splitPointer(pointer string) ([]string, error) {
+	if strings.HasPrefix(pointer, "#") {
+		unescaped, err := url.PathUnescape(pointer[1:])
+		if err != nil {
+			return nil, err
+		}
+		pointer = unescaped
+	}
+
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer must be empty or start with /")
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
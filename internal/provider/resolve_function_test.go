@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestResolveFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResolveFunctionConfig, "#/properties/replicas"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact(`{"maximum":10,"minimum":1,"type":"integer"}`)),
+				},
+			},
+			// $refs are followed, sibling keywords take precedence
+			{
+				Config: fmt.Sprintf(testAccResolveFunctionConfig, "/properties/ports/items"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact(`{"description":"Container port","maximum":65535,"minimum":1024,"type":"integer"}`)),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccResolveFunctionConfig, "#/properties/ports/items/maximum"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact(`65535`)),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccResolveFunctionConfig, "#/properties/missing"),
+				ExpectError: regexp.MustCompile(`no property "missing"`),
+			},
+		},
+	})
+}
+
+func TestSplitPointer(t *testing.T) {
+	tests := map[string][]string{
+		"":            nil,
+		"#":           nil,
+		"/a~1b/c~0d":  {"a/b", "c~d"},
+		"#/a%20b/0":   {"a b", "0"},
+		"#/$defs/foo": {"$defs", "foo"},
+	}
+
+	for pointer, expected := range tests {
+		actual, err := splitPointer(pointer)
+		require.NoError(t, err, pointer)
+		require.Equal(t, expected, actual, pointer)
+	}
+
+	_, err := splitPointer("properties")
+	require.Error(t, err)
+}
+
+const testAccResolveFunctionConfig = `
+output "test" {
+  value = provider::jsonschema::resolve(jsonencode({
+    type = "object"
+    properties = {
+      replicas = { type = "integer", minimum = 1, maximum = 10 }
+      ports = {
+        type  = "array"
+        items = { "$ref" = "#/$defs/port", minimum = 1024 }
+      }
+    }
+    "$defs" = {
+      port = { type = "integer", minimum = 1, maximum = 65535, description = "Container port" }
+    }
+  }), %q)
+}
+`
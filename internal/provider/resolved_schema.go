@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// schemaResolver inlines the $ref of schema documents loaded with loader.
+type schemaResolver struct {
+	loader    jsonschema.URLLoader
+	documents map[string]any
+}
+
+// resolveSchema returns the schema at location as it is compiled, after
+// ignore_keywords and overlay are applied, with every $ref replaced by the
+// referenced subschema, merged with the keywords next to the $ref. References
+// that cannot be inlined, e.g. cycles, anchors or schemas that fail to load,
+// are kept with absolute URLs.
+func (c *schemaCompiler) resolveSchema(location string, overlay map[string]any) (any, error) {
+	key := location
+	if !urlRegex.MatchString(location) {
+		if abs, err := filepath.Abs(location); err == nil {
+			key = abs
+		}
+	}
+
+	document, fragment, _ := strings.Cut(key, "#")
+	if !urlRegex.MatchString(document) {
+		document = schemaFileURL(document)
+	}
+
+	loader := c.loader
+	if overlay != nil {
+		loader = overlayLoader{loader: c.loader, url: document, overlay: overlay}
+	}
+
+	r := &schemaResolver{loader: loader, documents: make(map[string]any)}
+
+	target, err := r.target(document, fragment)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.inline(target, base, []string{document + "#" + fragment}), nil
+}
+
+// target returns the subschema at the JSON pointer fragment of document.
+func (r *schemaResolver) target(document, fragment string) (any, error) {
+	value, ok := r.documents[document]
+	if !ok {
+		var err error
+		value, err = r.loader.Load(document)
+		if err != nil {
+			return nil, err
+		}
+		r.documents[document] = value
+	}
+
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return nil, fmt.Errorf("anchor %q is not a JSON pointer", fragment)
+	}
+
+	tokens, err := splitPointer("#" + fragment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		switch node := value.(type) {
+		case map[string]any:
+			if value, ok = node[token]; !ok {
+				return nil, fmt.Errorf("no subschema at #%s", fragment)
+			}
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("no subschema at #%s", fragment)
+			}
+			value = node[index]
+		default:
+			return nil, fmt.Errorf("no subschema at #%s", fragment)
+		}
+	}
+
+	return value, nil
+}
+
+// inline returns schema, whose references resolve against base, with its
+// $ref and the $ref of its subschemas inlined. stack holds the references
+// being inlined, which are kept as they are to break cycles.
+func (r *schemaResolver) inline(schema any, base *url.URL, stack []string) any {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return schema
+	}
+
+	if id, ok := object["$id"].(string); ok {
+		if resolved, err := base.Parse(id); err == nil {
+			base = resolved
+		}
+	}
+
+	inlined := make(map[string]any, len(object))
+	for key, value := range object {
+		switch {
+		case slices.Contains(schemaMapKeywords, key):
+			if subschemas, ok := value.(map[string]any); ok {
+				inlinedSubschemas := make(map[string]any, len(subschemas))
+				for name, subschema := range subschemas {
+					inlinedSubschemas[name] = r.inline(subschema, base, stack)
+				}
+				value = inlinedSubschemas
+			}
+		case slices.Contains(schemaListKeywords, key):
+			if subschemas, ok := value.([]any); ok {
+				inlinedSubschemas := make([]any, 0, len(subschemas))
+				for _, subschema := range subschemas {
+					inlinedSubschemas = append(inlinedSubschemas, r.inline(subschema, base, stack))
+				}
+				value = inlinedSubschemas
+				break
+			}
+			fallthrough
+		case slices.Contains(subschemaKeywords, key):
+			value = r.inline(value, base, stack)
+		}
+
+		inlined[key] = value
+	}
+
+	ref, ok := object["$ref"].(string)
+	if !ok {
+		return inlined
+	}
+
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return inlined
+	}
+	inlined["$ref"] = resolved.String()
+
+	fragment := resolved.Fragment
+	resolved.Fragment = ""
+	resolved.RawFragment = ""
+	document := resolved.String()
+
+	if slices.Contains(stack, document+"#"+fragment) {
+		return inlined
+	}
+
+	target, err := r.target(document, fragment)
+	if err != nil {
+		return inlined
+	}
+
+	targetObject, ok := r.inline(target, resolved, append(stack, document+"#"+fragment)).(map[string]any)
+	if !ok {
+		// a boolean schema, which cannot be merged with the keywords next to the $ref
+		return inlined
+	}
+
+	merged := make(map[string]any, len(targetObject)+len(inlined))
+	for k, v := range targetObject {
+		merged[k] = v
+	}
+	for k, v := range inlined {
+		if k != "$ref" {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
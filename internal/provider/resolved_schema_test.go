@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestResolveSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "type": "object",
+  "properties": {
+    "name": {"$ref": "#/$defs/name", "maxLength": 8},
+    "owner": {"$ref": "defs.json#/$defs/owner"},
+    "tree": {"$ref": "#/$defs/tree"},
+    "kind": {"enum": [{"$ref": "#/$defs/name"}]}
+  },
+  "$defs": {
+    "name": {"type": "string", "maxLength": 63},
+    "tree": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/tree"}}}}
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "defs.json"), []byte(`{"$defs": {"owner": {"$ref": "#/$defs/email"}, "email": {"type": "string", "format": "email"}}}`), 0644)
+	require.NoError(t, err)
+
+	compiler := newSchemaCompiler(nil, nil)
+
+	resolved, err := compiler.resolveSchema(filepath.Join(tmpDir, "schema.json"), map[string]any{"required": []any{"name"}})
+	require.NoError(t, err)
+
+	properties := resolved.(map[string]any)["properties"].(map[string]any)
+
+	// keywords next to a $ref take precedence
+	require.Equal(t, map[string]any{"type": "string", "maxLength": json.Number("8")}, properties["name"])
+	// references of other documents resolve against their document
+	require.Equal(t, map[string]any{"type": "string", "format": "email"}, properties["owner"])
+	// cycles are kept as absolute references
+	tree := properties["tree"].(map[string]any)["properties"].(map[string]any)["children"].(map[string]any)["items"]
+	require.Equal(t, map[string]any{"$ref": schemaFileURL(filepath.Join(tmpDir, "schema.json")) + "#/$defs/tree"}, tree)
+	// enum values are data
+	require.Equal(t, map[string]any{"enum": []any{map[string]any{"$ref": "#/$defs/name"}}}, properties["kind"])
+	// the overlay is applied
+	require.Equal(t, []any{"name"}, resolved.(map[string]any)["required"])
+
+	resolved, err = compiler.resolveSchema(filepath.Join(tmpDir, "defs.json")+"#/$defs/owner", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"type": "string", "format": "email"}, resolved)
+
+	_, err = compiler.resolveSchema(filepath.Join(tmpDir, "missing.json"), nil)
+	require.Error(t, err)
+}
+
+func TestExportResolvedSchemaYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "values.yaml"), []byte("# yaml-language-server: $schema=./schema.json\nname: web\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"properties": {"name": {"$ref": "#/$defs/name"}}, "$defs": {"name": {"type": "string"}}}`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "schema.json"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccExportResolvedSchemaConfig, filepath.Join(tmpDir, "values.yaml"), false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("resolved_schema_json"),
+						knownvalue.MapSizeExact(0),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccExportResolvedSchemaConfig, filepath.Join(tmpDir, "values.yaml"), true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("resolved_schema_json").AtMapKey(schemaPath),
+						knownvalue.StringRegexp(regexp.MustCompile(`"properties":\{"name":\{"type":"string"\}\}`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccExportResolvedSchemaConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern          = "%s"
+  export_resolved_schema = %t
+}
+`
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// RouteModel describes a kind of document a file is classified as if its
+// documents conform to the schema of the route.
+type RouteModel struct {
+	Name   types.String `tfsdk:"name"`
+	Schema types.String `tfsdk:"schema"`
+}
+
+// routeDocuments returns the decoded documents of body, the way they are
+// validated: the value of JSON files, a single null document for empty
+// files and the documents of YAML files without the empty ones.
+func routeDocuments(body string, jsonValue any, isJSON, empty bool, decoder yamlDecoder) ([]any, error) {
+	if isJSON || empty {
+		return []any{jsonValue}, nil
+	}
+
+	var values []any
+	for _, document := range splitYAMLDocuments(body) {
+		value, err := decoder.decode([]byte(document))
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// selectRoute returns the index of the first of schemas every value
+// conforms to, or -1 if there is none.
+func selectRoute(schemas []*jsonschema.Schema, values []any) int {
+	for i, sch := range schemas {
+		valid := true
+		for _, value := range values {
+			if sch.Validate(value) != nil {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			return i
+		}
+	}
+
+	return -1
+}
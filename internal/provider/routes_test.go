@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestSelectRoute(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	compiler := newSchemaCompiler(nil, nil)
+	routeSchemas := make([]*jsonschema.Schema, 0, 2)
+	for i, content := range []string{
+		`{"required": ["kind"], "properties": {"kind": {"const": "Deployment"}}}`,
+		`{"required": ["kind"]}`,
+	} {
+		schemaPath := filepath.Join(tmpDir, fmt.Sprintf("%d.json", i))
+		require.NoError(t, os.WriteFile(schemaPath, []byte(content), 0644))
+
+		sch, err := compiler.Compile(schemaPath)
+		require.NoError(t, err)
+		routeSchemas = append(routeSchemas, sch)
+	}
+
+	require.Equal(t, 0, selectRoute(routeSchemas, []any{map[string]any{"kind": "Deployment"}}))
+	require.Equal(t, 1, selectRoute(routeSchemas, []any{map[string]any{"kind": "Deployment"}, map[string]any{"kind": "Service"}}))
+	require.Equal(t, -1, selectRoute(routeSchemas, []any{map[string]any{"name": "web"}}))
+	require.Equal(t, 0, selectRoute(routeSchemas, nil))
+
+	values, err := routeDocuments("---\nkind: Service\n---\n", nil, false, false, yamlDecoder{})
+	require.NoError(t, err)
+	require.Equal(t, []any{map[string]any{"kind": "Service"}}, values)
+}
+
+func TestRoutesYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"deployment.schema.json": `{"type": "object", "required": ["kind", "replicas"], "properties": {"kind": {"const": "Deployment"}, "replicas": {"type": "integer"}}}`,
+		"service.schema.json":    `{"type": "object", "required": ["kind", "port"], "properties": {"kind": {"const": "Service"}, "port": {"type": "integer"}}}`,
+		"web.yaml":               "kind: Deployment\nreplicas: 2\n",
+		"web-svc.yaml":           "kind: Service\nport: 80\n",
+		"services.yaml":          "kind: Service\nport: 80\n---\nkind: Service\nport: 443\n",
+		"unknown.yaml":           "kind: ConfigMap\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, "*.yaml"))
+	deploymentSchema := filepath.ToSlash(filepath.Join(tmpDir, "deployment.schema.json"))
+	serviceSchema := filepath.ToSlash(filepath.Join(tmpDir, "service.schema.json"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccRoutesConfig, pattern, "deployment", deploymentSchema, "deployment", serviceSchema, true),
+				ExpectError: regexp.MustCompile(`Duplicate route`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccRoutesConfig, pattern, "deployment", deploymentSchema, "service", serviceSchema, true),
+				ExpectError: regexp.MustCompile(`Error routing file`),
+			},
+			{
+				Config: fmt.Sprintf(testAccRoutesConfig, pattern, "deployment", deploymentSchema, "service", serviceSchema, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.test",
+						tfjsonpath.New("values_by_schema"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"deployment": knownvalue.MapExact(map[string]knownvalue.Check{
+								filepath.Join(tmpDir, "web.yaml"): knownvalue.StringExact("kind: Deployment\nreplicas: 2"),
+							}),
+							"service": knownvalue.MapExact(map[string]knownvalue.Check{
+								filepath.Join(tmpDir, "web-svc.yaml"):  knownvalue.StringExact("kind: Service\nport: 80"),
+								filepath.Join(tmpDir, "services.yaml"): knownvalue.StringExact("kind: Service\nport: 80\n---\nkind: Service\nport: 443"),
+							}),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.test",
+						tfjsonpath.New("invalid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.Join(tmpDir, "unknown.yaml"))}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.test",
+						tfjsonpath.New("file_schemas").AtMapKey(filepath.Join(tmpDir, "web.yaml")),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(deploymentSchema)}),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccRoutesConfig = `
+data "jsonschema_validated_yaml" "test" {
+  input_pattern   = "%s"
+  fail_on_invalid = %[6]t
+
+  routes = [
+    { name = "%[2]s", schema = "%[3]s" },
+    { name = "%[4]s", schema = "%[5]s" },
+  ]
+}
+`
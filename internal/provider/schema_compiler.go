@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// drafts maps the names of the drafts, like the URNs of their
+// meta-schemas, to the drafts of schemas without $schema.
+var drafts = map[string]*jsonschema.Draft{
+	"draft-04":      jsonschema.Draft4,
+	"draft-06":      jsonschema.Draft6,
+	"draft-07":      jsonschema.Draft7,
+	"draft-2019-09": jsonschema.Draft2019,
+	"draft-2020-12": jsonschema.Draft2020,
+}
+
+// schemaCompiler memoizes compiled schemas for all data sources and
+// resources of a provider instance, so every schema of a plan is compiled
+// once. jsonschema.Compiler is not safe for concurrent use, compilation is
+// serialized.
+type schemaCompiler struct {
+	// loader loads the schemas of every jsonschema.Compiler created.
+	loader jsonschema.URLLoader
+	// configure sets up every jsonschema.Compiler created, e.g. with formats.
+	configure func(*jsonschema.Compiler)
+	// settings is the digest of the provider settings that change the
+	// results of validations, see validationSettings.
+	settings string
+
+	mu       sync.Mutex
+	compiler *jsonschema.Compiler
+	// files holds the content hash of every local schema file loaded since
+	// the last reset, the roots and the files they reference, by URL.
+	files   map[string]string
+	schemas map[string]*jsonschema.Schema
+	// stale is set by refresh, the files are checked for changes by the next
+	// compile rather than by every one.
+	stale bool
+}
+
+func newSchemaCompiler(loader jsonschema.URLLoader, configure func(*jsonschema.Compiler)) *schemaCompiler {
+	if loader == nil {
+		loader = jsonschema.SchemeURLLoader{"file": jsonschema.FileLoader{}}
+	}
+	c := &schemaCompiler{loader: loader, configure: configure}
+	c.reset()
+	return c
+}
+
+func (c *schemaCompiler) reset() {
+	c.compiler = c.newCompiler()
+	c.compiler.UseLoader(trackingLoader{loader: c.loader, compiler: c})
+	c.files = make(map[string]string)
+	c.schemas = make(map[string]*jsonschema.Schema)
+}
+
+// refresh makes the next compile check whether a local schema file changed.
+// Data sources and resources are configured for every request, which
+// refresh the compiler, so the files are read once per request instead of
+// once per compiled schema.
+func (c *schemaCompiler) refresh() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stale = true
+}
+
+// changed reports whether a local schema file loaded since the last reset
+// changed, which the compiler may serve to any schema compiled after.
+func (c *schemaCompiler) changed() bool {
+	for url, hash := range c.files {
+		if localSchemaHash(url) != hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newCompiler returns a jsonschema.Compiler set up like the shared one, for
+// schemas that are compiled together with resources of their own.
+func (c *schemaCompiler) newCompiler() *jsonschema.Compiler {
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(c.loader)
+	if c.configure != nil {
+		c.configure(compiler)
+	}
+	return compiler
+}
+
+// Compile returns the compiled schema at location, which is compiled again
+// if the content of a local schema file it or its references were loaded from
+// changed before the compiler was last refreshed.
+func (c *schemaCompiler) Compile(location string) (*jsonschema.Schema, error) {
+	return c.compile(location, nil)
+}
+
+// CompileOverlay returns the compiled schema at location with overlay deep
+// merged onto its document, see mergeSchemaOverlay. Every overlay of a
+// schema is compiled with a jsonschema.Compiler of its own, so the schema
+// compiled without it is not affected.
+func (c *schemaCompiler) CompileOverlay(location string, overlay map[string]any) (*jsonschema.Schema, error) {
+	return c.compile(location, overlay)
+}
+
+func (c *schemaCompiler) compile(location string, overlay map[string]any) (*jsonschema.Schema, error) {
+	key := location
+	if !urlRegex.MatchString(location) {
+		if abs, err := filepath.Abs(location); err == nil {
+			key = abs
+		}
+	}
+
+	cacheKey := key
+	if overlay != nil {
+		cacheKey += "\x00" + jsonString(overlay)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the compiler caches the schemas and their references by location, start over
+	if c.stale {
+		c.stale = false
+		if c.changed() {
+			c.reset()
+		}
+	}
+
+	if sch, ok := c.schemas[cacheKey]; ok {
+		return sch, nil
+	}
+
+	document, _, _ := strings.Cut(key, "#")
+	if !urlRegex.MatchString(document) {
+		document = schemaFileURL(document)
+	}
+
+	compiler := c.compiler
+	var loader jsonschema.URLLoader = trackingLoader{loader: c.loader, compiler: c}
+	if overlay != nil {
+		loader = overlayLoader{loader: loader, url: document, overlay: overlay}
+		compiler = c.newCompiler()
+		compiler.UseLoader(loader)
+	}
+
+	sch, err := compiler.Compile(key)
+	if err != nil {
+		return nil, explainReferenceError(loader, document, err)
+	}
+
+	if err := referenceCycleError(sch); err != nil {
+		return nil, err
+	}
+
+	c.schemas[cacheKey] = sch
+
+	return sch, nil
+}
+
+// trackingLoader records the content hash of the local schema files it
+// loads in the files of compiler, which holds its lock while compiling.
+type trackingLoader struct {
+	loader   jsonschema.URLLoader
+	compiler *schemaCompiler
+}
+
+func (l trackingLoader) Load(url string) (any, error) {
+	document, err := l.loader.Load(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if hash := localSchemaHash(url); hash != "" {
+		l.compiler.files[url] = hash
+	}
+
+	return document, nil
+}
+
+// localSchemaHash returns the content hash of a local schema file, or an
+// empty string for remote schemas and files that cannot be read, which are
+// left for the compiler to report.
+func localSchemaHash(location string) string {
+	file, _, _ := strings.Cut(location, "#")
+	if strings.HasPrefix(file, "file://") {
+		var err error
+		if file, err = (jsonschema.FileLoader{}).ToFile(file); err != nil {
+			return ""
+		}
+	} else if urlRegex.MatchString(file) {
+		return ""
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSchemaCompilerMemoizes(t *testing.T) {
+	tmpDir := t.TempDir()
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err := os.WriteFile(schemaPath, []byte(`{"type": "string"}`), 0644)
+	require.NoError(t, err)
+
+	compiler := newSchemaCompiler(nil, nil)
+
+	first, err := compiler.Compile(schemaPath)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sch, err := compiler.Compile("file://" + filepath.ToSlash(schemaPath))
+			if err == nil {
+				sch, err = compiler.Compile(schemaPath)
+			}
+			require.NoError(t, err)
+			require.Same(t, first, sch)
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, first.Validate("value"))
+
+	// changed content is compiled again once the compiler is refreshed
+	err = os.WriteFile(schemaPath, []byte(`{"type": "integer"}`), 0644)
+	require.NoError(t, err)
+
+	sch, err := compiler.Compile(schemaPath)
+	require.NoError(t, err)
+	require.Same(t, first, sch)
+
+	compiler.refresh()
+
+	second, err := compiler.Compile(schemaPath)
+	require.NoError(t, err)
+	require.NotSame(t, first, second)
+	require.Error(t, second.Validate("value"))
+}
+
+func TestSchemaCompilerReferenceChanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	otherPath := filepath.Join(tmpDir, "other.json")
+	namePath := filepath.Join(tmpDir, "name.json")
+
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{"$ref": "name.json"}`), 0644))
+	require.NoError(t, os.WriteFile(otherPath, []byte(`{"properties": {"name": {"$ref": "name.json"}}}`), 0644))
+	require.NoError(t, os.WriteFile(namePath, []byte(`{"type": "string"}`), 0644))
+
+	compiler := newSchemaCompiler(nil, nil)
+
+	first, err := compiler.Compile(schemaPath)
+	require.NoError(t, err)
+	require.NoError(t, first.Validate("value"))
+
+	// the referenced schema is compiled again, for the schema referencing it and for new schemas
+	require.NoError(t, os.WriteFile(namePath, []byte(`{"type": "integer"}`), 0644))
+	compiler.refresh()
+
+	second, err := compiler.Compile(schemaPath)
+	require.NoError(t, err)
+	require.NotSame(t, first, second)
+	require.Error(t, second.Validate("value"))
+
+	other, err := compiler.Compile(otherPath)
+	require.NoError(t, err)
+	require.Error(t, other.Validate(map[string]any{"name": "value"}))
+
+	third, err := compiler.Compile(schemaPath)
+	require.NoError(t, err)
+	require.Same(t, second, third)
+}
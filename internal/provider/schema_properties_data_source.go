@@ -0,0 +1,318 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"maps"
+	"slices"
+)
+
+// Ensure SchemaPropertiesDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &SchemaPropertiesDataSource{}
+
+func NewSchemaPropertiesDataSource() datasource.DataSource {
+	return &SchemaPropertiesDataSource{}
+}
+
+// SchemaPropertiesDataSource defines the data source implementation.
+type SchemaPropertiesDataSource struct {
+	compiler *schemaCompiler
+}
+
+// SchemaPropertiesDataSourceModel describes the data source data model.
+type SchemaPropertiesDataSourceModel struct {
+	Schema     types.String `tfsdk:"schema"`
+	Separator  types.String `tfsdk:"separator"`
+	Properties types.Map    `tfsdk:"properties"`
+}
+
+// SchemaPropertyModel describes a property of the objects a schema accepts.
+type SchemaPropertyModel struct {
+	Type        types.String `tfsdk:"type"`
+	JSONTypes   types.List   `tfsdk:"json_types"`
+	Description types.String `tfsdk:"description"`
+	Required    types.Bool   `tfsdk:"required"`
+	Default     types.String `tfsdk:"default"`
+	Deprecated  types.Bool   `tfsdk:"deprecated"`
+}
+
+var schemaPropertyAttrTypes = map[string]attr.Type{
+	"type":        types.StringType,
+	"json_types":  types.ListType{ElemType: types.StringType},
+	"description": types.StringType,
+	"required":    types.BoolType,
+	"default":     types.StringType,
+	"deprecated":  types.BoolType,
+}
+
+func (d *SchemaPropertiesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_properties"
+}
+
+func (d *SchemaPropertiesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "The properties of the objects a json schema accepts by their path, with their type, description and default, " +
+			"so modules can document the configuration files they read, e.g. in a table rendered with `templatefile`, from the schema they are validated against. " +
+			"Properties of object properties are listed with the path of their parent, e.g. `database.port`, " +
+			"and properties of the objects of arrays with `*` in place of the index, e.g. `servers.*.host`. " +
+			"Properties of `allOf` branches are listed as properties of the object, `$ref` are followed. " +
+			"Properties of recursive schemas are listed once.",
+
+		Attributes: map[string]schema.Attribute{
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Path or URL of the json schema, may have a fragment like `#/$defs/app` for a subschema",
+				Required:            true,
+			},
+			"separator": schema.StringAttribute{
+				MarkdownDescription: "Separator of the names of the paths in `properties`, `.` (default) or `/`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(".", "/"),
+				},
+			},
+			"properties": schema.MapNestedAttribute{
+				Description: "Map of property paths to the properties",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Terraform type constraint of the property, as in `jsonschema_terraform_type`",
+							Computed:            true,
+						},
+						"json_types": schema.ListAttribute{
+							MarkdownDescription: "JSON types the property accepts, e.g. `[\"string\", \"null\"]`, empty if it accepts any value",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "`description` of the property, or its `title`",
+							Computed:            true,
+						},
+						"required": schema.BoolAttribute{
+							Description: "Whether the object must have the property",
+							Computed:    true,
+						},
+						"default": schema.StringAttribute{
+							MarkdownDescription: "JSON encoded `default` of the property, null if it has none",
+							Computed:            true,
+						},
+						"deprecated": schema.BoolAttribute{
+							MarkdownDescription: "Whether the property is `deprecated`",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SchemaPropertiesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *SchemaPropertiesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SchemaPropertiesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	properties := make(map[string]SchemaPropertyModel)
+	separator := cmp.Or(data.Separator.ValueString(), ".")
+	if err := schemaProperties(ctx, compiledSchema, "", separator, make(map[*jsonschema.Schema]bool), properties); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error converting schema",
+			"Could not list the properties of schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	value, diags := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: schemaPropertyAttrTypes}, properties)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Properties = value
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// schemaProperties adds the properties of the objects sch accepts to
+// properties by their path, starting with prefix, and those of their object
+// and array values. stack holds the schemas whose properties are being
+// listed, so recursive schemas end.
+func schemaProperties(ctx context.Context, sch *jsonschema.Schema, prefix, separator string, stack map[*jsonschema.Schema]bool, properties map[string]SchemaPropertyModel) error {
+	if sch == nil {
+		return nil
+	}
+	for sch.Ref != nil && len(sch.Properties) == 0 && len(sch.AllOf) == 0 {
+		sch = sch.Ref
+	}
+	if stack[sch] {
+		return nil
+	}
+	stack[sch] = true
+	defer delete(stack, sch)
+
+	objectProperties, required := objectSchemaProperties(sch)
+	for _, name := range slices.Sorted(maps.Keys(objectProperties)) {
+		property := objectProperties[name]
+		key := name
+		if prefix != "" {
+			key = prefix + separator + name
+		}
+
+		t, err := terraformType(property, make(map[*jsonschema.Schema]bool))
+		if err != nil {
+			return fmt.Errorf("property %s: %w", key, err)
+		}
+
+		description, deprecated := schemaAnnotations(property)
+
+		jsonTypes, diags := types.ListValueFrom(ctx, types.StringType, propertyJSONTypes(property))
+		if diags.HasError() {
+			return fmt.Errorf("property %s: %s", key, diags.Errors()[0].Detail())
+		}
+
+		defaultValue := types.StringNull()
+		if value, ok := schemaDefault(property); ok {
+			defaultValue = types.StringValue(jsonString(value))
+		}
+
+		properties[key] = SchemaPropertyModel{
+			Type:        types.StringValue(t),
+			JSONTypes:   jsonTypes,
+			Description: types.StringValue(description),
+			Required:    types.BoolValue(slices.Contains(required, name)),
+			Default:     defaultValue,
+			Deprecated:  types.BoolValue(deprecated),
+		}
+
+		if err := schemaProperties(ctx, property, key, separator, stack, properties); err != nil {
+			return err
+		}
+
+		if items := arrayItemsSchema(property); items != nil {
+			if err := schemaProperties(ctx, items, key+separator+"*", separator, stack, properties); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// objectSchemaProperties returns the properties and required properties of
+// sch, or of the schema it only references, merged with those of its allOf
+// branches.
+func objectSchemaProperties(sch *jsonschema.Schema) (map[string]*jsonschema.Schema, []string) {
+	for sch.Ref != nil && len(sch.Properties) == 0 && len(sch.AllOf) == 0 {
+		sch = sch.Ref
+	}
+
+	properties := maps.Clone(sch.Properties)
+	if properties == nil {
+		properties = make(map[string]*jsonschema.Schema)
+	}
+	required := slices.Clone(sch.Required)
+
+	for _, branch := range sch.AllOf {
+		branchProperties, branchRequired := objectSchemaProperties(branch)
+		for name, property := range branchProperties {
+			if _, ok := properties[name]; !ok {
+				properties[name] = property
+			}
+		}
+		required = append(required, branchRequired...)
+	}
+
+	return properties, required
+}
+
+// arrayItemsSchema returns the schema of the items of the arrays sch, or
+// the schema it only references, accepts, if all items share one.
+func arrayItemsSchema(sch *jsonschema.Schema) *jsonschema.Schema {
+	for sch.Ref != nil && sch.Items2020 == nil && sch.Items == nil {
+		sch = sch.Ref
+	}
+
+	if sch.Items2020 != nil {
+		return sch.Items2020
+	}
+	items, _ := sch.Items.(*jsonschema.Schema)
+	return items
+}
+
+// propertyJSONTypes returns the JSON types sch, or the schema it only
+// references, accepts.
+func propertyJSONTypes(sch *jsonschema.Schema) []string {
+	for sch.Ref != nil && len(schemaJSONTypes(sch)) == 0 {
+		sch = sch.Ref
+	}
+
+	return append([]string{}, schemaJSONTypes(sch)...)
+}
+
+// schemaAnnotations returns the description, or title, of sch and whether
+// it is deprecated, falling back to the schemas it references.
+func schemaAnnotations(sch *jsonschema.Schema) (string, bool) {
+	var description string
+	var deprecated bool
+	for ; sch != nil; sch = sch.Ref {
+		if description == "" {
+			description = cmp.Or(sch.Description, sch.Title)
+		}
+		deprecated = deprecated || sch.Deprecated
+	}
+
+	return description, deprecated
+}
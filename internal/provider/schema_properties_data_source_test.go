@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const testSchemaPropertiesSchema = `{
+  "type": "object",
+  "required": ["name"],
+  "allOf": [{ "$ref": "#/$defs/metadata" }],
+  "properties": {
+    "name": { "type": "string", "description": "Name of the app" },
+    "replicas": { "type": "integer", "default": 1, "deprecated": true },
+    "database": {
+      "title": "Database",
+      "type": "object",
+      "properties": { "port": { "type": ["integer", "null"] } }
+    },
+    "servers": { "type": "array", "items": { "$ref": "#/$defs/server" } },
+    "children": { "type": "array", "items": { "$ref": "#" } }
+  },
+  "$defs": {
+    "metadata": { "properties": { "owner": { "type": "string" } }, "required": ["owner"] },
+    "server": { "type": "object", "properties": { "host": { "type": "string", "description": "Host name" } } }
+  }
+}`
+
+func TestSchemaProperties(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(testSchemaPropertiesSchema), 0644))
+
+	sch, err := newSchemaCompiler(nil, nil).Compile(schemaPath)
+	require.NoError(t, err)
+
+	properties := make(map[string]SchemaPropertyModel)
+	require.NoError(t, schemaProperties(context.Background(), sch, "", ".", make(map[*jsonschema.Schema]bool), properties))
+	require.Equal(t, []string{"children", "database", "database.port", "name", "owner", "replicas", "servers", "servers.*.host"}, slices.Sorted(maps.Keys(properties)))
+
+	require.True(t, properties["owner"].Required.ValueBool())
+	require.False(t, properties["replicas"].Required.ValueBool())
+	require.True(t, properties["replicas"].Deprecated.ValueBool())
+	require.Equal(t, "1", properties["replicas"].Default.ValueString())
+	require.True(t, properties["name"].Default.IsNull())
+	require.Equal(t, "Database", properties["database"].Description.ValueString())
+	require.Equal(t, "Host name", properties["servers.*.host"].Description.ValueString())
+	require.Equal(t, "list(object({ host = optional(string) }))", properties["servers"].Type.ValueString())
+}
+
+func TestSchemaPropertiesDataSource(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "app.schema.json"))
+	require.NoError(t, os.WriteFile(schemaPath, []byte(testSchemaPropertiesSchema), 0644))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccSchemaPropertiesDataSourceConfig, schemaPath, ":"),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config: fmt.Sprintf(testAccSchemaPropertiesDataSourceConfig, schemaPath, "/"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_schema_properties.test",
+						tfjsonpath.New("properties").AtMapKey("database/port"),
+						knownvalue.ObjectExact(map[string]knownvalue.Check{
+							"type":        knownvalue.StringExact("number"),
+							"json_types":  knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("integer"), knownvalue.StringExact("null")}),
+							"description": knownvalue.StringExact(""),
+							"required":    knownvalue.Bool(false),
+							"default":     knownvalue.Null(),
+							"deprecated":  knownvalue.Bool(false),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_schema_properties.test",
+						tfjsonpath.New("properties").AtMapKey("replicas").AtMapKey("default"),
+						knownvalue.StringExact("1"),
+					),
+					statecheck.ExpectKnownOutputValue("required", knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("name"),
+						knownvalue.StringExact("owner"),
+					})),
+				},
+			},
+		},
+	})
+}
+
+const testAccSchemaPropertiesDataSourceConfig = `
+data "jsonschema_schema_properties" "test" {
+  schema    = "%s"
+  separator = "%s"
+}
+
+output "required" {
+  value = sort([for name, property in data.jsonschema_schema_properties.test.properties : name if property.required])
+}
+`
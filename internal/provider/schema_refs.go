@@ -0,0 +1,295 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// referenceCycleError reports subschemas that apply each other to the same
+// value, which the library only detects during validation with the keyword
+// locations of the last step.
+func referenceCycleError(root *jsonschema.Schema) error {
+	cycle := referenceCycle(root)
+	if cycle == nil {
+		return nil
+	}
+
+	locations := make([]string, 0, len(cycle))
+	for _, sch := range cycle {
+		locations = append(locations, displaySchemaLocation(sch.Location))
+	}
+
+	return fmt.Errorf("schemas apply each other to the same value in a cycle that validation cannot terminate: %s", strings.Join(locations, " -> "))
+}
+
+// referenceCycle returns a cycle of schemas reachable from root that apply
+// each other to the same value, e.g. through $ref or allOf, ending with the
+// schema it starts with, or nil. $dynamicRef is resolved at validation time,
+// so cycles through it are left for the validation to report.
+func referenceCycle(root *jsonschema.Schema) []*jsonschema.Schema {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+
+	state := make(map[*jsonschema.Schema]int)
+	var stack []*jsonschema.Schema
+
+	var visit func(sch *jsonschema.Schema) []*jsonschema.Schema
+	visit = func(sch *jsonschema.Schema) []*jsonschema.Schema {
+		switch state[sch] {
+		case onStack:
+			return append(slices.Clone(stack[slices.Index(stack, sch):]), sch)
+		case done:
+			return nil
+		}
+
+		state[sch] = onStack
+		stack = append(stack, sch)
+		for _, next := range inPlaceSchemas(sch) {
+			if cycle := visit(next); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[sch] = done
+
+		return nil
+	}
+
+	// every reachable schema may start a cycle, e.g. properties referencing their parent
+	reachable := []*jsonschema.Schema{root}
+	seen := map[*jsonschema.Schema]bool{root: true}
+	for i := 0; i < len(reachable); i++ {
+		if cycle := visit(reachable[i]); cycle != nil {
+			return cycle
+		}
+
+		for _, next := range slices.Concat(inPlaceSchemas(reachable[i]), nestedSchemas(reachable[i])) {
+			if !seen[next] {
+				seen[next] = true
+				reachable = append(reachable, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// inPlaceSchemas returns the subschemas sch applies to the value it is
+// applied to.
+func inPlaceSchemas(sch *jsonschema.Schema) []*jsonschema.Schema {
+	schemas := slices.Concat([]*jsonschema.Schema{sch.Ref, sch.RecursiveRef, sch.Not, sch.If, sch.Then, sch.Else}, sch.AllOf, sch.AnyOf, sch.OneOf)
+	for _, key := range slices.Sorted(maps.Keys(sch.DependentSchemas)) {
+		schemas = append(schemas, sch.DependentSchemas[key])
+	}
+	for _, key := range slices.Sorted(maps.Keys(sch.Dependencies)) {
+		if dependency, ok := sch.Dependencies[key].(*jsonschema.Schema); ok {
+			schemas = append(schemas, dependency)
+		}
+	}
+
+	return slices.DeleteFunc(schemas, func(s *jsonschema.Schema) bool { return s == nil })
+}
+
+// nestedSchemas returns the subschemas sch applies to other values, e.g.
+// properties and items, and the targets of $dynamicRef.
+func nestedSchemas(sch *jsonschema.Schema) []*jsonschema.Schema {
+	schemas := slices.Concat([]*jsonschema.Schema{sch.PropertyNames, sch.UnevaluatedProperties, sch.Contains, sch.Items2020, sch.UnevaluatedItems, sch.ContentSchema}, sch.PrefixItems)
+	if sch.DynamicRef != nil {
+		schemas = append(schemas, sch.DynamicRef.Ref)
+	}
+	for _, key := range slices.Sorted(maps.Keys(sch.Properties)) {
+		schemas = append(schemas, sch.Properties[key])
+	}
+	for _, sub := range sch.PatternProperties {
+		schemas = append(schemas, sub)
+	}
+	for _, value := range []any{sch.AdditionalProperties, sch.AdditionalItems, sch.Items} {
+		switch v := value.(type) {
+		case *jsonschema.Schema:
+			schemas = append(schemas, v)
+		case []*jsonschema.Schema:
+			schemas = append(schemas, v...)
+		}
+	}
+
+	return slices.DeleteFunc(schemas, func(s *jsonschema.Schema) bool { return s == nil })
+}
+
+// explainReferenceError adds the chain of references from the schema at
+// location to the reference that could not be resolved to err, which only
+// names the unresolved reference. Other errors are returned as they are.
+func explainReferenceError(loader jsonschema.URLLoader, location string, err error) error {
+	var target string
+	var document bool
+
+	var anchorErr *jsonschema.AnchorNotFoundError
+	var pointerErr *jsonschema.JSONPointerNotFoundError
+	var loadErr *jsonschema.LoadURLError
+	switch {
+	case errors.As(err, &anchorErr):
+		target = anchorErr.Reference
+	case errors.As(err, &pointerErr):
+		target = pointerErr.URL
+	case errors.As(err, &loadErr):
+		target, document = loadErr.URL, true
+	default:
+		return err
+	}
+
+	chain := referenceChain(loader, location, target, document)
+	if len(chain) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w (reference chain: %s)", err, strings.Join(chain, " -> "))
+}
+
+// schemaReference is a $ref or $dynamicRef of a schema document.
+type schemaReference struct {
+	// location is the keyword location of the reference.
+	location string
+	// target is the absolute URL of the referenced schema.
+	target *url.URL
+}
+
+// referenceChain returns the keyword locations of the references that lead
+// from the document at location to a reference of target, or to a document
+// of target if document is set. Documents are visited breadth first, so the
+// chain is a shortest one.
+func referenceChain(loader jsonschema.URLLoader, location, target string, document bool) []string {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+
+	start, _, _ := strings.Cut(location, "#")
+
+	chains := map[string][]string{start: nil}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		value, err := loader.Load(current)
+		if err != nil {
+			continue
+		}
+
+		base, err := url.Parse(current)
+		if err != nil {
+			continue
+		}
+
+		for _, ref := range documentReferences(base, base, "", value) {
+			chain := append(slices.Clip(chains[current]), displaySchemaLocation(ref.location))
+
+			referenced := *ref.target
+			referenced.Fragment, referenced.RawFragment = "", ""
+
+			if sameSchemaURL(ref.target, targetURL, document) {
+				return chain
+			}
+
+			if _, ok := chains[referenced.String()]; !ok {
+				chains[referenced.String()] = chain
+				queue = append(queue, referenced.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// sameSchemaURL reports whether ref refers to target, or to the document
+// of target if document is set, comparing fragments after unescaping.
+func sameSchemaURL(ref, target *url.URL, document bool) bool {
+	a, b := *ref, *target
+	if document {
+		a.Fragment, b.Fragment = "", ""
+	}
+	a.RawFragment, b.RawFragment = "", ""
+
+	return a.String() == b.String()
+}
+
+// documentReferences returns the references of value, a subschema at
+// pointer of document, resolved against base and the $id of enclosing
+// subschemas. Values that are not subschemas, e.g. of enum, are skipped.
+func documentReferences(document, base *url.URL, pointer string, value any) []schemaReference {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	if id, ok := object["$id"].(string); ok && !strings.HasPrefix(id, "#") {
+		if resolved, err := base.Parse(id); err == nil {
+			base = resolved
+		}
+	}
+
+	var refs []schemaReference
+	for _, keyword := range []string{"$ref", "$dynamicRef"} {
+		ref, ok := object[keyword].(string)
+		if !ok {
+			continue
+		}
+		if resolved, err := base.Parse(ref); err == nil {
+			keywordLocation := *document
+			keywordLocation.Fragment = pointer + "/" + escapePointerToken(keyword)
+			refs = append(refs, schemaReference{location: keywordLocation.String(), target: resolved})
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		child := pointer + "/" + escapePointerToken(key)
+
+		switch subschemas := object[key].(type) {
+		case map[string]any:
+			if slices.Contains(schemaMapKeywords, key) {
+				for _, name := range slices.Sorted(maps.Keys(subschemas)) {
+					refs = append(refs, documentReferences(document, base, child+"/"+escapePointerToken(name), subschemas[name])...)
+				}
+			} else if slices.Contains(subschemaKeywords, key) {
+				refs = append(refs, documentReferences(document, base, child, subschemas)...)
+			}
+		case []any:
+			if slices.Contains(schemaListKeywords, key) {
+				for i, subschema := range subschemas {
+					refs = append(refs, documentReferences(document, base, fmt.Sprintf("%s/%d", child, i), subschema)...)
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// displaySchemaLocation returns location with the file:// URLs of local
+// schemas as paths, like the paths the schemas are configured with.
+func displaySchemaLocation(location string) string {
+	if !strings.HasPrefix(location, "file://") {
+		return location
+	}
+
+	document, fragment, _ := strings.Cut(location, "#")
+	file, err := (jsonschema.FileLoader{}).ToFile(document)
+	if err != nil {
+		return location
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+
+	return file + "#" + fragment
+}
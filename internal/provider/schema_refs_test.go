@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		// recursion through properties terminates with the document
+		"tree.json":       `{"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#"}}}}`,
+		"cycle.json":      `{"$ref": "#/$defs/a", "$defs": {"a": {"allOf": [{"$ref": "cycle-defs.json#/$defs/b"}]}}}`,
+		"cycle-defs.json": `{"$defs": {"b": {"$ref": "cycle.json#/$defs/a"}}}`,
+		"anchor.json":     `{"properties": {"name": {"$ref": "names.json"}}}`,
+		"names.json":      `{"type": "object", "properties": {"first": {"$ref": "#first"}}}`,
+		"dynamic.json":    `{"$schema": "https://json-schema.org/draft/2020-12/schema", "items": {"$dynamicRef": "#node"}}`,
+		"pointer.json":    `{"anyOf": [{"$ref": "#/$defs/missing"}]}`,
+		"missing.json":    `{"properties": {"owner": {"$ref": "anchor.json"}, "team": {"$ref": "teams.json"}}}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	location := func(file, fragment string) string {
+		return filepath.Join(tmpDir, file) + "#" + fragment
+	}
+
+	tests := map[string]string{
+		"tree.json":    "",
+		"cycle.json":   "cycle that validation cannot terminate: " + location("cycle.json", "/$defs/a") + " -> " + location("cycle.json", "/$defs/a/allOf/0") + " -> " + location("cycle-defs.json", "/$defs/b") + " -> " + location("cycle.json", "/$defs/a"),
+		"anchor.json":  "(reference chain: " + location("anchor.json", "/properties/name/$ref") + " -> " + location("names.json", "/properties/first/$ref") + ")",
+		"dynamic.json": "(reference chain: " + location("dynamic.json", "/items/$dynamicRef") + ")",
+		"pointer.json": "(reference chain: " + location("pointer.json", "/anyOf/0/$ref") + ")",
+		"missing.json": "(reference chain: " + location("missing.json", "/properties/team/$ref") + ")",
+	}
+
+	for name, message := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := newSchemaCompiler(nil, nil).Compile(filepath.Join(tmpDir, name))
+			if message == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, message)
+		})
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Ensure SchemaSetDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &SchemaSetDataSource{}
+
+func NewSchemaSetDataSource() datasource.DataSource {
+	return &SchemaSetDataSource{}
+}
+
+// SchemaSetDataSource defines the data source implementation.
+type SchemaSetDataSource struct {
+	compiler *schemaCompiler
+}
+
+// SchemaSetDataSourceModel describes the data source data model.
+type SchemaSetDataSourceModel struct {
+	Root         types.String `tfsdk:"root"`
+	Schemas      types.List   `tfsdk:"schemas"`
+	IDs          types.Map    `tfsdk:"ids"`
+	Dependencies types.Map    `tfsdk:"dependencies"`
+	PublishOrder types.List   `tfsdk:"publish_order"`
+}
+
+func (d *SchemaSetDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_set"
+}
+
+func (d *SchemaSetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Every json schema (`.json` file) below a directory, compiled with its references resolved, " +
+			"and the graph of the schemas referencing each other with `$ref`, e.g. to publish schemas to a registry in order.",
+
+		Attributes: map[string]schema.Attribute{
+			"root": schema.StringAttribute{
+				Description: "Directory of the schemas, searched recursively",
+				Required:    true,
+			},
+			"schemas": schema.ListAttribute{
+				Description: "Paths of the compiled schemas",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"ids": schema.MapAttribute{
+				MarkdownDescription: "Map of schema paths to their `$id`, schemas without an `$id` are not listed",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"dependencies": schema.MapAttribute{
+				MarkdownDescription: "Map of schema paths to the paths of the schemas of the set they reference, by relative path or by `$id`. " +
+					"References to schemas outside of `root`, e.g. remote schemas, are not listed.",
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+			},
+			"publish_order": schema.ListAttribute{
+				MarkdownDescription: "Paths of the schemas ordered so that every schema comes after the schemas it depends on. " +
+					"Schemas that depend on each other cyclically are ordered by path, with a warning.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *SchemaSetDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *SchemaSetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SchemaSetDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	root := data.Root.ValueString()
+
+	var files []string
+	err := filepath.WalkDir(filepath.FromSlash(root), func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(file), ".json") {
+			files = append(files, filepath.ToSlash(file))
+		}
+		return nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("root"),
+			"Error reading schemas",
+			"Could not read schema directory "+root+": "+err.Error(),
+		)
+		return
+	}
+
+	if len(files) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("root"),
+			"No schemas found",
+			"No .json files found in the schema directory: "+root,
+		)
+		return
+	}
+
+	slices.Sort(files)
+
+	documents := make(map[string]any, len(files))
+	for _, file := range files {
+		document, err := loadTextFile(file)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("root"),
+				"Error reading schema",
+				"Could not read schema "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		documents[file] = document
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// schemas are referenced by the URL of their file or by their $id, which
+	// the compiler of the set resolves to the file instead of loading it
+	compiler := d.compiler.newCompiler()
+	ids := make(map[string]string)
+	locations := make(map[string]string, len(files))
+	for _, file := range files {
+		location := schemaFileURL(file)
+		locations[location] = file
+
+		object, _ := documents[file].(map[string]any)
+		id, ok := object["$id"].(string)
+		if !ok || id == "" {
+			continue
+		}
+		ids[file] = id
+
+		base, err := url.Parse(location)
+		if err != nil {
+			continue
+		}
+		resolved, err := base.Parse(id)
+		if err != nil {
+			continue
+		}
+		resolved.Fragment = ""
+		resolved.RawFragment = ""
+		if resolved.String() == location {
+			continue
+		}
+		locations[resolved.String()] = file
+
+		if err := compiler.AddResource(resolved.String(), documents[file]); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("root"),
+				"Error compiling schema",
+				"Could not add schema "+file+" as "+id+": "+err.Error(),
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, file := range files {
+		if _, err := compiler.Compile(file); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("root"),
+				"Error compiling schema",
+				"Could not compile schema "+file+": "+err.Error(),
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dependencies := make(map[string][]string, len(files))
+	for _, file := range files {
+		base, err := url.Parse(schemaFileURL(file))
+		if err != nil {
+			continue
+		}
+
+		deps := make([]string, 0)
+		for _, ref := range schemaReferences(base, documents[file]) {
+			dep, ok := locations[ref]
+			if ok && dep != file && !slices.Contains(deps, dep) {
+				deps = append(deps, dep)
+			}
+		}
+		slices.Sort(deps)
+
+		dependencies[file] = deps
+	}
+
+	order, cyclic := schemaPublishOrder(files, dependencies)
+	if len(cyclic) > 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("root"),
+			"Cyclic schema dependencies",
+			"Schemas "+strings.Join(cyclic, ", ")+" depend on each other cyclically and are ordered by path in publish_order",
+		)
+	}
+
+	schemas, diags := types.ListValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+
+	data.Schemas = schemas
+
+	data.IDs, diags = types.MapValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+
+	data.Dependencies, diags = types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, dependencies)
+	resp.Diagnostics.Append(diags...)
+
+	data.PublishOrder, diags = types.ListValueFrom(ctx, types.StringType, order)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// schemaFileURL returns the file:// URL of a local schema, which relative
+// references are resolved against.
+func schemaFileURL(file string) string {
+	if abs, err := filepath.Abs(filepath.FromSlash(file)); err == nil {
+		file = abs
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(file)}).String()
+}
+
+// schemaReferences returns the $ref and $dynamicRef of a schema document and
+// its subschemas resolved against base and the $id of enclosing subschemas,
+// without fragments.
+func schemaReferences(base *url.URL, value any) []string {
+	var refs []string
+
+	switch v := value.(type) {
+	case map[string]any:
+		if id, ok := v["$id"].(string); ok {
+			if resolved, err := base.Parse(id); err == nil {
+				base = resolved
+			}
+		}
+
+		for _, keyword := range []string{"$ref", "$dynamicRef"} {
+			ref, ok := v[keyword].(string)
+			if !ok {
+				continue
+			}
+			if resolved, err := base.Parse(ref); err == nil {
+				resolved.Fragment = ""
+				resolved.RawFragment = ""
+				refs = append(refs, resolved.String())
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			// enum and const values are data, not subschemas
+			if key == "enum" || key == "const" || key == "examples" || key == "default" {
+				continue
+			}
+			refs = append(refs, schemaReferences(base, v[key])...)
+		}
+	case []any:
+		for _, item := range v {
+			refs = append(refs, schemaReferences(base, item)...)
+		}
+	}
+
+	return refs
+}
+
+// schemaPublishOrder sorts files topologically by their dependencies. If
+// the remaining files only depend on each other, the first file by path that
+// is part of a cycle is published next. The files of cycles are returned as
+// cyclic.
+func schemaPublishOrder(files []string, dependencies map[string][]string) ([]string, []string) {
+	order := make([]string, 0, len(files))
+	published := make(map[string]bool, len(files))
+
+	var cyclic []string
+	for len(order) < len(files) {
+		progress := false
+		for _, file := range files {
+			if published[file] {
+				continue
+			}
+			if slices.ContainsFunc(dependencies[file], func(dep string) bool { return !published[dep] }) {
+				continue
+			}
+			order = append(order, file)
+			published[file] = true
+			progress = true
+		}
+
+		if progress {
+			continue
+		}
+
+		next := ""
+		for _, file := range files {
+			if !published[file] && dependsOn(file, file, dependencies, published) {
+				if !slices.Contains(cyclic, file) {
+					cyclic = append(cyclic, file)
+				}
+				if next == "" {
+					next = file
+				}
+			}
+		}
+		if next == "" {
+			break
+		}
+		order = append(order, next)
+		published[next] = true
+	}
+
+	slices.Sort(cyclic)
+
+	return order, cyclic
+}
+
+// dependsOn reports whether file depends on target directly or through
+// other schemas that are not published yet.
+func dependsOn(file, target string, dependencies map[string][]string, published map[string]bool) bool {
+	seen := make(map[string]bool)
+
+	var visit func(file string) bool
+	visit = func(file string) bool {
+		for _, dep := range dependencies[file] {
+			if dep == target {
+				return true
+			}
+			if published[dep] || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			if visit(dep) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return visit(file)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestSchemaSet(t *testing.T) {
+	tmpDir := filepath.ToSlash(t.TempDir())
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "types"), 0755))
+
+	schemas := map[string]string{
+		"address.json":    `{"$id": "https://example.com/schemas/address.json", "type": "object", "$defs": {"street": {"type": "string"}}}`,
+		"person.json":     `{"properties": {"name": {"$ref": "types/name.json"}, "address": {"$ref": "https://example.com/schemas/address.json"}, "street": {"$ref": "https://example.com/schemas/address.json#/$defs/street"}}}`,
+		"team.json":       `{"properties": {"members": {"items": {"$ref": "person.json"}}, "enum": {"enum": [{"$ref": "ignored.json"}]}}}`,
+		"types/name.json": `{"type": "string"}`,
+	}
+	for name, content := range schemas {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSchemaSetDataSourceConfig, tmpDir),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_schema_set.schemas",
+						tfjsonpath.New("ids"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							tmpDir + "/address.json": knownvalue.StringExact("https://example.com/schemas/address.json"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_schema_set.schemas",
+						tfjsonpath.New("dependencies"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							tmpDir + "/address.json": knownvalue.ListExact([]knownvalue.Check{}),
+							tmpDir + "/person.json": knownvalue.ListExact([]knownvalue.Check{
+								knownvalue.StringExact(tmpDir + "/address.json"),
+								knownvalue.StringExact(tmpDir + "/types/name.json"),
+							}),
+							tmpDir + "/team.json": knownvalue.ListExact([]knownvalue.Check{
+								knownvalue.StringExact(tmpDir + "/person.json"),
+							}),
+							tmpDir + "/types/name.json": knownvalue.ListExact([]knownvalue.Check{}),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_schema_set.schemas",
+						tfjsonpath.New("publish_order"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(tmpDir + "/address.json"),
+							knownvalue.StringExact(tmpDir + "/types/name.json"),
+							knownvalue.StringExact(tmpDir + "/person.json"),
+							knownvalue.StringExact(tmpDir + "/team.json"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestSchemaSetErrors(t *testing.T) {
+	tmpDir := filepath.ToSlash(t.TempDir())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccSchemaSetDataSourceConfig, tmpDir),
+				ExpectError: regexp.MustCompile(`No schemas found`),
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "broken.json"), []byte(`{"$ref": "missing.json"}`), 0644))
+				},
+				Config:      fmt.Sprintf(testAccSchemaSetDataSourceConfig, tmpDir),
+				ExpectError: regexp.MustCompile(`Error compiling schema`),
+			},
+		},
+	})
+}
+
+func TestSchemaPublishOrder(t *testing.T) {
+	order, cyclic := schemaPublishOrder(
+		[]string{"a.json", "b.json", "c.json", "d.json"},
+		map[string][]string{
+			"a.json": {"b.json"},
+			"b.json": {"a.json"},
+			"c.json": {"a.json"},
+		},
+	)
+
+	require.Equal(t, []string{"d.json", "a.json", "b.json", "c.json"}, order)
+	require.Equal(t, []string{"a.json", "b.json"}, cyclic)
+}
+
+const testAccSchemaSetDataSourceConfig = `
+data "jsonschema_schema_set" "schemas" {
+  root = "%s"
+}
+`
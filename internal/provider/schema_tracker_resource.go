@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure SchemaTrackerResource satisfies various resource interfaces.
+var _ resource.Resource = &SchemaTrackerResource{}
+var _ resource.ResourceWithConfigure = &SchemaTrackerResource{}
+var _ resource.ResourceWithModifyPlan = &SchemaTrackerResource{}
+
+func NewSchemaTrackerResource() resource.Resource {
+	return &SchemaTrackerResource{}
+}
+
+// SchemaTrackerResource defines the resource implementation.
+type SchemaTrackerResource struct {
+	compiler *schemaCompiler
+}
+
+// SchemaTrackerResourceModel describes the resource data model.
+type SchemaTrackerResourceModel struct {
+	ID                   types.String `tfsdk:"id"`
+	InputPattern         types.String `tfsdk:"input_pattern"`
+	Schemas              types.List   `tfsdk:"schemas"`
+	FailOnIsolatedChange types.Bool   `tfsdk:"fail_on_isolated_change"`
+	SchemaHash           types.String `tfsdk:"schema_hash"`
+	ContentHash          types.String `tfsdk:"content_hash"`
+}
+
+func (r *SchemaTrackerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_tracker"
+}
+
+func (r *SchemaTrackerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Records the hashes of json schemas and of the YAML and JSON files validated against them when it is applied, " +
+			"and warns when a plan finds that the schemas changed since the last apply but the files did not, or the files changed but the schemas did not, " +
+			"to catch schemas and configuration evolving in isolation. The hashes are recorded again on every apply that changes them.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Glob pattern of the tracked files",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of the YAML and JSON files written against the schemas",
+				Required:    true,
+			},
+			"schemas": schema.ListAttribute{
+				Description: "Paths or URLs of the tracked json schemas, the schemas they reference are tracked too",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"fail_on_isolated_change": schema.BoolAttribute{
+				MarkdownDescription: "Fail the plan instead of warning when only the schemas or only the files changed, defaults to `false`. " +
+					"Apply with `false` to record an intended isolated change.",
+				Optional: true,
+			},
+			"schema_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the schemas and the schemas they reference at the last apply",
+				Computed:    true,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the paths and content of the files at the last apply",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *SchemaTrackerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+}
+
+// ModifyPlan compares the schemas and files with the hashes of the last
+// apply, warns if only one of them changed and plans the hashes to be
+// recorded again if any changed.
+func (r *SchemaTrackerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state SchemaTrackerResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() || plan.InputPattern.IsUnknown() || plan.Schemas.IsUnknown() {
+		return
+	}
+
+	// errors, e.g. of files that do not exist yet, are left for the apply to report
+	var hashDiags diag.Diagnostics
+	schemaHash, contentHash := r.hashes(ctx, &plan, &hashDiags)
+	if hashDiags.HasError() {
+		return
+	}
+
+	schemaChanged := schemaHash != state.SchemaHash.ValueString()
+	contentChanged := contentHash != state.ContentHash.ValueString()
+
+	if schemaChanged != contentChanged {
+		summary, detail := "Schemas changed without the files", "The schemas changed since the last apply but the files matched by "+plan.InputPattern.ValueString()+" did not, "+
+			"the files may not use the changes of the schemas yet."
+		if contentChanged {
+			summary, detail = "Files changed without the schemas", "The files matched by "+plan.InputPattern.ValueString()+" changed since the last apply but the schemas did not, "+
+				"the schemas may not describe the changes of the files yet."
+		}
+
+		if plan.FailOnIsolatedChange.ValueBool() {
+			resp.Diagnostics.AddAttributeError(path.Root("schemas"), summary, detail)
+			return
+		}
+		resp.Diagnostics.AddAttributeWarning(path.Root("schemas"), summary, detail)
+	}
+
+	if schemaChanged || contentChanged {
+		plan.SchemaHash = types.StringUnknown()
+		plan.ContentHash = types.StringUnknown()
+	} else {
+		plan.SchemaHash = state.SchemaHash
+		plan.ContentHash = state.ContentHash
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *SchemaTrackerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SchemaTrackerResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.record(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SchemaTrackerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SchemaTrackerResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The hashes of the last apply are kept, changes are planned as updates.
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SchemaTrackerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SchemaTrackerResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.record(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SchemaTrackerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to clean up, the hashes are only kept in state.
+}
+
+// record fills in the hashes of the schemas and files of data.
+func (r *SchemaTrackerResource) record(ctx context.Context, data *SchemaTrackerResourceModel, diags *diag.Diagnostics) {
+	schemaHash, contentHash := r.hashes(ctx, data, diags)
+	if diags.HasError() {
+		return
+	}
+
+	data.ID = data.InputPattern
+	data.SchemaHash = types.StringValue(schemaHash)
+	data.ContentHash = types.StringValue(contentHash)
+}
+
+// hashes returns the hashes of the schemas and of the files of data.
+func (r *SchemaTrackerResource) hashes(ctx context.Context, data *SchemaTrackerResourceModel, diags *diag.Diagnostics) (string, string) {
+	var locations []string
+	diags.Append(data.Schemas.ElementsAs(ctx, &locations, false)...)
+	if diags.HasError() {
+		return "", ""
+	}
+
+	schemaHash, err := r.compiler.hashSchemas(locations)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("schemas"),
+			"Error hashing schemas",
+			"Could not load the schemas: "+err.Error(),
+		)
+		return "", ""
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), diags)
+	if diags.HasError() {
+		return "", ""
+	}
+
+	contentHash, err := assertionContentHash(files)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("input_pattern"),
+			"Error reading file",
+			err.Error(),
+		)
+		return "", ""
+	}
+
+	return schemaHash, contentHash
+}
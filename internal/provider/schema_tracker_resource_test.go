@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestSchemaTracker(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeFile := func(name, content string) func() {
+		return func() {
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+		}
+	}
+
+	writeFile("common.json", `{"type": "string"}`)()
+	writeFile("schema.json", `{"properties": {"name": {"$ref": "common.json"}}}`)()
+	writeFile("values.yaml", "name: web\n")()
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, "*.yaml"))
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "schema.json"))
+
+	var schemaHash string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSchemaTrackerResourceConfig, pattern, schemaPath, true),
+				Check: func(s *terraform.State) error {
+					schemaHash = s.RootModule().Resources["jsonschema_schema_tracker.test"].Primary.Attributes["schema_hash"]
+					return nil
+				},
+			},
+			// a referenced schema changed without the files
+			{
+				PreConfig:   writeFile("common.json", `{"type": "string", "minLength": 1}`),
+				Config:      fmt.Sprintf(testAccSchemaTrackerResourceConfig, pattern, schemaPath, true),
+				ExpectError: regexp.MustCompile(`Schemas changed without the files`),
+			},
+			// the isolated change is recorded without fail_on_isolated_change
+			{
+				Config: fmt.Sprintf(testAccSchemaTrackerResourceConfig, pattern, schemaPath, false),
+				Check: func(s *terraform.State) error {
+					if s.RootModule().Resources["jsonschema_schema_tracker.test"].Primary.Attributes["schema_hash"] == schemaHash {
+						return fmt.Errorf("schema_hash did not change")
+					}
+					return nil
+				},
+			},
+			{
+				PreConfig:   writeFile("values.yaml", "name: api\n"),
+				Config:      fmt.Sprintf(testAccSchemaTrackerResourceConfig, pattern, schemaPath, true),
+				ExpectError: regexp.MustCompile(`Files changed without the schemas`),
+			},
+			// both changed together
+			{
+				PreConfig: writeFile("schema.json", `{"properties": {"name": {"$ref": "common.json"}, "replicas": {"type": "integer"}}}`),
+				Config:    fmt.Sprintf(testAccSchemaTrackerResourceConfig, pattern, schemaPath, true),
+			},
+			{
+				Config:   fmt.Sprintf(testAccSchemaTrackerResourceConfig, pattern, schemaPath, true),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccSchemaTrackerResourceConfig = `
+resource "jsonschema_schema_tracker" "test" {
+  input_pattern           = "%s"
+  schemas                 = ["%s"]
+  fail_on_isolated_change = %t
+}
+`
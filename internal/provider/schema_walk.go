@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// walkSchema calls visit for value and every value nested in it with the
+// JSON pointer of the value and the subschemas of sch that apply to it.
+// Branches of anyOf, oneOf and if/then/else only apply if value matches them.
+func walkSchema(sch *jsonschema.Schema, value any, visit func(pointer string, value any, schemas []*jsonschema.Schema)) {
+	walkSchemas(applicableSchemas([]*jsonschema.Schema{sch}, value), value, "", visit)
+}
+
+func walkSchemas(schemas []*jsonschema.Schema, value any, pointer string, visit func(pointer string, value any, schemas []*jsonschema.Schema)) {
+	if len(schemas) == 0 {
+		return
+	}
+
+	visit(pointer, value, schemas)
+
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var children []*jsonschema.Schema
+			for _, s := range schemas {
+				children = append(children, childSchemas(s, key)...)
+			}
+			walkSchemas(applicableSchemas(children, v[key]), v[key], pointer+"/"+escapePointerToken(key), visit)
+		}
+	case []any:
+		for i, item := range v {
+			var children []*jsonschema.Schema
+			for _, s := range schemas {
+				children = append(children, itemSchemas(s, i)...)
+			}
+			walkSchemas(applicableSchemas(children, item), item, pointer+"/"+strconv.Itoa(i), visit)
+		}
+	}
+}
+
+// applicableSchemas expands schemas with the subschemas applying to the
+// same value through $ref, allOf and the branches value matches.
+func applicableSchemas(schemas []*jsonschema.Schema, value any) []*jsonschema.Schema {
+	var result []*jsonschema.Schema
+
+	seen := make(map[*jsonschema.Schema]bool)
+
+	var visit func(s *jsonschema.Schema)
+	visit = func(s *jsonschema.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		result = append(result, s)
+
+		visit(s.Ref)
+		visit(s.RecursiveRef)
+		if s.DynamicRef != nil {
+			visit(s.DynamicRef.Ref)
+		}
+
+		for _, sub := range s.AllOf {
+			visit(sub)
+		}
+		for _, sub := range s.AnyOf {
+			if sub.Validate(value) == nil {
+				visit(sub)
+			}
+		}
+		for _, sub := range s.OneOf {
+			if sub.Validate(value) == nil {
+				visit(sub)
+			}
+		}
+
+		if s.If != nil {
+			if s.If.Validate(value) == nil {
+				visit(s.If)
+				visit(s.Then)
+			} else {
+				visit(s.Else)
+			}
+		}
+	}
+
+	for _, s := range schemas {
+		visit(s)
+	}
+
+	return result
+}
+
+// childSchemas returns the subschemas of s applying to the property key.
+func childSchemas(s *jsonschema.Schema, key string) []*jsonschema.Schema {
+	var children []*jsonschema.Schema
+
+	if sub, ok := s.Properties[key]; ok {
+		children = append(children, sub)
+	}
+
+	for re, sub := range s.PatternProperties {
+		if re.MatchString(key) {
+			children = append(children, sub)
+		}
+	}
+
+	if len(children) == 0 {
+		if sub, ok := s.AdditionalProperties.(*jsonschema.Schema); ok {
+			children = append(children, sub)
+		}
+	}
+
+	return children
+}
+
+// itemSchemas returns the subschemas of s applying to the item at index i.
+func itemSchemas(s *jsonschema.Schema, i int) []*jsonschema.Schema {
+	if i < len(s.PrefixItems) {
+		return []*jsonschema.Schema{s.PrefixItems[i]}
+	}
+	if s.Items2020 != nil {
+		return []*jsonschema.Schema{s.Items2020}
+	}
+
+	switch items := s.Items.(type) {
+	case *jsonschema.Schema:
+		return []*jsonschema.Schema{items}
+	case []*jsonschema.Schema:
+		if i < len(items) {
+			return []*jsonschema.Schema{items[i]}
+		}
+		if sub, ok := s.AdditionalItems.(*jsonschema.Schema); ok {
+			return []*jsonschema.Schema{sub}
+		}
+	}
+
+	return nil
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
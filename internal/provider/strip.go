@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+// stripPointers removes the values at pointers from value, which is
+// modified in place, and reports whether any value was removed. Pointers
+// to values that do not exist are ignored. Every pointer is resolved
+// against value as it is, e.g. /items/0 and /items/1 remove the first two
+// items.
+func stripPointers(value any, pointers [][]string) (any, bool) {
+	removals, children := splitPointers(pointers)
+
+	var stripped bool
+	switch node := value.(type) {
+	case map[string]any:
+		for key, rest := range children {
+			child, ok := node[key]
+			if !ok {
+				continue
+			}
+			child, removed := stripPointers(child, rest)
+			node[key] = child
+			stripped = stripped || removed
+		}
+		for key := range removals {
+			if _, ok := node[key]; ok {
+				delete(node, key)
+				stripped = true
+			}
+		}
+		return node, stripped
+	case []any:
+		for token, rest := range children {
+			if index, ok := pointerIndex(token, len(node)); ok {
+				child, removed := stripPointers(node[index], rest)
+				node[index] = child
+				stripped = stripped || removed
+			}
+		}
+
+		removed := pointerIndexes(removals, len(node))
+		if len(removed) == 0 {
+			return node, stripped
+		}
+		kept := node[:0]
+		for i, item := range node {
+			if !removed[i] {
+				kept = append(kept, item)
+			}
+		}
+		return kept, true
+	}
+
+	return value, false
+}
+
+// splitPointers splits pointers into the first tokens of the pointers to
+// remove and the rest of the other pointers by their first token.
+func splitPointers(pointers [][]string) (map[string]bool, map[string][][]string) {
+	removals := make(map[string]bool)
+	children := make(map[string][][]string)
+	for _, tokens := range pointers {
+		if len(tokens) == 1 {
+			removals[tokens[0]] = true
+			continue
+		}
+		children[tokens[0]] = append(children[tokens[0]], tokens[1:])
+	}
+
+	return removals, children
+}
+
+// pointerIndex returns the index of the token of an array of length, if it
+// is one.
+func pointerIndex(token string, length int) (int, bool) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index >= length {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// pointerIndexes returns the indexes of the tokens of an array of length.
+func pointerIndexes(tokens map[string]bool, length int) map[int]bool {
+	indexes := make(map[int]bool)
+	for token := range tokens {
+		if index, ok := pointerIndex(token, length); ok {
+			indexes[index] = true
+		}
+	}
+
+	return indexes
+}
+
+// stripDocument re-encodes a document the values at pointers were removed
+// from. YAML documents are stripped as nodes, so their formatting and
+// comments are kept, with aliases and merge keys expanded. JSON documents
+// are encoded from their stripped value.
+func stripDocument(document string, value any, pointers [][]string, isJSON bool) (string, error) {
+	if isJSON {
+		encoded, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(encoded) + "\n", nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(document), &node); err != nil {
+		return "", err
+	}
+
+	expanded := expandYAMLNode(&node, true)
+	if len(expanded.Content) > 0 {
+		removeYAMLPointers(expanded.Content[0], pointers)
+	}
+
+	var buf strings.Builder
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err := encoder.Encode(expanded)
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// removeYAMLPointers removes the nodes at pointers from node, resolved
+// against node as it is, like stripPointers.
+func removeYAMLPointers(node *yaml.Node, pointers [][]string) {
+	removals, children := splitPointers(pointers)
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if rest, ok := children[node.Content[i].Value]; ok {
+				removeYAMLPointers(node.Content[i+1], rest)
+			}
+		}
+
+		kept := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !removals[node.Content[i].Value] {
+				kept = append(kept, node.Content[i], node.Content[i+1])
+			}
+		}
+		node.Content = kept
+	case yaml.SequenceNode:
+		for token, rest := range children {
+			if index, ok := pointerIndex(token, len(node.Content)); ok {
+				removeYAMLPointers(node.Content[index], rest)
+			}
+		}
+
+		removed := pointerIndexes(removals, len(node.Content))
+		kept := node.Content[:0]
+		for i, item := range node.Content {
+			if !removed[i] {
+				kept = append(kept, item)
+			}
+		}
+		node.Content = kept
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestStripPointers(t *testing.T) {
+	pointers := [][]string{{"internal"}, {"servers", "1"}, {"a/b"}, {"missing", "key"}}
+
+	value, stripped := stripPointers(map[string]any{
+		"name":     "web",
+		"internal": map[string]any{"owner": "platform"},
+		"servers":  []any{"a", "b", "c"},
+		"a/b":      true,
+	}, pointers)
+	require.True(t, stripped)
+	require.Equal(t, map[string]any{"name": "web", "servers": []any{"a", "c"}}, value)
+
+	_, stripped = stripPointers(map[string]any{"name": "web"}, pointers)
+	require.False(t, stripped)
+
+	document, err := stripDocument("# the service\nname: web # public\ninternal:\n  owner: platform\nservers: [a, b, c]\n", value, pointers, false)
+	require.NoError(t, err)
+	require.Equal(t, "# the service\nname: web # public\nservers: [a, c]\n", document)
+}
+
+func TestStripPointersIndexes(t *testing.T) {
+	// every pointer refers to the items as they were before any was removed
+	pointers := [][]string{{"items", "0"}, {"items", "1"}, {"items", "3", "name"}}
+
+	value, stripped := stripPointers(map[string]any{
+		"items": []any{"a", "b", "c", map[string]any{"name": "d", "id": 4}},
+	}, pointers)
+	require.True(t, stripped)
+	require.Equal(t, map[string]any{"items": []any{"c", map[string]any{"id": 4}}}, value)
+
+	document, err := stripDocument("items:\n  - a\n  - b # second\n  - c\n  - name: d\n    id: 4\n", value, pointers, false)
+	require.NoError(t, err)
+	require.Equal(t, "items:\n  - c\n  - id: 4\n", document)
+}
+
+func TestStripPointersYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"schema.json":  `{"type": "object", "required": ["name", "internal"], "properties": {"name": {"type": "string"}, "internal": {"type": "object"}}}`,
+		"service.yaml": "# yaml-language-server: $schema=./schema.json\nname: web\ninternal:\n  owner: platform\n---\nname: worker\ninternal: {}\n",
+		"public.yaml":  "# yaml-language-server: $schema=./schema.json\nname: db # kept as it is\ninternal: {}\ndebug: true\n",
+		"service.json": `{"$schema": "./schema.json", "name": "api", "internal": {"owner": "platform"}}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	pattern := filepath.ToSlash(filepath.Join(tmpDir, "*.*"))
+	service := filepath.Join(tmpDir, "service.yaml")
+	public := filepath.Join(tmpDir, "public.yaml")
+	serviceJSON := filepath.Join(tmpDir, "service.json")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccStripPointersConfig, pattern, `"internal"`),
+				ExpectError: regexp.MustCompile(`must be a JSON pointer`),
+			},
+			// the stripped values are still validated
+			{
+				Config: fmt.Sprintf(testAccStripPointersConfig, filepath.ToSlash(filepath.Join(tmpDir, "*.yaml")), `"/internal", "/debug"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							service: knownvalue.StringExact("name: web\n---\nname: worker"),
+							public:  knownvalue.StringExact("name: db # kept as it is"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							service: knownvalue.StringExact(`[{"name":"web"},{"name":"worker"}]`),
+							public:  knownvalue.StringExact(`{"name":"db"}`),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("documents_list").AtSliceIndex(2).AtMapKey("content"),
+						knownvalue.StringExact("name: worker"),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccStripPointersConfig, filepath.ToSlash(serviceJSON), `"/internal/owner"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(serviceJSON),
+						knownvalue.StringExact("{\n  \"$schema\": \"./schema.json\",\n  \"internal\": {},\n  \"name\": \"api\"\n}"),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccStripPointersConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern  = "%s"
+  syntax         = "auto"
+  strip_pointers = [%s]
+}
+`
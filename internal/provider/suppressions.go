@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SuppressionModel describes a rule downgrading known violations to
+// warnings.
+type SuppressionModel struct {
+	FileGlob types.String `tfsdk:"file_glob"`
+	Pointer  types.String `tfsdk:"pointer"`
+	Keyword  types.String `tfsdk:"keyword"`
+}
+
+// matches reports whether finding is suppressed by the rule. A glob without
+// a slash is matched against the file name, a pointer matches the value at
+// the pointer and the values below it.
+func (s SuppressionModel) matches(finding reportFinding) bool {
+	if !matchFileGlob(s.FileGlob.ValueString(), finding.File) {
+		return false
+	}
+
+	if pointer := strings.TrimSuffix(s.Pointer.ValueString(), "/"); pointer != "" && finding.Pointer != pointer && !strings.HasPrefix(finding.Pointer, pointer+"/") {
+		return false
+	}
+
+	return s.Keyword.ValueString() == "" || s.Keyword.ValueString() == finding.Keyword
+}
+
+// matchFileGlob reports whether glob matches the path of file with forward
+// slashes, or its name if glob does not contain a slash.
+func matchFileGlob(glob, file string) bool {
+	if !strings.Contains(glob, "/") {
+		file = filepath.Base(filepath.FromSlash(file))
+	}
+	ok, _ := filepath.Match(filepath.FromSlash(glob), filepath.FromSlash(file))
+	return ok
+}
+
+// suppressFindings downgrades the violations matched by a suppression to
+// warnings.
+func suppressFindings(findings []reportFinding, suppressions []SuppressionModel) {
+	for i, finding := range findings {
+		// errors other than violations, e.g. of a missing reference, cannot be suppressed
+		if finding.Keyword != "" && slices.ContainsFunc(suppressions, func(s SuppressionModel) bool { return s.matches(finding) }) {
+			findings[i].Severity = severityWarning
+			findings[i].Suppressed = true
+		}
+	}
+}
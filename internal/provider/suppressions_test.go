@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestSuppressionMatches(t *testing.T) {
+	finding := reportFinding{File: "envs/legacy/values.yaml", Pointer: "/spec/replicas", Keyword: "type"}
+
+	for name, tt := range map[string]struct {
+		suppression SuppressionModel
+		expected    bool
+	}{
+		"file name":     {SuppressionModel{FileGlob: types.StringValue("values.yaml")}, true},
+		"path":          {SuppressionModel{FileGlob: types.StringValue("envs/*/values.yaml")}, true},
+		"other path":    {SuppressionModel{FileGlob: types.StringValue("envs/prod/*.yaml")}, false},
+		"pointer":       {SuppressionModel{FileGlob: types.StringValue("*"), Pointer: types.StringValue("/spec/replicas")}, true},
+		"parent":        {SuppressionModel{FileGlob: types.StringValue("*"), Pointer: types.StringValue("/spec")}, true},
+		"prefix":        {SuppressionModel{FileGlob: types.StringValue("*"), Pointer: types.StringValue("/spec/rep")}, false},
+		"keyword":       {SuppressionModel{FileGlob: types.StringValue("*"), Keyword: types.StringValue("type")}, true},
+		"other keyword": {SuppressionModel{FileGlob: types.StringValue("*"), Keyword: types.StringValue("required")}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.suppression.matches(finding))
+		})
+	}
+}
+
+func TestSuppressionsYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "legacy.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: 123
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.Join(tmpDir, "*.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccSuppressionsConfig, pattern, `[
+    { file_glob = "legacy.yaml", pointer = "/id", keyword = "type" },
+    { file_glob = "*.yaml", keyword = "required" },
+  ]`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringRegexp(regexp.MustCompile(`"pointer":"/id","keyword":"type","message":"[^"]*","severity":"warning","suppressed":true`)),
+					),
+				},
+			},
+			// the missing name is not suppressed
+			{
+				Config:      fmt.Sprintf(testAccSuppressionsConfig, pattern, `[{ file_glob = "legacy.yaml", pointer = "/id" }]`),
+				ExpectError: regexp.MustCompile(`missing property 'name'`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccSuppressionsConfig, pattern, `[{ file_glob = "[" }]`),
+				ExpectError: regexp.MustCompile(`Invalid suppression`),
+			},
+		},
+	})
+}
+
+const testAccSuppressionsConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  suppressions  = %s
+}
+`
@@ -0,0 +1,472 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// hclIdentifierRegex matches the attribute names of HCL object types and
+// the keys of object values that need no quotes.
+var hclIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Ensure TerraformTypeDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &TerraformTypeDataSource{}
+
+func NewTerraformTypeDataSource() datasource.DataSource {
+	return &TerraformTypeDataSource{}
+}
+
+// TerraformTypeDataSource defines the data source implementation.
+type TerraformTypeDataSource struct {
+	compiler *schemaCompiler
+}
+
+// TerraformTypeDataSourceModel describes the data source data model.
+type TerraformTypeDataSourceModel struct {
+	Schema      types.String `tfsdk:"schema"`
+	Type        types.String `tfsdk:"type"`
+	Description types.String `tfsdk:"description"`
+}
+
+func (d *TerraformTypeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_terraform_type"
+}
+
+func (d *TerraformTypeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "The Terraform type constraint of a json schema, e.g. `object({ name = string, replicas = optional(number, 1) })`, " +
+			"to generate the `variable` blocks of a module from the schema its configuration files are validated against. " +
+			"Properties that are not `required` are `optional`, with the `default` of their schema. " +
+			"Integers and numbers are `number`, arrays are `list` or, with `prefixItems`, `tuple`, and arrays of `uniqueItems` are `set`. " +
+			"Objects with `properties` are `object`, other objects are `map` of their `additionalProperties`. " +
+			"Properties whose names are not valid attribute names, e.g. with spaces, cannot be converted. " +
+			"Schemas that no type constraint describes, e.g. `anyOf` of different types or recursive schemas, are `any`.",
+
+		Attributes: map[string]schema.Attribute{
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Path or URL of the json schema, may have a fragment like `#/$defs/app` for a subschema",
+				Required:            true,
+			},
+			"type": schema.StringAttribute{
+				Description: "Terraform type constraint of the schema",
+				Computed:    true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "`description` of the schema, or its `title`, e.g. for the description of the variable",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *TerraformTypeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *TerraformTypeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TerraformTypeDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	typeConstraint, err := terraformType(compiledSchema, make(map[*jsonschema.Schema]bool))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error converting schema",
+			"Could not convert schema "+schemaPath+" into a Terraform type: "+err.Error(),
+		)
+		return
+	}
+
+	description := compiledSchema.Description
+	if description == "" {
+		description = compiledSchema.Title
+	}
+
+	data.Type = types.StringValue(typeConstraint)
+	data.Description = types.StringValue(description)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// terraformType returns the Terraform type constraint of the values sch
+// accepts. Schemas on stack are being converted, references back to them
+// are recursive and converted to any.
+func terraformType(sch *jsonschema.Schema, stack map[*jsonschema.Schema]bool) (string, error) {
+	if sch == nil || sch.Bool != nil || stack[sch] {
+		return "any", nil
+	}
+
+	stack[sch] = true
+	defer delete(stack, sch)
+
+	jsonTypes := schemaJSONTypes(sch)
+
+	// a schema that only references or combines other schemas has their type
+	if len(jsonTypes) == 0 {
+		var branches []*jsonschema.Schema
+		switch {
+		case sch.Ref != nil:
+			branches = []*jsonschema.Schema{sch.Ref}
+		case sch.DynamicRef != nil:
+			branches = []*jsonschema.Schema{sch.DynamicRef.Ref}
+		case len(sch.AllOf) > 0:
+			return allOfTerraformType(sch.AllOf, stack)
+		case len(sch.AnyOf) > 0:
+			branches = sch.AnyOf
+		case len(sch.OneOf) > 0:
+			branches = sch.OneOf
+		}
+
+		var branchType string
+		for i, branch := range branches {
+			t, err := terraformType(branch, stack)
+			if err != nil {
+				return "", err
+			}
+			// branches of different types only have any in common
+			if i > 0 && t != branchType {
+				return "any", nil
+			}
+			branchType = t
+		}
+		if branchType != "" {
+			return branchType, nil
+		}
+
+		return "any", nil
+	}
+
+	// null is a value of every type
+	jsonTypes = slices.DeleteFunc(jsonTypes, func(t string) bool { return t == "null" })
+	if slices.Equal(jsonTypes, []string{"integer", "number"}) {
+		jsonTypes = []string{"number"}
+	}
+	if len(jsonTypes) != 1 {
+		return "any", nil
+	}
+
+	switch jsonTypes[0] {
+	case "string":
+		return "string", nil
+	case "integer", "number":
+		return "number", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		return arrayTerraformType(sch, stack)
+	case "object":
+		return objectTerraformType(sch, stack, sch.Properties, sch.Required)
+	}
+
+	return "any", nil
+}
+
+// schemaJSONTypes returns the sorted JSON types sch declares with type, or
+// implies with the values of enum and const or with keywords of objects and
+// arrays.
+func schemaJSONTypes(sch *jsonschema.Schema) []string {
+	if sch.Types != nil && !sch.Types.IsEmpty() {
+		return slices.Sorted(slices.Values(sch.Types.ToStrings()))
+	}
+
+	var values []any
+	switch {
+	case sch.Const != nil:
+		values = []any{*sch.Const}
+	case sch.Enum != nil:
+		values = sch.Enum.Values
+	case len(sch.Properties) > 0 || sch.AdditionalProperties != nil || len(sch.PatternProperties) > 0:
+		return []string{"object"}
+	case sch.Items != nil || sch.Items2020 != nil || len(sch.PrefixItems) > 0:
+		return []string{"array"}
+	}
+
+	jsonTypes := make(map[string]bool)
+	for _, value := range values {
+		switch value.(type) {
+		case nil:
+			jsonTypes["null"] = true
+		case bool:
+			jsonTypes["boolean"] = true
+		case json.Number, float64, int:
+			jsonTypes["number"] = true
+		case string:
+			jsonTypes["string"] = true
+		case []any:
+			jsonTypes["array"] = true
+		case map[string]any:
+			jsonTypes["object"] = true
+		}
+	}
+
+	return slices.Sorted(maps.Keys(jsonTypes))
+}
+
+// allOfTerraformType returns the type of the values all of schemas accept:
+// the properties of objects are merged, other schemas have to agree.
+func allOfTerraformType(schemas []*jsonschema.Schema, stack map[*jsonschema.Schema]bool) (string, error) {
+	properties := make(map[string]*jsonschema.Schema)
+	var required []string
+	objects := true
+	for _, sch := range schemas {
+		resolved := sch
+		for resolved.Ref != nil && len(schemaJSONTypes(resolved)) == 0 {
+			resolved = resolved.Ref
+		}
+		if !slices.Equal(schemaJSONTypes(resolved), []string{"object"}) || len(resolved.Properties) == 0 {
+			objects = false
+			break
+		}
+
+		for name, property := range resolved.Properties {
+			if _, ok := properties[name]; !ok {
+				properties[name] = property
+			}
+		}
+		required = append(required, resolved.Required...)
+	}
+
+	if objects {
+		return objectTerraformType(nil, stack, properties, required)
+	}
+
+	var allOfType string
+	for i, sch := range schemas {
+		t, err := terraformType(sch, stack)
+		if err != nil {
+			return "", err
+		}
+		if t == "any" {
+			continue
+		}
+		if i > 0 && allOfType != "" && t != allOfType {
+			return "any", nil
+		}
+		allOfType = t
+	}
+
+	return cmp.Or(allOfType, "any"), nil
+}
+
+// arrayTerraformType returns the tuple, list or set type of the arrays sch
+// accepts.
+func arrayTerraformType(sch *jsonschema.Schema, stack map[*jsonschema.Schema]bool) (string, error) {
+	prefixItems := sch.PrefixItems
+	if items, ok := sch.Items.([]*jsonschema.Schema); ok {
+		prefixItems = items
+	}
+
+	if len(prefixItems) > 0 {
+		elements := make([]string, 0, len(prefixItems))
+		for _, item := range prefixItems {
+			t, err := terraformType(item, stack)
+			if err != nil {
+				return "", err
+			}
+			elements = append(elements, t)
+		}
+		return "tuple([" + strings.Join(elements, ", ") + "])", nil
+	}
+
+	var itemSchema *jsonschema.Schema
+	switch {
+	case sch.Items2020 != nil:
+		itemSchema = sch.Items2020
+	case sch.Items != nil:
+		itemSchema, _ = sch.Items.(*jsonschema.Schema)
+	}
+
+	element, err := terraformType(itemSchema, stack)
+	if err != nil {
+		return "", err
+	}
+
+	if sch.UniqueItems {
+		return "set(" + element + ")", nil
+	}
+
+	return "list(" + element + ")", nil
+}
+
+// objectTerraformType returns the object type of properties, or the map
+// type of the additional properties of sch if there are none.
+func objectTerraformType(sch *jsonschema.Schema, stack map[*jsonschema.Schema]bool, properties map[string]*jsonschema.Schema, required []string) (string, error) {
+	if len(properties) == 0 {
+		var valueSchema *jsonschema.Schema
+		if additional, ok := sch.AdditionalProperties.(*jsonschema.Schema); ok {
+			valueSchema = additional
+		} else if len(sch.PatternProperties) == 1 {
+			for _, pattern := range sch.PatternProperties {
+				valueSchema = pattern
+			}
+		}
+
+		element, err := terraformType(valueSchema, stack)
+		if err != nil {
+			return "", err
+		}
+
+		return "map(" + element + ")", nil
+	}
+
+	attributes := make([]string, 0, len(properties))
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		property := properties[name]
+
+		// attribute names of object types cannot be quoted, unlike the keys of object values
+		if !hclIdentifierRegex.MatchString(name) {
+			return "", fmt.Errorf("property %q is not a valid attribute name of an object type", name)
+		}
+
+		t, err := terraformType(property, stack)
+		if err != nil {
+			return "", err
+		}
+
+		if !slices.Contains(required, name) {
+			if value, ok := schemaDefault(property); ok {
+				literal, err := hclLiteral(value)
+				if err != nil {
+					return "", fmt.Errorf("default of property %s: %w", name, err)
+				}
+				t = "optional(" + t + ", " + literal + ")"
+			} else {
+				t = "optional(" + t + ")"
+			}
+		}
+
+		attributes = append(attributes, name+" = "+t)
+	}
+
+	return "object({ " + strings.Join(attributes, ", ") + " })", nil
+}
+
+// schemaDefault returns the default of sch, or of the schema it only
+// references.
+func schemaDefault(sch *jsonschema.Schema) (any, bool) {
+	for ; sch != nil; sch = sch.Ref {
+		if sch.Default != nil {
+			return *sch.Default, true
+		}
+	}
+
+	return nil, false
+}
+
+// hclLiteral returns the HCL expression of a JSON value. Template sequences
+// of strings are escaped, so they are taken literally.
+func hclLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case string:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err != nil {
+			return "", err
+		}
+		quoted := strings.TrimSuffix(buf.String(), "\n")
+		quoted = strings.ReplaceAll(quoted, "${", "$${")
+		quoted = strings.ReplaceAll(quoted, "%{", "%%{")
+		return quoted, nil
+	case []any:
+		elements := make([]string, 0, len(v))
+		for _, item := range v {
+			element, err := hclLiteral(item)
+			if err != nil {
+				return "", err
+			}
+			elements = append(elements, element)
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}", nil
+		}
+		attributes := make([]string, 0, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			element, err := hclLiteral(v[key])
+			if err != nil {
+				return "", err
+			}
+			attributes = append(attributes, hclAttributeName(key)+" = "+element)
+		}
+		return "{ " + strings.Join(attributes, ", ") + " }", nil
+	}
+
+	return "", fmt.Errorf("unsupported value %v of type %T", value, value)
+}
+
+// hclAttributeName returns name as an attribute name of HCL object values,
+// quoted unless it is an identifier.
+func hclAttributeName(name string) string {
+	if hclIdentifierRegex.MatchString(name) {
+		return name
+	}
+
+	quoted, _ := hclLiteral(name)
+
+	return quoted
+}
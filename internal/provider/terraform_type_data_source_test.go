@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestTerraformType(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{"string", `{"type": "string"}`, "string"},
+		{"nullable integer", `{"type": ["integer", "null"]}`, "number"},
+		{"integer or number", `{"type": ["integer", "number"]}`, "number"},
+		{"enum", `{"enum": ["a", "b"]}`, "string"},
+		{"mixed types", `{"type": ["string", "boolean"]}`, "any"},
+		{"true", `true`, "any"},
+		{"list", `{"type": "array", "items": {"type": "boolean"}}`, "list(bool)"},
+		{"set", `{"type": "array", "items": {"type": "string"}, "uniqueItems": true}`, "set(string)"},
+		{"tuple", `{"type": "array", "prefixItems": [{"type": "string"}, {"type": "number"}]}`, "tuple([string, number])"},
+		{"draft-07 tuple", `{"$schema": "http://json-schema.org/draft-07/schema#", "items": [{"type": "string"}]}`, "tuple([string])"},
+		{"map", `{"type": "object", "additionalProperties": {"type": "number"}}`, "map(number)"},
+		{"pattern map", `{"type": "object", "patternProperties": {"^x-": {"type": "string"}}}`, "map(string)"},
+		{"object", `{
+  "type": "object",
+  "required": ["name"],
+  "properties": {
+    "name": {"type": "string"},
+    "replicas": {"type": "integer", "default": 1},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}, "default": {"app.kubernetes.io/name": "${name}"}},
+    "ports": {"type": "array", "items": {"type": "integer"}, "default": [80, 443]}
+  }
+}`, `object({ labels = optional(map(string), { "app.kubernetes.io/name" = "$${name}" }), name = string, ports = optional(list(number), [80, 443]), replicas = optional(number, 1) })`},
+		{"reference", `{"$ref": "#/$defs/name", "$defs": {"name": {"type": "string", "default": "app"}}}`, "string"},
+		{"any of same types", `{"anyOf": [{"type": "string", "format": "email"}, {"type": "string", "format": "uri"}]}`, "string"},
+		{"one of different types", `{"oneOf": [{"type": "string"}, {"type": "number"}]}`, "any"},
+		{"all of objects", `{"allOf": [
+  {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]},
+  {"$ref": "#/$defs/port"}
+], "$defs": {"port": {"properties": {"port": {"type": "integer"}}}}}`, "object({ name = string, port = optional(number) })"},
+		{"recursive", `{"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#"}}}}`, "object({ children = optional(list(any)) })"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaPath := filepath.Join(t.TempDir(), "schema.json")
+			require.NoError(t, os.WriteFile(schemaPath, []byte(tt.schema), 0644))
+
+			sch, err := newSchemaCompiler(nil, nil).Compile(schemaPath)
+			require.NoError(t, err)
+
+			typeConstraint, err := terraformType(sch, make(map[*jsonschema.Schema]bool))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, typeConstraint)
+		})
+	}
+}
+
+func TestTerraformTypeInvalidAttributeName(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{"properties": {"my key": {"type": "string"}}}`), 0644))
+
+	sch, err := newSchemaCompiler(nil, nil).Compile(schemaPath)
+	require.NoError(t, err)
+
+	_, err = terraformType(sch, make(map[*jsonschema.Schema]bool))
+	require.ErrorContains(t, err, `property "my key" is not a valid attribute name`)
+}
+
+func TestTerraformTypeDataSource(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	err := os.WriteFile(schemaPath, []byte(`{
+  "$defs": {
+    "app": {
+      "description": "Application deployed by the module",
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "replicas": {"type": "integer", "default": 1}
+      }
+    }
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccTerraformTypeDataSourceConfig, schemaPath+"#/$defs/app"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_terraform_type.app",
+						tfjsonpath.New("type"),
+						knownvalue.StringExact("object({ name = string, replicas = optional(number, 1) })"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_terraform_type.app",
+						tfjsonpath.New("description"),
+						knownvalue.StringExact("Application deployed by the module"),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccTerraformTypeDataSourceConfig = `
+data "jsonschema_terraform_type" "app" {
+  schema = "%s"
+}
+`
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// traceEntry is the evaluation of a condition or a branch of a schema by a
+// value of a document.
+type traceEntry struct {
+	Schema   string `json:"schema"`
+	Document int    `json:"document"`
+	Pointer  string `json:"pointer"`
+	// Keyword is if, then, else, anyOf or oneOf.
+	Keyword string `json:"keyword"`
+	Branch  string `json:"branch"`
+	Valid   bool   `json:"valid"`
+	// Errors are the violations of the subschema, empty if it is valid.
+	Errors []string `json:"errors"`
+}
+
+// conditionTrace returns the evaluation of the if, then and else subschemas
+// and of the anyOf and oneOf branches that apply to the values of a
+// document, so failures of heavily conditional schemas can be followed.
+// The subschema of then or else that applies is evaluated after the if.
+func conditionTrace(schemaPath string, document int, sch *jsonschema.Schema, value any) []traceEntry {
+	var entries []traceEntry
+
+	walkSchema(sch, value, func(pointer string, value any, schemas []*jsonschema.Schema) {
+		evaluate := func(keyword string, branch *jsonschema.Schema) bool {
+			err := branch.Validate(value)
+
+			violations := make([]string, 0)
+			if err != nil {
+				for _, finding := range validationFindings("", document, err) {
+					violations = append(violations, "'"+pointer+finding.Pointer+"': "+finding.Message)
+				}
+			}
+
+			entries = append(entries, traceEntry{
+				Schema:   schemaPath,
+				Document: document,
+				Pointer:  pointer,
+				Keyword:  keyword,
+				Branch:   branchName(branch),
+				Valid:    err == nil,
+				Errors:   violations,
+			})
+
+			return err == nil
+		}
+
+		for _, s := range schemas {
+			if s.If != nil {
+				if evaluate("if", s.If) {
+					if s.Then != nil {
+						evaluate("then", s.Then)
+					}
+				} else if s.Else != nil {
+					evaluate("else", s.Else)
+				}
+			}
+			for _, branch := range s.AnyOf {
+				evaluate("anyOf", branch)
+			}
+			for _, branch := range s.OneOf {
+				evaluate("oneOf", branch)
+			}
+		}
+	})
+
+	return entries
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const testTraceSchema = `{
+  "type": "object",
+  "properties": {
+    "database": {
+      "if": { "properties": { "engine": { "const": "postgres" } } },
+      "then": { "required": ["port"] },
+      "else": { "required": ["socket"] }
+    },
+    "size": {
+      "oneOf": [{ "title": "small", "enum": ["s", "m"] }, { "type": "integer", "minimum": 1 }]
+    }
+  }
+}`
+
+func TestConditionTrace(t *testing.T) {
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(testTraceSchema))
+	require.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource("schema.json", schema))
+	sch, err := compiler.Compile("schema.json")
+	require.NoError(t, err)
+
+	entries := conditionTrace("schema.json", 0, sch, map[string]any{
+		"database": map[string]any{"engine": "mysql"},
+		"size":     "xl",
+	})
+
+	require.Equal(t, []traceEntry{
+		{Schema: "schema.json", Pointer: "/database", Keyword: "if", Branch: "#/properties/database/if", Errors: []string{"'/database/engine': value must be 'postgres'"}},
+		{Schema: "schema.json", Pointer: "/database", Keyword: "else", Branch: "#/properties/database/else", Errors: []string{"'/database': missing property 'socket'"}},
+		{Schema: "schema.json", Pointer: "/size", Keyword: "oneOf", Branch: "small", Errors: []string{"'/size': value must be one of 's', 'm'"}},
+		{Schema: "schema.json", Pointer: "/size", Keyword: "oneOf", Branch: "#/properties/size/oneOf/1", Errors: []string{"'/size': got string, want integer"}},
+	}, entries)
+}
+
+func TestTraceYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"schema.json":  testTraceSchema,
+		"valid.yaml":   "# yaml-language-server: $schema=./schema.json\ndatabase:\n  engine: postgres\n  port: 5432\n",
+		"invalid.yaml": "# yaml-language-server: $schema=./schema.json\ndatabase:\n  engine: postgres\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "schema.json"))
+	invalid := filepath.Join(tmpDir, "invalid.yaml")
+
+	expected, err := json.Marshal([]traceEntry{
+		{Schema: schemaPath, Pointer: "/database", Keyword: "if", Branch: "#/properties/database/if", Valid: true, Errors: []string{}},
+		{Schema: schemaPath, Pointer: "/database", Keyword: "then", Branch: "#/properties/database/then", Errors: []string{"'/database': missing property 'port'"}},
+	})
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccTraceConfig, filepath.Join(tmpDir, "*.yaml"), "["),
+				ExpectError: regexp.MustCompile(`Invalid trace_file_glob`),
+			},
+			{
+				Config: fmt.Sprintf(testAccTraceConfig, filepath.Join(tmpDir, "*.yaml"), "invalid.yaml"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("trace"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							invalid: knownvalue.StringExact(string(expected)),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccTraceConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern   = "%s"
+  fail_on_invalid = false
+  trace_file_glob = "%s"
+}
+`
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the spans of the provider.
+const tracerName = "github.com/gaarutyunov/terraform-provider-jsonschema"
+
+// TracingConfigModel describes the tracing block of the provider data model.
+type TracingConfigModel struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Insecure types.Bool   `tfsdk:"insecure"`
+	Headers  types.Map    `tfsdk:"headers"`
+}
+
+// tracing emits spans of the validation phases to an OTLP endpoint, without
+// an endpoint spans are discarded.
+type tracing struct {
+	tracer   trace.Tracer
+	provider *sdktrace.TracerProvider
+}
+
+// newTracing creates the tracing of the provider, headers are the decoded
+// headers of config.
+func newTracing(ctx context.Context, config *TracingConfigModel, headers map[string]string, version string) (*tracing, error) {
+	if config == nil {
+		return &tracing{tracer: noop.NewTracerProvider().Tracer(tracerName)}, nil
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(config.Endpoint.ValueString()),
+	}
+
+	if config.Insecure.ValueBool() {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	if len(headers) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(headers))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName("terraform-provider-jsonschema"),
+			semconv.ServiceVersion(version),
+		)),
+	)
+
+	return &tracing{tracer: provider.Tracer(tracerName), provider: provider}, nil
+}
+
+// start starts a span, end it with endSpan.
+func (t *tracing) start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// flush exports the spans ended so far, the provider process may be stopped
+// at any time after a read.
+func (t *tracing) flush(ctx context.Context) {
+	if t.provider == nil {
+		return
+	}
+
+	if err := t.provider.ForceFlush(ctx); err != nil {
+		tflog.Warn(ctx, "Could not export spans", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// endSpan ends span, recording err if not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// diagnosticsError returns the summary of the first error of diags as an
+// error to record on a span, or nil if there is none.
+func diagnosticsError(diags diag.Diagnostics) error {
+	errs := diags.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errors.New(errs[0].Summary())
+}
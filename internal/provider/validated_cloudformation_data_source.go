@@ -0,0 +1,331 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"strings"
+)
+
+// cloudFormationFunctions are the intrinsic functions and condition
+// functions with a short form, e.g. !Sub for Fn::Sub.
+var cloudFormationFunctions = []string{
+	"Base64", "Cidr", "FindInMap", "GetAtt", "GetAZs", "ImportValue", "Join", "Length", "Select", "Split", "Sub",
+	"ToJsonString", "Transform", "And", "Equals", "If", "Not", "Or",
+}
+
+// cloudFormationTags resolve the short forms of intrinsic functions to
+// their long forms, e.g. !Ref Bucket to {"Ref": "Bucket"}.
+var cloudFormationTags = cloudFormationTagResolvers()
+
+func cloudFormationTagResolvers() map[string]yamlTagResolver {
+	tags := map[string]yamlTagResolver{
+		"!Ref":       func(_ *yaml.Node, value any) (any, error) { return map[string]any{"Ref": value}, nil },
+		"!Condition": func(_ *yaml.Node, value any) (any, error) { return map[string]any{"Condition": value}, nil },
+	}
+
+	for _, name := range cloudFormationFunctions {
+		tags["!"+name] = func(_ *yaml.Node, value any) (any, error) { return map[string]any{"Fn::" + name: value}, nil }
+	}
+
+	// the short form of Fn::GetAtt is the logical name and the attribute separated by a dot
+	tags["!GetAtt"] = func(_ *yaml.Node, value any) (any, error) {
+		if s, ok := value.(string); ok {
+			resource, attribute, ok := strings.Cut(s, ".")
+			if !ok {
+				return nil, fmt.Errorf("%q is not of the form resource.attribute", s)
+			}
+			value = []any{resource, attribute}
+		}
+		return map[string]any{"Fn::GetAtt": value}, nil
+	}
+
+	return tags
+}
+
+func NewValidatedCloudFormationDataSource() datasource.DataSource {
+	return &ValidatedCloudFormationDataSource{}
+}
+
+// ValidatedCloudFormationDataSource defines the data source implementation.
+type ValidatedCloudFormationDataSource struct {
+	compiler    *schemaCompiler
+	yamlDecoder yamlDecoder
+}
+
+// ValidatedCloudFormationDataSourceModel describes the data source data model.
+type ValidatedCloudFormationDataSourceModel struct {
+	Input           types.String `tfsdk:"input"`
+	Schema          types.String `tfsdk:"schema"`
+	ResourceSchemas types.Map    `tfsdk:"resource_schemas"`
+	Resources       types.List   `tfsdk:"resources"`
+}
+
+func (d *ValidatedCloudFormationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_cloudformation"
+}
+
+func (d *ValidatedCloudFormationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "A CloudFormation template in YAML or JSON validated against json schemas, e.g. the resource provider schemas of the CloudFormation registry. " +
+			"The short forms of intrinsic functions like `!Ref`, `!Sub` or `!GetAtt` are decoded as their long forms like `{\"Ref\": ...}`. " +
+			"Resource properties are validated as they are deployed, the values of intrinsic functions are unknown until then, " +
+			"so violations of properties whose values are intrinsic functions are tolerated.",
+
+		Attributes: map[string]schema.Attribute{
+			"input": schema.StringAttribute{
+				Description: "Path of the template",
+				Required:    true,
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path of the json schema the whole template is validated against",
+				Optional:    true,
+			},
+			"resource_schemas": schema.MapAttribute{
+				MarkdownDescription: "Map of resource types, e.g. `AWS::S3::Bucket`, to the path of the json schema the `Properties` of every resource of the type are validated against, " +
+					"e.g. the schema returned by `aws cloudformation describe-type`",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"resources": schema.ListAttribute{
+				MarkdownDescription: "Logical IDs of the resources validated against `resource_schemas` in order",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ValidatedCloudFormationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+	d.yamlDecoder = providerData.YAMLDecoder
+}
+
+func (d *ValidatedCloudFormationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedCloudFormationDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Schema.IsNull() && data.ResourceSchemas.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Missing schema",
+			"At least one of schema and resource_schemas must be set",
+		)
+		return
+	}
+
+	resourceSchemas := make(map[string]string)
+	if !data.ResourceSchemas.IsNull() {
+		resp.Diagnostics.Append(data.ResourceSchemas.ElementsAs(ctx, &resourceSchemas, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	file := data.Input.ValueString()
+
+	content, err := readTextFile(file, decodeText)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("input"),
+			"Error reading file",
+			"Could not read file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	// JSON templates are YAML too
+	decoder := d.yamlDecoder
+	decoder.tags = cloudFormationTags
+
+	document, err := decoder.decode(content)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("input"),
+			"Error decoding template",
+			"Could not decode template "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	template, ok := document.(map[string]any)
+	if !ok || template["Resources"] == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("input"),
+			"Invalid CloudFormation template",
+			"File "+file+" is not a CloudFormation template, which has Resources",
+		)
+		return
+	}
+
+	if !data.Schema.IsNull() {
+		schemaPath := data.Schema.ValueString()
+
+		compiledSchema, err := d.compiler.Compile(schemaPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schema"),
+				"Error compiling schema",
+				"Could not compile schema "+schemaPath+": "+err.Error(),
+			)
+			return
+		}
+
+		if err := compiledSchema.Validate(document); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input"),
+				"Error validating template",
+				"Template "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
+			)
+		}
+	}
+
+	validated := make([]string, 0)
+	if len(resourceSchemas) > 0 {
+		resources, _ := template["Resources"].(map[string]any)
+
+		logicalIDs := make([]string, 0, len(resources))
+		for logicalID := range resources {
+			logicalIDs = append(logicalIDs, logicalID)
+		}
+		slices.Sort(logicalIDs)
+
+		for _, logicalID := range logicalIDs {
+			resource, _ := resources[logicalID].(map[string]any)
+			resourceType, _ := resource["Type"].(string)
+
+			schemaPath, ok := resourceSchemas[resourceType]
+			if !ok {
+				continue
+			}
+
+			compiledSchema, err := d.compiler.Compile(schemaPath)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("resource_schemas").AtMapKey(resourceType),
+					"Error compiling schema",
+					"Could not compile schema "+schemaPath+": "+err.Error(),
+				)
+				// every resource of the type fails the same way
+				delete(resourceSchemas, resourceType)
+				continue
+			}
+
+			validated = append(validated, logicalID)
+
+			properties, ok := resource["Properties"]
+			if !ok {
+				properties = map[string]any{}
+			}
+
+			err = compiledSchema.Validate(properties)
+			if err == nil {
+				continue
+			}
+
+			intrinsics := intrinsicPointers(properties, "")
+
+			var violations []string
+			for _, finding := range validationFindings(file, 0, err) {
+				if !slices.ContainsFunc(intrinsics, func(pointer string) bool {
+					return finding.Pointer == pointer || strings.HasPrefix(finding.Pointer, pointer+"/")
+				}) {
+					violations = append(violations, finding.Pointer+": "+finding.Message)
+				}
+			}
+
+			if len(violations) > 0 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("resource_schemas").AtMapKey(resourceType),
+					"Error validating resource",
+					"Properties of resource "+logicalID+" of template "+file+" do not conform to schema "+schemaPath+":\n"+strings.Join(violations, "\n"),
+				)
+			}
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resources, diags := types.ListValueFrom(ctx, types.StringType, validated)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Resources = resources
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// isIntrinsic reports whether value is an intrinsic function in long form,
+// an object with Ref, Condition or a Fn:: function as its only key.
+func isIntrinsic(value any) bool {
+	object, ok := value.(map[string]any)
+	if !ok || len(object) != 1 {
+		return false
+	}
+
+	for key := range object {
+		return key == "Ref" || key == "Condition" || strings.HasPrefix(key, "Fn::")
+	}
+
+	return false
+}
+
+// intrinsicPointers returns the JSON pointers of the intrinsic functions of
+// value, which is at pointer, without those nested in other intrinsics.
+func intrinsicPointers(value any, pointer string) []string {
+	if isIntrinsic(value) {
+		return []string{pointer}
+	}
+
+	var pointers []string
+
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			pointers = append(pointers, intrinsicPointers(item, pointer+"/"+escapePointerToken(key))...)
+		}
+	case []any:
+		for i, item := range value {
+			pointers = append(pointers, intrinsicPointers(item, fmt.Sprintf("%s/%d", pointer, i))...)
+		}
+	}
+
+	return pointers
+}
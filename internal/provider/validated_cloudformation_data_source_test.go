@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidatedCloudFormation(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"template.yaml": testAccValidatedCloudFormationTemplate,
+		"invalid.yaml":  "Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n    Properties:\n      BucketName: 42\n",
+		"other.yaml":    "services: {}\n",
+		"bucket.json":   `{"typeName": "AWS::S3::Bucket", "type": "object", "additionalProperties": false, "properties": {"BucketName": {"type": "string"}, "Tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}}}, "definitions": {"Tag": {"type": "object", "required": ["Key", "Value"], "properties": {"Key": {"type": "string"}, "Value": {"type": "string"}}}}}`,
+		"template.json": `{"type": "object", "required": ["AWSTemplateFormatVersion"]}`,
+		"outputs.json":  `{"properties": {"Outputs": {"additionalProperties": {"properties": {"Value": {"type": "object"}}}}}}`,
+		"policy.json":   `{"required": ["Bucket"], "properties": {"Bucket": {"type": "string"}}}`,
+	} {
+		err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the intrinsic functions of the bucket name and the tag value are tolerated
+			{
+				Config: fmt.Sprintf(testAccValidatedCloudFormationConfig, filepath.Join(tmpDir, "template.yaml"), filepath.Join(tmpDir, "outputs.json"), `"AWS::S3::Bucket"`, filepath.Join(tmpDir, "bucket.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_cloudformation.test",
+						tfjsonpath.New("resources"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("Bucket"),
+							knownvalue.StringExact("Logs"),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedCloudFormationConfig, filepath.Join(tmpDir, "invalid.yaml"), filepath.Join(tmpDir, "outputs.json"), `"AWS::S3::Bucket"`, filepath.Join(tmpDir, "bucket.json")),
+				ExpectError: regexp.MustCompile(`(?s)Properties\s+of\s+resource\s+Bucket.*/BucketName:\s+got\s+number,\s+want\s+string`),
+			},
+			// intrinsics do not excuse violations of the properties they are not part of
+			{
+				Config:      fmt.Sprintf(testAccValidatedCloudFormationConfig, filepath.Join(tmpDir, "template.yaml"), filepath.Join(tmpDir, "outputs.json"), `"AWS::SQS::Queue"`, filepath.Join(tmpDir, "policy.json")),
+				ExpectError: regexp.MustCompile(`(?s)Properties\s+of\s+resource\s+Queue.*missing\s+property\s+'Bucket'`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedCloudFormationConfig, filepath.Join(tmpDir, "invalid.yaml"), filepath.Join(tmpDir, "template.json"), `"AWS::SQS::Queue"`, filepath.Join(tmpDir, "policy.json")),
+				ExpectError: regexp.MustCompile(`missing\s+property\s+'AWSTemplateFormatVersion'`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedCloudFormationConfig, filepath.Join(tmpDir, "other.yaml"), filepath.Join(tmpDir, "outputs.json"), `"AWS::S3::Bucket"`, filepath.Join(tmpDir, "bucket.json")),
+				ExpectError: regexp.MustCompile(`Invalid CloudFormation template`),
+			},
+			{
+				Config:      fmt.Sprintf(`data "jsonschema_validated_cloudformation" "test" { input = "%s" }`, filepath.Join(tmpDir, "template.yaml")),
+				ExpectError: regexp.MustCompile(`Missing schema`),
+			},
+		},
+	})
+}
+
+const (
+	testAccValidatedCloudFormationConfig = `
+data "jsonschema_validated_cloudformation" "test" {
+  input  = "%s"
+  schema = "%s"
+
+  resource_schemas = {
+    %s = "%s"
+  }
+}
+`
+	testAccValidatedCloudFormationTemplate = `AWSTemplateFormatVersion: "2010-09-09"
+Parameters:
+  Environment:
+    Type: String
+Resources:
+  Logs:
+    Type: AWS::S3::Bucket
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub "app-${Environment}"
+      Tags:
+        - Key: environment
+          Value: !Ref Environment
+  Queue:
+    Type: AWS::SQS::Queue
+    Properties:
+      QueueName: !Join ["-", [!Ref Environment, queue]]
+Outputs:
+  Arn:
+    Value: !GetAtt Bucket.Arn
+`
+)
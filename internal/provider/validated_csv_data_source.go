@@ -0,0 +1,344 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// csvCoercionNone keeps every cell as a string.
+	csvCoercionNone = "none"
+	// csvCoercionSchema converts cells to the types declared in the row schema.
+	csvCoercionSchema = "schema"
+	// csvCoercionInfer converts cells that are JSON numbers, true or false.
+	csvCoercionInfer = "infer"
+)
+
+func NewValidatedCSVDataSource() datasource.DataSource {
+	return &ValidatedCSVDataSource{}
+}
+
+// ValidatedCSVDataSource defines the data source implementation.
+type ValidatedCSVDataSource struct {
+	compiler *schemaCompiler
+}
+
+// ValidatedCSVDataSourceModel describes the data source data model.
+type ValidatedCSVDataSourceModel struct {
+	InputPattern types.String `tfsdk:"input_pattern"`
+	Encoding     types.String `tfsdk:"encoding"`
+	Schema       types.String `tfsdk:"schema"`
+	Delimiter    types.String `tfsdk:"delimiter"`
+	Coercion     types.String `tfsdk:"coercion"`
+	Rows         types.Map    `tfsdk:"rows"`
+}
+
+func (d *ValidatedCSVDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_csv"
+}
+
+func (d *ValidatedCSVDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "CSV files, of which every row is validated against a json schema. " +
+			"The first line of each file is the header, its column names become the property names of the row objects and must be unique.",
+
+		Attributes: map[string]schema.Attribute{
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of CSV files to validate",
+				Required:    true,
+			},
+			"encoding": encodingAttribute(),
+			"schema": schema.StringAttribute{
+				Description: "Path of the json schema every row is validated against",
+				Required:    true,
+			},
+			"delimiter": schema.StringAttribute{
+				Description: "Field delimiter, defaults to ','",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 1),
+				},
+			},
+			"coercion": schema.StringAttribute{
+				MarkdownDescription: "How cells are converted before validation: `schema` (default) uses the types declared for the " +
+					"properties of the row schema, `infer` converts cells that are JSON numbers or `true` and `false` and `none` keeps all cells as strings. " +
+					"Empty cells are omitted from the row unless they are kept as strings.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(csvCoercionNone, csvCoercionSchema, csvCoercionInfer),
+				},
+			},
+			"rows": schema.MapAttribute{
+				Description: "Map of file paths to JSON encoded lists of validated row objects",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ValidatedCSVDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *ValidatedCSVDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedCSVDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	delimiter := ','
+	if !data.Delimiter.IsNull() {
+		delimiter = []rune(data.Delimiter.ValueString())[0]
+	}
+
+	coercion := csvCoercionSchema
+	if !data.Coercion.IsNull() {
+		coercion = data.Coercion.ValueString()
+	}
+
+	rowSchemas := expandSchema(compiledSchema)
+
+	decode, err := textDecoder(data.Encoding.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("encoding"),
+			"Invalid encoding",
+			err.Error(),
+		)
+		return
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rowsMap := make(map[string]string)
+	for _, file := range files {
+		func() {
+			contentRaw, err := readTextFile(file, decode)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("input_pattern"),
+					"Error reading file",
+					"Could not read file "+file+": "+err.Error(),
+				)
+				return
+			}
+
+			reader := csv.NewReader(bytes.NewReader(contentRaw))
+			reader.Comma = delimiter
+
+			header, err := reader.Read()
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("input_pattern"),
+					"Error decoding CSV",
+					"Could not read header of CSV file "+file+": "+err.Error(),
+				)
+				return
+			}
+
+			// columns are the keys of the rows, a later column would replace an earlier one
+			for i, column := range header {
+				if slices.Contains(header[:i], column) {
+					resp.Diagnostics.AddAttributeError(
+						path.Root("input_pattern"),
+						"Duplicate column",
+						"Header of CSV file "+file+" has more than one column named "+column,
+					)
+					return
+				}
+			}
+
+			rows := make([]any, 0)
+			for {
+				record, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(
+						path.Root("input_pattern"),
+						"Error decoding CSV",
+						"Could not decode CSV file "+file+": "+err.Error(),
+					)
+					return
+				}
+
+				line, _ := reader.FieldPos(0)
+
+				row := make(map[string]any, len(header))
+				for i, column := range header {
+					value, ok := coerceCSVValue(record[i], propertySchema(rowSchemas, column), coercion)
+					if ok {
+						row[column] = value
+					}
+				}
+
+				err = compiledSchema.Validate(row)
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(
+						path.Root("input_pattern"),
+						"Error validating CSV",
+						"Line "+strconv.Itoa(line)+" of CSV file "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
+					)
+					continue
+				}
+
+				rows = append(rows, row)
+			}
+
+			encoded, err := json.Marshal(rows)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error encoding rows",
+					"Could not encode rows of CSV file "+file+": "+err.Error(),
+				)
+				return
+			}
+
+			rowsMap[file] = string(encoded)
+		}()
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rows, diag := types.MapValueFrom(ctx, types.StringType, rowsMap)
+	resp.Diagnostics.Append(diag...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Rows = rows
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// coerceCSVValue converts a cell to the value validated against the
+// property schema sch, ok is false if the cell has to be omitted.
+func coerceCSVValue(value string, sch *jsonschema.Schema, coercion string) (any, bool) {
+	switch coercion {
+	case csvCoercionNone:
+		return value, true
+	case csvCoercionInfer:
+		if value == "" {
+			return nil, false
+		}
+		// numbers first, e.g. 1 and 0 of an ID column are not booleans
+		if jsonNumberRegex.MatchString(value) {
+			return json.Number(value), true
+		}
+		if b, ok := csvBool(value); ok {
+			return b, true
+		}
+		return value, true
+	}
+
+	var declared []string
+	for _, s := range expandSchema(sch) {
+		if s.Types != nil {
+			declared = append(declared, s.Types.ToStrings()...)
+		}
+	}
+
+	if len(declared) == 0 {
+		return value, true
+	}
+
+	if value == "" {
+		if slices.Contains(declared, "string") {
+			return value, true
+		}
+		if slices.Contains(declared, "null") {
+			return nil, true
+		}
+		return nil, false
+	}
+
+	// only numbers in JSON syntax, NaN, Inf or +5 could not be encoded
+	if slices.Contains(declared, "integer") && jsonNumberRegex.MatchString(value) && !strings.ContainsAny(value, ".eE") {
+		return json.Number(value), true
+	}
+
+	if slices.Contains(declared, "number") && jsonNumberRegex.MatchString(value) {
+		return json.Number(value), true
+	}
+
+	if slices.Contains(declared, "boolean") {
+		if b, ok := csvBool(value); ok {
+			return b, true
+		}
+	}
+
+	// cells that cannot be converted are reported by the validation
+	return value, true
+}
+
+// csvBool returns the boolean of the cells true and false, other spellings
+// like 1 or T are not booleans.
+func csvBool(value string) (bool, bool) {
+	switch value {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+
+	return false, false
+}
@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidCSV(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "regions.csv"), []byte(`code,name,replicas,active,comment
+eu-west,Europe West,3,true,
+us-east,US East,1,false,primary
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedCSVDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedCSVDataSourceConfig, filepath.Join(tmpDir, "*.csv"), schemaPath),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_csv.regions",
+						tfjsonpath.New("rows").AtMapKey(filepath.Join(tmpDir, "regions.csv")),
+						knownvalue.StringExact(`[{"active":true,"code":"eu-west","comment":"","name":"Europe West","replicas":3},{"active":false,"code":"us-east","comment":"primary","name":"US East","replicas":1}]`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestInvalidCSV(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "regions.csv"), []byte(`code,name,replicas,active,comment
+eu-west,Europe West,three,true,
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedCSVDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config:      fmt.Sprintf(testAccValidatedCSVDataSourceConfig, filepath.Join(tmpDir, "*.csv"), schemaPath),
+				ExpectError: regexp.MustCompile(`- at '/replicas': got string, want integer`),
+			},
+		},
+	})
+}
+
+func TestCSVWithoutCoercion(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "regions.csv"), []byte(`code;name
+eu-west;Europe West
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedCSVDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedCSVDataSourceNoCoercionConfig, filepath.Join(tmpDir, "*.csv"), schemaPath),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_csv.regions",
+						tfjsonpath.New("rows").AtMapKey(filepath.Join(tmpDir, "regions.csv")),
+						knownvalue.StringExact(`[{"code":"eu-west","name":"Europe West"}]`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestCSVDuplicateColumn(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "regions.csv"), []byte(`code,name,name
+eu-west,Europe West,3
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedCSVDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedCSVDataSourceConfig, filepath.Join(tmpDir, "*.csv"), schemaPath),
+				ExpectError: regexp.MustCompile(`more\s+than\s+one\s+column\s+named\s+name`),
+			},
+		},
+	})
+}
+
+func TestCoerceCSVValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		value    string
+		expected any
+	}{
+		{"infer one", "", "1", json.Number("1")},
+		{"infer zero", "", "0", json.Number("0")},
+		{"infer true", "", "true", true},
+		{"infer t", "", "t", "t"},
+		{"infer F", "", "F", "F"},
+		{"infer NaN", "", "NaN", "NaN"},
+		{"infer Inf", "", "Inf", "Inf"},
+		{"infer hex float", "", "0x1p-2", "0x1p-2"},
+		{"infer plus", "", "+1", "+1"},
+		{"infer leading dot", "", ".5", ".5"},
+		{"infer trailing dot", "", "1.", "1."},
+		{"infer exponent", "", "1.5e3", json.Number("1.5e3")},
+		{"integer", `{"type": ["integer", "string"]}`, "5", json.Number("5")},
+		{"integer plus", `{"type": ["integer", "string"]}`, "+5", "+5"},
+		{"integer fraction", `{"type": ["integer", "string"]}`, "1.5", "1.5"},
+		{"number NaN", `{"type": ["number", "string"]}`, "NaN", "NaN"},
+		{"number Inf", `{"type": ["number", "string"]}`, "-Inf", "-Inf"},
+		{"number", `{"type": "number"}`, "-0.25", json.Number("-0.25")},
+		{"boolean", `{"type": ["boolean", "integer"]}`, "false", false},
+		{"boolean one", `{"type": ["boolean", "string"]}`, "1", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coercion := csvCoercionInfer
+			var sch *jsonschema.Schema
+			if tt.schema != "" {
+				coercion = csvCoercionSchema
+
+				schemaPath := filepath.Join(t.TempDir(), "schema.json")
+				require.NoError(t, os.WriteFile(schemaPath, []byte(tt.schema), 0644))
+
+				var err error
+				sch, err = newSchemaCompiler(nil, nil).Compile(schemaPath)
+				require.NoError(t, err)
+			}
+
+			value, ok := coerceCSVValue(tt.value, sch, coercion)
+			require.True(t, ok)
+			require.Equal(t, tt.expected, value)
+
+			// every coerced value can be encoded with the rows
+			_, err := json.Marshal(value)
+			require.NoError(t, err)
+		})
+	}
+}
+
+const (
+	testAccValidatedCSVDataSourceConfig = `
+data "jsonschema_validated_csv" "regions" {
+  input_pattern = "%s"
+  schema        = "%s"
+}
+`
+	testAccValidatedCSVDataSourceNoCoercionConfig = `
+data "jsonschema_validated_csv" "regions" {
+  input_pattern = "%s"
+  schema        = "%s"
+  delimiter     = ";"
+  coercion      = "none"
+}
+`
+	testAccValidatedCSVDataSourceSchema = `
+{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Region",
+  "type": "object",
+  "properties": {
+	"code": {
+	  "type": "string"
+	},
+	"name": {
+	  "type": "string"
+	},
+	"replicas": {
+	  "type": "integer"
+	},
+	"active": {
+	  "type": "boolean"
+	},
+	"comment": {
+	  "type": "string"
+	}
+  },
+  "required": ["code", "name"]
+}
+`
+)
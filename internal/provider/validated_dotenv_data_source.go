@@ -0,0 +1,263 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"regexp"
+	"strings"
+)
+
+var dotenvKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+func NewValidatedDotenvDataSource() datasource.DataSource {
+	return &ValidatedDotenvDataSource{}
+}
+
+// ValidatedDotenvDataSource defines the data source implementation.
+type ValidatedDotenvDataSource struct {
+	compiler *schemaCompiler
+}
+
+// ValidatedDotenvDataSourceModel describes the data source data model.
+type ValidatedDotenvDataSourceModel struct {
+	InputPattern types.String `tfsdk:"input_pattern"`
+	Encoding     types.String `tfsdk:"encoding"`
+	Schema       types.String `tfsdk:"schema"`
+	Variables    types.Map    `tfsdk:"variables"`
+}
+
+func (d *ValidatedDotenvDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_dotenv"
+}
+
+func (d *ValidatedDotenvDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "dotenv (`.env`) files validated against a json schema. " +
+			"Each file is parsed into an object of string values keyed by variable name.",
+
+		Attributes: map[string]schema.Attribute{
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of dotenv files to validate",
+				Required:    true,
+			},
+			"encoding": encodingAttribute(),
+			"schema": schema.StringAttribute{
+				Description: "Path of the json schema the variables of every file are validated against",
+				Required:    true,
+			},
+			"variables": schema.MapAttribute{
+				Description: "Map of file paths to the validated variables of the file",
+				Computed:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+			},
+		},
+	}
+}
+
+func (d *ValidatedDotenvDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *ValidatedDotenvDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedDotenvDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	decode, err := textDecoder(data.Encoding.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("encoding"),
+			"Invalid encoding",
+			err.Error(),
+		)
+		return
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variablesMap := make(map[string]map[string]string)
+	for _, file := range files {
+		contentRaw, err := readTextFile(file, decode)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error reading file",
+				"Could not read file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		variables, err := parseDotenv(string(contentRaw))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error decoding dotenv",
+				"Could not decode dotenv file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		value := make(map[string]any, len(variables))
+		for k, v := range variables {
+			value[k] = v
+		}
+
+		err = compiledSchema.Validate(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error validating dotenv",
+				"dotenv file "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
+			)
+			continue
+		}
+
+		variablesMap[file] = variables
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variables, diag := types.MapValueFrom(ctx, types.MapType{ElemType: types.StringType}, variablesMap)
+	resp.Diagnostics.Append(diag...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Variables = variables
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseDotenv decodes the KEY=value assignments of a dotenv file. Lines may
+// start with 'export ', values may be single quoted (taken literally) or
+// double quoted (with escape sequences and spanning multiple lines), and
+// unquoted values end at a ' #' comment.
+func parseDotenv(content string) (map[string]string, error) {
+	variables := make(map[string]string)
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value, got %q", lineNumber, line)
+		}
+
+		key = strings.TrimSpace(key)
+		if !dotenvKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNumber, key)
+		}
+
+		value = strings.TrimLeft(value, " \t")
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quoted value of %s", lineNumber, key)
+			}
+			value = value[1 : end+1]
+		case strings.HasPrefix(value, `"`):
+			var sb strings.Builder
+			rest := value[1:]
+			closed := false
+			for !closed {
+				for j := 0; j < len(rest); j++ {
+					c := rest[j]
+					if c == '\\' && j+1 < len(rest) {
+						j++
+						switch rest[j] {
+						case 'n':
+							sb.WriteByte('\n')
+						case 't':
+							sb.WriteByte('\t')
+						default:
+							sb.WriteByte(rest[j])
+						}
+						continue
+					}
+					if c == '"' {
+						closed = true
+						break
+					}
+					sb.WriteByte(c)
+				}
+				if !closed {
+					i++
+					if i >= len(lines) {
+						return nil, fmt.Errorf("line %d: unterminated double quoted value of %s", lineNumber, key)
+					}
+					sb.WriteByte('\n')
+					rest = lines[i]
+				}
+			}
+			value = sb.String()
+		default:
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = value[:idx]
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		variables[key] = value
+	}
+
+	return variables, nil
+}
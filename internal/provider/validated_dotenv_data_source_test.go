@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidDotenv(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "app.env"), []byte(`# application settings
+export DATABASE_URL="postgres://db:5432/app"
+PORT=8080 # http port
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedDotenvDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedDotenvDataSourceConfig, filepath.Join(tmpDir, "*.env"), schemaPath),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_dotenv.app",
+						tfjsonpath.New("variables").AtMapKey(filepath.Join(tmpDir, "app.env")),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"DATABASE_URL": knownvalue.StringExact("postgres://db:5432/app"),
+							"PORT":         knownvalue.StringExact("8080"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestInvalidDotenv(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "app.env"), []byte("PORT=http\n"), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedDotenvDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config:      fmt.Sprintf(testAccValidatedDotenvDataSourceConfig, filepath.Join(tmpDir, "*.env"), schemaPath),
+				ExpectError: regexp.MustCompile(`missing property 'DATABASE_URL'`),
+			},
+		},
+	})
+}
+
+func TestParseDotenv(t *testing.T) {
+	variables, err := parseDotenv(`
+# comment
+A=plain value # trailing comment
+export B='literal \n $HOME'
+C="line1\nline2 \"quoted\""
+D="multi
+line"
+E=
+`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"A": "plain value",
+		"B": `literal \n $HOME`,
+		"C": "line1\nline2 \"quoted\"",
+		"D": "multi\nline",
+		"E": "",
+	}, variables)
+
+	_, err = parseDotenv("NOT AN ASSIGNMENT\n")
+	require.ErrorContains(t, err, "line 1: expected KEY=value")
+
+	_, err = parseDotenv("A=\"unterminated\n")
+	require.ErrorContains(t, err, "unterminated double quoted value of A")
+}
+
+const (
+	testAccValidatedDotenvDataSourceConfig = `
+data "jsonschema_validated_dotenv" "app" {
+  input_pattern = "%s"
+  schema        = "%s"
+}
+`
+	testAccValidatedDotenvDataSourceSchema = `
+{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Application environment",
+  "type": "object",
+  "properties": {
+	"DATABASE_URL": {
+	  "type": "string",
+	  "pattern": "^postgres://"
+	},
+	"PORT": {
+	  "type": "string",
+	  "pattern": "^[0-9]+$"
+	}
+  },
+  "required": ["DATABASE_URL"]
+}
+`
+)
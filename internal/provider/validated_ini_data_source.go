@@ -0,0 +1,360 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	iniFormatINI        = "ini"
+	iniFormatProperties = "properties"
+)
+
+func NewValidatedINIDataSource() datasource.DataSource {
+	return &ValidatedINIDataSource{}
+}
+
+// ValidatedINIDataSource defines the data source implementation.
+type ValidatedINIDataSource struct {
+	compiler *schemaCompiler
+}
+
+// ValidatedINIDataSourceModel describes the data source data model.
+type ValidatedINIDataSourceModel struct {
+	InputPattern types.String `tfsdk:"input_pattern"`
+	Encoding     types.String `tfsdk:"encoding"`
+	Schema       types.String `tfsdk:"schema"`
+	Format       types.String `tfsdk:"format"`
+	Decoded      types.Map    `tfsdk:"decoded"`
+}
+
+func (d *ValidatedINIDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_ini"
+}
+
+func (d *ValidatedINIDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "INI and Java properties files validated against a json schema. " +
+			"INI sections become nested objects, keys outside of a section and properties keys are top-level properties. All values are strings.",
+
+		Attributes: map[string]schema.Attribute{
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of INI or properties files to validate",
+				Required:    true,
+			},
+			"encoding": encodingAttribute(),
+			"schema": schema.StringAttribute{
+				Description: "Path of the json schema every file is validated against",
+				Required:    true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Format of the files, `ini` or `properties`. Defaults to `properties` for files with the `.properties` extension and `ini` otherwise.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(iniFormatINI, iniFormatProperties),
+				},
+			},
+			"decoded": schema.MapAttribute{
+				Description: "Map of file paths to the JSON encoded validated content",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ValidatedINIDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *ValidatedINIDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedINIDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	decode, err := textDecoder(data.Encoding.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("encoding"),
+			"Invalid encoding",
+			err.Error(),
+		)
+		return
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	decodedMap := make(map[string]string)
+	for _, file := range files {
+		contentRaw, err := readTextFile(file, decode)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error reading file",
+				"Could not read file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		format := data.Format.ValueString()
+		if format == "" {
+			format = iniFormatINI
+			if strings.EqualFold(filepath.Ext(file), ".properties") {
+				format = iniFormatProperties
+			}
+		}
+
+		var value map[string]any
+		switch format {
+		case iniFormatProperties:
+			value, err = parseProperties(string(contentRaw))
+		default:
+			value, err = parseINI(string(contentRaw))
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error decoding "+format,
+				"Could not decode "+format+" file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		err = compiledSchema.Validate(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error validating "+format,
+				"File "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
+			)
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error encoding "+format,
+				"Could not encode "+format+" file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		decodedMap[file] = string(encoded)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	decoded, diag := types.MapValueFrom(ctx, types.StringType, decodedMap)
+	resp.Diagnostics.Append(diag...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Decoded = decoded
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseINI decodes an INI file, keys of a [section] are nested under the
+// section name. Lines starting with ';' or '#' are comments and values may
+// be enclosed in double quotes.
+func parseINI(content string) (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header %q", i+1, line)
+			}
+
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty section name", i+1)
+			}
+
+			section, ok := root[name].(map[string]any)
+			if !ok {
+				if _, exists := root[name]; exists {
+					return nil, fmt.Errorf("line %d: section %q conflicts with key of the same name", i+1, name)
+				}
+				section = make(map[string]any)
+				root[name] = section
+			}
+			current = section
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", i+1, line)
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+			value = unquoted
+		}
+
+		current[key] = value
+	}
+
+	return root, nil
+}
+
+// parseProperties decodes a Java properties file following the rules of
+// java.util.Properties: '#' and '!' comments, '=', ':' or whitespace
+// separators, backslash line continuations and escape sequences.
+func parseProperties(content string) (map[string]any, error) {
+	properties := make(map[string]any)
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		// join continuation lines, an odd number of trailing backslashes escapes the line break
+		for strings.HasSuffix(line, `\`) && (len(line)-len(strings.TrimRight(line, `\`)))%2 == 1 {
+			line = line[:len(line)-1]
+			i++
+			if i >= len(lines) {
+				break
+			}
+			line += strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		keyEnd := len(line)
+		for j := 0; j < len(line); j++ {
+			if line[j] == '\\' {
+				j++
+				continue
+			}
+			if strings.IndexByte("=: \t\f", line[j]) >= 0 {
+				keyEnd = j
+				break
+			}
+		}
+
+		rest := strings.TrimLeft(line[keyEnd:], " \t\f")
+		if strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, ":") {
+			rest = strings.TrimLeft(rest[1:], " \t\f")
+		}
+
+		key, err := unescapeProperty(line[:keyEnd])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		value, err := unescapeProperty(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		properties[key] = value
+	}
+
+	return properties, nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\uxxxx escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uxxxx escape in %q", s)
+			}
+			sb.WriteRune(rune(r))
+			i += 4
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidINI(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "service.ini"), []byte(`; service settings
+name = billing
+
+[database]
+host = db.internal
+port = "5432"
+`), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedINIDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedINIDataSourceConfig, filepath.Join(tmpDir, "*.ini"), schemaPath),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_ini.service",
+						tfjsonpath.New("decoded").AtMapKey(filepath.Join(tmpDir, "service.ini")),
+						knownvalue.StringExact(`{"database":{"host":"db.internal","port":"5432"},"name":"billing"}`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestInvalidProperties(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "service.properties"), []byte("name=billing\n"), 0644)
+	require.NoError(t, err)
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+
+	err = os.WriteFile(schemaPath, []byte(testAccValidatedINIDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config:      fmt.Sprintf(testAccValidatedINIDataSourceConfig, filepath.Join(tmpDir, "*.properties"), schemaPath),
+				ExpectError: regexp.MustCompile(`missing property 'database'`),
+			},
+		},
+	})
+}
+
+func TestParseINI(t *testing.T) {
+	value, err := parseINI(`
+top = level
+# comment
+[a]
+x = "quoted value"
+[b]
+y=1
+[a]
+z = 2
+`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"top": "level",
+		"a":   map[string]any{"x": "quoted value", "z": "2"},
+		"b":   map[string]any{"y": "1"},
+	}, value)
+
+	_, err = parseINI("[broken\n")
+	require.ErrorContains(t, err, "line 1: unterminated section header")
+}
+
+func TestParseProperties(t *testing.T) {
+	value, err := parseProperties(`
+# comment
+! comment
+a=1
+b : two
+c three
+long = first, \
+       second
+escaped\ key = tab\there é
+`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"a":           "1",
+		"b":           "two",
+		"c":           "three",
+		"long":        "first, second",
+		"escaped key": "tab\there é",
+	}, value)
+
+	_, err = parseProperties(`a=\u12`)
+	require.ErrorContains(t, err, "malformed \\uxxxx escape")
+}
+
+const (
+	testAccValidatedINIDataSourceConfig = `
+data "jsonschema_validated_ini" "service" {
+  input_pattern = "%s"
+  schema        = "%s"
+}
+`
+	testAccValidatedINIDataSourceSchema = `
+{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Service settings",
+  "type": "object",
+  "properties": {
+	"name": {
+	  "type": "string"
+	},
+	"database": {
+	  "type": "object",
+	  "properties": {
+		"host": {
+		  "type": "string"
+		},
+		"port": {
+		  "type": "string",
+		  "pattern": "^[0-9]+$"
+		}
+	  },
+	  "required": ["host"]
+	}
+  },
+  "required": ["name", "database"]
+}
+`
+)
@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"strings"
+)
+
+// Ensure ValidatedInputsDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &ValidatedInputsDataSource{}
+
+func NewValidatedInputsDataSource() datasource.DataSource {
+	return &ValidatedInputsDataSource{}
+}
+
+// ValidatedInputsDataSource defines the data source implementation.
+type ValidatedInputsDataSource struct {
+	compiler *schemaCompiler
+}
+
+// ValidatedInputsDataSourceModel describes the data source data model.
+type ValidatedInputsDataSourceModel struct {
+	Inputs types.Dynamic `tfsdk:"inputs"`
+	Schema types.String  `tfsdk:"schema"`
+	Values types.Dynamic `tfsdk:"values"`
+	JSON   types.String  `tfsdk:"json"`
+}
+
+func (d *ValidatedInputsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_inputs"
+}
+
+func (d *ValidatedInputsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Validates any Terraform value against a json schema and exposes it in `values` only if it is valid, " +
+			"so modules can funnel complex object variables through a schema before using them, with an error per violation instead of a failed `validation` condition. " +
+			"Objects and maps are validated as JSON objects, lists, tuples and sets as arrays, numbers, strings and booleans as they are and null as null. " +
+			"Attributes of objects that are null are validated as null values, e.g. optional attributes of variables that are not set, so schemas should allow `null` for them.",
+
+		Attributes: map[string]schema.Attribute{
+			"inputs": schema.DynamicAttribute{
+				MarkdownDescription: "Value to validate, e.g. `var.config`",
+				Required:            true,
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Path or URL of the json schema, e.g. `${path.module}/config.schema.json`",
+				Required:            true,
+			},
+			"values": schema.DynamicAttribute{
+				MarkdownDescription: "The validated `inputs`, unchanged",
+				Computed:            true,
+			},
+			"json": schema.StringAttribute{
+				Description: "JSON encoded validated inputs",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ValidatedInputsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *ValidatedInputsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedInputsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	value, err := terraformValueJSON(data.Inputs, "")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("inputs"),
+			"Error converting inputs",
+			"Could not convert inputs to JSON: "+err.Error(),
+		)
+		return
+	}
+
+	if err := compiledSchema.Validate(value); err != nil {
+		var violations []string
+		for _, finding := range validationFindings("", 0, err) {
+			violations = append(violations, "- '"+finding.Pointer+"' ("+finding.Keyword+"): "+finding.Message)
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("inputs"),
+			"Invalid inputs",
+			"Inputs do not conform to schema "+schemaPath+":\n"+strings.Join(violations, "\n"),
+		)
+		return
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error encoding inputs",
+			"Could not encode inputs as JSON: "+err.Error(),
+		)
+		return
+	}
+
+	data.Values = data.Inputs
+	data.JSON = types.StringValue(string(encoded))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// terraformValueJSON converts a Terraform value to the value of its JSON
+// encoding like jsonencode, with numbers kept exact as json.Number. pointer
+// is the JSON pointer of value, for errors.
+func terraformValueJSON(value attr.Value, pointer string) (any, error) {
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("value at '%s' is not known yet", pointer)
+	}
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	var elements []attr.Value
+	switch v := value.(type) {
+	case basetypes.DynamicValue:
+		return terraformValueJSON(v.UnderlyingValue(), pointer)
+	case basetypes.StringValue:
+		return v.ValueString(), nil
+	case basetypes.BoolValue:
+		return v.ValueBool(), nil
+	case basetypes.NumberValue:
+		return json.Number(v.ValueBigFloat().Text('g', -1)), nil
+	case basetypes.ObjectValue:
+		return terraformObjectJSON(v.Attributes(), pointer)
+	case basetypes.MapValue:
+		return terraformObjectJSON(v.Elements(), pointer)
+	case basetypes.ListValue:
+		elements = v.Elements()
+	case basetypes.TupleValue:
+		elements = v.Elements()
+	case basetypes.SetValue:
+		elements = v.Elements()
+	default:
+		return nil, fmt.Errorf("value at '%s' has the unsupported type %s", pointer, value.Type(context.Background()))
+	}
+
+	items := make([]any, 0, len(elements))
+	for i, element := range elements {
+		item, err := terraformValueJSON(element, fmt.Sprintf("%s/%d", pointer, i))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func terraformObjectJSON(attributes map[string]attr.Value, pointer string) (map[string]any, error) {
+	object := make(map[string]any, len(attributes))
+	for key, attribute := range attributes {
+		value, err := terraformValueJSON(attribute, pointer+"/"+escapePointerToken(key))
+		if err != nil {
+			return nil, err
+		}
+		object[key] = value
+	}
+
+	return object, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidatedInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "config.schema.json"))
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{
+  "type": "object",
+  "required": ["name", "replicas"],
+  "properties": {
+    "name": { "type": "string", "pattern": "^[a-z]+$" },
+    "replicas": { "type": "integer", "minimum": 1 },
+    "ports": { "type": "array", "items": { "type": "integer" }, "uniqueItems": true },
+    "labels": { "type": "object", "additionalProperties": { "type": "string" } },
+    "owner": { "type": ["string", "null"] }
+  }
+}`), 0644))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedInputsDataSourceConfig, schemaPath, `{ name = "Web", replicas = 0 }`),
+				ExpectError: regexp.MustCompile(`'/replicas' \(minimum\)`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedInputsDataSourceConfig, schemaPath, `{
+    name     = "web"
+    replicas = 2
+    ports    = [80, 443]
+    labels   = tomap({ tier = "frontend" })
+    owner    = null
+  }`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_inputs.test",
+						tfjsonpath.New("json"),
+						knownvalue.StringExact(`{"labels":{"tier":"frontend"},"name":"web","owner":null,"ports":[80,443],"replicas":2}`),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_inputs.test",
+						tfjsonpath.New("values").AtMapKey("replicas"),
+						knownvalue.Int64Exact(2),
+					),
+					statecheck.ExpectKnownOutputValue("first_port", knownvalue.Int64Exact(80)),
+				},
+			},
+		},
+	})
+}
+
+const testAccValidatedInputsDataSourceConfig = `
+data "jsonschema_validated_inputs" "test" {
+  schema = "%s"
+  inputs = %s
+}
+
+output "first_port" {
+  value = data.jsonschema_validated_inputs.test.values.ports[0]
+}
+`
@@ -0,0 +1,483 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"maps"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	kubernetesKindConfigMap = "ConfigMap"
+	kubernetesKindSecret    = "Secret"
+)
+
+func NewValidatedKubernetesConfigDataSource() datasource.DataSource {
+	return &ValidatedKubernetesConfigDataSource{}
+}
+
+// ValidatedKubernetesConfigDataSource defines the data source implementation.
+type ValidatedKubernetesConfigDataSource struct {
+	compiler    *schemaCompiler
+	yamlDecoder yamlDecoder
+}
+
+// ValidatedKubernetesConfigDataSourceModel describes the data source data model.
+type ValidatedKubernetesConfigDataSourceModel struct {
+	Kubeconfig      types.String `tfsdk:"kubeconfig"`
+	Context         types.String `tfsdk:"context"`
+	Namespace       types.String `tfsdk:"namespace"`
+	Kind            types.String `tfsdk:"kind"`
+	Name            types.String `tfsdk:"name"`
+	LabelSelector   types.String `tfsdk:"label_selector"`
+	Keys            types.Map    `tfsdk:"keys"`
+	FailOnInvalid   types.Bool   `tfsdk:"fail_on_invalid"`
+	Objects         types.List   `tfsdk:"objects"`
+	Violations      types.List   `tfsdk:"violations"`
+	Values          types.Map    `tfsdk:"values"`
+	SensitiveValues types.Map    `tfsdk:"sensitive_values"`
+}
+
+// KubernetesConfigViolationModel describes a violation of a schema by the
+// value of a key of a ConfigMap or Secret.
+type KubernetesConfigViolationModel struct {
+	Object  types.String `tfsdk:"object"`
+	Key     types.String `tfsdk:"key"`
+	Pointer types.String `tfsdk:"pointer"`
+	Message types.String `tfsdk:"message"`
+}
+
+var kubernetesConfigViolationAttrTypes = map[string]attr.Type{
+	"object":  types.StringType,
+	"key":     types.StringType,
+	"pointer": types.StringType,
+	"message": types.StringType,
+}
+
+// kubernetesConfigObject is a ConfigMap or Secret with the values of its
+// keys.
+type kubernetesConfigObject struct {
+	name string
+	data map[string][]byte
+}
+
+func (d *ValidatedKubernetesConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_kubernetes_config"
+}
+
+func (d *ValidatedKubernetesConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "The keys of ConfigMaps or Secrets deployed to a Kubernetes cluster validated against json schemas, " +
+			"to catch drift between the deployed configuration and its contract. " +
+			"The values of the `keys` are decoded as YAML or JSON, keys matching none of them are ignored. " +
+			"The cluster is accessed with a kubeconfig like `kubectl` does.",
+
+		Attributes: map[string]schema.Attribute{
+			"kubeconfig": schema.StringAttribute{
+				MarkdownDescription: "Path of the kubeconfig, defaults to the files of `KUBECONFIG` or `~/.kube/config`",
+				Optional:            true,
+			},
+			"context": schema.StringAttribute{
+				Description: "Context of the kubeconfig, defaults to its current context",
+				Optional:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace of the objects, defaults to the namespace of the context",
+				Optional:    true,
+			},
+			"kind": schema.StringAttribute{
+				MarkdownDescription: "Kind of the objects, `ConfigMap` (default) or `Secret`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(kubernetesKindConfigMap, kubernetesKindSecret),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the object, defaults to every object of the namespace matching label_selector",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("label_selector")),
+				},
+			},
+			"label_selector": schema.StringAttribute{
+				MarkdownDescription: "Label selector of the objects, e.g. `app.kubernetes.io/part-of=shop`",
+				Optional:            true,
+			},
+			"keys": schema.MapAttribute{
+				MarkdownDescription: "Map of glob patterns of keys, e.g. `config.yaml` or `*.json`, to the path of the json schema their values are validated against. " +
+					"Keys matching several patterns are validated against every schema.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"fail_on_invalid": schema.BoolAttribute{
+				MarkdownDescription: "Fail when a value cannot be decoded or does not conform to its schema, defaults to `true`. " +
+					"If `false`, errors are reported as warnings and listed in `violations`.",
+				Optional: true,
+			},
+			"objects": schema.ListAttribute{
+				Description: "Names of the objects read from the cluster",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"violations": schema.ListNestedAttribute{
+				MarkdownDescription: "Violations of the schemas by the values of the keys, only ever non-empty if `fail_on_invalid` is `false`",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"object": schema.StringAttribute{
+							Description: "Name of the object",
+							Computed:    true,
+						},
+						"key": schema.StringAttribute{
+							Description: "Key of the invalid value",
+							Computed:    true,
+						},
+						"pointer": schema.StringAttribute{
+							Description: "JSON pointer of the invalid value in the decoded value of the key, empty if it could not be decoded",
+							Computed:    true,
+						},
+						"message": schema.StringAttribute{
+							Description: "Description of the violation",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"values": schema.MapAttribute{
+				MarkdownDescription: "Map of `name/key` of ConfigMaps to the validated values of their keys",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"sensitive_values": schema.MapAttribute{
+				MarkdownDescription: "Map of `name/key` of Secrets to the validated values of their keys",
+				Computed:            true,
+				Sensitive:           true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ValidatedKubernetesConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+	d.yamlDecoder = providerData.YAMLDecoder
+}
+
+func (d *ValidatedKubernetesConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedKubernetesConfigDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keySchemas := make(map[string]string)
+	resp.Diagnostics.Append(data.Keys.ElementsAs(ctx, &keySchemas, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	patterns := slices.Sorted(maps.Keys(keySchemas))
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("keys").AtMapKey(pattern),
+				"Invalid key pattern",
+				"Could not parse pattern "+pattern+": "+err.Error(),
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	kind := data.Kind.ValueString()
+	if kind == "" {
+		kind = kubernetesKindConfigMap
+	}
+
+	objects := d.readObjects(ctx, &data, kind, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failOnInvalid := data.FailOnInvalid.IsNull() || data.FailOnInvalid.ValueBool()
+	addDiagnostic := resp.Diagnostics.AddAttributeError
+	if !failOnInvalid {
+		addDiagnostic = resp.Diagnostics.AddAttributeWarning
+	}
+
+	compiledSchemas := make(map[string]*jsonschema.Schema)
+	names := make([]string, 0, len(objects))
+	violations := make([]KubernetesConfigViolationModel, 0)
+	valuesMap := make(map[string]string)
+	var matched bool
+	for _, object := range objects {
+		names = append(names, object.name)
+
+		for _, key := range slices.Sorted(maps.Keys(object.data)) {
+			value := object.data[key]
+			id := object.name + "/" + key
+
+			var keyViolations []KubernetesConfigViolationModel
+			var document any
+			var decoded bool
+			for _, pattern := range patterns {
+				if ok, _ := filepath.Match(pattern, key); !ok {
+					continue
+				}
+				matched = true
+
+				schemaPath := keySchemas[pattern]
+				compiledSchema, ok := compiledSchemas[schemaPath]
+				if !ok {
+					var err error
+					compiledSchema, err = d.compiler.Compile(schemaPath)
+					if err != nil {
+						resp.Diagnostics.AddAttributeError(
+							path.Root("keys").AtMapKey(pattern),
+							"Error compiling schema",
+							"Could not compile schema "+schemaPath+": "+err.Error(),
+						)
+						return
+					}
+					compiledSchemas[schemaPath] = compiledSchema
+				}
+
+				// JSON values are YAML too
+				if !decoded {
+					var err error
+					document, err = d.yamlDecoder.decode(value)
+					if err != nil {
+						addDiagnostic(
+							path.Root("keys").AtMapKey(pattern),
+							"Error decoding value",
+							"Could not decode key "+key+" of "+kind+" "+object.name+" as YAML or JSON: "+err.Error(),
+						)
+						keyViolations = append(keyViolations, KubernetesConfigViolationModel{
+							Object:  types.StringValue(object.name),
+							Key:     types.StringValue(key),
+							Pointer: types.StringValue(""),
+							Message: types.StringValue(err.Error()),
+						})
+						break
+					}
+					decoded = true
+				}
+
+				if err := compiledSchema.Validate(document); err != nil {
+					addDiagnostic(
+						path.Root("keys").AtMapKey(pattern),
+						"Error validating value",
+						"Key "+key+" of "+kind+" "+object.name+" does not conform to schema "+schemaPath+": "+err.Error(),
+					)
+					for _, finding := range validationFindings(id, 0, err) {
+						keyViolations = append(keyViolations, KubernetesConfigViolationModel{
+							Object:  types.StringValue(object.name),
+							Key:     types.StringValue(key),
+							Pointer: types.StringValue(finding.Pointer),
+							Message: types.StringValue(finding.Message),
+						})
+					}
+				}
+			}
+
+			if decoded && len(keyViolations) == 0 {
+				valuesMap[id] = string(value)
+			}
+			violations = append(violations, keyViolations...)
+		}
+	}
+
+	if !matched {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("keys"),
+			"No matching keys found",
+			fmt.Sprintf("None of the keys of the %d %s objects read from the cluster matched the patterns of keys", len(objects), kind),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+
+	data.Objects, diags = types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+
+	data.Violations, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: kubernetesConfigViolationAttrTypes}, violations)
+	resp.Diagnostics.Append(diags...)
+
+	values, sensitiveValues := valuesMap, map[string]string{}
+	if kind == kubernetesKindSecret {
+		values, sensitiveValues = sensitiveValues, values
+	}
+
+	data.Values, diags = types.MapValueFrom(ctx, types.StringType, values)
+	resp.Diagnostics.Append(diags...)
+
+	data.SensitiveValues, diags = types.MapValueFrom(ctx, types.StringType, sensitiveValues)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readObjects returns the ConfigMaps or Secrets selected by data, sorted by
+// name, adding error diagnostics if they cannot be read.
+func (d *ValidatedKubernetesConfigDataSource) readObjects(ctx context.Context, data *ValidatedKubernetesConfigDataSourceModel, kind string, diags *diag.Diagnostics) []kubernetesConfigObject {
+	// the kubeconfig is loaded like kubectl does, from KUBECONFIG or ~/.kube/config by default
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if !data.Kubeconfig.IsNull() {
+		loadingRules.ExplicitPath = data.Kubeconfig.ValueString()
+	}
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: data.Context.ValueString()}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("kubeconfig"),
+			"Error loading kubeconfig",
+			"Could not load the kubeconfig: "+err.Error(),
+		)
+		return nil
+	}
+
+	namespace := data.Namespace.ValueString()
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			diags.AddAttributeError(
+				path.Root("namespace"),
+				"Error loading kubeconfig",
+				"Could not read the namespace of the context: "+err.Error(),
+			)
+			return nil
+		}
+	}
+
+	client, err := corev1client.NewForConfig(restConfig)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("kubeconfig"),
+			"Error creating Kubernetes client",
+			"Could not create the Kubernetes client: "+err.Error(),
+		)
+		return nil
+	}
+
+	var objects []kubernetesConfigObject
+	name := data.Name.ValueString()
+	listOptions := metav1.ListOptions{LabelSelector: data.LabelSelector.ValueString()}
+
+	switch kind {
+	case kubernetesKindSecret:
+		secrets := client.Secrets(namespace)
+		if name != "" {
+			secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				readObjectsError(diags, kind, namespace, err)
+				return nil
+			}
+			objects = append(objects, kubernetesConfigObject{name: secret.Name, data: secret.Data})
+		} else {
+			list, err := secrets.List(ctx, listOptions)
+			if err != nil {
+				readObjectsError(diags, kind, namespace, err)
+				return nil
+			}
+			for _, secret := range list.Items {
+				objects = append(objects, kubernetesConfigObject{name: secret.Name, data: secret.Data})
+			}
+		}
+	default:
+		configMaps := client.ConfigMaps(namespace)
+		if name != "" {
+			configMap, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				readObjectsError(diags, kind, namespace, err)
+				return nil
+			}
+			objects = append(objects, configMapObject(configMap.Name, configMap.Data, configMap.BinaryData))
+		} else {
+			list, err := configMaps.List(ctx, listOptions)
+			if err != nil {
+				readObjectsError(diags, kind, namespace, err)
+				return nil
+			}
+			for _, configMap := range list.Items {
+				objects = append(objects, configMapObject(configMap.Name, configMap.Data, configMap.BinaryData))
+			}
+		}
+	}
+
+	slices.SortFunc(objects, func(a, b kubernetesConfigObject) int {
+		return cmp.Compare(a.name, b.name)
+	})
+
+	return objects
+}
+
+// configMapObject returns the values of the keys of a ConfigMap, of both
+// its data and binary data.
+func configMapObject(name string, data map[string]string, binaryData map[string][]byte) kubernetesConfigObject {
+	object := kubernetesConfigObject{name: name, data: maps.Clone(binaryData)}
+	if object.data == nil {
+		object.data = make(map[string][]byte, len(data))
+	}
+	for key, value := range data {
+		object.data[key] = []byte(value)
+	}
+
+	return object
+}
+
+func readObjectsError(diags *diag.Diagnostics, kind, namespace string, err error) {
+	diags.AddError(
+		"Error reading "+kind,
+		"Could not read "+kind+" objects of namespace "+namespace+": "+err.Error(),
+	)
+}
@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// newTestKubernetesServer mocks the ConfigMap and Secret endpoints of the
+// Kubernetes API for the default namespace, objects maps the plural of a
+// kind to its objects. It returns the path of a kubeconfig for the server.
+func newTestKubernetesServer(t *testing.T, objects map[string][]map[string]any) string {
+	const token = "kube-token"
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		status := func(code int, reason string) {
+			w.WriteHeader(code)
+			_ = json.NewEncoder(w).Encode(map[string]any{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": reason, "message": strings.ToLower(reason), "code": code})
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			status(http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		resource, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/namespaces/default/"), "/")
+		items, ok := objects[resource]
+		if !ok {
+			status(http.StatusNotFound, "NotFound")
+			return
+		}
+
+		kind := map[string]string{"configmaps": "ConfigMap", "secrets": "Secret"}[resource]
+		for _, item := range items {
+			item["apiVersion"], item["kind"] = "v1", kind
+		}
+
+		if name != "" {
+			for _, item := range items {
+				if item["metadata"].(map[string]any)["name"] == name {
+					_ = json.NewEncoder(w).Encode(item)
+					return
+				}
+			}
+			status(http.StatusNotFound, "NotFound")
+			return
+		}
+
+		// only selectors of a single label are supported
+		selected := make([]map[string]any, 0)
+		for _, item := range items {
+			label, value, _ := strings.Cut(r.URL.Query().Get("labelSelector"), "=")
+			labels, _ := item["metadata"].(map[string]any)["labels"].(map[string]any)
+			if label == "" || labels[label] == value {
+				selected = append(selected, item)
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"kind": kind + "List", "apiVersion": "v1", "metadata": map[string]any{}, "items": selected})
+	}))
+
+	t.Cleanup(server.Close)
+
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	err := os.WriteFile(kubeconfig, []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+  - name: test
+    cluster:
+      server: %s
+      certificate-authority-data: %s
+users:
+  - name: test
+    user:
+      token: %s
+contexts:
+  - name: test
+    context:
+      cluster: test
+      user: test
+      namespace: default
+current-context: test
+`, server.URL, base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})), token)), 0600)
+	require.NoError(t, err)
+
+	return kubeconfig
+}
+
+func TestKubernetesConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	err := os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	kubeconfig := newTestKubernetesServer(t, map[string][]map[string]any{
+		"configmaps": {
+			{
+				"metadata": map[string]any{"name": "api", "labels": map[string]any{"app": "shop"}},
+				"data": map[string]any{
+					"config.yaml": "id: api-id\nname: API\n",
+					"README":      "not validated",
+				},
+			},
+			{
+				"metadata": map[string]any{"name": "web", "labels": map[string]any{"app": "shop"}},
+				"data":     map[string]any{"config.yaml": `{"id": "web-id"}`},
+			},
+			{
+				"metadata": map[string]any{"name": "other"},
+				"data":     map[string]any{"config.yaml": "id: [\n"},
+			},
+		},
+		"secrets": {
+			{
+				"metadata": map[string]any{"name": "api"},
+				"data":     map[string]any{"config.json": "eyJpZCI6ICJzZWNyZXQtaWQiLCAibmFtZSI6ICJTZWNyZXQifQ=="},
+			},
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedKubernetesConfigDataSourceConfig, kubeconfig, "ConfigMap", `name = "api"`, "config.yaml", schemaPath, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_kubernetes_config.test",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"api/config.yaml": knownvalue.StringExact("id: api-id\nname: API\n"),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedKubernetesConfigDataSourceConfig, kubeconfig, "ConfigMap", `label_selector = "app=shop"`, "*.yaml", schemaPath, true),
+				ExpectError: regexp.MustCompile(`Key config.yaml of ConfigMap web does not conform to schema`),
+			},
+			// Violations are listed instead of failing
+			{
+				Config: fmt.Sprintf(testAccValidatedKubernetesConfigDataSourceConfig, kubeconfig, "ConfigMap", `label_selector = "app=shop"`, "*.yaml", schemaPath, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_kubernetes_config.test",
+						tfjsonpath.New("objects"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("api"), knownvalue.StringExact("web")}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_kubernetes_config.test",
+						tfjsonpath.New("violations"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"object":  knownvalue.StringExact("web"),
+								"key":     knownvalue.StringExact("config.yaml"),
+								"pointer": knownvalue.StringExact(""),
+								"message": knownvalue.StringExact("missing property 'name'"),
+							}),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_kubernetes_config.test",
+						tfjsonpath.New("values"),
+						knownvalue.MapSizeExact(1),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedKubernetesConfigDataSourceConfig, kubeconfig, "ConfigMap", `name = "other"`, "config.yaml", schemaPath, true),
+				ExpectError: regexp.MustCompile(`Could not decode key config.yaml of ConfigMap other`),
+			},
+			// Values of Secrets are decoded from base64 and only exposed sensitively
+			{
+				Config: fmt.Sprintf(testAccValidatedKubernetesConfigDataSourceConfig, kubeconfig, "Secret", `name = "api"`, "*.json", schemaPath, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_kubernetes_config.test",
+						tfjsonpath.New("sensitive_values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"api/config.json": knownvalue.StringExact(`{"id": "secret-id", "name": "Secret"}`),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_kubernetes_config.test",
+						tfjsonpath.New("values"),
+						knownvalue.MapSizeExact(0),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedKubernetesConfigDataSourceConfig, kubeconfig, "ConfigMap", `name = "api"`, "*.json", schemaPath, true),
+				ExpectError: regexp.MustCompile(`No matching keys found`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedKubernetesConfigDataSourceConfig, kubeconfig, "ConfigMap", `name = "missing"`, "*.json", schemaPath, true),
+				ExpectError: regexp.MustCompile(`Error reading ConfigMap`),
+			},
+		},
+	})
+}
+
+const testAccValidatedKubernetesConfigDataSourceConfig = `
+data "jsonschema_validated_kubernetes_config" "test" {
+  kubeconfig      = "%s"
+  kind            = "%s"
+  %s
+  keys            = { "%s" = "%s" }
+  fail_on_invalid = %t
+}
+`
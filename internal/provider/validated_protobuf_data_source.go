@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewValidatedProtobufDataSource() datasource.DataSource {
+	return &ValidatedProtobufDataSource{}
+}
+
+// ValidatedProtobufDataSource defines the data source implementation.
+type ValidatedProtobufDataSource struct {
+	descriptors *descriptorSources
+}
+
+// ValidatedProtobufDataSourceModel describes the data source data model.
+type ValidatedProtobufDataSourceModel struct {
+	InputPattern   types.String `tfsdk:"input_pattern"`
+	Encoding       types.String `tfsdk:"encoding"`
+	DescriptorSet  types.String `tfsdk:"descriptor_set"`
+	Message        types.String `tfsdk:"message"`
+	DiscardUnknown types.Bool   `tfsdk:"discard_unknown"`
+	Decoded        types.Map    `tfsdk:"decoded"`
+}
+
+func (d *ValidatedProtobufDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_protobuf"
+}
+
+func (d *ValidatedProtobufDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "YAML and JSON files validated against a protobuf message type of a compiled `FileDescriptorSet` instead of a json schema, " +
+			"so contracts defined in proto files are checked by the same Terraform-native gate. " +
+			"Documents are decoded like protojson decodes the JSON mapping of the message: fields may use their JSON or proto names, " +
+			"enums are names or numbers, 64-bit integers may be strings and well-known types like `google.protobuf.Timestamp` use their JSON representation. " +
+			"Unknown fields, values of the wrong type and missing required fields of proto2 fail.",
+
+		Attributes: map[string]schema.Attribute{
+			"input_pattern": schema.StringAttribute{
+				Description: "Glob pattern of YAML or JSON files to validate",
+				Required:    true,
+			},
+			"encoding": encodingAttribute(),
+			"descriptor_set": schema.StringAttribute{
+				MarkdownDescription: "Path of the binary `FileDescriptorSet` with the message type and every file it imports, " +
+					"e.g. written by `buf build -o descriptors.binpb` or `protoc --include_imports --descriptor_set_out=descriptors.binpb`. " +
+					"Descriptors are fetched instead from a module of the Buf Schema Registry with `buf://buf.build/owner/module[:version]`, see the `buf` block of the provider, " +
+					"or from a gRPC server with the reflection service with `grpc://host:port`, or `grpcs://host:port` with TLS.",
+				Required: true,
+			},
+			"message": schema.StringAttribute{
+				MarkdownDescription: "Fully qualified name of the message type, e.g. `acme.shop.v1.Order`",
+				Required:            true,
+			},
+			"discard_unknown": schema.BoolAttribute{
+				Description: "Ignore fields that the message type does not have instead of failing",
+				Optional:    true,
+			},
+			"decoded": schema.MapAttribute{
+				Description: "Map of file paths to the JSON encoding of the decoded message, with the proto names of its fields",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ValidatedProtobufDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.descriptors = providerData.Descriptors
+}
+
+func (d *ValidatedProtobufDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedProtobufDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	descriptorSet := data.DescriptorSet.ValueString()
+
+	messageName := data.Message.ValueString()
+
+	descriptors, err := d.descriptors.load(ctx, descriptorSet, messageName)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("descriptor_set"),
+			"Error loading descriptor set",
+			"Could not load file descriptor set "+descriptorSet+": "+err.Error(),
+		)
+		return
+	}
+
+	message, err := newProtobufMessage(descriptors, messageName)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("message"),
+			"Invalid message type",
+			err.Error(),
+		)
+		return
+	}
+
+	decode, err := textDecoder(data.Encoding.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("encoding"),
+			"Invalid encoding",
+			err.Error(),
+		)
+		return
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	decodedMap := make(map[string]string)
+	for _, file := range files {
+		contentRaw, err := readTextFile(file, decode)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error reading file",
+				"Could not read file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		value, err := decodeYAML(contentRaw)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error decoding YAML",
+				"Could not decode YAML file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		decoded, err := message.decode(value, data.DiscardUnknown.ValueBool())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input_pattern"),
+				"Error validating message",
+				fmt.Sprintf("File %s does not conform to message %s: %s", file, messageName, err),
+			)
+			continue
+		}
+
+		decodedMap[file] = decoded
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	decoded, diag := types.MapValueFrom(ctx, types.StringType, decodedMap)
+	resp.Diagnostics.Append(diag...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Decoded = decoded
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
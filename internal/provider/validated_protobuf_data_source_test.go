@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// testDescriptorSet returns the file descriptor set of shop/v1/order.proto
+// and the files it imports, like protoc --include_imports writes it:
+//
+//	syntax = "proto3";
+//	package shop.v1;
+//	import "google/protobuf/timestamp.proto";
+//	enum Status { STATUS_UNSPECIFIED = 0; STATUS_OPEN = 1; }
+//	message Order {
+//	  string id = 1;
+//	  int64 quantity = 2;
+//	  repeated string tags = 3;
+//	  Status status = 4;
+//	  google.protobuf.Timestamp created_at = 5;
+//	}
+func testDescriptorSet() *descriptorpb.FileDescriptorSet {
+	field := func(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, fieldType descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  label.Enum(),
+			Type:   fieldType.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+
+	order := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("shop/v1/order.proto"),
+		Package:    proto.String("shop.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_OPEN"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("quantity", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+				field("tags", 3, descriptorpb.FieldDescriptorProto_LABEL_REPEATED, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("status", 4, optional, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".shop.v1.Status"),
+				field("created_at", 5, optional, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+			},
+		}},
+	}
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto), order},
+	}
+}
+
+// writeTestDescriptorSet writes set to a file of the test and returns its
+// path.
+func writeTestDescriptorSet(t *testing.T, set *descriptorpb.FileDescriptorSet) string {
+	content, err := proto.Marshal(set)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "descriptors.binpb")
+	require.NoError(t, os.WriteFile(file, content, 0644))
+
+	return file
+}
+
+func TestProtobufMessage(t *testing.T) {
+	files, err := readDescriptorSet(writeTestDescriptorSet(t, testDescriptorSet()))
+	require.NoError(t, err)
+
+	message, err := newProtobufMessage(files, "shop.v1.Order")
+	require.NoError(t, err)
+
+	// JSON and proto field names, enum numbers and YAML timestamps, int64 is encoded as string
+	value, err := decodeYAML([]byte("id: a-1\nquantity: 3\ntags: [x]\nstatus: 1\ncreatedAt: 2024-01-02T03:04:05Z\n"))
+	require.NoError(t, err)
+
+	decoded, err := message.decode(value, false)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"a-1","quantity":"3","tags":["x"],"status":"STATUS_OPEN","created_at":"2024-01-02T03:04:05Z"}`, decoded)
+
+	_, err = message.decode(map[string]any{"id": "a-1", "unknown": true}, false)
+	require.ErrorContains(t, err, `unknown field "unknown"`)
+
+	decoded, err = message.decode(map[string]any{"id": "a-1", "unknown": true}, true)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"a-1"}`, decoded)
+
+	_, err = message.decode(map[string]any{"status": "STATUS_CLOSED"}, false)
+	require.ErrorContains(t, err, "invalid value for enum field status")
+
+	_, err = newProtobufMessage(files, "shop.v1.Status")
+	require.ErrorContains(t, err, "shop.v1.Status is not a message")
+
+	_, err = newProtobufMessage(files, "shop.v1.Missing")
+	require.ErrorContains(t, err, "message shop.v1.Missing not found")
+
+	// descriptor sets without the imports of their files
+	set := testDescriptorSet()
+	set.File = set.File[1:]
+	_, err = readDescriptorSet(writeTestDescriptorSet(t, set))
+	require.ErrorContains(t, err, "it has to include the imports of its files")
+}
+
+func TestProtobufYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	descriptorSet := writeTestDescriptorSet(t, testDescriptorSet())
+	bufServer := newTestBufServer(t, "buf-token")
+
+	err := os.WriteFile(filepath.Join(tmpDir, "order.yaml"), []byte("id: a-1\nquantity: 3\nstatus: STATUS_OPEN\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "invalid.json"), []byte(`{"id": "a-2", "quantity": "many"}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedProtobufDataSourceConfig, filepath.Join(tmpDir, "*.yaml"), descriptorSet, "shop.v1.Order"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_protobuf.test",
+						tfjsonpath.New("decoded"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							filepath.Join(tmpDir, "order.yaml"): knownvalue.StringExact(`{"id":"a-1","quantity":"3","status":"STATUS_OPEN"}`),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedProtobufDataSourceConfig, filepath.Join(tmpDir, "*.json"), descriptorSet, "shop.v1.Order"),
+				ExpectError: regexp.MustCompile(`Error validating message`),
+			},
+			// Descriptors of the Buf Schema Registry and of gRPC reflection
+			{
+				Config: fmt.Sprintf(testAccValidatedProtobufDataSourceBufConfig, bufServer.URL, filepath.Join(tmpDir, "*.yaml"), "buf://buf.build/acme/shop:v1", "shop.v1.Order"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.jsonschema_validated_protobuf.test", tfjsonpath.New("decoded"), knownvalue.MapSizeExact(1)),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedProtobufDataSourceConfig, filepath.Join(tmpDir, "*.yaml"), "grpc://"+newTestReflectionServer(t), "shop.v1.Order"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.jsonschema_validated_protobuf.test", tfjsonpath.New("decoded"), knownvalue.MapSizeExact(1)),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedProtobufDataSourceConfig, filepath.Join(tmpDir, "*.yaml"), descriptorSet, "shop.v1.Missing"),
+				ExpectError: regexp.MustCompile(`Invalid message type`),
+			},
+		},
+	})
+}
+
+const (
+	testAccValidatedProtobufDataSourceConfig = `
+data "jsonschema_validated_protobuf" "test" {
+  input_pattern  = "%s"
+  descriptor_set = "%s"
+  message        = "%s"
+}
+`
+	testAccValidatedProtobufDataSourceBufConfig = `
+provider "jsonschema" {
+  buf = {
+    endpoint = "%s"
+    token    = "buf-token"
+  }
+}
+` + testAccValidatedProtobufDataSourceConfig
+)
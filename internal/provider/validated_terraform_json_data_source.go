@@ -0,0 +1,281 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const (
+	terraformJSONPlan  = "plan"
+	terraformJSONState = "state"
+)
+
+func NewValidatedTerraformJSONDataSource() datasource.DataSource {
+	return &ValidatedTerraformJSONDataSource{}
+}
+
+// ValidatedTerraformJSONDataSource defines the data source implementation.
+type ValidatedTerraformJSONDataSource struct {
+	compiler *schemaCompiler
+}
+
+// ValidatedTerraformJSONDataSourceModel describes the data source data model.
+type ValidatedTerraformJSONDataSourceModel struct {
+	Input           types.String `tfsdk:"input"`
+	Schema          types.String `tfsdk:"schema"`
+	ResourceSchemas types.Map    `tfsdk:"resource_schemas"`
+	Kind            types.String `tfsdk:"kind"`
+	Resources       types.List   `tfsdk:"resources"`
+}
+
+// terraformResource is a resource of the planned values or the values of a
+// state.
+type terraformResource struct {
+	Address string
+	Type    string
+	Values  any
+}
+
+func (d *ValidatedTerraformJSONDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_terraform_json"
+}
+
+func (d *ValidatedTerraformJSONDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "A Terraform plan or state encoded as JSON by `terraform show -json` validated against json schemas, " +
+			"e.g. policies over the attributes of resources expressed as schemas instead of OPA policies. " +
+			"Resources are validated with the values they have after the plan is applied, attributes that are unknown until apply are left out.",
+
+		Attributes: map[string]schema.Attribute{
+			"input": schema.StringAttribute{
+				MarkdownDescription: "Path of the output of `terraform show -json` for a saved plan or a state",
+				Required:            true,
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path of the json schema the whole document is validated against",
+				Optional:    true,
+			},
+			"resource_schemas": schema.MapAttribute{
+				MarkdownDescription: "Map of resource types, e.g. `aws_s3_bucket`, to the path of the json schema the attribute values of every managed resource of the type are validated against, " +
+					"including resources of child modules",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"kind": schema.StringAttribute{
+				MarkdownDescription: "Kind of the document, `plan` or `state`",
+				Computed:            true,
+			},
+			"resources": schema.ListAttribute{
+				MarkdownDescription: "Addresses of the resources validated against `resource_schemas`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ValidatedTerraformJSONDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+}
+
+func (d *ValidatedTerraformJSONDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedTerraformJSONDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Schema.IsNull() && data.ResourceSchemas.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Missing schema",
+			"At least one of schema and resource_schemas must be set",
+		)
+		return
+	}
+
+	resourceSchemas := make(map[string]string)
+	if !data.ResourceSchemas.IsNull() {
+		resp.Diagnostics.Append(data.ResourceSchemas.ElementsAs(ctx, &resourceSchemas, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	file := data.Input.ValueString()
+
+	content, err := readTextFile(file, decodeText)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("input"),
+			"Error reading file",
+			"Could not read file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	document, err := jsonschema.UnmarshalJSON(bytes.NewReader(content))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("input"),
+			"Error decoding JSON",
+			"Could not decode JSON file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	object, ok := document.(map[string]any)
+	if !ok || object["format_version"] == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("input"),
+			"Invalid Terraform JSON",
+			"File "+file+" is not the output of terraform show -json, which has a format_version",
+		)
+		return
+	}
+
+	kind, values := terraformJSONPlan, object["planned_values"]
+	if _, isPlan := object["planned_values"]; !isPlan {
+		if _, isPlan = object["resource_changes"]; !isPlan {
+			kind, values = terraformJSONState, object["values"]
+		}
+	}
+
+	if !data.Schema.IsNull() {
+		schemaPath := data.Schema.ValueString()
+
+		compiledSchema, err := d.compiler.Compile(schemaPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schema"),
+				"Error compiling schema",
+				"Could not compile schema "+schemaPath+": "+err.Error(),
+			)
+			return
+		}
+
+		if err := compiledSchema.Validate(document); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("input"),
+				"Error validating "+kind,
+				"Terraform "+kind+" "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
+			)
+		}
+	}
+
+	validated := make([]string, 0)
+	if len(resourceSchemas) > 0 {
+		module, _ := values.(map[string]any)
+		for _, resource := range terraformResources(module["root_module"]) {
+			schemaPath, ok := resourceSchemas[resource.Type]
+			if !ok {
+				continue
+			}
+
+			compiledSchema, err := d.compiler.Compile(schemaPath)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("resource_schemas").AtMapKey(resource.Type),
+					"Error compiling schema",
+					"Could not compile schema "+schemaPath+": "+err.Error(),
+				)
+				// every resource of the type fails the same way
+				delete(resourceSchemas, resource.Type)
+				continue
+			}
+
+			validated = append(validated, resource.Address)
+
+			if err := compiledSchema.Validate(resource.Values); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("resource_schemas").AtMapKey(resource.Type),
+					"Error validating resource",
+					"Resource "+resource.Address+" of "+kind+" "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
+				)
+			}
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Kind = types.StringValue(kind)
+
+	resources, diags := types.ListValueFrom(ctx, types.StringType, validated)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Resources = resources
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// terraformResources returns the managed resources of a module of the JSON
+// output of terraform show and of its child modules.
+func terraformResources(module any) []terraformResource {
+	object, ok := module.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var resources []terraformResource
+
+	items, _ := object["resources"].([]any)
+	for _, item := range items {
+		resource, ok := item.(map[string]any)
+		if !ok || resource["mode"] != "managed" {
+			continue
+		}
+
+		address, _ := resource["address"].(string)
+		resourceType, _ := resource["type"].(string)
+
+		values, ok := resource["values"]
+		if !ok {
+			values = map[string]any{}
+		}
+
+		resources = append(resources, terraformResource{Address: address, Type: resourceType, Values: values})
+	}
+
+	children, _ := object["child_modules"].([]any)
+	for _, child := range children {
+		resources = append(resources, terraformResources(child)...)
+	}
+
+	return resources
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidatedTerraformJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"plan.json":         testAccValidatedTerraformJSONPlan,
+		"state.json":        testAccValidatedTerraformJSONState,
+		"other.json":        `{"resources": []}`,
+		"bucket.json":       `{"type": "object", "required": ["tags"], "properties": {"tags": {"required": ["owner"]}}}`,
+		"acl.json":          `{"properties": {"acl": {"const": "private"}}}`,
+		"state-schema.json": `{"type": "object", "required": ["terraform_version"], "properties": {"terraform_version": {"pattern": "^1\\."}}}`,
+	} {
+		err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedTerraformJSONResourcesConfig, filepath.Join(tmpDir, "plan.json"), "aws_s3_bucket", filepath.Join(tmpDir, "bucket.json")),
+				ExpectError: regexp.MustCompile(`(?s)Resource module\.logs\.aws_s3_bucket\.this of plan.*missing property 'owner'`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedTerraformJSONResourcesConfig, filepath.Join(tmpDir, "plan.json"), "aws_s3_bucket_acl", filepath.Join(tmpDir, "acl.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_terraform_json.test",
+						tfjsonpath.New("kind"),
+						knownvalue.StringExact("plan"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_terraform_json.test",
+						tfjsonpath.New("resources"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("aws_s3_bucket_acl.main"),
+							knownvalue.StringExact("module.logs.aws_s3_bucket_acl.this"),
+						}),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedTerraformJSONSchemaConfig, filepath.Join(tmpDir, "state.json"), filepath.Join(tmpDir, "state-schema.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_terraform_json.test",
+						tfjsonpath.New("kind"),
+						knownvalue.StringExact("state"),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedTerraformJSONResourcesConfig, filepath.Join(tmpDir, "state.json"), "aws_s3_bucket", filepath.Join(tmpDir, "bucket.json")),
+				ExpectError: regexp.MustCompile(`Resource aws_s3_bucket\.main of state`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedTerraformJSONSchemaConfig, filepath.Join(tmpDir, "other.json"), filepath.Join(tmpDir, "state-schema.json")),
+				ExpectError: regexp.MustCompile(`Invalid Terraform JSON`),
+			},
+			{
+				Config:      fmt.Sprintf(`data "jsonschema_validated_terraform_json" "test" { input = "%s" }`, filepath.Join(tmpDir, "plan.json")),
+				ExpectError: regexp.MustCompile(`Missing schema`),
+			},
+		},
+	})
+}
+
+const (
+	testAccValidatedTerraformJSONResourcesConfig = `
+data "jsonschema_validated_terraform_json" "test" {
+  input = "%s"
+
+  resource_schemas = {
+    %s = "%s"
+  }
+}
+`
+	testAccValidatedTerraformJSONSchemaConfig = `
+data "jsonschema_validated_terraform_json" "test" {
+  input  = "%s"
+  schema = "%s"
+}
+`
+	testAccValidatedTerraformJSONPlan = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.8",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.main",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "main",
+          "values": {"bucket": "main", "tags": {"owner": "platform"}}
+        },
+        {
+          "address": "aws_s3_bucket_acl.main",
+          "mode": "managed",
+          "type": "aws_s3_bucket_acl",
+          "name": "main",
+          "values": {"acl": "private"}
+        },
+        {
+          "address": "data.aws_s3_bucket.existing",
+          "mode": "data",
+          "type": "aws_s3_bucket",
+          "name": "existing",
+          "values": {"bucket": "existing"}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.logs",
+          "resources": [
+            {
+              "address": "module.logs.aws_s3_bucket.this",
+              "mode": "managed",
+              "type": "aws_s3_bucket",
+              "name": "this",
+              "values": {"bucket": "logs", "tags": {}}
+            },
+            {
+              "address": "module.logs.aws_s3_bucket_acl.this",
+              "mode": "managed",
+              "type": "aws_s3_bucket_acl",
+              "name": "this",
+              "values": {"acl": "private"}
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "resource_changes": []
+}`
+	testAccValidatedTerraformJSONState = `{
+  "format_version": "1.0",
+  "terraform_version": "1.9.8",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.main",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "main",
+          "values": {"bucket": "main"}
+        }
+      ]
+    }
+  }
+}`
+)
@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// Ensure ValidatedURLDataSource satisfies various data source interfaces.
+var _ datasource.DataSource = &ValidatedURLDataSource{}
+
+func NewValidatedURLDataSource() datasource.DataSource {
+	return &ValidatedURLDataSource{}
+}
+
+// ValidatedURLDataSource defines the data source implementation.
+type ValidatedURLDataSource struct {
+	compiler    *schemaCompiler
+	yamlDecoder yamlDecoder
+	client      *http.Client
+}
+
+// ValidatedURLDataSourceModel describes the data source data model.
+type ValidatedURLDataSourceModel struct {
+	URL            types.String `tfsdk:"url"`
+	Schema         types.String `tfsdk:"schema"`
+	RequestHeaders types.Map    `tfsdk:"request_headers"`
+	Content        types.String `tfsdk:"content"`
+	Decoded        types.String `tfsdk:"decoded"`
+	ETag           types.String `tfsdk:"etag"`
+	SHA256         types.String `tfsdk:"sha256"`
+}
+
+func (d *ValidatedURLDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validated_url"
+}
+
+func (d *ValidatedURLDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "A single YAML or JSON document fetched over HTTP and validated against a json schema, " +
+			"to gate configuration managed outside of Terraform before it is consumed. " +
+			"Responses other than `200 OK` fail, like documents that do not conform to the schema. " +
+			"The `etag` and `sha256` of the document change with its content, e.g. to trigger replacements.",
+
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				MarkdownDescription: "`http://` or `https://` URL of the document",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http:// or https:// URL"),
+				},
+			},
+			"schema": schema.StringAttribute{
+				Description: "Path or URL of the json schema the document is validated against",
+				Required:    true,
+			},
+			"request_headers": schema.MapAttribute{
+				MarkdownDescription: "Headers of the request, e.g. `Authorization`",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"content": schema.StringAttribute{
+				Description: "Raw content of the document",
+				Computed:    true,
+			},
+			"decoded": schema.StringAttribute{
+				Description: "JSON encoded validated document",
+				Computed:    true,
+			},
+			"etag": schema.StringAttribute{
+				MarkdownDescription: "`ETag` header of the response, empty if the server sends none",
+				Computed:            true,
+			},
+			"sha256": schema.StringAttribute{
+				Description: "Hex encoded SHA-256 digest of the content",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ValidatedURLDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+	d.yamlDecoder = providerData.YAMLDecoder
+	d.client = &http.Client{Timeout: httpLoadTimeout}
+}
+
+func (d *ValidatedURLDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ValidatedURLDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schemaPath := data.Schema.ValueString()
+
+	compiledSchema, err := d.compiler.Compile(schemaPath)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("schema"),
+			"Error compiling schema",
+			"Could not compile schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	headers := make(map[string]string)
+	if !data.RequestHeaders.IsNull() {
+		resp.Diagnostics.Append(data.RequestHeaders.ElementsAs(ctx, &headers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	documentURL := data.URL.ValueString()
+
+	content, etag, err := d.fetch(ctx, documentURL, headers)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Error fetching document",
+			"Could not fetch document "+documentURL+": "+err.Error(),
+		)
+		return
+	}
+
+	value, err := d.yamlDecoder.decode(content)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Error decoding document",
+			"Could not decode document "+documentURL+": "+err.Error(),
+		)
+		return
+	}
+
+	if err := compiledSchema.Validate(value); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("url"),
+			"Error validating document",
+			"Document "+documentURL+" does not conform to schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	decoded, err := json.Marshal(value)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error encoding document",
+			"Could not encode document "+documentURL+": "+err.Error(),
+		)
+		return
+	}
+
+	sum := sha256.Sum256(content)
+
+	data.Content = types.StringValue(string(content))
+	data.Decoded = types.StringValue(string(decoded))
+	data.ETag = types.StringValue(etag)
+	data.SHA256 = types.StringValue(hex.EncodeToString(sum[:]))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// fetch returns the content and the ETag of the document at documentURL.
+func (d *ValidatedURLDataSource) fetch(ctx context.Context, documentURL string, headers map[string]string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s returned %s", req.URL.Redacted(), resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err = decodeText(content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return content, resp.Header.Get("ETag"), nil
+}
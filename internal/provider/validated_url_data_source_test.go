@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidatedURL(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	err := os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/valid.yaml":
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte("id: config-id\nname: Config\n"))
+		case "/invalid.json":
+			_, _ = w.Write([]byte(`{"id": "config-id"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedURLDataSourceConfig, "file:///etc/config.yaml", schemaPath, "token"),
+				ExpectError: regexp.MustCompile(`must be an http:// or https:// URL`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedURLDataSourceConfig, server.URL+"/valid.yaml", schemaPath, "token"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_url.test",
+						tfjsonpath.New("content"),
+						knownvalue.StringExact("id: config-id\nname: Config\n"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_url.test",
+						tfjsonpath.New("decoded"),
+						knownvalue.StringExact(`{"id":"config-id","name":"Config"}`),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_url.test",
+						tfjsonpath.New("etag"),
+						knownvalue.StringExact(`"v1"`),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_url.test",
+						tfjsonpath.New("sha256"),
+						knownvalue.StringExact("894ce7304677fc1669a6d6e02e3712114eba7986223b5f70bb9bc24e919d56e5"),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedURLDataSourceConfig, server.URL+"/invalid.json", schemaPath, "token"),
+				ExpectError: regexp.MustCompile(`Error validating document`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedURLDataSourceConfig, server.URL+"/valid.yaml", schemaPath, "wrong"),
+				ExpectError: regexp.MustCompile(`401 Unauthorized`),
+			},
+		},
+	})
+}
+
+const testAccValidatedURLDataSourceConfig = `
+data "jsonschema_validated_url" "test" {
+  url    = "%s"
+  schema = "%s"
+
+  request_headers = {
+    Authorization = "Bearer %s"
+  }
+}
+`
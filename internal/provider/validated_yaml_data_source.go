@@ -4,21 +4,60 @@
 package provider
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
+	"filippo.io/age"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/santhosh-tekuri/jsonschema/v6"
-	"gopkg.in/yaml.v3"
-	"io"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/text/unicode/norm"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
-var schemaRegex = regexp.MustCompile(`# yaml-language-server: \$schema=(.+)`)
+// schemaRegex matches the schema reference of a modeline, which ends before
+// trailing whitespace such as the CR of CRLF line endings.
+var schemaRegex = regexp.MustCompile(`# yaml-language-server: \$schema=([^\r\n]*[^\s])`)
+
+const (
+	syntaxYAML = "yaml"
+	syntaxJSON = "json"
+	syntaxAuto = "auto"
+)
+
+const (
+	emptyFileError = "error"
+	emptyFileSkip  = "skip"
+	emptyFileNull  = "null"
+)
+
+// frontMatterExtensions lists the extensions of Markdown files, of which
+// only the YAML front matter is validated.
+var frontMatterExtensions = []string{".md", ".markdown"}
+
+// tfvarsJSONExtension is the extension of Terraform variable files in JSON
+// syntax, which are always parsed as JSON.
+const tfvarsJSONExtension = ".tfvars.json"
 
 func NewValidatedYAMLDataSource() datasource.DataSource {
 	return &ValidatedYAMLDataSource{}
@@ -26,13 +65,126 @@ func NewValidatedYAMLDataSource() datasource.DataSource {
 
 // ValidatedYAMLDataSource defines the data source implementation.
 type ValidatedYAMLDataSource struct {
-	compiler *jsonschema.Compiler
+	compiler      *schemaCompiler
+	ageIdentities []age.Identity
+	vault         *vaultClient
+	kv            *kvDocuments
+	tracing       *tracing
+	yamlDecoder   yamlDecoder
 }
 
 // ValidatedYAMLDataSourceModel describes the data source data model.
 type ValidatedYAMLDataSourceModel struct {
-	InputPattern types.String `tfsdk:"input_pattern"`
-	Values       types.Map    `tfsdk:"values"`
+	InputPattern    types.String `tfsdk:"input_pattern"`
+	WaitFor         types.Map    `tfsdk:"wait_for"`
+	Schemas         types.List   `tfsdk:"schemas"`
+	SchemaOverlay   types.String `tfsdk:"schema_overlay"`
+	Suppressions    types.List   `tfsdk:"suppressions"`
+	References      types.List   `tfsdk:"references"`
+	AggregateSchema types.String `tfsdk:"aggregate_schema"`
+	AggregateShape  types.String `tfsdk:"aggregate_shape"`
+	ValidationCache types.String `tfsdk:"validation_cache"`
+	BaselineFile    types.String `tfsdk:"baseline_file"`
+	Preset          types.String `tfsdk:"preset"`
+	Encoding        types.String `tfsdk:"encoding"`
+	TemplateVars    types.Map    `tfsdk:"template_vars"`
+	ExpandEnv       types.Bool   `tfsdk:"expand_env"`
+	Env             types.Map    `tfsdk:"env"`
+	ProcessEnv      types.Bool   `tfsdk:"process_env"`
+	Values          types.Map    `tfsdk:"values"`
+	ValuesJSON      types.Map    `tfsdk:"values_json"`
+	ValuesYAML      types.Map    `tfsdk:"values_yaml"`
+	Flattened       types.Map    `tfsdk:"flattened"`
+	FlattenSep      types.String `tfsdk:"flatten_separator"`
+	SensitiveValues types.Map    `tfsdk:"sensitive_values"`
+	DocumentsList   types.List   `tfsdk:"documents_list"`
+	FailOnInvalid   types.Bool   `tfsdk:"fail_on_invalid"`
+	MatchedFiles    types.List   `tfsdk:"matched_files"`
+	ValidFiles      types.List   `tfsdk:"valid_files"`
+	InvalidFiles    types.List   `tfsdk:"invalid_files"`
+	Annotations     types.Map    `tfsdk:"annotations"`
+	Report          types.String `tfsdk:"report"`
+	Mode            types.String `tfsdk:"mode"`
+	SecretDetection types.String `tfsdk:"secret_detection"`
+	MaxFiles        types.Int64  `tfsdk:"max_files"`
+	MaxFileSize     types.Int64  `tfsdk:"max_file_size"`
+	MaxTotalSize    types.Int64  `tfsdk:"max_total_size"`
+	SchemaRoots     types.List   `tfsdk:"schema_roots"`
+	FSOverrides     types.Map    `tfsdk:"fs_overrides"`
+	KeyFormat       types.String `tfsdk:"key_format"`
+	Extensions      types.List   `tfsdk:"extensions"`
+	IncludeHidden   types.Bool   `tfsdk:"include_hidden"`
+	Syntax          types.String `tfsdk:"syntax"`
+	YAMLTimestamps  types.Bool   `tfsdk:"yaml_timestamps"`
+	YAMLVersion     types.String `tfsdk:"yaml_version"`
+	EmptyFile       types.String `tfsdk:"empty_file_behavior"`
+	Stats           types.Object `tfsdk:"stats"`
+	Sources         types.List   `tfsdk:"sources"`
+	Routes          types.List   `tfsdk:"routes"`
+	ValuesBySchema  types.Map    `tfsdk:"values_by_schema"`
+
+	ListOnly    types.Bool `tfsdk:"list_only"`
+	FileSchemas types.Map  `tfsdk:"file_schemas"`
+
+	ExportResolvedSchema types.Bool `tfsdk:"export_resolved_schema"`
+	ResolvedSchemaJSON   types.Map  `tfsdk:"resolved_schema_json"`
+
+	ExportPropertyCoverage types.Bool `tfsdk:"export_property_coverage"`
+	PropertyCoverageJSON   types.Map  `tfsdk:"property_coverage_json"`
+
+	TraceFileGlob types.String `tfsdk:"trace_file_glob"`
+	Trace         types.Map    `tfsdk:"trace"`
+
+	PreserveComments types.Bool `tfsdk:"preserve_comments"`
+
+	Raw       types.Bool `tfsdk:"raw"`
+	RawValues types.Map  `tfsdk:"raw_values"`
+
+	StripPointers types.List `tfsdk:"strip_pointers"`
+
+	NormalizeLineEndings   types.Bool `tfsdk:"normalize_line_endings"`
+	TrimTrailingWhitespace types.Bool `tfsdk:"trim_trailing_whitespace"`
+	NormalizeUnicode       types.Bool `tfsdk:"normalize_unicode"`
+}
+
+// ValidatedYAMLDocumentModel describes a single document of a multi-document YAML file.
+type ValidatedYAMLDocumentModel struct {
+	File    types.String `tfsdk:"file"`
+	Index   types.Int64  `tfsdk:"index"`
+	Content types.String `tfsdk:"content"`
+}
+
+var validatedYAMLDocumentAttrTypes = map[string]attr.Type{
+	"file":    types.StringType,
+	"index":   types.Int64Type,
+	"content": types.StringType,
+}
+
+// ValidatedYAMLSourceModel describes a group of files validated against the
+// same schema.
+type ValidatedYAMLSourceModel struct {
+	Pattern types.String `tfsdk:"pattern"`
+	Schema  types.String `tfsdk:"schema"`
+	Syntax  types.String `tfsdk:"syntax"`
+}
+
+// ValidatedYAMLStatsModel describes the cost of a validation.
+type ValidatedYAMLStatsModel struct {
+	FilesMatched    types.Int64  `tfsdk:"files_matched"`
+	BytesRead       types.Int64  `tfsdk:"bytes_read"`
+	SchemasCompiled types.Int64  `tfsdk:"schemas_compiled"`
+	DurationMs      types.Int64  `tfsdk:"duration_ms"`
+	SlowestFile     types.String `tfsdk:"slowest_file"`
+	SlowestFileMs   types.Int64  `tfsdk:"slowest_file_ms"`
+}
+
+var validatedYAMLStatsAttrTypes = map[string]attr.Type{
+	"files_matched":    types.Int64Type,
+	"bytes_read":       types.Int64Type,
+	"schemas_compiled": types.Int64Type,
+	"duration_ms":      types.Int64Type,
+	"slowest_file":     types.StringType,
+	"slowest_file_ms":  types.Int64Type,
 }
 
 func (d *ValidatedYAMLDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -42,18 +194,532 @@ func (d *ValidatedYAMLDataSource) Metadata(ctx context.Context, req datasource.M
 func (d *ValidatedYAMLDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "YAML files validated against a json schema",
+		MarkdownDescription: "YAML files validated against a json schema. " +
+			"For Markdown files (`.md`, `.markdown`) only the YAML front matter between the leading `---` fences is validated.",
 
 		Attributes: map[string]schema.Attribute{
 			"input_pattern": schema.StringAttribute{
-				Description: "Directory containing YAML files to validate",
-				Required:    true,
+				MarkdownDescription: "Glob pattern of the YAML files to validate, a directory whose files with one of the `extensions` are validated recursively, " +
+					"files inside a zip or tar archive, optionally gzip compressed, with the archive and a pattern of its entries separated by `//` like `bundle.tar.gz//configs/*.yaml`, " +
+					"a `vault://mount/path#field` reference to a single document stored in Vault KV, " +
+					"a `consul://key` or `etcd://key` reference to a value of a KV store, or a `ssm:///path/name` parameter of SSM Parameter Store or `secretsmanager://name` secret of Secrets Manager, " +
+					"where references ending with a slash like `consul://prefix/` or `ssm:///app/prod/` validate every value below the prefix. " +
+					"Documents read from AWS are only exposed in `sensitive_values`. Defaults to the files of the `preset`, may be omitted if `sources` are set.",
+				Optional: true,
+				Computed: true,
+			},
+			"preset": schema.StringAttribute{
+				MarkdownDescription: "Validate well-known files against their SchemaStore schema, which files without a schema reference are validated against. " +
+					"`github-workflow` for GitHub Actions workflows, with `input_pattern` defaulting to `.github/workflows`. " +
+					"`compose` for Compose files, with `input_pattern` defaulting to `*compose*.y*ml`, e.g. `compose.yaml` or `docker-compose.prod.yml`, " +
+					"validated against the Compose Specification or, if they declare a `version` of the legacy `2.x` and `3.x` file formats, against the schema of the version. " +
+					"`argocd` for Argo CD `Application` and `ApplicationSet` manifests, with `input_pattern` defaulting to `apps`. " +
+					"`flux` for Flux `Kustomization` and `HelmRelease` manifests, with `input_pattern` defaulting to `clusters`. " +
+					"Manifests of other kinds are not validated by the `argocd` and `flux` presets, whose schemas are bundled with the provider. " +
+					"`renovate` for Renovate configuration, with `input_pattern` defaulting to `renovate.json`, validated against the schema published by Renovate at `https://docs.renovatebot.com`. " +
+					"`dependabot` for Dependabot configuration, with `input_pattern` defaulting to `.github/dependabot.y*ml`. " +
+					"The other schemas are loaded from `https://json.schemastore.org` and, for legacy Compose files, the `v1` branch of `docker/compose`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(presetNames()...),
+				},
+			},
+			"wait_for": schema.MapAttribute{
+				MarkdownDescription: "Values of the resources that generate the input files, e.g. the `id` of a `local_file`. " +
+					"While any value is unknown, e.g. because the resource is created in the same apply, the files are read at apply time instead of plan time " +
+					"and the attributes of the data source are unknown until then. Unlike `depends_on`, only changes of these values defer the read.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"schemas": schema.ListAttribute{
+				MarkdownDescription: "Paths or URLs of json schemas every document is validated against in addition to the schema the file references, " +
+					"as if they were combined with `allOf`, e.g. an organization wide base schema and the schema of a service. " +
+					"Files do not need to reference a schema if set. Relative paths are resolved against the working directory.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"schema_overlay": schema.StringAttribute{
+				MarkdownDescription: "JSON object deep merged onto the schema referenced by each file before it is compiled, " +
+					"e.g. `jsonencode({ required = [\"owner\"] })` to tighten a shared schema per environment. " +
+					"Objects are merged by keyword and `null` removes a keyword, arrays like `required` and `enum` are extended with the values they do not contain yet, " +
+					"any other value replaces the one of the schema. The schemas of `schemas` and referenced by `$ref` are not changed.",
+				Optional: true,
+			},
+			"syntax": schema.StringAttribute{
+				MarkdownDescription: "Syntax of the files, `yaml` (default), `json` or `auto` to parse files with the `.json` extension, " +
+					"or content starting with `{` or `[`, as JSON and anything else as YAML. " +
+					"JSON files reference their schema with the `$schema` property instead of a modeline, which is validated like any other property. " +
+					"Terraform variable files (`.tfvars.json`, including `.auto.tfvars.json`) are always parsed as JSON and validated without the `//` comment and `$schema` properties, " +
+					"which Terraform does not read as variables, e.g. with a schema of the variables in `schemas`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(syntaxYAML, syntaxJSON, syntaxAuto),
+				},
+			},
+			"extensions": schema.ListAttribute{
+				MarkdownDescription: "Extensions of the files to validate, e.g. `[\".yaml\", \".yml\"]` or `[\".tfvars.json\"]`, compared case-insensitively. " +
+					"Files matched by a glob `input_pattern` with other extensions are skipped, all files are validated if unset. " +
+					"If `input_pattern` is a directory, defaults to `[\".yaml\", \".yml\"]`.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"include_hidden": schema.BoolAttribute{
+				MarkdownDescription: "Validate hidden files and the files of hidden directories, whose names start with a dot like `.git` or `.cache`, defaults to `false`. " +
+					"Names a glob `input_pattern` matches with a dot explicitly, e.g. `.github/workflows/*.yml`, are never hidden.",
+				Optional: true,
+			},
+			"encoding": encodingAttribute(),
+			"key_format": schema.StringAttribute{
+				MarkdownDescription: "Keys of `values`, `sensitive_values`, `raw_values` and `annotations`, the path of the file as matched by default. " +
+					"`absolute` for the absolute path, `relative` for the path relative to the directory of `input_pattern` before the first glob character, " +
+					"`basename` for the file name, or a regular expression matched against the path whose capture groups, joined by `/`, are the key, " +
+					"e.g. `envs/([^/]+)/values\\.yaml$` for the name of the environment. Files must not share a key.",
+				Optional: true,
+			},
+			"fs_overrides": schema.MapAttribute{
+				MarkdownDescription: "Map of file paths to content read instead of the file on disk, matched by `input_pattern` whether the file exists or not, " +
+					"e.g. to test modules with `terraform test` without creating files. Schemas are always read from their location.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"schema_roots": schema.ListAttribute{
+				MarkdownDescription: "Directories searched in order for schemas referenced by a relative path that does not exist next to the file, " +
+					"e.g. `[\"schemas\", \"vendor/schemas\"]` for a central schema directory of a monorepo",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"max_files": schema.Int64Attribute{
+				Description: "Maximum number of matched files, more files abort the read before any file is read, e.g. if a pattern matches dependencies by mistake",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_file_size": schema.Int64Attribute{
+				Description: "Maximum size in bytes of a single matched file, larger files abort the read before any file is validated",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_total_size": schema.Int64Attribute{
+				Description: "Maximum size in bytes of all matched files together, larger inputs abort the read before any file is validated",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"template_vars": schema.MapAttribute{
+				MarkdownDescription: "Variables to render files with as Go templates before validation, e.g. `{{ .env }}`. " +
+					"Files are not rendered if unset.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"expand_env": schema.BoolAttribute{
+				MarkdownDescription: "Substitute `${VAR}` references, including the `${VAR:-default}` and `${VAR:?message}` forms " +
+					"of docker compose, with the values of `env` before validation. Use `$$` for a literal `$`.",
+				Optional: true,
+			},
+			"env": schema.MapAttribute{
+				MarkdownDescription: "Variables substituted when `expand_env` is set",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"process_env": schema.BoolAttribute{
+				MarkdownDescription: "Fall back to the environment of the provider process for variables missing from `env`",
+				Optional:            true,
+			},
+			"yaml_timestamps": schema.BoolAttribute{
+				MarkdownDescription: "Allow unquoted YAML timestamps like `2024-01-02`, which are validated as strings, defaults to `true`. " +
+					"If `false`, unquoted timestamps are rejected, so dates have to be quoted like other strings.",
+				Optional: true,
+			},
+			"yaml_version": schema.StringAttribute{
+				MarkdownDescription: "YAML version whose rules type unquoted scalars, so documents are validated like the parsers of the applications reading them do. " +
+					"`1.1` decodes `yes`, `no`, `on`, `off`, `y` and `n` as booleans and `0o17` as a string, `1.2` decodes them as strings and `0644` as the decimal 644. " +
+					"By default `yes` and the other YAML 1.1 booleans are strings and both `0644` and `0o17` are octal. Quoted and tagged scalars like `!!str yes` keep their type. " +
+					"Outputs with the documents as YAML, like `values`, keep the scalars as they are written.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(yamlVersion11, yamlVersion12),
+				},
+			},
+			"empty_file_behavior": schema.StringAttribute{
+				MarkdownDescription: "Handling of empty files, which contain no documents or only null documents, e.g. placeholder files of overlays: " +
+					"`error` (default) fails the file, `skip` leaves the file out of the outputs without validating it " +
+					"and `null` validates the file as a single null document, e.g. against a schema allowing `null`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(emptyFileError, emptyFileSkip, emptyFileNull),
+				},
+			},
+			"raw": schema.BoolAttribute{
+				MarkdownDescription: "Expose the exact content of the valid files in `raw_values`",
+				Optional:            true,
+			},
+			"strip_pointers": schema.ListAttribute{
+				MarkdownDescription: "JSON pointers of values removed from the documents after they are validated, e.g. `/internal` or `/spec/debug`, " +
+					"so only the public part of the documents ends up in `values`, `values_json`, `values_yaml`, `flattened`, `sensitive_values` and `documents_list`. " +
+					"Pointers to values a document does not contain are ignored. Documents with removed values are re-encoded, " +
+					"YAML documents keep their comments with aliases and merge keys (`<<`) expanded. Conflicts with `raw`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(`^/`), "must be a JSON pointer starting with /")),
+					listvalidator.ConflictsWith(path.MatchRoot("raw")),
+				},
+			},
+			"raw_values": schema.MapAttribute{
+				MarkdownDescription: "Map of file paths to the exact content of the file including the schema reference, only set if `raw` is `true`, e.g. for checksums. " +
+					"Files that are not valid UTF-8 are listed after decoding, files in `sensitive_values` are not listed.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"list_only": schema.BoolAttribute{
+				MarkdownDescription: "Only list the files matched by `input_pattern` and `sources` in `matched_files` and the schemas they would be validated against in `file_schemas`, " +
+					"without compiling schemas or decoding and validating documents, e.g. to check patterns and schema mappings before enforcing them. " +
+					"Files are still read to find the schemas they reference. `valid_files`, `invalid_files` and the values are empty.",
+				Optional: true,
+			},
+			"file_schemas": schema.MapAttribute{
+				Description: "Map of file paths to the schemas the file is validated against, the schema the file references first",
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+			},
+			"export_resolved_schema": schema.BoolAttribute{
+				MarkdownDescription: "Expose the schemas the files are validated against in `resolved_schema_json`, e.g. to debug why a document fails when the schema on disk looks fine",
+				Optional:            true,
+			},
+			"resolved_schema_json": schema.MapAttribute{
+				MarkdownDescription: "Map of the schemas the files are validated against to the JSON encoded schema as it is compiled, after `ignore_keywords` and `schema_overlay` are applied, " +
+					"with every `$ref` replaced by the referenced subschema merged with the keywords next to the `$ref`. " +
+					"References that cannot be inlined, e.g. cycles or anchors, are kept with absolute URLs. Only set if `export_resolved_schema` is `true`.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"export_property_coverage": schema.BoolAttribute{
+				MarkdownDescription: "Report in `property_coverage_json` how the valid files cover the properties of their schemas, e.g. to prune properties no file sets or tighten `additionalProperties`",
+				Optional:            true,
+			},
+			"property_coverage_json": schema.MapAttribute{
+				MarkdownDescription: "Map of the schemas the files are validated against to the JSON encoded property coverage of the valid files, " +
+					"with `unused_properties`, the locations of the properties no document sets, like `#/properties/legacy_id`, " +
+					"and `additional_keys`, the `file`, `document` and `pointer` of the keys of objects declaring properties that no property or pattern property matches, " +
+					"i.e. that fall through to `additionalProperties`. Files in `sensitive_values` are left out. Only set if `export_property_coverage` is `true`.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"trace_file_glob": schema.StringAttribute{
+				MarkdownDescription: "Glob pattern of the files to trace in `trace`, matched against the path of the file with forward slashes, or against the file name if it does not contain a `/`. " +
+					"Set `fail_on_invalid` to `false` to read the trace of invalid files.",
+				Optional: true,
+			},
+			"trace": schema.MapAttribute{
+				MarkdownDescription: "Map of the files matched by `trace_file_glob` to the JSON encoded list of the `if`, `then` and `else` subschemas and the `anyOf` and `oneOf` branches evaluated for their documents, " +
+					"with the `schema`, `document` and `pointer` of the value, the `keyword`, the `branch` by its title or location, whether it is `valid` and the `errors` it failed with, like `'/engine/type': value must be 'mysql'`. " +
+					"Only the `then` or `else` chosen by the `if` is evaluated. Files in `sensitive_values` are not traced.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"normalize_line_endings": schema.BoolAttribute{
+				MarkdownDescription: "Convert CRLF and CR line endings to LF in `values`, `sensitive_values` and `documents_list`, " +
+					"so checkouts with different line endings produce the same state",
+				Optional: true,
+			},
+			"trim_trailing_whitespace": schema.BoolAttribute{
+				MarkdownDescription: "Remove trailing spaces and tabs from every line in `values`, `sensitive_values` and `documents_list`",
+				Optional:            true,
+			},
+			"normalize_unicode": schema.BoolAttribute{
+				MarkdownDescription: "Normalize the content of the files to Unicode NFC before validation, so keys and values written decomposed (NFD), " +
+					"e.g. by macOS, validate and appear in the outputs like their composed equivalents",
+				Optional: true,
 			},
 			"values": schema.MapAttribute{
 				Description: "Map of file paths to validated YAML content",
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+			"values_json": schema.MapAttribute{
+				MarkdownDescription: "Map of file paths to the validated documents encoded as JSON for `jsondecode`, a list of the documents if the file contains multiple documents. " +
+					"Documents may be of any kind, e.g. lists or scalars, `documents_list` tells a file with multiple documents from a file with a list. " +
+					"Integers and decimals are encoded exactly as written, so 64-bit IDs keep their precision. Files in `sensitive_values` are not listed.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"values_yaml": schema.MapAttribute{
+				MarkdownDescription: "Map of the paths of YAML files to the validated documents re-encoded as YAML, with aliases and merge keys (`<<`) expanded as they are validated, " +
+					"e.g. to write the result back to a repository. Comments are only kept if `preserve_comments` is `true`. " +
+					"JSON files and files in `sensitive_values` are not listed.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"flattened": schema.MapAttribute{
+				MarkdownDescription: "Map of file paths to the scalar values of the validated documents by their path, e.g. `{\"db.port\" = \"5432\"}`, " +
+					"to write them to key-value stores like Consul KV or SSM Parameter Store with `for_each`. " +
+					"Paths join the keys and list indexes with `flatten_separator`, starting with the index of the document if the file contains multiple documents. " +
+					"Strings are kept as they are, other scalars and empty objects and lists are encoded as JSON. Values with the same path, e.g. of the keys `db.port` and `port` of `db`, are an error. " +
+					"Files in `sensitive_values` are not listed.",
+				Computed:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+			},
+			"flatten_separator": schema.StringAttribute{
+				MarkdownDescription: "Separator of the keys and indexes of the paths in `flattened`, `.` (default) or `/`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(".", "/"),
+				},
+			},
+			"preserve_comments": schema.BoolAttribute{
+				MarkdownDescription: "Keep the comments of YAML files in `values_yaml`, defaults to `false`",
+				Optional:            true,
+			},
+			"sensitive_values": schema.MapAttribute{
+				MarkdownDescription: "Map of file paths to validated YAML content of age encrypted files (`.age` extension), " +
+					"which are decrypted with the `age_identities` of the provider, of documents read from Vault, SSM Parameter Store and Secrets Manager, " +
+					"and of files containing secrets if `secret_detection` is `sensitive`. " +
+					"Validation errors of these files name the locations and keywords of the violations, not the values.",
+				Computed:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "Direction the documents are used in, `read` rejects values marked `writeOnly` by the schema and `write` rejects values marked `readOnly`. " +
+					"Neither is enforced if unset.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(accessModeRead, accessModeWrite),
+				},
+			},
+			"secret_detection": schema.StringAttribute{
+				MarkdownDescription: "Handling of values marked as secrets by the schema with `writeOnly: true` or `x-secret: true`. " +
+					"`error` rejects the documents, e.g. if secrets must not be committed to the files, " +
+					"and `sensitive` exposes the files in `sensitive_values` instead of the other outputs, like encrypted files. " +
+					"Secrets are not detected if unset.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(secretDetectionError, secretDetectionSensitive),
+				},
+			},
+			"fail_on_invalid": schema.BoolAttribute{
+				MarkdownDescription: "Fail when a file cannot be read or does not conform to its schema, defaults to `true`. " +
+					"If `false`, errors are reported as warnings, invalid files are left out of the other outputs and listed in `invalid_files`.",
+				Optional: true,
+			},
+			"matched_files": schema.ListAttribute{
+				MarkdownDescription: "Paths of the files matched by `input_pattern` and `sources`, valid or not, to check that a pattern matches the intended files",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"valid_files": schema.ListAttribute{
+				Description: "Paths of the files that passed validation",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"invalid_files": schema.ListAttribute{
+				MarkdownDescription: "Paths of the files that failed validation, only ever non-empty if `fail_on_invalid` is `false`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"annotations": schema.MapAttribute{
+				MarkdownDescription: "Map of file paths to the JSON encoded custom `x-*` keywords of the subschemas matched by the documents of the file, " +
+					"a list with an object per document mapping the JSON pointer of each annotated value to its keywords, e.g. `{\"/owner\": {\"x-owner\": \"platform\"}}`. " +
+					"Files in `sensitive_values` are not listed.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"report": schema.StringAttribute{
+				MarkdownDescription: "JSON encoded report of the validation, `findings` lists violations and warnings such as the use of values marked `deprecated` " +
+					"as objects with the `file`, the index of the `document`, the JSON `pointer` of the value, the `keyword`, a `message` and the `severity` (`error` or `warning`), `suppressed` and `baselined` are set for violations downgraded by `suppressions` and `baseline_file`. " +
+					"`matches` lists the `anyOf` and `oneOf` branches matched by the values of valid documents, the `branch` is identified by its `title` or else its schema location. " +
+					"If the provider asserts `content`, `contents` lists the values of valid documents with a `contentEncoding` with their `encoding`, `media_type`, decoded `length` and the `sha256` digest of the decoded bytes. " +
+					"Violations are only reported if `fail_on_invalid` is `false`, files in `sensitive_values` are not reported.",
+				Computed: true,
+			},
+			"suppressions": schema.ListNestedAttribute{
+				MarkdownDescription: "Known violations downgraded to warnings, e.g. long-standing issues that should not block adoption. " +
+					"A violation is suppressed if every attribute of a rule matches it, a document whose violations are all suppressed is valid. " +
+					"Suppressed violations are listed in `report` with the `warning` severity and `suppressed` set.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"file_glob": schema.StringAttribute{
+							MarkdownDescription: "Glob pattern matched against the path of the file with forward slashes, or against the file name if it does not contain a `/`",
+							Required:            true,
+						},
+						"pointer": schema.StringAttribute{
+							MarkdownDescription: "JSON pointer of the violating value, e.g. `/spec/replicas`, values below it are matched too. All values if unset.",
+							Optional:            true,
+						},
+						"keyword": schema.StringAttribute{
+							MarkdownDescription: "Violated keyword, e.g. `required` or `additionalProperties`. All keywords if unset.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"references": schema.ListNestedAttribute{
+				MarkdownDescription: "References of the documents to the documents of other files, like foreign keys, e.g. the `/team` of every service has to be the `/id` of a team. " +
+					"A reference is broken if no document of the files matched by `target_pattern` has the same value at `target_pointer`, " +
+					"each item of a list is a reference of its own and documents without a value at `pointer` reference nothing. " +
+					"The references of the valid files are checked after every file is validated, broken references fail like violations or are reported as warnings " +
+					"if `fail_on_invalid` is `false`, and are listed in `report` with the `references` keyword, so they can be suppressed and baselined.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"pointer": schema.StringAttribute{
+							MarkdownDescription: "JSON pointer of the referencing value of the documents, e.g. `/team`",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^/`), "must be a JSON pointer starting with /"),
+							},
+						},
+						"target_pattern": schema.StringAttribute{
+							MarkdownDescription: "Glob pattern of the YAML or JSON files with the referenced documents, e.g. `teams/*.yaml`, which are not validated",
+							Required:            true,
+						},
+						"target_pointer": schema.StringAttribute{
+							MarkdownDescription: "JSON pointer of the referenced value of the target documents, e.g. `/id`",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^/`), "must be a JSON pointer starting with /"),
+							},
+						},
+					},
+				},
+			},
+			"aggregate_schema": schema.StringAttribute{
+				MarkdownDescription: "Path or URL of a json schema the documents of all valid files are validated against together, once every file is validated, " +
+					"for rules of the whole set like `maxItems` or at most one document with `tier: critical`. " +
+					"Violations fail like the violations of a file or are reported as warnings if `fail_on_invalid` is `false`, and are listed in `report` " +
+					"for the file and document they are located in, or with an empty `file` and the pointer into the aggregate for violations of the aggregate as a whole.",
+				Optional: true,
+			},
+			"aggregate_shape": schema.StringAttribute{
+				MarkdownDescription: "Shape of the value validated against `aggregate_schema`: `list` of all documents in the order of the files, the default, " +
+					"or `files`, an object mapping the key of each file like in `values_json` to its document, or to the list of its documents if it has more than one",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(aggregateShapeList, aggregateShapeFiles),
+					stringvalidator.AlsoRequires(path.MatchRoot("aggregate_schema")),
+				},
+			},
+			"validation_cache": schema.StringAttribute{
+				MarkdownDescription: "Path of the cache file of a `jsonschema_validation_cache` resource, to skip validating the documents of files the cache records as valid with the same path, content and schemas. " +
+					"Set the same path as the resource instead of a reference to its `path`, data sources depending on a resource with planned changes are only read on apply. " +
+					"Skipped files are still decoded and included in every output. " +
+					"Files whose schema is changed by `schema_overlay`, files of data sources setting `yaml_version` or `yaml_timestamps` and files recorded with other settings of the provider, e.g. `formats`, are always validated, " +
+					"and every file is validated until the resource is applied and writes the cache.",
+				Optional: true,
+			},
+			"baseline_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a JSON file with previously recorded violations, e.g. the `report` of a validation with `fail_on_invalid = false` written to a file. " +
+					"Violations recorded in the baseline are downgraded to warnings, so only new violations fail. " +
+					"Violations are compared by `file`, `document`, `pointer` and `keyword`, baselined violations are listed in `report` with `baselined` set.",
+				Optional: true,
+			},
+			"sources": schema.ListNestedAttribute{
+				MarkdownDescription: "Groups of files validated in addition to the files of `input_pattern`, each against a schema of its own, " +
+					"so one data source can validate files of different kinds. The files of a group are treated like the files of `input_pattern` otherwise, " +
+					"a file must not be matched by more than one group or by `input_pattern` too.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"pattern": schema.StringAttribute{
+							MarkdownDescription: "Glob pattern of the files of the group or a directory, like `input_pattern`",
+							Required:            true,
+						},
+						"schema": schema.StringAttribute{
+							MarkdownDescription: "Path or URL of the json schema the documents of the group are validated against in addition to `schemas` and the schema the file references. " +
+								"Files do not need to reference a schema if set.",
+							Optional: true,
+						},
+						"syntax": schema.StringAttribute{
+							MarkdownDescription: "Syntax of the files of the group like `syntax`, which it defaults to",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(syntaxYAML, syntaxJSON, syntaxAuto),
+							},
+						},
+					},
+				},
+			},
+			"routes": schema.ListNestedAttribute{
+				MarkdownDescription: "Kinds of documents the files are classified as, so a single pattern can cover files of different kinds, e.g. the deployments and services of a directory. " +
+					"Each file is validated against the schema of the first route all of its documents conform to, in addition to `schemas` and the schema the file references, " +
+					"and is listed in `values_by_schema` under the name of the route. Files do not need to reference a schema if set. " +
+					"Files conforming to none of the schemas are invalid.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the route, the key of its files in `values_by_schema`",
+							Required:            true,
+						},
+						"schema": schema.StringAttribute{
+							Description: "Path or URL of the json schema the documents of the files of the route conform to",
+							Required:    true,
+						},
+					},
+				},
+			},
+			"values_by_schema": schema.MapAttribute{
+				MarkdownDescription: "Map of the names of `routes` to the valid files classified by the route, mapping file paths to content like `values`, e.g. to create a resource per kind of document with `for_each`. " +
+					"Every route is listed, also if no file was classified by it, files in `sensitive_values` are not listed.",
+				Computed:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+			},
+			"stats": schema.SingleNestedAttribute{
+				MarkdownDescription: "Cost of the validation, e.g. to track it over time with outputs. Durations are measured on every read, so they differ between plans.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"files_matched": schema.Int64Attribute{
+						MarkdownDescription: "Number of files matched by `input_pattern`",
+						Computed:            true,
+					},
+					"bytes_read": schema.Int64Attribute{
+						Description: "Number of bytes read from the matched files, before decryption and decoding",
+						Computed:    true,
+					},
+					"schemas_compiled": schema.Int64Attribute{
+						Description: "Number of distinct schemas compiled to validate the files, including schemas compiled before by other data sources and reused",
+						Computed:    true,
+					},
+					"duration_ms": schema.Int64Attribute{
+						Description: "Duration of the whole validation in milliseconds",
+						Computed:    true,
+					},
+					"slowest_file": schema.StringAttribute{
+						Description: "Path of the file that took the longest to read and validate",
+						Computed:    true,
+					},
+					"slowest_file_ms": schema.Int64Attribute{
+						MarkdownDescription: "Duration of reading and validating `slowest_file` in milliseconds",
+						Computed:            true,
+					},
+				},
+			},
+			"documents_list": schema.ListNestedAttribute{
+				MarkdownDescription: "Every document of the validated files in order, files may contain multiple documents separated by `---` lines. " +
+					"Documents of files in `sensitive_values` are not listed.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"file": schema.StringAttribute{
+							Description: "Path of the file containing the document",
+							Computed:    true,
+						},
+						"index": schema.Int64Attribute{
+							Description: "Position of the document in the file, starting at 0",
+							Computed:    true,
+						},
+						"content": schema.StringAttribute{
+							Description: "Validated YAML content of the document",
+							Computed:    true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -64,21 +730,29 @@ func (d *ValidatedYAMLDataSource) Configure(ctx context.Context, req datasource.
 		return
 	}
 
-	compiler, ok := req.ProviderData.(*jsonschema.Compiler)
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *jsonschema.Compiler, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.compiler = compiler
+	d.compiler = providerData.Compiler
+	d.compiler.refresh()
+	d.ageIdentities = providerData.AgeIdentities
+	d.vault = providerData.Vault
+	d.kv = providerData.KV
+	d.tracing = providerData.Tracing
+	d.yamlDecoder = providerData.YAMLDecoder
 }
 
 func (d *ValidatedYAMLDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	readStart := time.Now()
+
 	var data ValidatedYAMLDataSourceModel
 
 	// Read Terraform configuration data into the model
@@ -88,114 +762,1534 @@ func (d *ValidatedYAMLDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
-	data.InputPattern = types.StringValue(data.InputPattern.ValueString())
+	var filePreset *preset
+	if !data.Preset.IsNull() {
+		p := presets[data.Preset.ValueString()]
+		filePreset = &p
+		if data.InputPattern.IsNull() {
+			data.InputPattern = types.StringValue(p.inputPattern)
+		}
+	}
+
+	if data.InputPattern.IsNull() && data.Sources.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("input_pattern"),
+			"Missing input pattern",
+			"input_pattern must be set if no preset or sources are used",
+		)
+		return
+	}
+
+	ctx, span := d.tracing.start(ctx, "jsonschema_validated_yaml", attribute.String("input_pattern", data.InputPattern.ValueString()))
+	defer d.tracing.flush(ctx)
+	defer func() { endSpan(span, diagnosticsError(resp.Diagnostics)) }()
+
+	if !data.InputPattern.IsNull() {
+		data.InputPattern = types.StringValue(data.InputPattern.ValueString())
+	}
+
+	var sources []ValidatedYAMLSourceModel
+	if !data.Sources.IsNull() {
+		resp.Diagnostics.Append(data.Sources.ElementsAs(ctx, &sources, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var templateVars map[string]string
+	if !data.TemplateVars.IsNull() {
+		resp.Diagnostics.Append(data.TemplateVars.ElementsAs(ctx, &templateVars, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var env map[string]string
+	if !data.Env.IsNull() {
+		resp.Diagnostics.Append(data.Env.ElementsAs(ctx, &env, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var schemaRoots []string
+	if !data.SchemaRoots.IsNull() {
+		resp.Diagnostics.Append(data.SchemaRoots.ElementsAs(ctx, &schemaRoots, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var schemas []string
+	if !data.Schemas.IsNull() {
+		resp.Diagnostics.Append(data.Schemas.ElementsAs(ctx, &schemas, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var suppressions []SuppressionModel
+	if !data.Suppressions.IsNull() {
+		resp.Diagnostics.Append(data.Suppressions.ElementsAs(ctx, &suppressions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for i, suppression := range suppressions {
+			if _, err := filepath.Match(suppression.FileGlob.ValueString(), ""); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("suppressions").AtListIndex(i).AtName("file_glob"),
+					"Invalid suppression",
+					"Could not parse file_glob "+suppression.FileGlob.ValueString()+": "+err.Error(),
+				)
+			}
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if _, err := filepath.Match(data.TraceFileGlob.ValueString(), ""); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("trace_file_glob"),
+			"Invalid trace_file_glob",
+			"Could not parse trace_file_glob "+data.TraceFileGlob.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	var stripPaths [][]string
+	if !data.StripPointers.IsNull() {
+		var pointers []string
+		resp.Diagnostics.Append(data.StripPointers.ElementsAs(ctx, &pointers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for _, pointer := range pointers {
+			tokens, err := splitPointer(pointer)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("strip_pointers"),
+					"Invalid pointer",
+					"Could not parse pointer "+pointer+": "+err.Error(),
+				)
+				return
+			}
+			stripPaths = append(stripPaths, tokens)
+		}
+	}
+
+	var references []ReferenceModel
+	if !data.References.IsNull() {
+		resp.Diagnostics.Append(data.References.ElementsAs(ctx, &references, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var routes []RouteModel
+	if !data.Routes.IsNull() {
+		resp.Diagnostics.Append(data.Routes.ElementsAs(ctx, &routes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// routes are compiled once, every file is classified by all of them
+	routeSchemas := make([]*jsonschema.Schema, 0, len(routes))
+	valuesBySchemaMap := make(map[string]map[string]string, len(routes))
+	for i, route := range routes {
+		routePath := path.Root("routes").AtListIndex(i)
+		if _, ok := valuesBySchemaMap[route.Name.ValueString()]; ok {
+			resp.Diagnostics.AddAttributeError(
+				routePath.AtName("name"),
+				"Duplicate route",
+				"Route "+route.Name.ValueString()+" is defined more than once, the names of routes must be unique",
+			)
+			return
+		}
+		valuesBySchemaMap[route.Name.ValueString()] = make(map[string]string)
+
+		routeSchema, err := d.compiler.Compile(route.Schema.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				routePath.AtName("schema"),
+				"Error compiling schema",
+				"Could not compile schema "+route.Schema.ValueString()+" of route "+route.Name.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		routeSchemas = append(routeSchemas, routeSchema)
+	}
+
+	var baseline []reportFinding
+	if !data.BaselineFile.IsNull() {
+		var err error
+		baseline, err = readBaseline(data.BaselineFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("baseline_file"),
+				"Error reading baseline",
+				"Could not read baseline "+data.BaselineFile.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	var cachedFiles map[string]string
+	if !data.ValidationCache.IsNull() {
+		cache, err := readValidationCache(data.ValidationCache.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("validation_cache"),
+				"Error reading validation cache",
+				"Could not read validation cache "+data.ValidationCache.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		cachedFiles = cache.Files
+	}
+	cacheHashes := newSchemaHashes(d.compiler)
+
+	var overlay map[string]any
+	if !data.SchemaOverlay.IsNull() {
+		value, err := jsonschema.UnmarshalJSON(strings.NewReader(data.SchemaOverlay.ValueString()))
+		if err == nil {
+			var ok bool
+			if overlay, ok = value.(map[string]any); !ok {
+				err = fmt.Errorf("expected a JSON object, got %s", jsonString(value))
+			}
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schema_overlay"),
+				"Invalid schema overlay",
+				"Could not decode schema_overlay: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	lookupEnv := func(name string) (string, bool) {
+		if value, ok := env[name]; ok {
+			return value, true
+		}
+		if data.ProcessEnv.ValueBool() {
+			return os.LookupEnv(name)
+		}
+		return "", false
+	}
 
-	files, err := filepath.Glob(data.InputPattern.ValueString())
+	decode, err := textDecoder(data.Encoding.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading input files",
-			"Could not read input files: "+err.Error(),
+		resp.Diagnostics.AddAttributeError(
+			path.Root("encoding"),
+			"Invalid encoding",
+			err.Error(),
 		)
 		return
 	}
 
-	if len(files) == 0 {
+	overrides := make(map[string]string)
+	if !data.FSOverrides.IsNull() {
+		var fsOverrides map[string]string
+		resp.Diagnostics.Append(data.FSOverrides.ElementsAs(ctx, &fsOverrides, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for file, content := range fsOverrides {
+			overrides[filepath.ToSlash(filepath.Clean(file))] = content
+		}
+	}
+
+	normalizeOutput := func(content string) string {
+		return normalizeText(content, data.NormalizeLineEndings.ValueBool(), data.TrimTrailingWhitespace.ValueBool())
+	}
+
+	extensions := defaultYAMLExtensions
+	if !data.Extensions.IsNull() {
+		extensions = nil
+		resp.Diagnostics.Append(data.Extensions.ElementsAs(ctx, &extensions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for i, extension := range extensions {
+			if !strings.HasPrefix(extension, ".") {
+				extensions[i] = "." + extension
+			}
+		}
+	}
+
+	// patterns that match nothing only fail if no other pattern is validated
+	multiplePatterns := len(sources) > 1 || (len(sources) > 0 && !data.InputPattern.IsNull())
+
+	var files []string
+	if !data.InputPattern.IsNull() {
+		var patternDiags diag.Diagnostics
+		files = d.inputFiles(ctx, data.InputPattern.ValueString(), extensions, !data.Extensions.IsNull(), data.IncludeHidden.ValueBool(), overrides, &patternDiags)
+		resp.Diagnostics.Append(patternDiagnostics(patternDiags, path.Root("input_pattern"), multiplePatterns)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// fileSources maps the files of sources to the index of their source
+	fileSources := make(map[string]int)
+	for i, source := range sources {
+		sourcePath := path.Root("sources").AtListIndex(i).AtName("pattern")
+
+		var sourceDiags diag.Diagnostics
+		sourceFiles := d.inputFiles(ctx, source.Pattern.ValueString(), extensions, !data.Extensions.IsNull(), data.IncludeHidden.ValueBool(), overrides, &sourceDiags)
+		resp.Diagnostics.Append(patternDiagnostics(sourceDiags, sourcePath, multiplePatterns)...)
+
+		for _, file := range sourceFiles {
+			if slices.Contains(files, file) {
+				resp.Diagnostics.AddAttributeError(
+					sourcePath,
+					"Duplicate input file",
+					"File "+file+" is matched by more than one of input_pattern and sources, each file must have exactly one source",
+				)
+				continue
+			}
+
+			fileSources[file] = i
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 && !resp.Diagnostics.HasError() {
 		resp.Diagnostics.AddError(
-			"No input files found",
-			"No files matched the provided input pattern: "+data.InputPattern.ValueString(),
+			noInputFilesSummary,
+			"None of input_pattern and sources matched any files",
+		)
+	}
+
+	checkInputCount(files, data.MaxFiles.ValueInt64(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fileKey, err := keyFormatter(data.KeyFormat.ValueString(), data.InputPattern.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("key_format"),
+			"Invalid key format",
+			"Could not parse key_format: "+err.Error(),
 		)
 		return
 	}
 
+	// the keys of the files of sources are relative to their own pattern
+	sourceKeys := make([]func(file string) (string, error), len(sources))
+	for i, source := range sources {
+		sourceKeys[i], _ = keyFormatter(data.KeyFormat.ValueString(), source.Pattern.ValueString())
+	}
+
+	keys := make(map[string]string, len(files))
+	keyFiles := make(map[string]string, len(files))
+	for _, file := range files {
+		formatKey := fileKey
+		if i, ok := fileSources[file]; ok {
+			formatKey = sourceKeys[i]
+		}
+
+		key, err := formatKey(file)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key_format"),
+				"Invalid key format",
+				"Could not format the key of file "+file+": "+err.Error(),
+			)
+			continue
+		}
+
+		if other, ok := keyFiles[key]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("key_format"),
+				"Duplicate key",
+				"Files "+other+" and "+file+" have the same key "+key,
+			)
+			continue
+		}
+
+		keys[file] = key
+		keyFiles[key] = file
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Matched input files", map[string]interface{}{
+		"input_pattern": data.InputPattern.ValueString(),
+		"files":         len(files),
+	})
+
+	checkInputSizes(files, data.MaxFileSize.ValueInt64(), data.MaxTotalSize.ValueInt64(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failOnInvalid := data.FailOnInvalid.IsNull() || data.FailOnInvalid.ValueBool()
+	decoder := d.yamlDecoder
+	decoder.rejectTimestamps = !data.YAMLTimestamps.IsNull() && !data.YAMLTimestamps.ValueBool()
+	decoder.version = data.YAMLVersion.ValueString()
+
+	// the resource validated the documents decoded with the settings of the provider only
+	if decoder.version != "" || decoder.rejectTimestamps {
+		cachedFiles = nil
+	}
+
 	valuesMap := make(map[string]string)
+	// fileRoutes maps the files to the index of the route they were classified by
+	fileRoutes := make(map[string]int)
+	valuesJSONMap := make(map[string]string)
+	valuesYAMLMap := make(map[string]string)
+	flattenedMap := make(map[string]map[string]string)
+	flattenSeparator := cmp.Or(data.FlattenSep.ValueString(), ".")
+	rawValuesMap := make(map[string]string)
+	resolvedSchemasMap := make(map[string]string)
+	propertyCoverages := make(map[string]*propertyCoverage)
+	traceMap := make(map[string]string)
+	sensitiveValuesMap := make(map[string]string)
+	annotationsMap := make(map[string]string)
+	findings := make([]reportFinding, 0)
+	var validDocuments []validDocument
+	matchedBranches := make([]reportMatch, 0)
+	var decodedContents []reportContent
+	documentsList := make([]ValidatedYAMLDocumentModel, 0)
+	validFiles := make([]string, 0)
+	invalidFiles := make([]string, 0)
+	compiledSchemaPaths := make(map[string]bool)
+	fileSchemasMap := make(map[string][]string)
+	listOnly := data.ListOnly.ValueBool()
+	stats := ValidatedYAMLStatsModel{
+		FilesMatched:  types.Int64Value(int64(len(files))),
+		SlowestFile:   types.StringNull(),
+		SlowestFileMs: types.Int64Value(0),
+	}
+	var bytesRead int64
+	var slowest time.Duration
 	for _, file := range files {
+		var fileDiags diag.Diagnostics
+		var fileDocuments []ValidatedYAMLDocumentModel
+		var fileAnnotations []map[string]map[string]any
+		var fileValues []any
+		var fileFindings []reportFinding
+		var fileMatches []reportMatch
+		var fileContents []reportContent
+		fileCoverage := make(map[string][]propertyUsage)
+		var fileTrace []traceEntry
+		var skipped bool
+
+		encrypted := strings.EqualFold(filepath.Ext(file), ageExtension)
+		sensitive := encrypted || isVaultURL(file) || isAWSURL(file)
+		traced := !sensitive && !data.TraceFileGlob.IsNull() && matchFileGlob(data.TraceFileGlob.ValueString(), file)
+
+		// files of sources report errors at their pattern and use its schema and syntax
+		inputPath := path.Root("input_pattern")
+		syntax := data.Syntax.ValueString()
+		fileSchemas := schemas
+		fileSchemaPaths := make([]path.Path, len(schemas))
+		for i := range schemas {
+			fileSchemaPaths[i] = path.Root("schemas").AtListIndex(i)
+		}
+		if i, ok := fileSources[file]; ok {
+			inputPath = path.Root("sources").AtListIndex(i).AtName("pattern")
+			if !sources[i].Syntax.IsNull() {
+				syntax = sources[i].Syntax.ValueString()
+			}
+			if !sources[i].Schema.IsNull() {
+				fileSchemas = append(slices.Clip(schemas), sources[i].Schema.ValueString())
+				fileSchemaPaths = append(fileSchemaPaths, path.Root("sources").AtListIndex(i).AtName("schema"))
+			}
+		}
+
+		fileCtx, fileSpan := d.tracing.start(ctx, "file", attribute.String("file", file))
+		fileStart := time.Now()
+
 		func() {
-			fi, err := os.Open(file)
+			_, readSpan := d.tracing.start(fileCtx, "read")
+			contentRaw, err := d.readFile(fileCtx, file, overrides)
+			endSpan(readSpan, err)
 			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error opening file",
-					"Could not open file "+file+": "+err.Error(),
+				fileDiags.AddAttributeError(
+					inputPath,
+					"Error reading file",
+					"Could not read file "+file+": "+err.Error(),
 				)
 				return
 			}
-			defer func(fi *os.File) {
-				err := fi.Close()
+
+			bytesRead += int64(len(contentRaw))
+			raw := contentRaw
+
+			// age encrypted files are decrypted in memory and handled by the extension of the decrypted file
+			name := file
+			if encrypted {
+				contentRaw, err = decryptAge(contentRaw, d.ageIdentities)
 				if err != nil {
-					resp.Diagnostics.AddError(
-						"Error closing file",
-						"Could not close file "+file+": "+err.Error(),
+					fileDiags.AddAttributeError(
+						inputPath,
+						"Error decrypting file",
+						"Could not decrypt file "+file+": "+err.Error(),
 					)
+					return
 				}
-			}(fi)
+				name = strings.TrimSuffix(file, filepath.Ext(file))
+			}
 
-			contentRaw, err := io.ReadAll(fi)
+			contentRaw, err = decode(contentRaw)
 			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error reading file",
-					"Could not read file "+file+": "+err.Error(),
+				fileDiags.AddAttributeError(
+					inputPath,
+					"Error decoding file",
+					"Could not decode file "+file+": "+err.Error(),
 				)
 				return
 			}
 
+			if !utf8.Valid(raw) {
+				raw = contentRaw
+			}
+
 			content := string(contentRaw)
 
-			// check that first line contains schema reference
-			// e.g. # yaml-language-server: $schema=path
-			matches := schemaRegex.FindStringSubmatchIndex(content)
-			// matches should contain 4 elements: full match start, full match end, first group start, first group end
-			if len(matches) != 4 {
-				resp.Diagnostics.AddError(
-					"Error validating file",
-					"File "+file+" does not contain a valid schema reference in the first line, e.g. '# yaml-language-server: $schema=path'",
-				)
-				return
+			if templateVars != nil {
+				content, err = renderTemplate(file, content, templateVars)
+				if err != nil {
+					fileDiags.AddAttributeError(
+						path.Root("template_vars"),
+						"Error rendering template",
+						"Could not render file "+file+": "+err.Error(),
+					)
+					return
+				}
 			}
 
-			schemaPath := filepath.Join(filepath.Dir(file), content[matches[2]:matches[3]])
-
-			compiledSchema, err := d.compiler.Compile(schemaPath)
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error compiling schema",
-					"Could not compile schema "+schemaPath+" for file "+file+": "+err.Error(),
-				)
-				return
+			if data.ExpandEnv.ValueBool() {
+				content, err = expandVariables(content, lookupEnv)
+				if err != nil {
+					fileDiags.AddAttributeError(
+						path.Root("env"),
+						"Error expanding variables",
+						"Could not expand variables in file "+file+": "+err.Error(),
+					)
+					return
+				}
 			}
 
-			var value interface{}
+			if data.NormalizeUnicode.ValueBool() {
+				content = norm.NFC.String(content)
+			}
 
-			err = yaml.Unmarshal(contentRaw, &value)
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error decoding YAML",
-					"Could not decode YAML file "+file+": "+err.Error(),
-				)
-				return
+			if slices.Contains(frontMatterExtensions, strings.ToLower(filepath.Ext(name))) {
+				frontMatter, ok := extractFrontMatter(content)
+				if !ok {
+					fileDiags.AddAttributeError(
+						inputPath,
+						"Error reading front matter",
+						"Markdown file "+file+" does not start with YAML front matter enclosed in '---' lines",
+					)
+					return
+				}
+				content = frontMatter
 			}
 
-			err = compiledSchema.Validate(value)
+			tfvars := isTFVarsJSON(name)
+			isJSON := tfvars || syntax == syntaxJSON || (syntax == syntaxAuto && isJSONInput(name, content))
 
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error validating YAML",
-					"YAML file "+file+" does not conform to schema "+schemaPath+": "+err.Error(),
-				)
-				return
+			syntaxName := "YAML"
+			if isJSON {
+				syntaxName = "JSON"
 			}
 
-			// content without the first line (which contains the schema reference)
-			valuesMap[file] = strings.Trim(content[matches[1]:], "\n")
-		}()
-	}
-
-	values, diag := types.MapValueFrom(ctx, types.StringType, valuesMap)
-	resp.Diagnostics.Append(diag...)
-	if resp.Diagnostics.HasError() {
+			empty := isEmptyInput(content, isJSON, decoder)
+			if empty {
+				switch data.EmptyFile.ValueString() {
+				case emptyFileSkip:
+					tflog.Debug(ctx, "Skipped empty file", map[string]interface{}{"file": file})
+					skipped = true
+					return
+				case emptyFileNull:
+				default:
+					fileDiags.AddAttributeError(
+						path.Root("empty_file_behavior"),
+						"Empty file",
+						syntaxName+" file "+file+" is empty or only contains null documents, set empty_file_behavior to skip or null to allow empty files",
+					)
+					return
+				}
+			}
+
+			var ref, body string
+			var jsonValue any
+			if isJSON && !empty {
+				jsonValue, err = jsonschema.UnmarshalJSON(strings.NewReader(content))
+				if err != nil {
+					fileDiags.AddAttributeError(
+						inputPath,
+						"Error decoding JSON",
+						"Could not decode JSON file "+file+": "+err.Error(),
+					)
+					return
+				}
+
+				// JSON files reference their schema with the $schema property, like editors expect
+				object, _ := jsonValue.(map[string]any)
+				ref, _ = object["$schema"].(string)
+				if tfvars && object != nil {
+					jsonValue = tfvarsValues(object)
+				}
+				if ref == "" && filePreset != nil {
+					ref = filePreset.schema(content)
+				}
+				if ref == "" && len(fileSchemas) == 0 && len(routes) == 0 {
+					detail := "JSON file " + file + " does not contain a schema reference in the $schema property"
+					if object == nil {
+						detail = "JSON file " + file + " is not an object, which could reference its schema in the $schema property, set schemas to validate it"
+					}
+					fileDiags.AddAttributeError(
+						inputPath,
+						"Error validating file",
+						detail,
+					)
+					return
+				}
+
+				body = content
+			} else {
+				// check that first line contains schema reference
+				// e.g. # yaml-language-server: $schema=path
+				matches := schemaRegex.FindStringSubmatchIndex(content)
+				// matches should contain 4 elements: full match start, full match end, first group start, first group end
+				switch {
+				case len(matches) == 4:
+					ref = content[matches[2]:matches[3]]
+
+					// content without the first line (which contains the schema reference)
+					body = content[matches[1]:]
+				case filePreset != nil:
+					ref = filePreset.schema(content)
+					body = content
+				case len(fileSchemas) > 0 || len(routes) > 0:
+					body = content
+				default:
+					fileDiags.AddAttributeError(
+						inputPath,
+						"Error validating file",
+						"File "+file+" does not contain a valid schema reference in the first line, e.g. '# yaml-language-server: $schema=path'",
+					)
+					return
+				}
+			}
+
+			// the schema of the route is validated again with the others, for the annotations and the report
+			if len(routes) > 0 {
+				values, err := routeDocuments(body, jsonValue, isJSON, empty, decoder)
+				if err != nil {
+					fileDiags.AddAttributeError(
+						inputPath,
+						"Error decoding YAML",
+						"Could not decode YAML file "+file+": "+err.Error(),
+					)
+					return
+				}
+
+				route := selectRoute(routeSchemas, values)
+				switch {
+				case route >= 0:
+					fileRoutes[file] = route
+					fileSchemas = append(slices.Clip(fileSchemas), routes[route].Schema.ValueString())
+					fileSchemaPaths = append(fileSchemaPaths, path.Root("routes").AtListIndex(route).AtName("schema"))
+				case !listOnly:
+					// listed files are not validated, so they need no route
+					fileDiags.AddAttributeError(
+						path.Root("routes"),
+						"Error routing file",
+						syntaxName+" file "+file+" does not conform to the schema of any route",
+					)
+					return
+				}
+			}
+
+			// the referenced schema is applied first, followed by the schemas of the data source
+			var schemaPaths []string
+			var attributePaths []path.Path
+			if ref != "" {
+				schemaPath := resolveSchemaReference(file, ref, schemaRoots...)
+
+				tflog.Trace(ctx, "Resolved schema", map[string]interface{}{
+					"file":   file,
+					"schema": schemaPath,
+				})
+
+				schemaPaths = append(schemaPaths, schemaPath)
+				attributePaths = append(attributePaths, inputPath)
+			}
+			schemaPaths = append(schemaPaths, fileSchemas...)
+			attributePaths = append(attributePaths, fileSchemaPaths...)
+
+			fileSchemasMap[keys[file]] = schemaPaths
+			if listOnly {
+				return
+			}
+
+			// files recorded valid by the validation cache are decoded but not validated again
+			var cached bool
+			if cachedKey, ok := cachedFiles[file]; ok && len(schemaPaths) > 0 && (ref == "" || overlay == nil) {
+				schemaHash, err := cacheHashes.hash(schemaPaths)
+				cached = err == nil && cachedKey == validationCacheKey(file, content, schemaHash)
+				tflog.Debug(ctx, "Checked validation cache", map[string]interface{}{
+					"file":   file,
+					"cached": cached,
+				})
+			}
+
+			compiledSchemas := make([]*jsonschema.Schema, 0, len(schemaPaths))
+			for i, schemaPath := range schemaPaths {
+				_, compileSpan := d.tracing.start(fileCtx, "compile", attribute.String("schema", schemaPath))
+				compileStart := time.Now()
+				var compiledSchema *jsonschema.Schema
+				if i == 0 && ref != "" && overlay != nil {
+					compiledSchema, err = d.compiler.CompileOverlay(schemaPath, overlay)
+				} else {
+					compiledSchema, err = d.compiler.Compile(schemaPath)
+				}
+				endSpan(compileSpan, err)
+				tflog.Debug(ctx, "Compiled schema", map[string]interface{}{
+					"schema":      schemaPath,
+					"duration_ms": time.Since(compileStart).Milliseconds(),
+				})
+				if err != nil {
+					fileDiags.AddAttributeError(
+						attributePaths[i],
+						"Error compiling schema",
+						"Could not compile schema "+schemaPath+" for file "+file+": "+err.Error(),
+					)
+					return
+				}
+				compiledSchemas = append(compiledSchemas, compiledSchema)
+				compiledSchemaPaths[schemaPath] = true
+
+				if _, ok := propertyCoverages[schemaPath]; !ok && data.ExportPropertyCoverage.ValueBool() {
+					propertyCoverages[schemaPath] = newPropertyCoverage(compiledSchema)
+				}
+
+				if _, ok := resolvedSchemasMap[schemaPath]; ok || !data.ExportResolvedSchema.ValueBool() {
+					continue
+				}
+
+				var resolvedOverlay map[string]any
+				if i == 0 && ref != "" {
+					resolvedOverlay = overlay
+				}
+
+				resolved, err := d.compiler.resolveSchema(schemaPath, resolvedOverlay)
+				if err == nil {
+					var encoded []byte
+					encoded, err = json.Marshal(resolved)
+					resolvedSchemasMap[schemaPath] = string(encoded)
+				}
+				if err != nil {
+					fileDiags.AddAttributeError(
+						attributePaths[i],
+						"Error resolving schema",
+						"Could not resolve schema "+schemaPath+" for file "+file+": "+err.Error(),
+					)
+					return
+				}
+			}
+
+			// empty files are validated as a single null document
+			documents := []string{body}
+			if !isJSON && !empty {
+				documents = splitYAMLDocuments(body)
+			}
+			var fileYAML []string
+			index := 0
+			var stripped bool
+			for i, document := range documents {
+				value := jsonValue
+
+				if !isJSON {
+					value, err = decoder.decode([]byte(document))
+				}
+				if err != nil {
+					fileDiags.AddAttributeError(
+						inputPath,
+						"Error decoding YAML",
+						"Could not decode YAML file "+file+": "+err.Error(),
+					)
+					return
+				}
+
+				// empty documents, e.g. before a leading document separator, are skipped
+				if value == nil && !empty {
+					continue
+				}
+
+				source := syntaxName + " file " + file
+				if len(documents) > 1 {
+					source = fmt.Sprintf("Document %d of %s file %s", index, syntaxName, file)
+				}
+
+				documentAnnotations := make(map[string]map[string]any)
+				for i, compiledSchema := range compiledSchemas {
+					schemaPath := schemaPaths[i]
+
+					err = nil
+					if !cached {
+						_, validateSpan := d.tracing.start(fileCtx, "validate", attribute.Int("document", index), attribute.String("schema", schemaPath))
+						validateStart := time.Now()
+						err = compiledSchema.Validate(value)
+						endSpan(validateSpan, err)
+						tflog.Debug(ctx, "Validated document", map[string]interface{}{
+							"file":        file,
+							"document":    index,
+							"schema":      schemaPath,
+							"valid":       err == nil,
+							"duration_ms": time.Since(validateStart).Milliseconds(),
+						})
+					}
+
+					if traced {
+						fileTrace = append(fileTrace, conditionTrace(schemaPath, index, compiledSchema, value)...)
+					}
+
+					if err != nil {
+						violations := validationFindings(file, index, err)
+						suppressFindings(violations, suppressions)
+						baselineFindings(violations, baseline)
+						fileFindings = append(fileFindings, violations...)
+
+						if slices.ContainsFunc(violations, func(f reportFinding) bool { return f.Severity == severityError }) {
+							// the messages name the offending values, which stay out of the diagnostics of sensitive files
+							detail := err.Error()
+							if sensitive {
+								detail = findingLocations(violations)
+							}
+							fileDiags.AddAttributeError(
+								inputPath,
+								"Error validating "+syntaxName,
+								source+" does not conform to schema "+schemaPath+": "+detail,
+							)
+							return
+						}
+
+						for _, violation := range violations {
+							attributePath, summary := path.Root("suppressions"), "Suppressed violation"
+							if !violation.Suppressed {
+								attributePath, summary = path.Root("baseline_file"), "Baselined violation"
+							}
+							detail := "Value at '" + violation.Pointer + "' of " + source + " violates '" + violation.Keyword + "' of schema " + schemaPath
+							if !sensitive {
+								detail += ": " + violation.Message
+							}
+							fileDiags.AddAttributeWarning(attributePath, summary, detail)
+						}
+					}
+
+					if mode := data.Mode.ValueString(); mode != "" {
+						violations := accessModeFindings(file, index, compiledSchema, value, mode)
+						if len(violations) > 0 {
+							fileFindings = append(fileFindings, violations...)
+
+							pointers := make([]string, 0, len(violations))
+							for _, violation := range violations {
+								pointers = append(pointers, "'"+violation.Pointer+"' ("+violation.Keyword+")")
+							}
+							fileDiags.AddAttributeError(
+								inputPath,
+								"Error validating "+syntaxName,
+								syntaxName+" file "+file+" contains values not allowed in "+mode+" mode by schema "+schemaPath+": "+strings.Join(pointers, ", "),
+							)
+							return
+						}
+					}
+
+					if detection := data.SecretDetection.ValueString(); detection != "" {
+						secrets := secretFindings(file, index, compiledSchema, value)
+						if len(secrets) > 0 && detection == secretDetectionError {
+							fileFindings = append(fileFindings, secrets...)
+
+							pointers := make([]string, 0, len(secrets))
+							for _, secret := range secrets {
+								pointers = append(pointers, "'"+secret.Pointer+"' ("+secret.Keyword+")")
+							}
+							fileDiags.AddAttributeError(
+								inputPath,
+								"Error validating "+syntaxName,
+								source+" contains values marked as secrets by schema "+schemaPath+": "+strings.Join(pointers, ", "),
+							)
+							return
+						}
+
+						// the documents read so far are left out like the rest of the file
+						if len(secrets) > 0 {
+							sensitive, traced = true, false
+							fileDocuments = nil
+						}
+					}
+
+					// keywords of later schemas take precedence
+					for pointer, keywords := range collectAnnotations(compiledSchema, value) {
+						if documentAnnotations[pointer] == nil {
+							documentAnnotations[pointer] = make(map[string]any)
+						}
+						maps.Copy(documentAnnotations[pointer], keywords)
+					}
+					fileMatches = append(fileMatches, branchMatches(file, index, compiledSchema, value)...)
+					for _, content := range documentContents(file, index, compiledSchema, value) {
+						if !slices.ContainsFunc(fileContents, func(c reportContent) bool { return c.Document == content.Document && c.Pointer == content.Pointer }) {
+							fileContents = append(fileContents, content)
+						}
+					}
+					if propertyCoverages[schemaPath] != nil {
+						fileCoverage[schemaPath] = append(fileCoverage[schemaPath], documentPropertyUsage(file, index, compiledSchema, value))
+					}
+
+					for _, finding := range deprecationFindings(file, index, compiledSchema, value) {
+						fileDiags.AddAttributeWarning(
+							inputPath,
+							"Deprecated value",
+							"Value at '"+finding.Pointer+"' of "+syntaxName+" file "+file+" is deprecated by schema "+schemaPath,
+						)
+						fileFindings = append(fileFindings, finding)
+					}
+				}
+
+				if len(stripPaths) > 0 {
+					var removed bool
+					value, removed = stripPointers(value, stripPaths)
+					if removed {
+						document, err = stripDocument(document, value, stripPaths, isJSON)
+						if err != nil {
+							fileDiags.AddAttributeError(
+								path.Root("strip_pointers"),
+								"Error stripping values",
+								"Could not encode "+source+" without the stripped values: "+err.Error(),
+							)
+							return
+						}
+						documents[i] = document
+						stripped = true
+					}
+				}
+
+				fileAnnotations = append(fileAnnotations, documentAnnotations)
+				fileValues = append(fileValues, value)
+
+				if !isJSON && !sensitive {
+					normalized, err := normalizeYAML([]byte(document), data.PreserveComments.ValueBool())
+					if err != nil {
+						fileDiags.AddAttributeError(
+							inputPath,
+							"Error encoding YAML",
+							"Could not encode "+source+" as YAML: "+err.Error(),
+						)
+						return
+					}
+					fileYAML = append(fileYAML, normalized)
+				}
+
+				if !sensitive {
+					fileDocuments = append(fileDocuments, ValidatedYAMLDocumentModel{
+						File:    types.StringValue(file),
+						Index:   types.Int64Value(int64(index)),
+						Content: types.StringValue(strings.Trim(normalizeOutput(document), "\n")),
+					})
+				}
+
+				index++
+			}
+
+			if stripped {
+				body = strings.Join(documents, "---\n")
+			}
+
+			if sensitive {
+				sensitiveValuesMap[keys[file]] = strings.Trim(normalizeOutput(body), "\n")
+			} else {
+				valuesMap[keys[file]] = strings.Trim(normalizeOutput(body), "\n")
+				if !isJSON {
+					valuesYAMLMap[keys[file]] = strings.Trim(normalizeOutput(strings.Join(fileYAML, "---\n")), "\n")
+				}
+				if data.Raw.ValueBool() {
+					rawValuesMap[keys[file]] = string(raw)
+				}
+			}
+		}()
+
+		endSpan(fileSpan, diagnosticsError(fileDiags))
+
+		if elapsed := time.Since(fileStart); stats.SlowestFile.IsNull() || elapsed > slowest {
+			slowest = elapsed
+			stats.SlowestFile = types.StringValue(file)
+			stats.SlowestFileMs = types.Int64Value(elapsed.Milliseconds())
+		}
+
+		if skipped {
+			continue
+		}
+
+		// listed files are not validated, so they are neither valid nor invalid
+		if listOnly {
+			resp.Diagnostics.Append(fileDiags...)
+			continue
+		}
+
+		if traced {
+			encoded, err := json.Marshal(append(make([]traceEntry, 0, len(fileTrace)), fileTrace...))
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error encoding trace",
+					"Could not encode trace of file "+file+": "+err.Error(),
+				)
+				continue
+			}
+			traceMap[file] = string(encoded)
+		}
+
+		if !sensitive {
+			findings = append(findings, fileFindings...)
+
+			// errors other than violations, e.g. a missing schema reference, are reported as they are
+			if fileDiags.HasError() && !slices.ContainsFunc(fileFindings, func(f reportFinding) bool { return f.Severity == severityError }) {
+				for _, fileDiag := range fileDiags.Errors() {
+					findings = append(findings, reportFinding{
+						File:     file,
+						Message:  fileDiag.Detail(),
+						Severity: severityError,
+					})
+				}
+			}
+		}
+
+		if fileDiags.HasError() {
+			invalidFiles = append(invalidFiles, file)
+
+			// in non-fatal mode errors are downgraded to warnings and the file is left out of the values
+			if !failOnInvalid {
+				for _, fileDiag := range fileDiags {
+					if withPath, ok := fileDiag.(diag.DiagnosticWithPath); ok {
+						resp.Diagnostics.AddAttributeWarning(withPath.Path(), fileDiag.Summary(), fileDiag.Detail())
+						continue
+					}
+					resp.Diagnostics.AddWarning(fileDiag.Summary(), fileDiag.Detail())
+				}
+				continue
+			}
+		} else {
+			validFiles = append(validFiles, file)
+			if len(references) > 0 || !data.AggregateSchema.IsNull() {
+				for index, value := range fileValues {
+					validDocuments = append(validDocuments, validDocument{file: file, index: index, value: value, sensitive: sensitive})
+				}
+			}
+			if !sensitive {
+				matchedBranches = append(matchedBranches, fileMatches...)
+				decodedContents = append(decodedContents, fileContents...)
+				for schemaPath, usages := range fileCoverage {
+					for _, usage := range usages {
+						propertyCoverages[schemaPath].add(usage)
+					}
+				}
+			}
+			documentsList = append(documentsList, fileDocuments...)
+			if route, ok := fileRoutes[file]; ok && !sensitive {
+				valuesBySchemaMap[routes[route].Name.ValueString()][keys[file]] = valuesMap[keys[file]]
+			}
+
+			if !sensitive {
+				encoded, err := json.Marshal(fileAnnotations)
+				if err != nil {
+					resp.Diagnostics.AddError(
+						"Error encoding annotations",
+						"Could not encode annotations of file "+file+": "+err.Error(),
+					)
+					continue
+				}
+				annotationsMap[keys[file]] = string(encoded)
+
+				var document any = fileValues
+				if len(fileValues) == 1 {
+					document = fileValues[0]
+				}
+				encoded, err = json.Marshal(document)
+				if err != nil {
+					resp.Diagnostics.AddAttributeError(
+						inputPath,
+						"Error encoding JSON",
+						"Could not encode the documents of file "+file+" as JSON: "+err.Error(),
+					)
+					continue
+				}
+				valuesJSONMap[keys[file]] = string(encoded)
+
+				flattened := make(map[string]string)
+				if err := flattenValue("", flattenSeparator, document, flattened); errors.Is(err, errDuplicatePath) {
+					resp.Diagnostics.AddAttributeError(
+						path.Root("flatten_separator"),
+						"Duplicate path",
+						"Could not flatten the documents of file "+file+", "+err.Error()+", set flatten_separator to a separator the keys do not contain",
+					)
+					continue
+				} else if err != nil {
+					resp.Diagnostics.AddAttributeError(
+						inputPath,
+						"Error flattening values",
+						"Could not flatten the documents of file "+file+": "+err.Error(),
+					)
+					continue
+				}
+				flattenedMap[keys[file]] = flattened
+			}
+		}
+
+		resp.Diagnostics.Append(fileDiags...)
+	}
+
+	for i, reference := range references {
+		referencePath := path.Root("references").AtListIndex(i)
+
+		tokens, _ := splitPointer(reference.Pointer.ValueString())
+		targetTokens, _ := splitPointer(reference.TargetPointer.ValueString())
+
+		targets, err := referenceTargets(reference.TargetPattern.ValueString(), targetTokens, decoder)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				referencePath.AtName("target_pattern"),
+				"Error reading referenced files",
+				"Could not read the files referenced by "+reference.Pointer.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+
+		broken := brokenReferences(validDocuments, reference.Pointer.ValueString(), tokens, reference.TargetPattern.ValueString(), reference.TargetPointer.ValueString(), targets)
+		suppressFindings(broken, suppressions)
+		baselineFindings(broken, baseline)
+
+		for _, finding := range broken {
+			if !slices.ContainsFunc(validDocuments, func(r validDocument) bool { return r.file == finding.File && r.sensitive }) {
+				findings = append(findings, finding)
+			}
+
+			detail := "Document " + strconv.Itoa(finding.Document) + " of file " + finding.File + " has a broken reference: " + finding.Message
+			if finding.Severity == severityError && failOnInvalid {
+				resp.Diagnostics.AddAttributeError(referencePath, "Broken reference", detail)
+				continue
+			}
+			resp.Diagnostics.AddAttributeWarning(referencePath, "Broken reference", detail)
+		}
+	}
+
+	if !data.AggregateSchema.IsNull() {
+		aggregateSchemaPath := data.AggregateSchema.ValueString()
+
+		aggregateSchema, err := d.compiler.Compile(aggregateSchemaPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("aggregate_schema"),
+				"Error compiling schema",
+				"Could not compile schema "+aggregateSchemaPath+": "+err.Error(),
+			)
+			return
+		}
+
+		aggregate, pointers := aggregateDocuments(validDocuments, data.AggregateShape.ValueString(), keys)
+		if err := aggregateSchema.Validate(aggregate); err != nil {
+			violations := validationFindings("", 0, err)
+			aggregateFindings(violations, validDocuments, pointers)
+			suppressFindings(violations, suppressions)
+			baselineFindings(violations, baseline)
+
+			for _, violation := range violations {
+				sensitive := slices.ContainsFunc(validDocuments, func(v validDocument) bool { return v.file == violation.File && v.sensitive })
+				if !sensitive {
+					findings = append(findings, violation)
+				}
+
+				source := "the aggregate of the valid documents"
+				if violation.File != "" {
+					source = "document " + strconv.Itoa(violation.Document) + " of file " + violation.File
+				}
+				detail := "Value at '" + violation.Pointer + "' of " + source + " violates '" + violation.Keyword + "' of schema " + aggregateSchemaPath
+				if !sensitive {
+					detail += ": " + violation.Message
+				}
+				if violation.Severity == severityError && failOnInvalid {
+					resp.Diagnostics.AddAttributeError(path.Root("aggregate_schema"), "Invalid aggregate", detail)
+					continue
+				}
+				resp.Diagnostics.AddAttributeWarning(path.Root("aggregate_schema"), "Invalid aggregate", detail)
+			}
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	values, diags := types.MapValueFrom(ctx, types.StringType, valuesMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	data.Values = values
 
+	data.ValuesJSON, diags = types.MapValueFrom(ctx, types.StringType, valuesJSONMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Flattened, diags = types.MapValueFrom(ctx, types.MapType{ElemType: types.StringType}, flattenedMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ValuesYAML, diags = types.MapValueFrom(ctx, types.StringType, valuesYAMLMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.RawValues, diags = types.MapValueFrom(ctx, types.StringType, rawValuesMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ValuesBySchema, diags = types.MapValueFrom(ctx, types.MapType{ElemType: types.StringType}, valuesBySchemaMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.FileSchemas, diags = types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, fileSchemasMap)
+	resp.Diagnostics.Append(diags...)
+
+	data.ResolvedSchemaJSON, diags = types.MapValueFrom(ctx, types.StringType, resolvedSchemasMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	propertyCoverageMap := make(map[string]string, len(propertyCoverages))
+	for schemaPath, coverage := range propertyCoverages {
+		encoded, err := json.Marshal(coverage.report())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error encoding property coverage",
+				"Could not encode property coverage of schema "+schemaPath+": "+err.Error(),
+			)
+			return
+		}
+		propertyCoverageMap[schemaPath] = string(encoded)
+	}
+
+	data.PropertyCoverageJSON, diags = types.MapValueFrom(ctx, types.StringType, propertyCoverageMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Trace, diags = types.MapValueFrom(ctx, types.StringType, traceMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sensitiveValues, diags := types.MapValueFrom(ctx, types.StringType, sensitiveValuesMap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.SensitiveValues = sensitiveValues
+
+	documents, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: validatedYAMLDocumentAttrTypes}, documentsList)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.DocumentsList = documents
+
+	data.Annotations, diags = types.MapValueFrom(ctx, types.StringType, annotationsMap)
+	resp.Diagnostics.Append(diags...)
+
+	report, err := json.Marshal(validationReport{Findings: findings, Matches: matchedBranches, Contents: decodedContents})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error encoding report",
+			"Could not encode report: "+err.Error(),
+		)
+		return
+	}
+
+	data.Report = types.StringValue(string(report))
+
+	tflog.Debug(ctx, "Validated input files", map[string]interface{}{
+		"valid_files":   len(validFiles),
+		"invalid_files": len(invalidFiles),
+		"duration_ms":   time.Since(readStart).Milliseconds(),
+	})
+
+	stats.BytesRead = types.Int64Value(bytesRead)
+	stats.SchemasCompiled = types.Int64Value(int64(len(compiledSchemaPaths)))
+	stats.DurationMs = types.Int64Value(time.Since(readStart).Milliseconds())
+
+	data.Stats, diags = types.ObjectValueFrom(ctx, validatedYAMLStatsAttrTypes, stats)
+	resp.Diagnostics.Append(diags...)
+
+	data.MatchedFiles, diags = types.ListValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+
+	data.ValidFiles, diags = types.ListValueFrom(ctx, types.StringType, validFiles)
+	resp.Diagnostics.Append(diags...)
+
+	data.InvalidFiles, diags = types.ListValueFrom(ctx, types.StringType, invalidFiles)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// inputFiles returns the files matched by pattern, a glob pattern, a
+// directory, a vault:// reference or a key or prefix of a key-value store,
+// e.g. consul:// or ssm://, adding error diagnostics at input_pattern if
+// there are none.
+func (d *ValidatedYAMLDataSource) inputFiles(ctx context.Context, pattern string, extensions []string, filterExtensions, includeHidden bool, overrides map[string]string, diags *diag.Diagnostics) []string {
+	if isVaultURL(pattern) {
+		return []string{pattern}
+	}
+
+	if isKVURL(pattern) {
+		files, err := d.kv.documents(ctx, pattern)
+		if err != nil {
+			diags.AddAttributeError(path.Root("input_pattern"), "Error listing keys", "Could not list the keys of "+pattern+": "+err.Error())
+			return nil
+		}
+		if len(files) == 0 {
+			diags.AddAttributeError(path.Root("input_pattern"), noInputFilesSummary, "No keys found below the provided input pattern: "+pattern)
+		}
+		return files
+	}
+
+	_, globSpan := d.tracing.start(ctx, "glob")
+
+	var files []string
+	if isInputDir(pattern) {
+		files = walkInputDir(pattern, extensions, diags, slices.Collect(maps.Keys(overrides))...)
+	} else {
+		files = globInputFiles(pattern, diags, slices.Collect(maps.Keys(overrides))...)
+		if filterExtensions {
+			files = filterInputExtensions(pattern, files, extensions, diags)
+		}
+	}
+
+	if !includeHidden {
+		files = filterHiddenFiles(pattern, files, diags)
+	}
+
+	globSpan.SetAttributes(attribute.Int("files", len(files)))
+	endSpan(globSpan, diagnosticsError(*diags))
+
+	return files
+}
+
+// patternDiagnostics returns diags of matching the files of the pattern at
+// attributePath, reported for that attribute. If the files of other patterns
+// are validated too, a pattern that matches nothing is reported as a warning,
+// so a typo in it is noticed although validation succeeds.
+func patternDiagnostics(diags diag.Diagnostics, attributePath path.Path, multiplePatterns bool) diag.Diagnostics {
+	var patternDiags diag.Diagnostics
+	for _, diagnostic := range diags {
+		switch {
+		case diagnostic.Severity() == diag.SeverityWarning:
+			patternDiags.AddAttributeWarning(attributePath, diagnostic.Summary(), diagnostic.Detail())
+		case multiplePatterns && diagnostic.Summary() == noInputFilesSummary:
+			patternDiags.AddAttributeWarning(
+				attributePath,
+				"Pattern matched no files",
+				diagnostic.Detail()+". The files of the other patterns are validated without it, check the pattern for typos.",
+			)
+		default:
+			patternDiags.AddAttributeError(attributePath, diagnostic.Summary(), diagnostic.Detail())
+		}
+	}
+
+	return patternDiags
+}
+
+// readFile returns the content of a local file, of an entry inside an
+// archive, of a vault:// reference or of a key of a key-value store, unless
+// the content is overridden.
+func (d *ValidatedYAMLDataSource) readFile(ctx context.Context, file string, overrides map[string]string) ([]byte, error) {
+	if content, ok := overrides[file]; ok {
+		return []byte(content), nil
+	}
+
+	if isVaultURL(file) {
+		return d.vault.readDocument(ctx, file)
+	}
+
+	if isKVURL(file) {
+		return d.kv.readDocument(ctx, file)
+	}
+
+	return readInputFile(file)
+}
+
+// errDuplicatePath is returned by flattenValue for values with the same path.
+var errDuplicatePath = errors.New("more than one value has the path")
+
+// flattenValue adds the scalar values of value to flattened by their path
+// below prefix, the keys of objects and indexes of lists joined with
+// separator. Strings are added as they are, other scalars and empty objects
+// and lists as JSON. Values with the same path, e.g. of the key "db.port"
+// and the key port of db, are an error.
+func flattenValue(prefix, separator string, value any, flattened map[string]string) error {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + separator + key
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) > 0 {
+			// sorted, so the same of two colliding values is reported every time
+			for _, key := range sortedKeys(v) {
+				if err := flattenValue(join(key), separator, v[key], flattened); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case []any:
+		if len(v) > 0 {
+			for i, child := range v {
+				if err := flattenValue(join(strconv.Itoa(i)), separator, child, flattened); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	if _, ok := flattened[prefix]; ok {
+		return fmt.Errorf("%w %s", errDuplicatePath, prefix)
+	}
+
+	if s, ok := value.(string); ok {
+		flattened[prefix] = s
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	flattened[prefix] = string(encoded)
+
+	return nil
+}
+
+// isJSONInput reports whether the file name, or else its content, looks
+// like JSON rather than YAML.
+func isJSONInput(name, content string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return true
+	case ".yaml", ".yml":
+		return false
+	}
+
+	trimmed := strings.TrimSpace(content)
+
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
+
+// isTFVarsJSON reports whether name is a Terraform variable file in JSON
+// syntax, e.g. prod.tfvars.json or terraform.auto.tfvars.json.
+func isTFVarsJSON(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), tfvarsJSONExtension)
+}
+
+// tfvarsValues returns the variables of a JSON variable file without the
+// properties Terraform does not read as variables, the "//" comment and the
+// $schema reference.
+func tfvarsValues(object map[string]any) map[string]any {
+	values := make(map[string]any, len(object))
+	for name, value := range object {
+		if name != "//" && name != "$schema" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// isEmptyInput reports whether content has no documents other than null
+// documents. Content that fails to decode is not empty.
+func isEmptyInput(content string, isJSON bool, decoder yamlDecoder) bool {
+	if isJSON {
+		trimmed := strings.TrimSpace(content)
+		return trimmed == "" || trimmed == "null"
+	}
+
+	for _, document := range splitYAMLDocuments(content) {
+		if value, err := decoder.decode([]byte(document)); err != nil || value != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitYAMLDocuments splits a YAML stream at its '---' document separators.
+// Anything following the separator on the same line, e.g. a tag, is kept as
+// the start of the next document.
+func splitYAMLDocuments(content string) []string {
+	var documents []string
+	var sb strings.Builder
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "---" || strings.HasPrefix(trimmed, "--- ") {
+			documents = append(documents, sb.String())
+			sb.Reset()
+			sb.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "---"), " "))
+			continue
+		}
+		sb.WriteString(line)
+	}
+
+	return append(documents, sb.String())
+}
+
+// extractFrontMatter returns the YAML block at the start of a Markdown
+// document enclosed in '---' lines, the body of the document is dropped.
+func extractFrontMatter(content string) (string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r\n") != "---" {
+		return "", false
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r\n") == "---" {
+			return strings.Join(lines[1:i], ""), true
+		}
+	}
+
+	return "", false
+}
@@ -4,16 +4,23 @@
 package provider
 
 import (
+	"bytes"
+	"filippo.io/age"
+	"filippo.io/age/armor"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/unicode"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
@@ -64,78 +71,2629 @@ tags:
 	})
 }
 
-func TestInvalidYAML(t *testing.T) {
+func TestSchemaRootsYAML(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	metadataDir := filepath.Join(tmpDir, "metadata")
+	for _, dir := range []string{"app", "schemas"} {
+		require.NoError(t, os.Mkdir(filepath.Join(tmpDir, dir), 0755))
+	}
 
-	err := os.Mkdir(metadataDir, 0755)
+	err := os.WriteFile(filepath.Join(tmpDir, "app/example.yaml"), []byte(`# yaml-language-server: $schema=person.json
+id: "example-id"
+name: "Example Name"
+`), 0644)
 	require.NoError(t, err)
 
-	err = os.Mkdir(filepath.Join(metadataDir, "examples"), 0755)
+	err = os.WriteFile(filepath.Join(tmpDir, "schemas/person.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
 	require.NoError(t, err)
 
-	err = os.WriteFile(filepath.Join(metadataDir, "examples/example.yaml"), []byte(`
-# yaml-language-server: $schema=../schema.json
-id: 12345
-name: "Example Name"
-tags:
-  - "tag1"
-  - "tag2"
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "app/*.yaml")),
+				ExpectError: regexp.MustCompile(`Error compiling schema`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSchemaRootsConfig, filepath.Join(tmpDir, "app/*.yaml"), filepath.Join(tmpDir, "schemas")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "app/example.yaml")),
+						knownvalue.StringExact(`id: "example-id"
+name: "Example Name"`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestEmbeddedSchemaYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "schema.yaml"), []byte(`# yaml-language-server: $schema=urn:jsonschema:draft-2020-12
+type: object
+required: [id]
 `), 0644)
 	require.NoError(t, err)
-	err = os.WriteFile(filepath.Join(metadataDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "patch.yaml"), []byte(`# yaml-language-server: $schema=urn:jsonschema:json-patch
+- op: remove
+`), 0644)
 	require.NoError(t, err)
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
-			// Read testing
 			{
-				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(metadataDir, "**/*.yaml")),
-				ExpectError: regexp.MustCompile(`- at '/id': got number, want string`),
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "schema.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.Join(tmpDir, "schema.yaml"))}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "patch.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
 			},
 		},
 	})
 }
 
-func TestNoSchema(t *testing.T) {
+func TestAbsoluteSchemaYAML(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	metadataDir := filepath.Join(tmpDir, "metadata")
+	schemaDir := filepath.Join(tmpDir, "schemas")
+	require.NoError(t, os.Mkdir(schemaDir, 0755))
 
-	err := os.Mkdir(metadataDir, 0755)
+	err := os.WriteFile(filepath.Join(schemaDir, "person.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
 	require.NoError(t, err)
 
-	err = os.Mkdir(filepath.Join(metadataDir, "examples"), 0755)
+	// the generated schema references the person schema by absolute path
+	err = os.WriteFile(filepath.Join(schemaDir, "generated.json"), []byte(`{"$ref": "`+filepath.Join(schemaDir, "person.json")+`"}`), 0644)
 	require.NoError(t, err)
 
-	err = os.WriteFile(filepath.Join(metadataDir, "examples/example.yaml"), []byte(`
+	err = os.WriteFile(filepath.Join(tmpDir, "absolute.yaml"), []byte(`# yaml-language-server: $schema=`+filepath.Join(schemaDir, "generated.json")+`
+id: "absolute-id"
+name: "Absolute Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "uri.yaml"), []byte(`# yaml-language-server: $schema=file://`+filepath.ToSlash(filepath.Join(schemaDir, "person.json"))+`
+id: "uri-id"
+`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "absolute.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "absolute.yaml")),
+						knownvalue.StringExact(`id: "absolute-id"
+name: "Absolute Name"`),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "uri.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+		},
+	})
+}
+
+func TestUTF16YAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(`# yaml-language-server: $schema=schema.json
+id: "utf16-id"
+name: "Zoë"
+`)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(encoded), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), append([]byte{0xEF, 0xBB, 0xBF}, testAccValidatedYAMLDataSourceSchema...), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "utf16-id"
+name: "Zoë"`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestEncodingYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte("# yaml-language-server: $schema=schema.json\nid: \"latin1-id\"\nname: \"Zo\xeb\"\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceEncodingConfig, filepath.Join(tmpDir, "example.yaml"), "latin-1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "latin1-id"
+name: "Zoë"`),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceEncodingConfig, filepath.Join(tmpDir, "example.yaml"), "klingon"),
+				ExpectError: regexp.MustCompile(`Invalid encoding`),
+			},
+		},
+	})
+}
+
+func TestNormalizeYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte("# yaml-language-server: $schema=schema.json\r\nid: \"crlf-id\"  \r\nname: \"CRLF Name\"\r\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceNormalizeConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "crlf-id"
+name: "CRLF Name"`),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("documents_list").AtSliceIndex(0).AtMapKey("content"),
+						knownvalue.StringExact(`id: "crlf-id"
+name: "CRLF Name"`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestPreserveCommentsYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "example.yaml")
+
+	err := os.WriteFile(file, []byte(`# yaml-language-server: $schema=schema.json
+defaults: &defaults
+  name: "Example Name" # display name
+other:
+  <<: *defaults
+  id: "other-id"
 id: "example-id"
-name: "Example Name"
-tags:
-  - "tag1"
-  - "tag2"
 `), 0644)
 	require.NoError(t, err)
 
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"type": "object", "required": ["id"]}`), 0644)
+	require.NoError(t, err)
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
-			// Read testing
 			{
-				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(metadataDir, "**/*.yaml")),
-				ExpectError: regexp.MustCompile(`valid schema reference`),
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourcePreserveCommentsConfig, file, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_yaml").AtMapKey(file),
+						knownvalue.StringExact(`defaults:
+  name: "Example Name"
+other:
+  name: "Example Name"
+  id: "other-id"
+id: "example-id"`),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourcePreserveCommentsConfig, file, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_yaml").AtMapKey(file),
+						knownvalue.StringExact(`defaults:
+  name: "Example Name" # display name
+other:
+  name: "Example Name"
+  id: "other-id"
+id: "example-id"`),
+					),
+				},
 			},
 		},
 	})
 }
 
-const (
-	testAccValidatedYAMLDataSourceConfig = `
-data "jsonschema_validated_yaml" "metadata" {
-  input_pattern = "%s"
+func TestFlattenedYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "example.yaml")
+
+	err := os.WriteFile(file, []byte(`# yaml-language-server: $schema=schema.json
+db:
+  host: "db.internal"
+  port: 5432
+  tls: true
+hosts: ["a", "b"]
+tags: {}
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"type": "object"}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, file),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("flattened").AtMapKey(file),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"db.host": knownvalue.StringExact("db.internal"),
+							"db.port": knownvalue.StringExact("5432"),
+							"db.tls":  knownvalue.StringExact("true"),
+							"hosts.0": knownvalue.StringExact("a"),
+							"hosts.1": knownvalue.StringExact("b"),
+							"tags":    knownvalue.StringExact("{}"),
+						}),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceFlattenConfig, file),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("flattened").AtMapKey(file).AtMapKey("db/port"),
+						knownvalue.StringExact("5432"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestFlattenValueDuplicatePath(t *testing.T) {
+	err := flattenValue("", ".", map[string]any{"db.port": "5432", "db": map[string]any{"port": "5433"}}, make(map[string]string))
+	require.ErrorIs(t, err, errDuplicatePath)
+
+	err = flattenValue("", "/", map[string]any{"a/b": true, "a": map[string]any{"b": false}}, make(map[string]string))
+	require.ErrorIs(t, err, errDuplicatePath)
+
+	flattened := make(map[string]string)
+	require.NoError(t, flattenValue("", "/", map[string]any{"db.port": "5432", "db": map[string]any{"port": "5433"}}, flattened))
+	require.Equal(t, map[string]string{"db.port": "5432", "db/port": "5433"}, flattened)
+}
+
+func TestFlattenedYAMLDuplicatePath(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.Join(tmpDir, "example.yaml")
+
+	err := os.WriteFile(file, []byte("# yaml-language-server: $schema=schema.json\ndb.port: 5432\ndb:\n  port: 5433\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"type": "object"}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, file),
+				ExpectError: regexp.MustCompile(`Duplicate path`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceFlattenConfig, file),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("flattened").AtMapKey(file),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"db.port": knownvalue.StringExact("5432"),
+							"db/port": knownvalue.StringExact("5433"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestNormalizeUnicodeYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// decomposed u followed by a combining diaeresis, as written by macOS
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte("# yaml-language-server: $schema=schema.json\ncity: Zu\u0308rich\n"), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte("{\"properties\": {\"city\": {\"enum\": [\"Z\u00fcrich\"]}}}"), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "example.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceNormalizeUnicodeConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact("city: Z\u00fcrich"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestRawYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := "\n# yaml-language-server: $schema=schema.json\r\nid: \"raw-id\"\r\nname: \"Raw Name\"\r\n\n"
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(content), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceRawConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("raw_values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(content),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("raw_values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestNumberFidelityYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=schema.json
+id: 9007199254740993
+price: 19.99
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "type": "object",
+  "properties": {
+    "id": {"const": 9007199254740993},
+    "price": {"type": "number", "multipleOf": 0.01}
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`{"id":9007199254740993,"price":19.99}`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestFormatsYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=schema.json
+released: 2024-01-02
+updated: "2024-01-02 10:30"
+timeout: 1h30m
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "released": {"type": "string", "format": "date"},
+    "updated": {"type": "string", "format": "date-time"},
+    "timeout": {"type": "string", "format": "duration"}
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`{"released":"2024-01-02","timeout":"1h30m","updated":"2024-01-02 10:30"}`),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceFormatsConfig, "strict", filepath.Join(tmpDir, "example.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceFormatsConfig, "lenient", filepath.Join(tmpDir, "example.yaml")),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceTimestampsConfig, filepath.Join(tmpDir, "example.yaml")),
+				ExpectError: regexp.MustCompile(`unquoted timestamp 2024-01-02`),
+			},
+		},
+	})
+}
+
+func TestRegexEngineYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=schema.json
+user: admin
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "type": "object",
+  "properties": {
+    "user": {"type": "string", "pattern": "^(?!admin$)[a-z]+$"}
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "example.yaml")),
+				ExpectError: regexp.MustCompile(`Error compiling schema`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceRegexConfig, "engine = \"ecma\"", filepath.Join(tmpDir, "example.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceRegexConfig, "skip_unsupported = true", filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`{"user":"admin"}`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestFSOverridesYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "disk.yaml"), []byte(`# yaml-language-server: $schema=schema.json
+id: "disk-id"
+name: "Disk Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceFSOverridesConfig, filepath.Join(tmpDir, "*.yaml"), filepath.Join(tmpDir, "virtual.yaml"), `name: \"Virtual Name\"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							filepath.Join(tmpDir, "disk.yaml"): knownvalue.StringExact(`id: "disk-id"
+name: "Disk Name"`),
+							filepath.Join(tmpDir, "virtual.yaml"): knownvalue.StringExact(`id: "virtual-id"
+name: "Virtual Name"`),
+						}),
+					),
+				},
+			},
+			// overrides take precedence over the file on disk
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceFSOverridesConfig, filepath.Join(tmpDir, "*.yaml"), filepath.Join(tmpDir, "disk.yaml"), `name: 1`),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+		},
+	})
+}
+
+func TestKeyFormatYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, env := range []string{"dev", "prod"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "envs", env), 0755))
+
+		err := os.WriteFile(filepath.Join(tmpDir, "envs", env, "values.yaml"), []byte(`# yaml-language-server: $schema=../../schema.json
+id: "`+env+`"
+name: "`+env+` Name"
+`), 0644)
+		require.NoError(t, err)
+	}
+
+	err := os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceKeyFormatConfig, filepath.Join(tmpDir, "envs/*/values.yaml"), `envs/([^/]+)/values\\.yaml$`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"dev": knownvalue.StringExact(`id: "dev"
+name: "dev Name"`),
+							"prod": knownvalue.StringExact(`id: "prod"
+name: "prod Name"`),
+						}),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceKeyFormatConfig, filepath.Join(tmpDir, "envs/*/values.yaml"), "relative"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey("dev/values.yaml"),
+						knownvalue.StringExact(`id: "dev"
+name: "dev Name"`),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceKeyFormatConfig, filepath.Join(tmpDir, "envs/*/values.yaml"), "basename"),
+				ExpectError: regexp.MustCompile(`Duplicate key`),
+			},
+		},
+	})
+}
+
+func TestDirectoryYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "configs", "nested"), 0755))
+
+	for _, file := range []string{"configs/a.yaml", "configs/nested/b.yml", "configs/nested/c.conf"} {
+		err := os.WriteFile(filepath.Join(tmpDir, file), []byte(`# yaml-language-server: $schema=`+filepath.Join(tmpDir, "schema.json")+`
+id: "`+filepath.Base(file)+`"
+name: "Name"
+`), 0644)
+		require.NoError(t, err)
+	}
+
+	err := os.WriteFile(filepath.Join(tmpDir, "configs", "nested", "d.json"), []byte(`{"id": 1}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "configs")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.Join(tmpDir, "configs/a.yaml")),
+							knownvalue.StringExact(filepath.Join(tmpDir, "configs/nested/b.yml")),
+						}),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceExtensionsConfig, filepath.Join(tmpDir, "configs"), "conf"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.Join(tmpDir, "configs/nested/c.conf")),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceExtensionsConfig, filepath.Join(tmpDir, "configs"), ".toml"),
+				ExpectError: regexp.MustCompile(`No input files found`),
+			},
+			// extensions filter the files matched by a glob
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceExtensionsConfig, filepath.Join(tmpDir, "configs/*/*"), "yml"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.Join(tmpDir, "configs/nested/b.yml")),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceExtensionsConfig, filepath.Join(tmpDir, "configs/*/*"), ".toml"),
+				ExpectError: regexp.MustCompile(`No input files found`),
+			},
+		},
+	})
+}
+
+func TestSyntaxAutoYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "a.yaml"), []byte(`# yaml-language-server: $schema=schema.json
+id: "yaml-id"
+name: "YAML Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "b.json"), []byte(`{"$schema": "schema.json", "id": "json-id", "name": "JSON Name"}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "c.json"), []byte(`{"$schema": "schema.json", "id": 1}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSyntaxConfig, filepath.Join(tmpDir, "[ab].*")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							filepath.Join(tmpDir, "a.yaml"): knownvalue.StringExact(`id: "yaml-id"
+name: "YAML Name"`),
+							filepath.Join(tmpDir, "b.json"): knownvalue.StringExact(`{"$schema": "schema.json", "id": "json-id", "name": "JSON Name"}`),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSyntaxConfig, filepath.Join(tmpDir, "c.json")),
+				ExpectError: regexp.MustCompile(`Error validating JSON`),
+			},
+		},
+	})
+}
+
+func TestInvalidYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	metadataDir := filepath.Join(tmpDir, "metadata")
+
+	err := os.Mkdir(metadataDir, 0755)
+	require.NoError(t, err)
+
+	err = os.Mkdir(filepath.Join(metadataDir, "examples"), 0755)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(metadataDir, "examples/example.yaml"), []byte(`
+# yaml-language-server: $schema=../schema.json
+id: 12345
+name: "Example Name"
+tags:
+  - "tag1"
+  - "tag2"
+`), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(metadataDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(metadataDir, "**/*.yaml")),
+				ExpectError: regexp.MustCompile(`- at '/id': got number, want string`),
+			},
+			// errors point at the attribute of the data source block
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(metadataDir, "**/*.yaml")),
+				ExpectError: regexp.MustCompile(`(?s)Error validating YAML.*with data\.jsonschema_validated_yaml\.metadata.*input_pattern = "`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(metadataDir, "*.none")),
+				ExpectError: regexp.MustCompile(`(?s)No input files found.*input_pattern = "`),
+			},
+		},
+	})
+}
+
+func TestNoSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	metadataDir := filepath.Join(tmpDir, "metadata")
+
+	err := os.Mkdir(metadataDir, 0755)
+	require.NoError(t, err)
+
+	err = os.Mkdir(filepath.Join(metadataDir, "examples"), 0755)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(metadataDir, "examples/example.yaml"), []byte(`
+id: "example-id"
+name: "Example Name"
+tags:
+  - "tag1"
+  - "tag2"
+`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(metadataDir, "**/*.yaml")),
+				ExpectError: regexp.MustCompile(`valid schema reference`),
+			},
+		},
+	})
+}
+
+func TestFrontMatter(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	docsDir := filepath.Join(tmpDir, "docs")
+
+	err := os.Mkdir(docsDir, 0755)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(docsDir, "index.md"), []byte(`---
+# yaml-language-server: $schema=../schema.json
+id: "example-id"
+name: "Example Name"
+---
+
+# Example
+
+Body with a horizontal rule:
+
+---
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(docsDir, "*.md")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(docsDir, "index.md")),
+						knownvalue.StringExact(`id: "example-id"
+name: "Example Name"`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestFrontMatterMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "index.md"), []byte("# Example\n\nNo front matter.\n"), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.md")),
+				ExpectError: regexp.MustCompile(`Error reading front matter`),
+			},
+		},
+	})
+}
+
+func TestTemplateVars(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "{{ .env }}-id"
+name: "Example Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceTemplateVarsConfig, filepath.Join(tmpDir, "*.yaml"), "env"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "prod-id"
+name: "Example Name"`),
+					),
+				},
+			},
+			// Variables missing from template_vars are an error
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceTemplateVarsConfig, filepath.Join(tmpDir, "*.yaml"), "stage"),
+				ExpectError: regexp.MustCompile(`Error rendering template`),
+			},
+		},
+	})
+}
+
+func TestExpandEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "${ENV}-id"
+name: "${NAME:-Example Name}"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	t.Setenv("ENV", "process")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceExpandEnvConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "prod-id"
+name: "Example Name"`),
+					),
+				},
+			},
+			// The environment of the provider is only used if process_env is set
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceProcessEnvConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "process-id"
+name: "Example Name"`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAgeEncryptedYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	var encrypted bytes.Buffer
+
+	armored := armor.NewWriter(&encrypted)
+	w, err := age.Encrypt(armored, identity.Recipient())
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte(`# yaml-language-server: $schema=./schema.json
+id: "secret-id"
+name: "Secret Name"
+`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, armored.Close())
+
+	err = os.WriteFile(filepath.Join(tmpDir, "secret.yaml.age"), encrypted.Bytes(), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceAgeConfig, identity.String(), filepath.Join(tmpDir, "*.age")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("sensitive_values").AtMapKey(filepath.Join(tmpDir, "secret.yaml.age")),
+						knownvalue.StringExact(`id: "secret-id"
+name: "Secret Name"`),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapSizeExact(0),
+					),
+				},
+			},
+			// Encrypted files cannot be read without identities
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.age")),
+				ExpectError: regexp.MustCompile(`Error decrypting file`),
+			},
+		},
+	})
+}
+
+func TestAgeEncryptedYAMLInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	var encrypted bytes.Buffer
+
+	armored := armor.NewWriter(&encrypted)
+	w, err := age.Encrypt(armored, identity.Recipient())
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("# yaml-language-server: $schema=./schema.json\ntoken: \"hunter2-secret\"\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, armored.Close())
+
+	err = os.WriteFile(filepath.Join(tmpDir, "secret.yaml.age"), encrypted.Bytes(), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"type": "object", "properties": {"token": {"type": "string", "pattern": "^[0-9]+$"}}}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// decrypted values are left out of the diagnostics, only their locations are reported
+		ErrorCheck: func(err error) error {
+			if strings.Contains(err.Error(), "hunter2") {
+				return fmt.Errorf("expected the error to leave out the decrypted value: %w", err)
+			}
+			if !regexp.MustCompile(`'/token'\s+\(pattern\)`).MatchString(err.Error()) {
+				return err
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceAgeConfig, identity.String(), filepath.Join(tmpDir, "*.age")),
+				Check: func(s *terraform.State) error {
+					return fmt.Errorf("expected an error validating the decrypted file")
+				},
+			},
+		},
+	})
+}
+
+func TestVaultYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	server := newTestVaultServer(t, map[string]map[string]any{
+		"schemas": {"person": testAccValidatedYAMLDataSourceSchema},
+		"docs": {"config": `# yaml-language-server: $schema=schemas#person
+id: "vault-id"
+name: "Vault Name"
+`},
+	})
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=vault://secret/schemas#person
+id: "local-id"
+name: "Local Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "invalid.yaml"), []byte(`# yaml-language-server: $schema=vault://secret/schemas#person
+id: "local-id"
+`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Document and schema read from Vault
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceVaultConfig, server.URL, "secret", "vault://secret/docs#config"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("sensitive_values").AtMapKey("vault://secret/docs#config"),
+						knownvalue.StringExact(`id: "vault-id"
+name: "Vault Name"`),
+					),
+				},
+			},
+			// Local document with a schema read from Vault
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceVaultConfig, server.URL, "secret", filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "local-id"
+name: "Local Name"`),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceVaultConfig, server.URL, "secret", filepath.Join(tmpDir, "invalid.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceVaultConfig, server.URL, "wrong", "vault://secret/docs#config"),
+				ExpectError: regexp.MustCompile(`Error reading file`),
+			},
+		},
+	})
+}
+
+func TestKVYAML(t *testing.T) {
+	values := map[string]string{
+		"config/schemas/person.json": testAccValidatedYAMLDataSourceSchema,
+		"config/apps/api.yaml": `# yaml-language-server: $schema=../schemas/person.json
+id: "api-id"
+name: "API"
+`,
+		"config/apps/web": `# yaml-language-server: $schema=consul://config/schemas/person.json
+id: "web-id"
+name: "Web"
+`,
+		"invalid/app.yaml": `# yaml-language-server: $schema=etcd://config/schemas/person.json
+id: "invalid-id"
+`,
+	}
+
+	consul := newTestConsulServer(t, "consul-token", values)
+	etcd := newTestEtcdServer(t, values)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Every key below the prefix is validated, with or without extension
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceKVConfig, consul.URL, etcd.URL, "consul://config/apps/"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("matched_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("consul://config/apps/api.yaml"),
+							knownvalue.StringExact("consul://config/apps/web"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey("consul://config/apps/api.yaml"),
+						knownvalue.StringExact(`id: "api-id"
+name: "API"`),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceKVConfig, consul.URL, etcd.URL, "etcd://invalid/"),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceKVConfig, consul.URL, etcd.URL, "etcd://missing/"),
+				ExpectError: regexp.MustCompile(`No keys found below the provided input pattern`),
+			},
+		},
+	})
+}
+
+func TestAWSYAML(t *testing.T) {
+	server := newTestAWSServer(t, map[string]string{
+		"/app/prod/api": `{"id": "api-id", "name": "API"}`,
+		"/app/dev/api":  `{"id": "api-id"}`,
+	}, map[string]string{
+		"schemas/person": testAccValidatedYAMLDataSourceSchema,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Parameters are validated against a schema read from Secrets Manager and only exposed sensitively
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceAWSConfig, server.URL, server.URL, "ssm:///app/prod/"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("sensitive_values").AtMapKey("ssm:///app/prod/api"),
+						knownvalue.StringExact(`{"id": "api-id", "name": "API"}`),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceAWSConfig, server.URL, server.URL, "ssm:///app/dev/"),
+				ExpectError: regexp.MustCompile(`Error validating JSON`),
+			},
+		},
+	})
+}
+
+func TestRemoteSchemaYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	server, requests := newFlakyServer(t, 2, http.StatusServiceUnavailable, testAccValidatedYAMLDataSourceSchema)
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=`+server.URL+`/schema.json
+id: "remote-id"
+name: "Remote Name"
+`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceRetryConfig, filepath.Join(tmpDir, "example.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.Join(tmpDir, "example.yaml")),
+						knownvalue.StringExact(`id: "remote-id"
+name: "Remote Name"`),
+					),
+				},
+			},
+		},
+	})
+
+	// the first read retried past the failures, later reads of the test steps hit the server once each
+	require.GreaterOrEqual(t, requests.Load(), int32(3))
+}
+
+func TestMultiDocumentYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "a.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+---
+id: "first-id"
+name: "First Name"
+---
+# second document
+id: "second-id"
+name: "Second Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "b.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "third-id"
+name: "Third Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	invalidDir := t.TempDir()
+
+	err = os.WriteFile(filepath.Join(invalidDir, "invalid.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "first-id"
+name: "First Name"
+---
+id: "second-id"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(invalidDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	document := func(file string, index int64, content string) knownvalue.Check {
+		return knownvalue.ObjectExact(map[string]knownvalue.Check{
+			"file":    knownvalue.StringExact(filepath.Join(tmpDir, file)),
+			"index":   knownvalue.Int64Exact(index),
+			"content": knownvalue.StringExact(content),
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("documents_list"),
+						knownvalue.ListExact([]knownvalue.Check{
+							document("a.yaml", 0, `id: "first-id"
+name: "First Name"`),
+							document("a.yaml", 1, `# second document
+id: "second-id"
+name: "Second Name"`),
+							document("b.yaml", 0, `id: "third-id"
+name: "Third Name"`),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(invalidDir, "*.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+		},
+	})
+}
+
+func TestNonFatalYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "invalid.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "invalid-id"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "no-schema.yaml"), []byte(`id: "no-schema-id"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "valid.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "valid-id"
+name: "Valid Name"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceNonFatalConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.Join(tmpDir, "valid.yaml")),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("invalid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.Join(tmpDir, "invalid.yaml")),
+							knownvalue.StringExact(filepath.Join(tmpDir, "no-schema.yaml")),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							filepath.Join(tmpDir, "valid.yaml"): knownvalue.StringExact(`id: "valid-id"
+name: "Valid Name"`),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("documents_list"),
+						knownvalue.ListSizeExact(1),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringExact(fmt.Sprintf(
+							`{"findings":[{"file":%q,"document":0,"pointer":"","keyword":"required","message":"missing property 'name'","severity":"error"},`+
+								`{"file":%q,"document":0,"pointer":"","keyword":"","message":"File %s does not contain a valid schema reference in the first line, e.g. '# yaml-language-server: $schema=path'","severity":"error"}],"matches":[]}`,
+							filepath.Join(tmpDir, "invalid.yaml"),
+							filepath.Join(tmpDir, "no-schema.yaml"),
+							filepath.Join(tmpDir, "no-schema.yaml"),
+						)),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+		},
+	})
+}
+
+func TestAnnotations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "database.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+name: "orders"
+engine:
+  type: "postgres"
+  version: 16
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "x-owner": "platform",
+  "properties": {
+    "name": { "$ref": "#/$defs/name", "x-tier": "gold" },
+    "engine": {
+      "oneOf": [
+        { "properties": { "type": { "const": "postgres" } }, "x-variant": "postgres" },
+        { "properties": { "type": { "const": "mysql" } }, "x-variant": "mysql" }
+      ]
+    }
+  },
+  "$defs": {
+    "name": { "type": "string", "x-tier": "bronze", "x-pii": false }
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("annotations").AtMapKey(filepath.Join(tmpDir, "database.yaml")),
+						knownvalue.StringExact(`[{"":{"x-owner":"platform"},"/engine":{"x-variant":"postgres"},"/name":{"x-pii":false,"x-tier":"gold"}}]`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestBranchMatchesYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "database.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+name: "orders"
+engine:
+  type: "postgres"
+  version: 16
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "name": {
+      "anyOf": [{ "type": "string" }, { "type": "null" }]
+    },
+    "engine": {
+      "oneOf": [
+        { "$ref": "#/$defs/postgres" },
+        { "title": "mysql", "properties": { "type": { "const": "mysql" } } }
+      ]
+    }
+  },
+  "$defs": {
+    "postgres": { "title": "postgres", "properties": { "type": { "const": "postgres" } } }
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringExact(fmt.Sprintf(
+							`{"findings":[],"matches":[`+
+								`{"file":%[1]q,"document":0,"pointer":"/engine","keyword":"oneOf","branch":"postgres"},`+
+								`{"file":%[1]q,"document":0,"pointer":"/name","keyword":"anyOf","branch":"#/properties/name/anyOf/0"}]}`,
+							filepath.Join(tmpDir, "database.yaml"),
+						)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestDeprecatedYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "example.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "example-id"
+name: "Example Name"
+legacy_id: 42
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "id": { "type": "string" },
+    "name": { "type": "string" },
+    "legacy_id": { "type": "integer", "deprecated": true }
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringExact(fmt.Sprintf(
+							`{"findings":[{"file":%q,"document":0,"pointer":"/legacy_id","keyword":"deprecated","message":"value at '/legacy_id' is deprecated","severity":"warning"}],"matches":[]}`,
+							filepath.Join(tmpDir, "example.yaml"),
+						)),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccessModeYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "user.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+id: "user-id"
+password: "secret"
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "id": { "type": "string", "readOnly": true },
+    "password": { "type": "string", "writeOnly": true }
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceModeConfig, filepath.Join(tmpDir, "*.yaml"), "read"),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceModeConfig, filepath.Join(tmpDir, "*.yaml"), "write"),
+				ExpectError: regexp.MustCompile(`Error validating YAML`),
+			},
+			// Without a mode neither is enforced
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceModeNonFatalConfig, filepath.Join(tmpDir, "*.yaml"), "read"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("report"),
+						knownvalue.StringExact(fmt.Sprintf(
+							`{"findings":[{"file":%q,"document":0,"pointer":"/password","keyword":"writeOnly","message":"value at '/password' is writeOnly and not allowed in read mode","severity":"error"}],"matches":[]}`,
+							filepath.Join(tmpDir, "user.yaml"),
+						)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestSecretDetectionYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"user.yaml":   "# yaml-language-server: $schema=./schema.json\nid: \"user-id\"\ncredentials:\n  token: \"secret\"\n",
+		"public.yaml": "# yaml-language-server: $schema=./schema.json\nid: \"public-id\"\n",
+		"schema.json": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "id": { "type": "string" },
+    "password": { "type": "string", "writeOnly": true },
+    "credentials": { "type": "object", "x-secret": true }
+  }
+}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	user := filepath.Join(tmpDir, "user.yaml")
+	public := filepath.Join(tmpDir, "public.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSecretDetectionConfig, filepath.Join(tmpDir, "*.yaml"), "error"),
+				ExpectError: regexp.MustCompile(`marked as secrets`),
+			},
+			// files with secrets are only exposed as sensitive values
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSecretDetectionConfig, filepath.Join(tmpDir, "*.yaml"), "sensitive"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							public: knownvalue.StringExact(`id: "public-id"`),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("sensitive_values"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							user: knownvalue.StringExact("id: \"user-id\"\ncredentials:\n  token: \"secret\""),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("documents_list"),
+						knownvalue.ListSizeExact(1),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestSizeLimitsYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		err := os.WriteFile(filepath.Join(tmpDir, name), []byte(`# yaml-language-server: $schema=./schema.json
+id: "example-id"
+name: "Example Name"
+`), 0644)
+		require.NoError(t, err)
+	}
+
+	err := os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSizeConfig, filepath.Join(tmpDir, "*.yaml"), 1024, 1024),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(2),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSizeConfig, filepath.Join(tmpDir, "*.yaml"), 16, 1024),
+				ExpectError: regexp.MustCompile(`File too large`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSizeConfig, filepath.Join(tmpDir, "*.yaml"), 1024, 100),
+				ExpectError: regexp.MustCompile(`Input files too large`),
+			},
+		},
+	})
+}
+
+func TestMaxFilesYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{"apps/web.yaml", "node_modules/a/values.yaml", "node_modules/b/values.yaml", "node_modules/c/values.yaml"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte("id: \"example-id\"\nname: \"Example Name\"\n"), 0644))
+	}
+
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "schema.json"))
+	require.NoError(t, os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceMaxFilesConfig, filepath.ToSlash(tmpDir), schemaPath, 3),
+				ExpectError: regexp.MustCompile(`(?s)Too many input files.*4 files were matched, which exceeds max_files of 3.*node_modules\s+\(3\s+files\)`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceMaxFilesConfig, filepath.ToSlash(tmpDir), schemaPath, 4),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(4),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestWaitForYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// the file only matches the input pattern once the bundle file is written
+	err := os.WriteFile(filepath.Join(tmpDir, "template.json"), []byte(`{"id": "generated-id", "name": "Generated Name"}`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "generated"), 0755))
+	generated := filepath.Join(tmpDir, "generated", "generated.json")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceWaitForConfig, filepath.ToSlash(generated), filepath.ToSlash(filepath.Join(tmpDir, "template.json")), filepath.ToSlash(filepath.Join(tmpDir, "generated", "*.json")), filepath.ToSlash(filepath.Join(tmpDir, "schema.json"))),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.ToSlash(generated))}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestSchemasYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"service.yaml": "# yaml-language-server: $schema=./schema.json\nid: \"service-id\"\nname: \"Service\"\nowner: \"platform\"\n",
+		"plain.yaml":   "id: \"plain-id\"\nowner: \"platform\"\n",
+		"orphan.yaml":  "# yaml-language-server: $schema=./schema.json\nid: \"orphan-id\"\nname: \"Orphan\"\n",
+	} {
+		err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	err := os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(testAccValidatedYAMLDataSourceSchema), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "base.json"), []byte(`{"type": "object", "required": ["owner"], "properties": {"owner": {"x-team": true}}}`), 0644)
+	require.NoError(t, err)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSchemasConfig, filepath.Join(tmpDir, "s*.yaml"), filepath.Join(tmpDir, "base.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.ToSlash(filepath.Join(tmpDir, "service.yaml"))),
+						knownvalue.StringExact("id: \"service-id\"\nname: \"Service\"\nowner: \"platform\""),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("annotations").AtMapKey(filepath.ToSlash(filepath.Join(tmpDir, "service.yaml"))),
+						knownvalue.StringExact(`[{"/owner":{"x-team":true}}]`),
+					),
+				},
+			},
+			// files without a schema reference are validated against the schemas alone
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSchemasConfig, filepath.Join(tmpDir, "p*.yaml"), filepath.Join(tmpDir, "base.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(filepath.ToSlash(filepath.Join(tmpDir, "plain.yaml"))),
+						knownvalue.StringExact("id: \"plain-id\"\nowner: \"platform\""),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSchemasConfig, filepath.Join(tmpDir, "o*.yaml"), filepath.Join(tmpDir, "base.json")),
+				ExpectError: regexp.MustCompile(`(?s)does not conform to\s+schema.*base\.json.*missing property 'owner'`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSchemasConfig, filepath.Join(tmpDir, "p*.yaml"), filepath.Join(tmpDir, "missing.json")),
+				ExpectError: regexp.MustCompile(`Error compiling schema`),
+			},
+		},
+	})
+}
+
+func TestTFVarsJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"vars/prod.tfvars.json":           `{"//": "managed by the release pipeline", "region": "eu-west-1", "replicas": 3}`,
+		"vars/terraform.auto.tfvars.json": `{"$schema": "../variables.json", "region": "eu-central-1"}`,
+		"vars/other.json":                 `{"unrelated": true}`,
+		"invalid/dev.auto.tfvars.json":    `{"region": "eu-west-1", "replicas": "three"}`,
+		"variables.json":                  `{"type": "object", "properties": {"region": {"type": "string"}, "replicas": {"type": "integer"}}, "required": ["region"], "additionalProperties": false}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceTFVarsConfig, filepath.Join(tmpDir, "vars"), filepath.Join(tmpDir, "variables.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "vars/prod.tfvars.json"))),
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "vars/terraform.auto.tfvars.json"))),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json").AtMapKey(filepath.ToSlash(filepath.Join(tmpDir, "vars/prod.tfvars.json"))),
+						knownvalue.StringExact(`{"region":"eu-west-1","replicas":3}`),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceTFVarsConfig, filepath.Join(tmpDir, "invalid"), filepath.Join(tmpDir, "variables.json")),
+				ExpectError: regexp.MustCompile(`(?s)JSON file .*dev\.auto\.tfvars\.json\s+does\s+not\s+conform.*want integer`),
+			},
+		},
+	})
+}
+
+func TestEmptyFileYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"files/values.yaml":  "name: app\n",
+		"files/blank.yaml":   "",
+		"files/comment.yaml": "# placeholder for the overlay\n",
+		"files/null.yaml":    "---\n~\n",
+		"object.json":        `{"type": "object"}`,
+		"nullable.json":      `{"type": ["object", "null"]}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	pattern := filepath.Join(tmpDir, "files", "*.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceEmptyFileConfig, pattern, filepath.Join(tmpDir, "object.json"), emptyFileError),
+				ExpectError: regexp.MustCompile(`(?s)Empty file.*blank\.yaml\s+is\s+empty`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceEmptyFileConfig, pattern, filepath.Join(tmpDir, "object.json"), emptyFileSkip),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "files", "values.yaml")))}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("invalid_files"),
+						knownvalue.ListSizeExact(0),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceEmptyFileConfig, pattern, filepath.Join(tmpDir, "object.json"), emptyFileNull),
+				ExpectError: regexp.MustCompile(`(?s)comment\.yaml\s+does\s+not\s+conform.*got\s+null,\s+want\s+object`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceEmptyFileConfig, pattern, filepath.Join(tmpDir, "nullable.json"), emptyFileNull),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(4),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json").AtMapKey(filepath.ToSlash(filepath.Join(tmpDir, "files", "null.yaml"))),
+						knownvalue.StringExact(`null`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestStatsYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"files/web.yaml":    "# yaml-language-server: $schema=../app.json\nname: web\n",
+		"files/worker.yaml": "# yaml-language-server: $schema=../app.json\nname: worker\n",
+		"files/db.yaml":     "# yaml-language-server: $schema=../db.json\nengine: postgres\n",
+		"app.json":          `{"type": "object", "required": ["name"]}`,
+		"db.json":           `{"type": "object", "required": ["engine"]}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	bytesRead := len(files["files/web.yaml"]) + len(files["files/worker.yaml"]) + len(files["files/db.yaml"])
+
+	stats := tfjsonpath.New("stats")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "files", "*.yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("data.jsonschema_validated_yaml.metadata", stats.AtMapKey("files_matched"), knownvalue.Int64Exact(3)),
+					statecheck.ExpectKnownValue("data.jsonschema_validated_yaml.metadata", stats.AtMapKey("bytes_read"), knownvalue.Int64Exact(int64(bytesRead))),
+					statecheck.ExpectKnownValue("data.jsonschema_validated_yaml.metadata", stats.AtMapKey("schemas_compiled"), knownvalue.Int64Exact(2)),
+					statecheck.ExpectKnownValue("data.jsonschema_validated_yaml.metadata", stats.AtMapKey("duration_ms"), knownvalue.Int64Func(func(v int64) error {
+						if v < 0 {
+							return fmt.Errorf("negative duration %d", v)
+						}
+						return nil
+					})),
+					statecheck.ExpectKnownValue("data.jsonschema_validated_yaml.metadata", stats.AtMapKey("slowest_file"), knownvalue.StringRegexp(regexp.MustCompile(`/files/(web|worker|db)\.yaml$`))),
+				},
+			},
+		},
+	})
+}
+
+func TestSourcesYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"apps/web.yaml":      "name: web\nreplicas: 2\n",
+		"apps/worker.yaml":   "name: worker\nreplicas: 1\n",
+		"config/limits.json": `{"cpu": "500m"}`,
+		"app.json":           `{"type": "object", "required": ["name", "replicas"]}`,
+		"config.json":        `{"type": "object", "required": ["cpu"]}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	apps := filepath.ToSlash(filepath.Join(tmpDir, "apps", "*.yaml"))
+	config := filepath.ToSlash(filepath.Join(tmpDir, "config", "*.json"))
+	sources := fmt.Sprintf(`[
+    { pattern = "%s", schema = "%s" },
+    { pattern = "%s", schema = "%s", syntax = "json" },
+  ]`, apps, filepath.ToSlash(filepath.Join(tmpDir, "app.json")), config, filepath.ToSlash(filepath.Join(tmpDir, "config.json")))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSourcesConfig, `input_pattern = "`+filepath.ToSlash(filepath.Join(tmpDir, "apps"))+`"`, sources),
+				ExpectError: regexp.MustCompile(`(?s)Duplicate input file.*web\.yaml\s+is\s+matched\s+by\s+more\s+than\s+one`),
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config", "limits.json"), []byte(`{"memory": "1Gi"}`), 0644))
+				},
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSourcesConfig, "", sources),
+				ExpectError: regexp.MustCompile(`(?s)limits\.json\s+does\s+not\s+conform\s+to\s+schema.*config\.json`),
+			},
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config", "limits.json"), []byte(`{"cpu": "500m"}`), 0644))
+				},
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSourcesConfig, "", sources),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "apps", "web.yaml"))),
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "apps", "worker.yaml"))),
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "config", "limits.json"))),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("input_pattern"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestMatchedFilesYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"apps/web.yaml":    "name: web\n",
+		"apps/worker.yaml": "name: worker\n",
+		"app.json":         `{"type": "object", "required": ["name"]}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	apps := filepath.ToSlash(filepath.Join(tmpDir, "apps", "*.yaml"))
+	// a typo'd pattern of the config files
+	config := filepath.ToSlash(filepath.Join(tmpDir, "confg", "*.json"))
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "app.json"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSourcesConfig, "", fmt.Sprintf(`[{ pattern = "%s" }, { pattern = "%s" }]`, config, config+"l")),
+				ExpectError: regexp.MustCompile(`None\s+of\s+input_pattern\s+and\s+sources\s+matched\s+any\s+files`),
+			},
+			// patterns that match nothing are warnings if other patterns match files
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSourcesConfig, "", fmt.Sprintf(`[{ pattern = "%s", schema = "%s" }, { pattern = "%s" }]`, apps, schemaPath, config)),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("matched_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "apps", "web.yaml"))),
+							knownvalue.StringExact(filepath.ToSlash(filepath.Join(tmpDir, "apps", "worker.yaml"))),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestArchiveYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.json"), []byte(`{"type": "object", "required": ["name"]}`), 0644))
+
+	writeTestTarGz(t, filepath.Join(tmpDir, "bundle.tar.gz"), map[string]string{
+		// references of entries resolve next to the archive
+		"configs/web.yaml":    "# yaml-language-server: $schema=app.json\nname: web\n",
+		"configs/worker.yaml": "name: worker\n",
+		"README.md":           "# bundle\n",
+	})
+	writeTestZip(t, filepath.Join(tmpDir, "invalid.zip"), map[string]string{
+		"configs/db.yaml": "engine: postgres\n",
+	})
+
+	bundle := filepath.ToSlash(filepath.Join(tmpDir, "bundle.tar.gz"))
+	schemaPath := filepath.ToSlash(filepath.Join(tmpDir, "app.json"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceMaxFilesConfig, filepath.ToSlash(filepath.Join(tmpDir, "invalid.zip"))+"//configs/*.yaml", schemaPath, 10),
+				ExpectError: regexp.MustCompile(`missing\s+property\s+'name'`),
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, bundle+"//configs/web.yaml"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json").AtMapKey(bundle+"//configs/web.yaml"),
+						knownvalue.StringExact(`{"name":"web"}`),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceMaxFilesConfig, bundle+"//configs/*.yaml", schemaPath, 10),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(bundle + "//configs/web.yaml"),
+							knownvalue.StringExact(bundle + "//configs/worker.yaml"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json").AtMapKey(bundle+"//configs/worker.yaml"),
+						knownvalue.StringExact(`{"name":"worker"}`),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestListOnlyYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"apps/web.yaml":    "# yaml-language-server: $schema=../app.json\nname: 42\n",
+		"apps/worker.yaml": "name: [\n",
+		"app.json":         `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+		"common.json":      `{"type": "object"`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	web := filepath.ToSlash(filepath.Join(tmpDir, "apps", "web.yaml"))
+	worker := filepath.ToSlash(filepath.Join(tmpDir, "apps", "worker.yaml"))
+	common := filepath.ToSlash(filepath.Join(tmpDir, "common.json"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// invalid documents and schemas that do not compile are only listed
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceListOnlyConfig, filepath.ToSlash(filepath.Join(tmpDir, "apps", "*.yaml")), common),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("matched_files"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(web), knownvalue.StringExact(worker)}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("file_schemas"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							web:    knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(filepath.Join(tmpDir, "app.json")), knownvalue.StringExact(common)}),
+							worker: knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact(common)}),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(0),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values"),
+						knownvalue.MapSizeExact(0),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestNonObjectRootsYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"yaml/list.yaml":    "# yaml-language-server: $schema=../list.json\n- web\n- worker\n",
+		"yaml/string.yaml":  "# yaml-language-server: $schema=../string.json\nhello\n",
+		"yaml/number.yaml":  "# yaml-language-server: $schema=../number.json\n9007199254740993\n",
+		"yaml/boolean.yaml": "# yaml-language-server: $schema=../boolean.json\ntrue\n",
+		"json/list.json":    `["web", "worker"]`,
+		"json/string.json":  `"hello"`,
+		"invalid/list.yaml": "# yaml-language-server: $schema=../list.json\n- web\n- 42\n",
+		"list.json":         `{"type": "array", "items": {"type": "string"}}`,
+		"string.json":       `{"type": "string"}`,
+		"number.json":       `{"type": "integer", "minimum": 9007199254740993}`,
+		"boolean.json":      `{"const": true}`,
+		"scalars.json":      `{"type": ["array", "string"], "items": {"type": "string"}}`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	list := filepath.ToSlash(filepath.Join(tmpDir, "yaml", "list.yaml"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "yaml")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(4),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values").AtMapKey(list),
+						knownvalue.StringExact("- web\n- worker"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							list: knownvalue.StringExact(`["web","worker"]`),
+							filepath.ToSlash(filepath.Join(tmpDir, "yaml", "string.yaml")):  knownvalue.StringExact(`"hello"`),
+							filepath.ToSlash(filepath.Join(tmpDir, "yaml", "number.yaml")):  knownvalue.StringExact(`9007199254740993`),
+							filepath.ToSlash(filepath.Join(tmpDir, "yaml", "boolean.yaml")): knownvalue.StringExact(`true`),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("documents_list"),
+						knownvalue.ListSizeExact(4),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(testAccValidatedYAMLDataSourceSchemasConfig, filepath.Join(tmpDir, "json", "*.json"), filepath.Join(tmpDir, "scalars.json")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.metadata",
+						tfjsonpath.New("values_json"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							filepath.ToSlash(filepath.Join(tmpDir, "json", "list.json")):   knownvalue.StringExact(`["web","worker"]`),
+							filepath.ToSlash(filepath.Join(tmpDir, "json", "string.json")): knownvalue.StringExact(`"hello"`),
+						}),
+					),
+				},
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceSyntaxConfig, filepath.Join(tmpDir, "json", "list.json")),
+				ExpectError: regexp.MustCompile(`list\.json\s+is\s+not\s+an\s+object`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccValidatedYAMLDataSourceConfig, filepath.Join(tmpDir, "invalid")),
+				ExpectError: regexp.MustCompile(`(?s)at\s+'/1':\s+got\s+number,\s+want\s+string`),
+			},
+		},
+	})
+}
+
+const (
+	testAccValidatedYAMLDataSourceConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceSourcesConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  %s
+  sources = %s
+}
+`
+	testAccValidatedYAMLDataSourcePreserveCommentsConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern     = "%s"
+  preserve_comments = %t
+}
+`
+	testAccValidatedYAMLDataSourceFlattenConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern     = "%s"
+  flatten_separator = "/"
+}
+`
+	testAccValidatedYAMLDataSourceListOnlyConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  schemas       = ["%s"]
+  list_only     = true
+}
+`
+	testAccValidatedYAMLDataSourceNonFatalConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern   = "%s"
+  fail_on_invalid = false
+}
+`
+	testAccValidatedYAMLDataSourceModeConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  mode          = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceModeNonFatalConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern   = "%s"
+  mode            = "%s"
+  fail_on_invalid = false
+}
+`
+	testAccValidatedYAMLDataSourceSecretDetectionConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern    = "%s"
+  secret_detection = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceSizeConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern  = "%s"
+  max_file_size  = %d
+  max_total_size = %d
+}
+`
+	testAccValidatedYAMLDataSourceMaxFilesConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  schemas       = ["%s"]
+  max_files     = %d
+}
+`
+	testAccValidatedYAMLDataSourceTemplateVarsConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+
+  template_vars = {
+    %s = "prod"
+  }
+}
+`
+	testAccValidatedYAMLDataSourceExpandEnvConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  expand_env    = true
+
+  env = {
+    ENV = "prod"
+  }
+}
+`
+	testAccValidatedYAMLDataSourceProcessEnvConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  expand_env    = true
+  process_env   = true
+}
+`
+	testAccValidatedYAMLDataSourceAgeConfig = `
+provider "jsonschema" {
+  age_identities = ["%s"]
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceVaultConfig = `
+provider "jsonschema" {
+  vault = {
+    address = "%s"
+
+    approle = {
+      role_id   = "role"
+      secret_id = "%s"
+    }
+  }
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceKVConfig = `
+provider "jsonschema" {
+  consul = {
+    address = "%s"
+    token   = "consul-token"
+  }
+
+  etcd = {
+    endpoint = "%s"
+    username = "user"
+    password = "password"
+  }
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceAWSConfig = `
+provider "jsonschema" {
+  aws = {
+    region                  = "eu-west-1"
+    ssm_endpoint            = "%s"
+    secretsmanager_endpoint = "%s"
+  }
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  sources = [
+    { pattern = "%s", schema = "secretsmanager://schemas/person", syntax = "json" },
+  ]
+}
+`
+	testAccValidatedYAMLDataSourceSyntaxConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  syntax        = "auto"
+}
+`
+	testAccValidatedYAMLDataSourceExtensionsConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  extensions    = ["%s"]
+}
+`
+	testAccValidatedYAMLDataSourceKeyFormatConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  key_format    = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceFSOverridesConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+
+  fs_overrides = {
+    "%s" = "# yaml-language-server: $schema=schema.json\nid: \"virtual-id\"\n%s\n"
+  }
+}
+`
+	testAccValidatedYAMLDataSourceRawConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  raw           = true
+}
+`
+	testAccValidatedYAMLDataSourceNormalizeUnicodeConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern     = "%s"
+  normalize_unicode = true
+}
+`
+	testAccValidatedYAMLDataSourceNormalizeConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern            = "%s"
+  normalize_line_endings   = true
+  trim_trailing_whitespace = true
+}
+`
+	testAccValidatedYAMLDataSourceEncodingConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  encoding      = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceSchemaRootsConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  schema_roots  = ["%s"]
+}
+`
+	testAccValidatedYAMLDataSourceFormatsConfig = `
+provider "jsonschema" {
+  formats = {
+    date_time = "%s"
+    assert    = true
+  }
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceTimestampsConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern   = "%s"
+  yaml_timestamps = false
+}
+`
+	testAccValidatedYAMLDataSourceRegexConfig = `
+provider "jsonschema" {
+  regex = {
+    %s
+  }
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceRetryConfig = `
+provider "jsonschema" {
+  retry = {
+    attempts    = 3
+    min_backoff = "10ms"
+  }
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+}
+`
+	testAccValidatedYAMLDataSourceWaitForConfig = `
+resource "jsonschema_bundle_file" "generator" {
+  path   = "%s"
+  schema = "%s"
+}
+
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  schemas       = ["%s"]
+
+  wait_for = {
+    generator = jsonschema_bundle_file.generator.id
+  }
+}
+`
+	testAccValidatedYAMLDataSourceSchemasConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  schemas       = ["%s"]
+}
+`
+	testAccValidatedYAMLDataSourceTFVarsConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern = "%s"
+  extensions    = [".tfvars.json"]
+  schemas       = ["%s"]
+}
+`
+	testAccValidatedYAMLDataSourceEmptyFileConfig = `
+data "jsonschema_validated_yaml" "metadata" {
+  input_pattern       = "%s"
+  schemas             = ["%s"]
+  empty_file_behavior = "%s"
 }
 `
 	testAccValidatedYAMLDataSourceSchema = `
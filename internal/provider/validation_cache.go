@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// defaultValidationCache is the path of the validation cache, relative to
+	// the working directory.
+	defaultValidationCache = ".jsonschema-cache.json"
+	validationCacheVersion = 1
+)
+
+// validationCache records the files that were valid with the hash of their
+// content and schemas, so they are not validated again until either changes.
+type validationCache struct {
+	Version int `json:"version"`
+	// Files maps the paths of valid files to their validationCacheKey.
+	Files map[string]string `json:"files"`
+}
+
+// validationCacheKey returns the SHA-256 hash of the path and content of file
+// and the hash of the schemas it is validated against.
+func validationCacheKey(file, content, schemaHash string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%d\x00%s\x00", filepath.ToSlash(file), len(content), content)
+	hash.Write([]byte(schemaHash))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// schemaHashes memoizes the hashes of lists of schemas, which are loaded
+// again to be hashed.
+type schemaHashes struct {
+	compiler *schemaCompiler
+	hashes   map[string]string
+}
+
+func newSchemaHashes(compiler *schemaCompiler) *schemaHashes {
+	return &schemaHashes{compiler: compiler, hashes: make(map[string]string)}
+}
+
+// hash returns the hash of the schemas at schemaPaths together with the
+// settings of the compiler, so files are validated again if either changes.
+func (h *schemaHashes) hash(schemaPaths []string) (string, error) {
+	key := strings.Join(schemaPaths, "\x00")
+	if hash, ok := h.hashes[key]; ok {
+		return hash, nil
+	}
+
+	hash, err := h.compiler.hashSchemas(schemaPaths)
+	if err != nil {
+		return "", err
+	}
+	if h.compiler.settings != "" {
+		hash += "\x00" + h.compiler.settings
+	}
+	h.hashes[key] = hash
+
+	return hash, nil
+}
+
+// validationSettingAttributes are the provider attributes that change the
+// results of validations, e.g. formats asserted or keywords ignored.
+var validationSettingAttributes = []string{"content", "default_draft", "formats", "ignore_keywords", "loaders", "regex", "yaml_limits", "yaml_tags"}
+
+// validationSettings returns the SHA-256 hash of the validationSettingAttributes
+// of the provider configuration config, or an empty string if none is set.
+func validationSettings(config tftypes.Value) (string, error) {
+	var attributes map[string]tftypes.Value
+	if err := config.As(&attributes); err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	var set bool
+	for _, name := range validationSettingAttributes {
+		value, ok := attributes[name]
+		if !ok || value.IsNull() {
+			continue
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00", name, value.String())
+		set = true
+	}
+
+	// providers without settings keep the keys of the schemas alone
+	if !set {
+		return "", nil
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readValidationCache reads the validation cache at file, a cache that does
+// not exist yet is empty.
+func readValidationCache(file string) (validationCache, error) {
+	cache := validationCache{Version: validationCacheVersion, Files: map[string]string{}}
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return cache, err
+	}
+
+	if err := json.Unmarshal(content, &cache); err != nil {
+		return cache, err
+	}
+
+	if cache.Version != validationCacheVersion {
+		return cache, fmt.Errorf("unsupported validation cache version %d, expected %d", cache.Version, validationCacheVersion)
+	}
+
+	return cache, nil
+}
+
+func encodeValidationCache(files map[string]string) (string, error) {
+	encoded, err := json.MarshalIndent(validationCache{Version: validationCacheVersion, Files: files}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded) + "\n", nil
+}
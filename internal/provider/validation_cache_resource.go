@@ -0,0 +1,366 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"maps"
+	"os"
+)
+
+// Ensure ValidationCacheResource satisfies various resource interfaces.
+var _ resource.Resource = &ValidationCacheResource{}
+var _ resource.ResourceWithConfigure = &ValidationCacheResource{}
+var _ resource.ResourceWithModifyPlan = &ValidationCacheResource{}
+
+func NewValidationCacheResource() resource.Resource {
+	return &ValidationCacheResource{}
+}
+
+// ValidationCacheResource defines the resource implementation.
+type ValidationCacheResource struct {
+	compiler    *schemaCompiler
+	yamlDecoder yamlDecoder
+}
+
+// ValidationCacheResourceModel describes the resource data model.
+type ValidationCacheResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Path         types.String `tfsdk:"path"`
+	InputPattern types.String `tfsdk:"input_pattern"`
+	Schemas      types.List   `tfsdk:"schemas"`
+	ContentHash  types.String `tfsdk:"content_hash"`
+	FileHashes   types.Map    `tfsdk:"file_hashes"`
+}
+
+// validationCacheEntry is a file of the validation cache.
+type validationCacheEntry struct {
+	file  string
+	key   string
+	valid bool
+}
+
+func (r *ValidationCacheResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_validation_cache"
+}
+
+func (r *ValidationCacheResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Validates YAML and JSON files when it is applied and writes the hashes of the content and schemas of the valid files to a cache file, " +
+			"which the `validation_cache` of the `jsonschema_validated_yaml` data source reads to skip validating files that did not change since, " +
+			"so large repositories are only validated incrementally. The files are validated against the schema they reference followed by `schemas`, " +
+			"like the data source does, and the cache is written again on every apply that finds files, schemas or the provider settings that change validations, e.g. `formats`, changed. " +
+			"Invalid files do not fail the apply, they are left out of the cache and validated by the data source every time.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Path of the cache file",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the cache file, defaults to `" + defaultValidationCache + "`. Set the same path as `validation_cache` of the data source.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(defaultValidationCache),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"input_pattern": schema.StringAttribute{
+				MarkdownDescription: "Glob pattern of the YAML and JSON files to cache, like the `input_pattern` of the data source",
+				Required:            true,
+			},
+			"schemas": schema.ListAttribute{
+				MarkdownDescription: "Paths or URLs of json schemas every file is validated against in addition to the schema it references, like the `schemas` of the data source",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the paths, content and schemas of every file matched at the last apply, valid or not",
+				Computed:    true,
+			},
+			"file_hashes": schema.MapAttribute{
+				Description: "Map of the paths of the valid files to the hashes of their content and schemas",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *ValidationCacheResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+	r.yamlDecoder = providerData.YAMLDecoder
+}
+
+// ModifyPlan plans the cache to be written again if the files or schemas
+// changed since the last apply.
+func (r *ValidationCacheResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state ValidationCacheResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.InputPattern.Equal(state.InputPattern) && plan.Schemas.Equal(state.Schemas) && plan.Path.Equal(state.Path) {
+		// errors are left for the apply to report
+		var entryDiags diag.Diagnostics
+		entries := r.entries(ctx, &plan, false, &entryDiags)
+		if !entryDiags.HasError() && validationCacheContentHash(entries) == state.ContentHash.ValueString() {
+			return
+		}
+	}
+
+	plan.ContentHash = types.StringUnknown()
+	plan.FileHashes = types.MapUnknown(types.StringType)
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *ValidationCacheResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ValidationCacheResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ValidationCacheResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ValidationCacheResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var fileHashes map[string]string
+	resp.Diagnostics.Append(data.FileHashes.ElementsAs(ctx, &fileHashes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The cache was removed or edited outside of Terraform, so it has to be written again.
+	var cache validationCache
+	_, err := os.Stat(data.Path.ValueString())
+	if err == nil {
+		cache, err = readValidationCache(data.Path.ValueString())
+	}
+	if err != nil || !maps.Equal(cache.Files, fileHashes) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ValidationCacheResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ValidationCacheResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ValidationCacheResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ValidationCacheResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// a stale cache would skip the validation of files it recorded
+	if err := os.Remove(data.Path.ValueString()); err != nil && !os.IsNotExist(err) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Error removing validation cache",
+			"Could not remove validation cache "+data.Path.ValueString()+": "+err.Error(),
+		)
+	}
+}
+
+// write validates the files of data, writes the hashes of the valid files to
+// the cache file and fills in the computed attributes of data.
+func (r *ValidationCacheResource) write(ctx context.Context, data *ValidationCacheResourceModel, diags *diag.Diagnostics) {
+	entries := r.entries(ctx, data, true, diags)
+	if diags.HasError() {
+		return
+	}
+
+	fileHashes := make(map[string]string)
+	for _, entry := range entries {
+		if entry.valid {
+			fileHashes[entry.file] = entry.key
+		}
+	}
+
+	content, err := encodeValidationCache(fileHashes)
+	if err != nil {
+		diags.AddError(
+			"Error encoding validation cache",
+			"Could not encode validation cache: "+err.Error(),
+		)
+		return
+	}
+
+	file := data.Path.ValueString()
+
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		diags.AddAttributeError(
+			path.Root("path"),
+			"Error writing validation cache",
+			"Could not write validation cache "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	hashes, d := types.MapValueFrom(ctx, types.StringType, fileHashes)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(file)
+	data.ContentHash = types.StringValue(validationCacheContentHash(entries))
+	data.FileHashes = hashes
+}
+
+// entries returns the cache keys of the files of data and, if validate is
+// set, whether they are valid. Files that cannot be read, decoded or hashed
+// are invalid, the data source reports why.
+func (r *ValidationCacheResource) entries(ctx context.Context, data *ValidationCacheResourceModel, validate bool, diags *diag.Diagnostics) []validationCacheEntry {
+	var schemas []string
+	if !data.Schemas.IsNull() {
+		diags.Append(data.Schemas.ElementsAs(ctx, &schemas, false)...)
+		if diags.HasError() {
+			return nil
+		}
+	}
+
+	files := globInputFiles(data.InputPattern.ValueString(), diags)
+	if diags.HasError() {
+		return nil
+	}
+
+	hashes := newSchemaHashes(r.compiler)
+
+	entries := make([]validationCacheEntry, 0, len(files))
+	for _, file := range files {
+		entry := validationCacheEntry{file: file}
+		entries = append(entries, entry)
+
+		contentRaw, err := readTextFile(file, decodeText)
+		if err != nil {
+			continue
+		}
+
+		content := string(contentRaw)
+		isJSON := isJSONInput(file, content)
+
+		values, err := decodeInputDocuments(file, content, isJSON, r.yamlDecoder)
+		if err != nil {
+			continue
+		}
+
+		// the referenced schema is applied first, followed by schemas, like in the data source
+		var schemaPaths []string
+		if ref := referencedSchema(content, isJSON, values); ref != "" {
+			schemaPaths = append(schemaPaths, resolveSchemaReference(file, ref))
+		}
+		schemaPaths = append(schemaPaths, schemas...)
+		if len(schemaPaths) == 0 {
+			continue
+		}
+
+		schemaHash, err := hashes.hash(schemaPaths)
+		if err != nil {
+			continue
+		}
+
+		entry.key = validationCacheKey(file, content, schemaHash)
+		entry.valid = validate && validateInputDocuments(r.compiler, file, values, schemaPaths...) == nil
+		entries[len(entries)-1] = entry
+	}
+
+	return entries
+}
+
+// validationCacheContentHash returns the SHA-256 hash of the cache keys of
+// entries, which changes with the content and schemas of any file.
+func validationCacheContentHash(entries []validationCacheEntry) string {
+	hash := sha256.New()
+	for _, entry := range entries {
+		fmt.Fprintf(hash, "%s\x00%s\x00", entry.file, entry.key)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestValidationCache(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	file := filepath.ToSlash(filepath.Join(tmpDir, "service.yaml"))
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	cachePath := filepath.ToSlash(filepath.Join(tmpDir, "cache.json"))
+
+	require.NoError(t, os.WriteFile(schemaPath, []byte(testAccValidatedYAMLDataSourceSchema), 0644))
+	require.NoError(t, os.WriteFile(file, []byte("# yaml-language-server: $schema=./schema.json\nid: \"web\"\nname: \"Web\"\n"), 0644))
+
+	config := fmt.Sprintf(testAccValidationCacheResourceConfig, cachePath, filepath.ToSlash(filepath.Join(tmpDir, "*.yaml")))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"jsonschema_validation_cache.test",
+						tfjsonpath.New("file_hashes").AtMapKey(file),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			// a file recorded as valid is not validated again, the edited cache is planned to be written again
+			{
+				PreConfig: func() {
+					content := "# yaml-language-server: $schema=./schema.json\nid: 123\n"
+					require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+
+					schemaHash, err := newSchemaCompiler(nil, nil).hashSchemas([]string{schemaPath})
+					require.NoError(t, err)
+					cache, err := encodeValidationCache(map[string]string{file: validationCacheKey(file, content, schemaHash)})
+					require.NoError(t, err)
+					require.NoError(t, os.WriteFile(cachePath, []byte(cache), 0644))
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// the rewritten cache does not record the invalid file
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`missing property 'name'`),
+			},
+		},
+	})
+}
+
+func TestValidationCacheProviderSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	cachePath := filepath.ToSlash(filepath.Join(tmpDir, "cache.json"))
+
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{"type": "object", "properties": {"created": {"type": "string", "format": "date"}}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "service.yaml"), []byte("# yaml-language-server: $schema=./schema.json\ncreated: yesterday\n"), 0644))
+
+	config := fmt.Sprintf(testAccValidationCacheResourceConfig, cachePath, filepath.ToSlash(filepath.Join(tmpDir, "*.yaml")))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// formats are not asserted for draft 2020-12 schemas by default
+			{
+				Config: config,
+			},
+			// the file recorded as valid is validated again once formats are asserted
+			{
+				Config:      testAccValidationCacheAssertFormatsConfig + config,
+				ExpectError: regexp.MustCompile(`is not valid date`),
+			},
+		},
+	})
+}
+
+const testAccValidationCacheAssertFormatsConfig = `
+provider "jsonschema" {
+  formats = {
+    assert = true
+  }
+}
+`
+
+const testAccValidationCacheResourceConfig = `
+locals {
+  cache   = "%s"
+  pattern = "%s"
+}
+
+resource "jsonschema_validation_cache" "test" {
+  path          = local.cache
+  input_pattern = local.pattern
+}
+
+data "jsonschema_validated_yaml" "test" {
+  input_pattern    = local.pattern
+  validation_cache = local.cache
+}
+`
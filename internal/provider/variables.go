@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"maps"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// reservedVariableNames are the names Terraform does not allow for the
+// variables of modules, they are arguments of module blocks.
+var reservedVariableNames = []string{"count", "depends_on", "for_each", "lifecycle", "locals", "providers", "source", "version"}
+
+// terraformValidation is a validation block of a variable.
+type terraformValidation struct {
+	condition    string
+	errorMessage string
+}
+
+// terraformVariables returns the variable blocks of the properties of the
+// object schema sch, formatted like terraform fmt does.
+func terraformVariables(sch *jsonschema.Schema) (string, error) {
+	for sch.Ref != nil && len(sch.Properties) == 0 {
+		sch = sch.Ref
+	}
+	if len(sch.Properties) == 0 {
+		return "", fmt.Errorf("schema %s has no properties to generate variables from", sch.Location)
+	}
+
+	blocks := make([]string, 0, len(sch.Properties))
+	for _, name := range slices.Sorted(maps.Keys(sch.Properties)) {
+		block, err := terraformVariable(name, sch.Properties[name], slices.Contains(sch.Required, name))
+		if err != nil {
+			return "", fmt.Errorf("property %s: %w", name, err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	return strings.Join(blocks, "\n"), nil
+}
+
+// terraformVariable returns the variable block of the property name with
+// schema sch. Required properties are not nullable and have no default,
+// other properties default to the default of their schema or null.
+func terraformVariable(name string, sch *jsonschema.Schema, required bool) (string, error) {
+	if !hclIdentifierRegex.MatchString(name) {
+		return "", fmt.Errorf("%q is not a valid variable name", name)
+	}
+	if slices.Contains(reservedVariableNames, name) {
+		return "", fmt.Errorf("%q is reserved and cannot be the name of a variable", name)
+	}
+
+	typeConstraint, err := terraformType(sch, make(map[*jsonschema.Schema]bool))
+	if err != nil {
+		return "", err
+	}
+
+	var attributes [][2]string
+
+	description := sch.Description
+	if description == "" {
+		description = sch.Title
+	}
+	if description != "" {
+		literal, err := hclLiteral(description)
+		if err != nil {
+			return "", err
+		}
+		attributes = append(attributes, [2]string{"description", literal})
+	}
+
+	attributes = append(attributes, [2]string{"type", typeConstraint})
+
+	if required {
+		attributes = append(attributes, [2]string{"nullable", "false"})
+	} else {
+		literal := "null"
+		if value, ok := schemaDefault(sch); ok {
+			if literal, err = hclLiteral(value); err != nil {
+				return "", fmt.Errorf("default: %w", err)
+			}
+		}
+		attributes = append(attributes, [2]string{"default", literal})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "variable %q {\n", name)
+	writeHCLAttributes(&b, "  ", attributes)
+
+	for _, validation := range terraformValidations(name, sch, typeConstraint) {
+		condition := validation.condition
+		// nullable variables are only validated if they are set
+		if !required {
+			condition = "var." + name + " == null ? true : " + condition
+		}
+
+		errorMessage, err := hclLiteral(validation.errorMessage)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString("\n  validation {\n")
+		writeHCLAttributes(&b, "    ", [][2]string{{"condition", condition}, {"error_message", errorMessage}})
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+
+	return b.String(), nil
+}
+
+// terraformValidations returns the validation blocks of the enum, const,
+// pattern and range keywords of sch. Only the keywords of the JSON type of
+// typeConstraint are converted, patterns RE2 does not support are skipped.
+func terraformValidations(name string, sch *jsonschema.Schema, typeConstraint string) []terraformValidation {
+	for sch.Ref != nil && len(schemaJSONTypes(sch)) == 0 {
+		sch = sch.Ref
+	}
+
+	variable := "var." + name
+	scalar := typeConstraint == "string" || typeConstraint == "number" || typeConstraint == "bool"
+	collection := strings.HasPrefix(typeConstraint, "list(") || strings.HasPrefix(typeConstraint, "set(") || strings.HasPrefix(typeConstraint, "tuple(")
+
+	var validations []terraformValidation
+
+	if sch.Const != nil && scalar {
+		if literal, err := hclLiteral(*sch.Const); err == nil {
+			validations = append(validations, terraformValidation{
+				condition:    variable + " == " + literal,
+				errorMessage: fmt.Sprintf("The value of %s must be %s.", name, literal),
+			})
+		}
+	}
+
+	if sch.Enum != nil && scalar {
+		values := make([]string, 0, len(sch.Enum.Values))
+		for _, value := range sch.Enum.Values {
+			literal, err := hclLiteral(value)
+			if err != nil || value == nil {
+				continue
+			}
+			values = append(values, literal)
+		}
+		if len(values) > 0 {
+			validations = append(validations, terraformValidation{
+				condition:    "contains([" + strings.Join(values, ", ") + "], " + variable + ")",
+				errorMessage: fmt.Sprintf("The value of %s must be one of %s.", name, strings.Join(values, ", ")),
+			})
+		}
+	}
+
+	if typeConstraint == "string" {
+		if sch.Pattern != nil {
+			if _, err := regexp.Compile(sch.Pattern.String()); err == nil {
+				pattern, _ := hclLiteral(sch.Pattern.String())
+				validations = append(validations, terraformValidation{
+					condition:    "can(regex(" + pattern + ", " + variable + "))",
+					errorMessage: fmt.Sprintf("The value of %s must match the pattern %s.", name, sch.Pattern.String()),
+				})
+			}
+		}
+
+		validations = append(validations, lengthValidations(name, "characters", sch.MinLength, sch.MaxLength)...)
+	}
+
+	if typeConstraint == "number" {
+		bounds := []struct {
+			limit    *big.Rat
+			operator string
+			message  string
+		}{
+			{sch.Minimum, ">=", "at least"},
+			{sch.ExclusiveMinimum, ">", "greater than"},
+			{sch.Maximum, "<=", "at most"},
+			{sch.ExclusiveMaximum, "<", "less than"},
+		}
+		for _, bound := range bounds {
+			if bound.limit == nil {
+				continue
+			}
+			limit := hclNumber(bound.limit)
+			validations = append(validations, terraformValidation{
+				condition:    variable + " " + bound.operator + " " + limit,
+				errorMessage: fmt.Sprintf("The value of %s must be %s %s.", name, bound.message, limit),
+			})
+		}
+	}
+
+	if collection {
+		validations = append(validations, lengthValidations(name, "items", sch.MinItems, sch.MaxItems)...)
+	}
+
+	return validations
+}
+
+// lengthValidations returns the validations of the minimum and maximum
+// length of the variable name, counted in unit.
+func lengthValidations(name, unit string, minimum, maximum *int) []terraformValidation {
+	var validations []terraformValidation
+
+	if minimum != nil && *minimum > 0 {
+		validations = append(validations, terraformValidation{
+			condition:    fmt.Sprintf("length(var.%s) >= %d", name, *minimum),
+			errorMessage: fmt.Sprintf("The value of %s must have at least %d %s.", name, *minimum, unit),
+		})
+	}
+	if maximum != nil {
+		validations = append(validations, terraformValidation{
+			condition:    fmt.Sprintf("length(var.%s) <= %d", name, *maximum),
+			errorMessage: fmt.Sprintf("The value of %s must have at most %d %s.", name, *maximum, unit),
+		})
+	}
+
+	return validations
+}
+
+// hclNumber returns the HCL literal of a number of a schema keyword.
+func hclNumber(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	f, _ := r.Float64()
+
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// writeHCLAttributes writes attributes to b with their equals signs aligned
+// like terraform fmt does.
+func writeHCLAttributes(b *strings.Builder, indent string, attributes [][2]string) {
+	width := 0
+	for _, attribute := range attributes {
+		width = max(width, len(attribute[0]))
+	}
+
+	for _, attribute := range attributes {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, attribute[0], attribute[1])
+	}
+}
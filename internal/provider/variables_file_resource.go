@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"os"
+)
+
+// Ensure VariablesFileResource satisfies various resource interfaces.
+var _ resource.Resource = &VariablesFileResource{}
+var _ resource.ResourceWithConfigure = &VariablesFileResource{}
+
+func NewVariablesFileResource() resource.Resource {
+	return &VariablesFileResource{}
+}
+
+// VariablesFileResource defines the resource implementation.
+type VariablesFileResource struct {
+	compiler *schemaCompiler
+}
+
+// VariablesFileResourceModel describes the resource data model.
+type VariablesFileResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Path    types.String `tfsdk:"path"`
+	Schema  types.String `tfsdk:"schema"`
+	Content types.String `tfsdk:"content"`
+}
+
+func (r *VariablesFileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_variables_file"
+}
+
+func (r *VariablesFileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Writes the `variable` blocks of a module from an object schema, one variable per property, " +
+			"to keep the interface of the module and the schema of its configuration in lockstep. " +
+			"Variables get the type constraint of `jsonschema_terraform_type`, the `description` or `title` and the `default` of their schema. " +
+			"Required properties are not nullable, other properties default to null unless their schema has a `default`. " +
+			"The `enum`, `const`, `pattern`, `minLength`, `maxLength`, `minimum`, `maximum`, `exclusiveMinimum`, `exclusiveMaximum`, " +
+			"`minItems` and `maxItems` keywords of properties become `validation` blocks, patterns Terraform's RE2 syntax does not support are skipped. " +
+			"The file is written again if it is edited or removed outside of Terraform, or if the variables of the schema change. " +
+			"The file is left in place when the resource is destroyed.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Path of the variables file",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the variables file to write, e.g. `variables.tf` of the module",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Path or URL of the object schema, may have a fragment like `#/$defs/inputs` for a subschema",
+				Required:            true,
+			},
+			"content": schema.StringAttribute{
+				Description: "HCL of the variable blocks written to the file",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *VariablesFileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*JsonschemaProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *JsonschemaProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.compiler = providerData.Compiler
+	r.compiler.refresh()
+}
+
+func (r *VariablesFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data VariablesFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(&data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VariablesFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data VariablesFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The file was removed or edited outside of Terraform, so it has to be written again.
+	contentRaw, err := os.ReadFile(data.Path.ValueString())
+	if err != nil || string(contentRaw) != data.Content.ValueString() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// The schema or a schema it references changed, errors are reported when the file is written again.
+	content, err := r.variables(data.Schema.ValueString())
+	if err != nil || content != data.Content.ValueString() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VariablesFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data VariablesFileResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(&data, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VariablesFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The variables file is part of the module, so it is kept on disk.
+}
+
+// variables returns the variable blocks of the object schema at location.
+func (r *VariablesFileResource) variables(location string) (string, error) {
+	sch, err := r.compiler.Compile(location)
+	if err != nil {
+		return "", err
+	}
+
+	return terraformVariables(sch)
+}
+
+// write generates the variable blocks of data.Schema, writes them to
+// data.Path and fills in the computed attributes of data.
+func (r *VariablesFileResource) write(data *VariablesFileResourceModel, diags *diag.Diagnostics) {
+	schemaPath := data.Schema.ValueString()
+
+	content, err := r.variables(schemaPath)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("schema"),
+			"Error generating variables",
+			"Could not generate variables from schema "+schemaPath+": "+err.Error(),
+		)
+		return
+	}
+
+	file := data.Path.ValueString()
+
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		diags.AddAttributeError(
+			path.Root("path"),
+			"Error writing file",
+			"Could not write file "+file+": "+err.Error(),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(file)
+	data.Content = types.StringValue(content)
+}
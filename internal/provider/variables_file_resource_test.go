@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestTerraformVariables(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	err := os.WriteFile(schemaPath, []byte(`{
+  "$ref": "#/$defs/inputs",
+  "$defs": {
+    "inputs": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string", "description": "Name of the ${app}", "pattern": "^[a-z]+$", "maxLength": 20},
+        "replicas": {"type": "integer", "default": 1, "minimum": 1, "exclusiveMaximum": 10.5},
+        "env": {"enum": ["dev", "prod"], "default": "dev"},
+        "ports": {"title": "Ports", "type": "array", "items": {"type": "integer"}, "minItems": 1},
+        "suffix": {"type": "string", "pattern": "(?<=a)b"}
+      }
+    }
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	// patterns of ECMA-262 that RE2 does not support are skipped
+	sch, err := newSchemaCompiler(nil, func(c *jsonschema.Compiler) { c.UseRegexpEngine(compileECMA) }).Compile(schemaPath)
+	require.NoError(t, err)
+
+	content, err := terraformVariables(sch)
+	require.NoError(t, err)
+	require.Equal(t, `variable "env" {
+  type    = string
+  default = "dev"
+
+  validation {
+    condition     = var.env == null ? true : contains(["dev", "prod"], var.env)
+    error_message = "The value of env must be one of \"dev\", \"prod\"."
+  }
+}
+
+variable "name" {
+  description = "Name of the $${app}"
+  type        = string
+  nullable    = false
+
+  validation {
+    condition     = can(regex("^[a-z]+$", var.name))
+    error_message = "The value of name must match the pattern ^[a-z]+$."
+  }
+
+  validation {
+    condition     = length(var.name) <= 20
+    error_message = "The value of name must have at most 20 characters."
+  }
+}
+
+variable "ports" {
+  description = "Ports"
+  type        = list(number)
+  default     = null
+
+  validation {
+    condition     = var.ports == null ? true : length(var.ports) >= 1
+    error_message = "The value of ports must have at least 1 items."
+  }
+}
+
+variable "replicas" {
+  type    = number
+  default = 1
+
+  validation {
+    condition     = var.replicas == null ? true : var.replicas >= 1
+    error_message = "The value of replicas must be at least 1."
+  }
+
+  validation {
+    condition     = var.replicas == null ? true : var.replicas < 10.5
+    error_message = "The value of replicas must be less than 10.5."
+  }
+}
+
+variable "suffix" {
+  type    = string
+  default = null
+}
+`, content)
+
+	err = os.WriteFile(schemaPath, []byte(`{"properties": {"count": {"type": "number"}}}`), 0644)
+	require.NoError(t, err)
+
+	sch, err = newSchemaCompiler(nil, nil).Compile(schemaPath)
+	require.NoError(t, err)
+
+	_, err = terraformVariables(sch)
+	require.ErrorContains(t, err, `"count" is reserved`)
+
+	err = os.WriteFile(schemaPath, []byte(`{"type": "string"}`), 0644)
+	require.NoError(t, err)
+
+	sch, err = newSchemaCompiler(nil, nil).Compile(schemaPath)
+	require.NoError(t, err)
+
+	_, err = terraformVariables(sch)
+	require.ErrorContains(t, err, "has no properties to generate variables from")
+}
+
+func TestVariablesFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	schemaPath := filepath.Join(tmpDir, "schema.json")
+	file := filepath.Join(tmpDir, "variables.tf")
+
+	err := os.WriteFile(schemaPath, []byte(`{"properties": {"name": {"type": "string"}}}`), 0644)
+	require.NoError(t, err)
+
+	config := fmt.Sprintf(testAccVariablesFileResourceConfig, file, schemaPath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jsonschema_variables_file.test", "id", file),
+					testAccCheckResourceFileContent("jsonschema_variables_file.test", file, `variable "name" {`),
+				),
+			},
+			// Changes of the schema are written again
+			{
+				PreConfig: func() {
+					err := os.WriteFile(schemaPath, []byte(`{"properties": {"name": {"type": "string"}, "replicas": {"type": "integer", "default": 2}}}`), 0644)
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check:  testAccCheckResourceFileContent("jsonschema_variables_file.test", file, "default = 2"),
+			},
+			// Files edited outside of Terraform are written again
+			{
+				PreConfig: func() {
+					err := os.WriteFile(file, []byte("\n"), 0644)
+					require.NoError(t, err)
+				},
+				Config: config,
+				Check:  testAccCheckResourceFileContent("jsonschema_variables_file.test", file, `variable "replicas" {`),
+			},
+			{
+				PreConfig: func() {
+					err := os.WriteFile(schemaPath, []byte(`{"properties": {"my name": {"type": "string"}}}`), 0644)
+					require.NoError(t, err)
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile(`not a valid variable name`),
+			},
+		},
+	})
+}
+
+const testAccVariablesFileResourceConfig = `
+resource "jsonschema_variables_file" "test" {
+  path   = "%s"
+  schema = "%s"
+}
+`
@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/vault/api"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// vaultScheme is the URL scheme of schemas and documents stored in Vault KV,
+// e.g. vault://secret/path/to/secret#field.
+const vaultScheme = "vault"
+
+// VaultConfigModel describes the vault block of the provider data model.
+type VaultConfigModel struct {
+	Address   types.String       `tfsdk:"address"`
+	Token     types.String       `tfsdk:"token"`
+	Namespace types.String       `tfsdk:"namespace"`
+	KVVersion types.Int64        `tfsdk:"kv_version"`
+	AppRole   *VaultAppRoleModel `tfsdk:"approle"`
+}
+
+// VaultAppRoleModel describes the AppRole credentials of the vault block.
+type VaultAppRoleModel struct {
+	RoleID   types.String `tfsdk:"role_id"`
+	SecretID types.String `tfsdk:"secret_id"`
+	Mount    types.String `tfsdk:"mount"`
+}
+
+// vaultClient reads schemas and documents from Vault KV. The Vault client
+// is created and logged in on first use, so configurations that do not
+// reference Vault never need to reach it.
+type vaultClient struct {
+	config VaultConfigModel
+
+	mu     sync.Mutex
+	client *api.Client
+}
+
+// Ensure vaultClient can load schemas.
+var _ jsonschema.URLLoader = &vaultClient{}
+
+func newVaultClient(config *VaultConfigModel) *vaultClient {
+	v := &vaultClient{}
+	if config != nil {
+		v.config = *config
+	}
+	return v
+}
+
+func (v *vaultClient) apiClient(ctx context.Context) (*api.Client, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.client != nil {
+		return v.client, nil
+	}
+
+	// the defaults are read from VAULT_ADDR, VAULT_TOKEN and the other standard environment variables
+	config := api.DefaultConfig()
+	if config.Error != nil {
+		return nil, config.Error
+	}
+
+	if !v.config.Address.IsNull() {
+		config.Address = v.config.Address.ValueString()
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if !v.config.Namespace.IsNull() {
+		client.SetNamespace(v.config.Namespace.ValueString())
+	}
+
+	if !v.config.Token.IsNull() {
+		client.SetToken(v.config.Token.ValueString())
+	}
+
+	if appRole := v.config.AppRole; appRole != nil {
+		mount := "approle"
+		if !appRole.Mount.IsNull() {
+			mount = appRole.Mount.ValueString()
+		}
+
+		secret, err := client.Logical().WriteWithContext(ctx, "auth/"+mount+"/login", map[string]any{
+			"role_id":   appRole.RoleID.ValueString(),
+			"secret_id": appRole.SecretID.ValueString(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not log in with AppRole: %w", err)
+		}
+		if secret == nil || secret.Auth == nil {
+			return nil, errors.New("could not log in with AppRole: no token returned")
+		}
+
+		client.SetToken(secret.Auth.ClientToken)
+	}
+
+	v.client = client
+
+	return client, nil
+}
+
+// read returns the value at ref, a vault:// URL. Without a field, the data
+// of the whole secret is returned.
+func (v *vaultClient) read(ctx context.Context, ref string) (any, error) {
+	mount, secretPath, field, err := parseVaultURL(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := v.apiClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret *api.KVSecret
+	if v.config.KVVersion.ValueInt64() == 1 {
+		secret, err = client.KVv1(mount).Get(ctx, secretPath)
+	} else {
+		secret, err = client.KVv2(mount).Get(ctx, secretPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if field == "" {
+		return secret.Data, nil
+	}
+
+	value, ok := secret.Data[field]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no field %s", mount, secretPath, field)
+	}
+
+	return value, nil
+}
+
+// Load implements jsonschema.URLLoader, fields holding a string are decoded
+// as JSON.
+func (v *vaultClient) Load(ref string) (any, error) {
+	value, err := v.read(context.Background(), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := value.(string); ok {
+		return jsonschema.UnmarshalJSON(strings.NewReader(s))
+	}
+
+	// re-decode to get the number representation the compiler expects
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
+}
+
+// readDocument returns the content of the document at ref, fields holding
+// a string are returned as is and anything else is encoded as JSON.
+func (v *vaultClient) readDocument(ctx context.Context, ref string) ([]byte, error) {
+	value, err := v.read(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+
+	return json.Marshal(value)
+}
+
+// parseVaultURL splits vault://mount/path#field into its parts. The field
+// may also be given as the field query parameter, which is the form schema
+// locations are compiled with since fragments are JSON pointers there.
+func parseVaultURL(ref string) (mount, secretPath, field string, err error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if u.Scheme != vaultScheme || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return "", "", "", fmt.Errorf("invalid Vault reference %q, expected vault://mount/path#field", ref)
+	}
+
+	field = u.Fragment
+	if field == "" {
+		field = u.Query().Get("field")
+	}
+
+	return u.Host, strings.Trim(u.Path, "/"), field, nil
+}
+
+// vaultSchemaLocation rewrites vault://mount/path#field, the form used in
+// schema references, into vault://mount/path?field=field, so the compiler
+// does not mistake the field for a JSON pointer.
+func vaultSchemaLocation(ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil || u.Fragment == "" || strings.HasPrefix(u.Fragment, "/") {
+		return ref
+	}
+
+	query := u.Query()
+	query.Set("field", u.Fragment)
+
+	u.RawQuery = query.Encode()
+	u.Fragment = ""
+
+	return u.String()
+}
+
+func isVaultURL(ref string) bool {
+	return strings.HasPrefix(ref, vaultScheme+"://")
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestVaultServer mocks the AppRole login and KV version 2 read endpoints
+// of Vault, secrets maps the path of a secret below the secret mount to its data.
+func newTestVaultServer(t *testing.T, secrets map[string]map[string]any) *httptest.Server {
+	const token = "test-token"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPut || r.Method == http.MethodPost {
+			if r.URL.Path != "/v1/auth/approle/login" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["role_id"] != "role" || body["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"auth": map[string]any{"client_token": token},
+			})
+			return
+		}
+
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		data, ok := secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{
+				"data":     data,
+				"metadata": map[string]any{"version": 1},
+			},
+		})
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestResolveSchemaReference(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		ref  string
+		want string
+	}{
+		{
+			name: "relative to file",
+			file: "/data/examples/example.yaml",
+			ref:  "../schema.json",
+			want: "/data/schema.json",
+		},
+		{
+			name: "absolute path",
+			file: "/data/examples/example.yaml",
+			ref:  "/schemas/../schemas/person.json",
+			want: "/schemas/person.json",
+		},
+		{
+			name: "file uri",
+			file: "/data/examples/example.yaml",
+			ref:  "file:///schemas/my%20person.json#/$defs/person",
+			want: "/schemas/my person.json#/$defs/person",
+		},
+		{
+			name: "vault field",
+			file: "/data/example.yaml",
+			ref:  "vault://secret/schemas#person",
+			want: "vault://secret/schemas?field=person",
+		},
+		{
+			name: "relative to vault document",
+			file: "vault://secret/docs/example#config",
+			ref:  "schemas#person",
+			want: "vault://secret/docs/schemas?field=person",
+		},
+		{
+			name: "json pointer is kept",
+			file: "/data/example.yaml",
+			ref:  "https://example.com/schema.json#/$defs/person",
+			want: "https://example.com/schema.json#/$defs/person",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, resolveSchemaReference(tt.file, tt.ref))
+		})
+	}
+}
+
+func TestParseVaultURL(t *testing.T) {
+	mount, secretPath, field, err := parseVaultURL("vault://secret/path/to/secret#field")
+	require.NoError(t, err)
+	require.Equal(t, "secret", mount)
+	require.Equal(t, "path/to/secret", secretPath)
+	require.Equal(t, "field", field)
+
+	_, _, field, err = parseVaultURL("vault://secret/path?field=other")
+	require.NoError(t, err)
+	require.Equal(t, "other", field)
+
+	_, _, _, err = parseVaultURL("vault://secret")
+	require.Error(t, err)
+}
@@ -0,0 +1,435 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Default limits of decoded documents, which bound the memory used by
+// documents expanding aliases exponentially (billion laughs).
+const (
+	// defaultYAMLMaxDepth bounds the nesting, including the expansion of aliases.
+	defaultYAMLMaxDepth = 1000
+	// defaultYAMLMaxAliases bounds the number of expanded aliases.
+	defaultYAMLMaxAliases = 10000
+	// defaultYAMLMaxNodes bounds the number of decoded nodes, including the
+	// nodes of expanded aliases.
+	defaultYAMLMaxNodes = 1000000
+)
+
+// YAML versions, which type plain scalars like yes and 0644 differently.
+const (
+	yamlVersion11 = "1.1"
+	yamlVersion12 = "1.2"
+)
+
+// yaml11Bools are the booleans of YAML 1.1 besides true and false, which
+// are strings in YAML 1.2 (the Norway problem).
+var yaml11Bools = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+	"n": false, "N": false, "no": false, "No": false, "NO": false, "off": false, "Off": false, "OFF": false,
+}
+
+// jsonNumberRegex matches numbers in JSON syntax.
+var jsonNumberRegex = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?$`)
+
+// yamlDecoder decodes YAML documents like yaml.Unmarshal into an interface,
+// except that integers and decimals are decoded as json.Number, so large
+// integers and decimals are validated exactly instead of as float64, and
+// timestamps are decoded as strings, so they are validated by format.
+type yamlDecoder struct {
+	// rejectTimestamps fails on unquoted timestamps instead.
+	rejectTimestamps bool
+	// version types plain scalars by the rules of YAML 1.1 or 1.2, if set,
+	// instead of the mix of yaml.v3, which has the booleans of YAML 1.2 and
+	// the octals of both.
+	version string
+	// tags resolve the values of nodes with local tags like !Ref, scalars
+	// with other local tags are decoded as strings.
+	tags map[string]yamlTagResolver
+	// maxDepth, maxAliases and maxNodes limit decoded documents, the
+	// defaults apply if zero.
+	maxDepth, maxAliases, maxNodes int
+
+	// aliases and nodes count the expanded aliases and decoded nodes of a
+	// document.
+	aliases, nodes int
+}
+
+// yamlTagResolver converts the decoded value of a node with a local tag.
+type yamlTagResolver func(node *yaml.Node, value any) (any, error)
+
+// Decoding of the custom tags configured in the provider.
+const (
+	yamlTagString = "string"
+	yamlTagMap    = "map"
+	yamlTagError  = "error"
+)
+
+// yamlTagResolvers returns the resolvers of tags configured as string, to
+// decode scalars as strings, map, to decode values as a map of the tag name
+// to the value, or error, to fail decoding.
+func yamlTagResolvers(tags map[string]string) map[string]yamlTagResolver {
+	resolvers := make(map[string]yamlTagResolver, len(tags))
+
+	for tag, decoding := range tags {
+		switch decoding {
+		case yamlTagString:
+			resolvers[tag] = func(node *yaml.Node, _ any) (any, error) {
+				if node.Kind != yaml.ScalarNode {
+					return nil, errors.New("only scalars can be decoded as strings")
+				}
+				return node.Value, nil
+			}
+		case yamlTagMap:
+			resolvers[tag] = func(_ *yaml.Node, value any) (any, error) {
+				return map[string]any{strings.TrimPrefix(tag, "!"): value}, nil
+			}
+		case yamlTagError:
+			resolvers[tag] = func(*yaml.Node, any) (any, error) {
+				return nil, errors.New("tag is not allowed")
+			}
+		}
+	}
+
+	return resolvers
+}
+
+// decodeYAML decodes a YAML document with the default yamlDecoder.
+func decodeYAML(content []byte) (any, error) {
+	return yamlDecoder{}.decode(content)
+}
+
+func (d yamlDecoder) decode(content []byte) (any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+
+	if node.Kind == 0 {
+		return nil, nil
+	}
+
+	d.maxDepth = cmp.Or(d.maxDepth, defaultYAMLMaxDepth)
+	d.maxAliases = cmp.Or(d.maxAliases, defaultYAMLMaxAliases)
+	d.maxNodes = cmp.Or(d.maxNodes, defaultYAMLMaxNodes)
+	d.aliases, d.nodes = 0, 0
+
+	return d.value(&node, 0)
+}
+
+func (d *yamlDecoder) value(node *yaml.Node, depth int) (any, error) {
+	if depth > d.maxDepth {
+		return nil, fmt.Errorf("line %d: document is nested deeper than %d levels", node.Line, d.maxDepth)
+	}
+
+	if d.nodes++; d.nodes > d.maxNodes {
+		return nil, fmt.Errorf("line %d: document has more than %d nodes", node.Line, d.maxNodes)
+	}
+
+	if resolve, ok := d.tags[node.Tag]; ok {
+		untagged := *node
+		untagged.Tag = ""
+
+		value, err := d.value(&untagged, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved, err := resolve(node, value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", node.Line, node.Tag, err)
+		}
+		return resolved, nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return d.value(node.Content[0], depth+1)
+	case yaml.AliasNode:
+		if d.aliases++; d.aliases > d.maxAliases {
+			return nil, fmt.Errorf("line %d: document expands more than %d aliases", node.Line, d.maxAliases)
+		}
+		return d.value(node.Alias, depth+1)
+	case yaml.SequenceNode:
+		values := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := d.value(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case yaml.MappingNode:
+		values := make(map[string]any, len(node.Content)/2)
+		if err := d.mergeMapping(values, node, depth); err != nil {
+			return nil, err
+		}
+		return values, nil
+	case yaml.ScalarNode:
+		return d.scalar(node)
+	}
+
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// mergeMapping adds the keys of node to values. Keys merged with << are
+// overridden by the keys of node itself.
+func (d *yamlDecoder) mergeMapping(values map[string]any, node *yaml.Node, depth int) error {
+	var merged []*yaml.Node
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge" {
+			merged = append(merged, value)
+			continue
+		}
+
+		if key.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: only scalar mapping keys are supported", key.Line)
+		}
+
+		decoded, err := d.value(value, depth+1)
+		if err != nil {
+			return err
+		}
+		values[key.Value] = decoded
+	}
+
+	for _, value := range merged {
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+
+		for _, source := range sources {
+			decoded, err := d.value(source, depth+1)
+			if err != nil {
+				return err
+			}
+
+			mapping, ok := decoded.(map[string]any)
+			if !ok {
+				return fmt.Errorf("line %d: map merge requires a mapping or a sequence of mappings", source.Line)
+			}
+
+			for k, v := range mapping {
+				if _, ok := values[k]; !ok {
+					values[k] = v
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *yamlDecoder) scalar(node *yaml.Node) (any, error) {
+	// only plain scalars are typed by the version, quoted and tagged scalars keep their type
+	if d.version == yamlVersion11 && node.Style&^yaml.FlowStyle == 0 {
+		if b, ok := yaml11Bools[node.Value]; ok {
+			return b, nil
+		}
+		if node.ShortTag() == "!!int" && strings.Contains(strings.ToLower(node.Value), "0o") {
+			return node.Value, nil
+		}
+	}
+
+	switch node.ShortTag() {
+	case "!!int":
+		return yamlInt(node.Value, d.version == yamlVersion12 && node.Style&^yaml.FlowStyle == 0)
+	case "!!float":
+		return yamlFloat(node)
+	case "!!timestamp":
+		if d.rejectTimestamps {
+			return nil, fmt.Errorf("line %d: unquoted timestamp %s, quote it to use it as a string", node.Line, node.Value)
+		}
+		return node.Value, nil
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// yamlInt converts the decimal, octal (0o), hexadecimal (0x) and binary
+// (0b) integers of YAML to json.Number. Integers with leading zeros are
+// octal like in YAML 1.1, or decimal like in YAML 1.2 if leadingZeros is set.
+func yamlInt(value string, leadingZeros bool) (any, error) {
+	s := strings.ReplaceAll(value, "_", "")
+
+	sign := ""
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		if s[0] == '-' {
+			sign = "-"
+		}
+		s = s[1:]
+	}
+
+	// YAML 1.1 octals like 0644 are decoded as octal by yaml.v3
+	if len(s) > 1 && s[0] == '0' && s[1] >= '0' && s[1] <= '9' {
+		if leadingZeros {
+			s = cmp.Or(strings.TrimLeft(s, "0"), "0")
+		} else {
+			s = "0o" + s[1:]
+		}
+	}
+
+	n, ok := new(big.Int).SetString(sign+s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", value)
+	}
+
+	return json.Number(n.String()), nil
+}
+
+// yamlFloat converts the decimals of YAML to json.Number, infinity and NaN
+// have no JSON representation and stay float64.
+func yamlFloat(node *yaml.Node) (any, error) {
+	s := strings.TrimPrefix(strings.ReplaceAll(node.Value, "_", ""), "+")
+	if jsonNumberRegex.MatchString(s) {
+		return json.Number(s), nil
+	}
+
+	// e.g. 1., .5 or .inf
+	var f float64
+	if err := node.Decode(&f); err != nil {
+		return nil, err
+	}
+
+	if r, ok := new(big.Rat).SetString(s); ok {
+		return json.Number(r.FloatString(decimalPlaces(s))), nil
+	}
+
+	return f, nil
+}
+
+// decimalPlaces returns the number of digits after the decimal point of s.
+func decimalPlaces(s string) int {
+	_, fraction, ok := strings.Cut(s, ".")
+	if !ok {
+		return 0
+	}
+
+	if end := strings.IndexAny(fraction, "eE"); end >= 0 {
+		exp, err := strconv.Atoi(fraction[end+1:])
+		if err != nil {
+			return end
+		}
+		return max(end-exp, 0)
+	}
+
+	return len(fraction)
+}
+
+// normalizeYAML returns a YAML document re-encoded with aliases and merge
+// keys expanded like they are decoded, without anchors, so the output has
+// the keys and values that were validated. Comments are kept if
+// keepComments is set. Documents are expected to be decoded successfully
+// before, which bounds the expansion of aliases.
+func normalizeYAML(content []byte, keepComments bool) (string, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return "", err
+	}
+
+	if node.Kind == 0 {
+		return "", nil
+	}
+
+	var buf strings.Builder
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err := encoder.Encode(expandYAMLNode(&node, keepComments))
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// expandYAMLNode returns a copy of node with the nodes of aliases in place
+// of the aliases and the keys of merged mappings in place of merge keys.
+// Keys merged with << are overridden by the keys of the mapping itself and
+// by the keys of mappings merged before them.
+func expandYAMLNode(node *yaml.Node, keepComments bool) *yaml.Node {
+	expanded := *node
+	if node.Kind == yaml.AliasNode {
+		// the comments of the anchored nodes stay where they are, the alias keeps its own
+		expanded = *expandYAMLNode(node.Alias, false)
+		expanded.HeadComment, expanded.LineComment, expanded.FootComment = node.HeadComment, node.LineComment, node.FootComment
+	}
+
+	expanded.Anchor = ""
+	expanded.Alias = nil
+	if !keepComments {
+		expanded.HeadComment, expanded.LineComment, expanded.FootComment = "", "", ""
+	}
+
+	if node.Kind == yaml.AliasNode {
+		return &expanded
+	}
+
+	if node.Kind != yaml.MappingNode {
+		expanded.Content = make([]*yaml.Node, 0, len(node.Content))
+		for _, child := range node.Content {
+			expanded.Content = append(expanded.Content, expandYAMLNode(child, keepComments))
+		}
+		return &expanded
+	}
+
+	keys := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i]; key.ShortTag() != "!!merge" {
+			keys[key.Value] = true
+		}
+	}
+
+	expanded.Content = make([]*yaml.Node, 0, len(node.Content))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if key.ShortTag() != "!!merge" {
+			expanded.Content = append(expanded.Content, expandYAMLNode(key, keepComments), expandYAMLNode(value, keepComments))
+			continue
+		}
+
+		merged := expandYAMLNode(value, keepComments)
+		sources := []*yaml.Node{merged}
+		if merged.Kind == yaml.SequenceNode {
+			sources = merged.Content
+		}
+
+		for _, source := range sources {
+			for j := 0; j+1 < len(source.Content); j += 2 {
+				if !keys[source.Content[j].Value] {
+					keys[source.Content[j].Value] = true
+					expanded.Content = append(expanded.Content, source.Content[j], source.Content[j+1])
+				}
+			}
+		}
+	}
+
+	return &expanded
+}
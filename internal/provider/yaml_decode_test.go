@@ -0,0 +1,413 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected any
+	}{
+		{
+			name:     "large integer",
+			content:  "id: 9007199254740993",
+			expected: map[string]any{"id": json.Number("9007199254740993")},
+		},
+		{
+			name:     "decimal",
+			content:  "price: 0.10",
+			expected: map[string]any{"price": json.Number("0.10")},
+		},
+		{
+			name:     "integer notations",
+			content:  "hex: 0x1F\noctal: 0o17\nlegacy_octal: 0644\nseparated: 1_000\nnegative: -0x1F",
+			expected: map[string]any{"hex": json.Number("31"), "octal": json.Number("15"), "legacy_octal": json.Number("420"), "separated": json.Number("1000"), "negative": json.Number("-31")},
+		},
+		{
+			name:     "decimal notations",
+			content:  "leading: .5\ntrailing: 1.\nexponent: 1.5e3",
+			expected: map[string]any{"leading": json.Number("0.5"), "trailing": json.Number("1"), "exponent": json.Number("1.5e3")},
+		},
+		{
+			name:     "other scalars",
+			content:  "string: !!str 12\nbool: true\nnull: ~",
+			expected: map[string]any{"string": "12", "bool": true, "null": nil},
+		},
+		{
+			name:     "merge keys",
+			content:  "base: &base {a: 1, b: 1}\nmerged: {<<: *base, b: 2}",
+			expected: map[string]any{"base": map[string]any{"a": json.Number("1"), "b": json.Number("1")}, "merged": map[string]any{"a": json.Number("1"), "b": json.Number("2")}},
+		},
+		{
+			name:     "sequence",
+			content:  "- 1\n- [2]",
+			expected: []any{json.Number("1"), []any{json.Number("2")}},
+		},
+		{
+			name:     "sequence of scalars",
+			content:  "- hello\n- 42\n- true\n- ~",
+			expected: []any{"hello", json.Number("42"), true, nil},
+		},
+		{
+			name:     "string root",
+			content:  "hello",
+			expected: "hello",
+		},
+		{
+			name:     "number root",
+			content:  "9007199254740993",
+			expected: json.Number("9007199254740993"),
+		},
+		{
+			name:     "empty",
+			content:  "",
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := decodeYAML([]byte(test.content))
+			require.NoError(t, err)
+			require.Equal(t, test.expected, value)
+		})
+	}
+
+	value, err := decodeYAML([]byte("value: .inf"))
+	require.NoError(t, err)
+	require.True(t, math.IsInf(value.(map[string]any)["value"].(float64), 1))
+}
+
+func TestDecodeYAMLTimestamps(t *testing.T) {
+	value, err := decodeYAML([]byte("released: 2024-01-02"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"released": "2024-01-02"}, value)
+
+	_, err = yamlDecoder{rejectTimestamps: true}.decode([]byte("released: 2024-01-02"))
+	require.ErrorContains(t, err, "line 1: unquoted timestamp 2024-01-02")
+
+	value, err = yamlDecoder{rejectTimestamps: true}.decode([]byte(`released: "2024-01-02"`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"released": "2024-01-02"}, value)
+}
+
+func TestDecodeYAMLVersion(t *testing.T) {
+	content := []byte("country: NO\nenabled: yes\nflow: [on, Off]\nquoted: \"yes\"\ntagged: !!str y\nmode: 0644\noctal: 0o17\nzero: 00\nhex: 0x1F")
+
+	value, err := decodeYAML(content)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"country": "NO", "enabled": "yes", "flow": []any{"on", "Off"}, "quoted": "yes", "tagged": "y",
+		"mode": json.Number("420"), "octal": json.Number("15"), "zero": json.Number("0"), "hex": json.Number("31"),
+	}, value)
+
+	value, err = yamlDecoder{version: yamlVersion11}.decode(content)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"country": false, "enabled": true, "flow": []any{true, false}, "quoted": "yes", "tagged": "y",
+		"mode": json.Number("420"), "octal": "0o17", "zero": json.Number("0"), "hex": json.Number("31"),
+	}, value)
+
+	value, err = yamlDecoder{version: yamlVersion12}.decode(content)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"country": "NO", "enabled": "yes", "flow": []any{"on", "Off"}, "quoted": "yes", "tagged": "y",
+		"mode": json.Number("644"), "octal": json.Number("15"), "zero": json.Number("0"), "hex": json.Number("31"),
+	}, value)
+}
+
+func TestDecodeYAMLTags(t *testing.T) {
+	value, err := yamlDecoder{tags: cloudFormationTags}.decode([]byte(`name: !Ref Name
+arn: !GetAtt Bucket.Arn
+url: !Sub "https://${Bucket}"
+list: !Split [",", !ImportValue Shared]
+other: !Unknown value`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"name":  map[string]any{"Ref": "Name"},
+		"arn":   map[string]any{"Fn::GetAtt": []any{"Bucket", "Arn"}},
+		"url":   map[string]any{"Fn::Sub": "https://${Bucket}"},
+		"list":  map[string]any{"Fn::Split": []any{",", map[string]any{"Fn::ImportValue": "Shared"}}},
+		"other": "value",
+	}, value)
+
+	_, err = yamlDecoder{tags: cloudFormationTags}.decode([]byte("arn: !GetAtt Bucket"))
+	require.ErrorContains(t, err, `line 1: !GetAtt: "Bucket" is not of the form resource.attribute`)
+}
+
+func TestDecodeYAMLLimits(t *testing.T) {
+	// every level expands the previous one ten times
+	laughs := "a: &a [lol, lol, lol, lol, lol, lol, lol, lol, lol, lol]\n"
+	for i, name := range []string{"b", "c", "d", "e", "f", "g", "h", "i"} {
+		previous := string(rune('a' + i))
+		laughs += fmt.Sprintf("%s: &%s [*%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s, *%s]\n", name, name, previous, previous, previous, previous, previous, previous, previous, previous, previous, previous)
+	}
+
+	_, err := decodeYAML([]byte(laughs))
+	require.ErrorContains(t, err, "document expands more than 10000 aliases")
+
+	_, err = yamlDecoder{maxAliases: 1000000}.decode([]byte(laughs))
+	require.ErrorContains(t, err, "document has more than 1000000 nodes")
+
+	_, err = yamlDecoder{maxDepth: 2}.decode([]byte("a: {b: {c: 1}}"))
+	require.ErrorContains(t, err, "line 1: document is nested deeper than 2 levels")
+
+	_, err = yamlDecoder{maxNodes: 4}.decode([]byte("a: [1, 2, 3]"))
+	require.ErrorContains(t, err, "line 1: document has more than 4 nodes")
+
+	// the limits apply to every document on its own
+	decoder := yamlDecoder{maxAliases: 1}
+	for range 2 {
+		_, err = decoder.decode([]byte("a: &a 1\nb: *a"))
+		require.NoError(t, err)
+	}
+}
+
+func TestNormalizeYAMLNodes(t *testing.T) {
+	content := `# shared settings
+defaults: &defaults
+  replicas: 1 # minimum
+  image: web
+service:
+  <<: *defaults
+  # scaled out
+  replicas: 3
+extra:
+  - *defaults
+`
+
+	normalized, err := normalizeYAML([]byte(content), true)
+	require.NoError(t, err)
+	require.Equal(t, `# shared settings
+defaults:
+  replicas: 1 # minimum
+  image: web
+service:
+  image: web
+  # scaled out
+  replicas: 3
+extra:
+  - replicas: 1
+    image: web
+`, normalized)
+
+	// the keys and values are the ones that are decoded
+	decoded, err := decodeYAML([]byte(content))
+	require.NoError(t, err)
+	renormalized, err := decodeYAML([]byte(normalized))
+	require.NoError(t, err)
+	require.Equal(t, decoded, renormalized)
+
+	normalized, err = normalizeYAML([]byte(content), false)
+	require.NoError(t, err)
+	require.NotContains(t, normalized, "#")
+
+	normalized, err = normalizeYAML(nil, true)
+	require.NoError(t, err)
+	require.Empty(t, normalized)
+}
+
+func TestYAMLTagResolvers(t *testing.T) {
+	decoder := yamlDecoder{tags: yamlTagResolvers(map[string]string{
+		"!vault":   yamlTagString,
+		"!Ref":     yamlTagMap,
+		"!include": yamlTagError,
+	})}
+
+	value, err := decoder.decode([]byte("port: !vault 8080\nbucket: !Ref Bucket\nlist: !Ref [a, b]\nother: !other 1"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"port":   "8080",
+		"bucket": map[string]any{"Ref": "Bucket"},
+		"list":   map[string]any{"Ref": []any{"a", "b"}},
+		"other":  "1",
+	}, value)
+
+	_, err = decoder.decode([]byte("values: !include values.yaml"))
+	require.ErrorContains(t, err, "line 1: !include: tag is not allowed")
+
+	_, err = decoder.decode([]byte("secrets: !vault {path: db}"))
+	require.ErrorContains(t, err, "line 1: !vault: only scalars can be decoded as strings")
+}
+
+func TestYAMLTags(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "values.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+password: !vault 1234
+bucket: !Ref Bucket
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "type": "object",
+  "properties": {
+    "password": {"type": "string"},
+    "bucket": {"type": "object", "required": ["Ref"]}
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.Join(tmpDir, "*.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccYAMLTagsConfig, `{ "!vault" = "error" }`, pattern),
+				ExpectError: regexp.MustCompile(`!vault:\s+tag\s+is\s+not\s+allowed`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccYAMLTagsConfig, `{ "!vault" = "number" }`, pattern),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccYAMLTagsConfig, `{ "!!str" = "string" }`, pattern),
+				ExpectError: regexp.MustCompile(`must be a local tag like !vault`),
+			},
+			{
+				Config: fmt.Sprintf(testAccYAMLTagsConfig, `{ "!vault" = "string", "!Ref" = "map" }`, pattern),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.tags",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestYAMLLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "values.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+base: &base {replicas: 1}
+web: *base
+worker: *base
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{"type": "object"}`), 0644)
+	require.NoError(t, err)
+
+	pattern := filepath.Join(tmpDir, "*.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccYAMLLimitsConfig, `{ max_aliases = 1 }`, pattern),
+				ExpectError: regexp.MustCompile(`document\s+expands\s+more\s+than\s+1\s+aliases`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccYAMLLimitsConfig, `{ max_depth = 1 }`, pattern),
+				ExpectError: regexp.MustCompile(`document\s+is\s+nested\s+deeper\s+than\s+1\s+levels`),
+			},
+			{
+				Config: fmt.Sprintf(testAccYAMLLimitsConfig, `{ max_aliases = 2, max_nodes = 20 }`, pattern),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.limits",
+						tfjsonpath.New("valid_files"),
+						knownvalue.ListSizeExact(1),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestYAMLVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "feature.yaml"), []byte(`# yaml-language-server: $schema=./schema.json
+enabled: yes
+country: NO
+mode: 0644
+`), 0644)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, "schema.json"), []byte(`{
+  "type": "object",
+  "properties": {
+    "enabled": {"type": "boolean"},
+    "mode": {"type": "integer"}
+  }
+}`), 0644)
+	require.NoError(t, err)
+
+	file := filepath.Join(tmpDir, "feature.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccYAMLVersionConfig, file, "1.2"),
+				ExpectError: regexp.MustCompile(`got string, want boolean`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccYAMLVersionConfig, file, "1.3"),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config: fmt.Sprintf(testAccYAMLVersionConfig, file, "1.1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.jsonschema_validated_yaml.version",
+						tfjsonpath.New("values_json").AtMapKey(file),
+						knownvalue.StringExact(`{"country":false,"enabled":true,"mode":420}`),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccYAMLVersionConfig = `
+data "jsonschema_validated_yaml" "version" {
+  input_pattern = "%s"
+  yaml_version  = "%s"
+}
+`
+
+const testAccYAMLLimitsConfig = `
+provider "jsonschema" {
+  yaml_limits = %s
+}
+
+data "jsonschema_validated_yaml" "limits" {
+  input_pattern = "%s"
+}
+`
+
+const testAccYAMLTagsConfig = `
+provider "jsonschema" {
+  yaml_tags = %s
+}
+
+data "jsonschema_validated_yaml" "tags" {
+  input_pattern = "%s"
+}
+`